@@ -3,33 +3,36 @@ package kvm
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"os"
 	"time"
 
+	"github.com/pojntfx/go-nbd/pkg/backend"
 	"github.com/pojntfx/go-nbd/pkg/server"
 	"github.com/rs/zerolog"
 )
 
 type remoteImageBackend struct {
+	lun int
 }
 
 func (r remoteImageBackend) ReadAt(p []byte, off int64) (n int, err error) {
 	virtualMediaStateMutex.RLock()
-	logger.Debug().Interface("currentVirtualMediaState", currentVirtualMediaState).Msg("currentVirtualMediaState")
+	l := virtualMediaLuns[r.lun]
+	logger.Debug().Int("lun", r.lun).Interface("currentVirtualMediaState", l.state).Msg("currentVirtualMediaState")
 	logger.Debug().Int64("read size", int64(len(p))).Int64("off", off).Msg("read size and off")
-	if currentVirtualMediaState == nil {
+	virtualMediaStateMutex.RUnlock()
+	if l.state == nil {
 		return 0, errors.New("image not mounted")
 	}
-	source := currentVirtualMediaState.Source
-	virtualMediaStateMutex.RUnlock()
 
 	_, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	switch source {
+	switch l.state.Source {
 	case HTTP:
-		return httpRangeReader.ReadAt(p, off)
+		return l.httpRangeReader.ReadAt(p, off)
 	default:
 		return 0, errors.New("unknown image source")
 	}
@@ -42,20 +45,28 @@ func (r remoteImageBackend) WriteAt(p []byte, off int64) (n int, err error) {
 func (r remoteImageBackend) Size() (int64, error) {
 	virtualMediaStateMutex.Lock()
 	defer virtualMediaStateMutex.Unlock()
-	if currentVirtualMediaState == nil {
+	state := virtualMediaLuns[r.lun].state
+	if state == nil {
 		return 0, errors.New("no virtual media state")
 	}
-	return currentVirtualMediaState.Size, nil
+	return state.Size, nil
 }
 
 func (r remoteImageBackend) Sync() error {
 	return nil
 }
 
-const nbdSocketPath = "/var/run/nbd.socket"
-const nbdDevicePath = "/dev/nbd0"
+// each LUN gets its own NBD device and socket
+const nbdSocketPathFormat = "/var/run/nbd%d.socket"
+const nbdDevicePathFormat = "/dev/nbd%d"
 
 type NBDDevice struct {
+	lun        int
+	socketPath string
+	devicePath string
+	backend    backend.Backend
+	readOnly   bool
+
 	listener   net.Listener
 	serverConn net.Conn
 	clientConn net.Conn
@@ -64,44 +75,56 @@ type NBDDevice struct {
 	l *zerolog.Logger
 }
 
-func NewNBDDevice() *NBDDevice {
-	return &NBDDevice{}
+func NewNBDDevice(lun int, b backend.Backend, readOnly bool) *NBDDevice {
+	return &NBDDevice{
+		lun:        lun,
+		socketPath: fmt.Sprintf(nbdSocketPathFormat, lun),
+		devicePath: fmt.Sprintf(nbdDevicePathFormat, lun),
+		backend:    b,
+		readOnly:   readOnly,
+	}
+}
+
+// DevicePath returns the block device the image is exposed as.
+func (d *NBDDevice) DevicePath() string {
+	return d.devicePath
 }
 
 func (d *NBDDevice) Start() error {
 	var err error
 
-	if _, err := os.Stat(nbdDevicePath); os.IsNotExist(err) {
+	if _, err := os.Stat(d.devicePath); os.IsNotExist(err) {
 		return errors.New("NBD device does not exist")
 	}
 
-	d.dev, err = os.Open(nbdDevicePath)
+	d.dev, err = os.Open(d.devicePath)
 	if err != nil {
 		return err
 	}
 
 	if d.l == nil {
 		scopedLogger := nbdLogger.With().
-			Str("socket_path", nbdSocketPath).
-			Str("device_path", nbdDevicePath).
+			Int("lun", d.lun).
+			Str("socket_path", d.socketPath).
+			Str("device_path", d.devicePath).
 			Logger()
 		d.l = &scopedLogger
 	}
 
 	// Remove the socket file if it already exists
-	if _, err := os.Stat(nbdSocketPath); err == nil {
-		if err := os.Remove(nbdSocketPath); err != nil {
+	if _, err := os.Stat(d.socketPath); err == nil {
+		if err := os.Remove(d.socketPath); err != nil {
 			d.l.Error().Err(err).Msg("failed to remove existing socket file")
 			os.Exit(1)
 		}
 	}
 
-	d.listener, err = net.Listen("unix", nbdSocketPath)
+	d.listener, err = net.Listen("unix", d.socketPath)
 	if err != nil {
 		return err
 	}
 
-	d.clientConn, err = net.Dial("unix", nbdSocketPath)
+	d.clientConn, err = net.Dial("unix", d.socketPath)
 	if err != nil {
 		return err
 	}
@@ -122,11 +145,11 @@ func (d *NBDDevice) runServerConn() {
 			{
 				Name:        "jetkvm",
 				Description: "",
-				Backend:     &remoteImageBackend{},
+				Backend:     d.backend,
 			},
 		},
 		&server.Options{
-			ReadOnly:           true,
+			ReadOnly:           d.readOnly,
 			MinimumBlockSize:   uint32(1024),
 			PreferredBlockSize: uint32(4 * 1024),
 			MaximumBlockSize:   uint32(16 * 1024),
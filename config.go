@@ -7,6 +7,10 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/jetkvm/kvm/internal/hidrpc"
+	"github.com/jetkvm/kvm/internal/kbmacro"
+	"github.com/jetkvm/kvm/internal/keymap"
+	"github.com/jetkvm/kvm/internal/keyseq"
 	"github.com/jetkvm/kvm/internal/logging"
 	"github.com/jetkvm/kvm/internal/network"
 	"github.com/jetkvm/kvm/internal/usbgadget"
@@ -19,93 +23,56 @@ type WakeOnLanDevice struct {
 	MacAddress string `json:"macAddress"`
 }
 
-// Constants for keyboard macro limits
-const (
-	MaxMacrosPerDevice = 25
-	MaxStepsPerMacro   = 10
-	MaxKeysPerStep     = 10
-	MinStepDelay       = 50
-	MaxStepDelay       = 2000
-)
-
-type KeyboardMacroStep struct {
-	Keys      []string `json:"keys"`
-	Modifiers []string `json:"modifiers"`
-	Delay     int      `json:"delay"`
-}
-
-func (s *KeyboardMacroStep) Validate() error {
-	if len(s.Keys) > MaxKeysPerStep {
-		return fmt.Errorf("too many keys in step (max %d)", MaxKeysPerStep)
-	}
-
-	if s.Delay < MinStepDelay {
-		s.Delay = MinStepDelay
-	} else if s.Delay > MaxStepDelay {
-		s.Delay = MaxStepDelay
-	}
-
-	return nil
-}
-
-type KeyboardMacro struct {
-	ID        string              `json:"id"`
-	Name      string              `json:"name"`
-	Steps     []KeyboardMacroStep `json:"steps"`
-	SortOrder int                 `json:"sortOrder,omitempty"`
-}
+const MaxScriptsPerDevice = 25
 
-func (m *KeyboardMacro) Validate() error {
-	if m.Name == "" {
-		return fmt.Errorf("macro name cannot be empty")
-	}
-
-	if len(m.Steps) == 0 {
-		return fmt.Errorf("macro must have at least one step")
-	}
-
-	if len(m.Steps) > MaxStepsPerMacro {
-		return fmt.Errorf("too many steps in macro (max %d)", MaxStepsPerMacro)
-	}
-
-	for i := range m.Steps {
-		if err := m.Steps[i].Validate(); err != nil {
-			return fmt.Errorf("invalid step %d: %w", i+1, err)
-		}
-	}
-
-	return nil
+// KeyboardScript is a stored keyscript program, see internal/keyscript for the syntax.
+type KeyboardScript struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Script string `json:"script"`
 }
 
 type Config struct {
-	CloudURL             string                 `json:"cloud_url"`
-	CloudAppURL          string                 `json:"cloud_app_url"`
-	CloudToken           string                 `json:"cloud_token"`
-	GoogleIdentity       string                 `json:"google_identity"`
-	JigglerEnabled       bool                   `json:"jiggler_enabled"`
-	JigglerConfig        *JigglerConfig         `json:"jiggler_config"`
-	AutoUpdateEnabled    bool                   `json:"auto_update_enabled"`
-	IncludePreRelease    bool                   `json:"include_pre_release"`
-	HashedPassword       string                 `json:"hashed_password"`
-	LocalAuthToken       string                 `json:"local_auth_token"`
-	LocalAuthMode        string                 `json:"localAuthMode"` //TODO: fix it with migration
-	LocalLoopbackOnly    bool                   `json:"local_loopback_only"`
-	WakeOnLanDevices     []WakeOnLanDevice      `json:"wake_on_lan_devices"`
-	KeyboardMacros       []KeyboardMacro        `json:"keyboard_macros"`
-	KeyboardLayout       string                 `json:"keyboard_layout"`
-	EdidString           string                 `json:"hdmi_edid_string"`
-	ActiveExtension      string                 `json:"active_extension"`
-	DisplayRotation      string                 `json:"display_rotation"`
-	DisplayMaxBrightness int                    `json:"display_max_brightness"`
-	DisplayDimAfterSec   int                    `json:"display_dim_after_sec"`
-	DisplayOffAfterSec   int                    `json:"display_off_after_sec"`
-	TLSMode              string                 `json:"tls_mode"` // options: "self-signed", "user-defined", ""
-	UsbConfig            *usbgadget.Config      `json:"usb_config"`
-	UsbDevices           *usbgadget.Devices     `json:"usb_devices"`
-	NetworkConfig        *network.NetworkConfig `json:"network_config"`
-	DefaultLogLevel      string                 `json:"default_log_level"`
-	HDMIOutputEnabled    bool                   `json:"hdmi_output_enabled"`
-	HDMIOutputAutoStart  bool                   `json:"hdmi_output_auto_start"`
+	CloudURL             string                     `json:"cloud_url"`
+	CloudAppURL          string                     `json:"cloud_app_url"`
+	CloudToken           string                     `json:"cloud_token"`
+	GoogleIdentity       string                     `json:"google_identity"`
+	JigglerEnabled       bool                       `json:"jiggler_enabled"`
+	JigglerConfig        *JigglerConfig             `json:"jiggler_config"`
+	AutoUpdateEnabled    bool                       `json:"auto_update_enabled"`
+	IncludePreRelease    bool                       `json:"include_pre_release"`
+	HashedPassword       string                     `json:"hashed_password"`
+	LocalAuthToken       string                     `json:"local_auth_token"`
+	LocalAuthMode        string                     `json:"localAuthMode"` //TODO: fix it with migration
+	LocalLoopbackOnly    bool                       `json:"local_loopback_only"`
+	WakeOnLanDevices     []WakeOnLanDevice          `json:"wake_on_lan_devices"`
+	KeyboardMacros       []kbmacro.Macro            `json:"keyboard_macros,omitempty"` // only read to migrate them to the macro store
+	KeyboardMacroLimits  kbmacro.Limits             `json:"keyboard_macro_limits"`
+	KeyboardScripts      []KeyboardScript           `json:"keyboard_scripts"`
+	KeyRemapProfiles     []keymap.Profile           `json:"key_remap_profiles"`
+	KeyRemapProfile      string                     `json:"key_remap_profile"`
+	HidCoalescing        hidrpc.CoalesceOptions     `json:"hid_coalescing"`
+	VideoRecording       VideoRecordingConfig       `json:"video_recording"`
+	KeySequences         []keyseq.Sequence          `json:"key_sequences"`
+	KeyboardLayout       string                     `json:"keyboard_layout"`
+	EdidString           string                     `json:"hdmi_edid_string"`
+	ActiveExtension      string                     `json:"active_extension"`
+	DisplayRotation      string                     `json:"display_rotation"`
+	DisplayMaxBrightness int                        `json:"display_max_brightness"`
+	DisplayDimAfterSec   int                        `json:"display_dim_after_sec"`
+	DisplayOffAfterSec   int                        `json:"display_off_after_sec"`
+	TLSMode              string                     `json:"tls_mode"` // options: "self-signed", "user-defined", ""
+	UsbConfig            *usbgadget.Config          `json:"usb_config"`
+	UsbDevices           *usbgadget.Devices         `json:"usb_devices"`
+	UsbNetworkConfig     *UsbNetworkConfig          `json:"usb_network_config"`
+	UsbIdentityPreset    string                     `json:"usb_identity_preset"`
+	UsbIdentityPresets   []usbgadget.IdentityPreset `json:"usb_identity_presets"` // user-defined presets
+	UsbRemoteWakeup      bool                       `json:"usb_remote_wakeup"`
+	UsbWakeOnKeyPress    bool                       `json:"usb_wake_on_key_press"`
+	NetworkConfig        *network.NetworkConfig     `json:"network_config"`
+	DefaultLogLevel      string                     `json:"default_log_level"`
+	HDMIOutputEnabled    bool                       `json:"hdmi_output_enabled"`
+	HDMIOutputAutoStart  bool                       `json:"hdmi_output_auto_start"`
 }
 
 func (c *Config) GetDisplayRotation() uint16 {
@@ -134,7 +101,12 @@ var defaultConfig = &Config{
 	CloudAppURL:          "https://app.jetkvm.com",
 	AutoUpdateEnabled:    true, // Set a default value
 	ActiveExtension:      "",
-	KeyboardMacros:       []KeyboardMacro{},
+	KeyboardMacroLimits:  kbmacro.DefaultLimits,
+	KeyboardScripts:      []KeyboardScript{},
+	KeyRemapProfiles:     []keymap.Profile{},
+	KeySequences:         []keyseq.Sequence{},
+	HidCoalescing:        hidrpc.CoalesceOptions{Pointer: true, Wheel: true},
+	VideoRecording:       defaultVideoRecordingConfig,
 	DisplayRotation:      "270",
 	KeyboardLayout:       "en-US",
 	DisplayMaxBrightness: 64,
@@ -162,10 +134,18 @@ var defaultConfig = &Config{
 		AbsoluteMouse: true,
 		RelativeMouse: true,
 		Keyboard:      true,
+		Fido:          false,
 		MassStorage:   true,
+		SerialConsole: false,
+		Printer:       false,
+		Audio:         false,
+		Network:       false,
 	},
-	NetworkConfig:   &network.NetworkConfig{},
-	DefaultLogLevel: "INFO",
+	UsbNetworkConfig:  defaultUsbNetworkConfig,
+	UsbRemoteWakeup:   false,
+	UsbWakeOnKeyPress: false,
+	NetworkConfig:     &network.NetworkConfig{},
+	DefaultLogLevel:   "INFO",
 }
 
 var (
@@ -226,6 +206,10 @@ func LoadConfig() {
 		loadedConfig.UsbDevices = defaultConfig.UsbDevices
 	}
 
+	if loadedConfig.UsbNetworkConfig == nil {
+		loadedConfig.UsbNetworkConfig = defaultConfig.UsbNetworkConfig
+	}
+
 	if loadedConfig.NetworkConfig == nil {
 		loadedConfig.NetworkConfig = defaultConfig.NetworkConfig
 	}
@@ -239,6 +223,9 @@ func LoadConfig() {
 		loadedConfig.KeyboardLayout = "en-US"
 	}
 
+	// older versions allowed more keys per step than a keyboard report holds
+	loadedConfig.KeyboardMacroLimits.MaxKeysPerStep = min(loadedConfig.KeyboardMacroLimits.MaxKeysPerStep, kbmacro.MaxKeysPerStep)
+
 	config = &loadedConfig
 
 	logging.GetRootLogger().UpdateLogLevel(config.DefaultLogLevel)
@@ -0,0 +1,249 @@
+package kvm
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jetkvm/kvm/internal/usbgadget"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// hidLatencyBuckets are in seconds, shared by the Prometheus histograms and the diagnostics
+var hidLatencyBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}
+
+var (
+	metricHidMessageLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "jetkvm_hidrpc_message_latency_seconds",
+			Help:    "The time from receiving a HID RPC message until it has been handled and written to the gadget",
+			Buckets: hidLatencyBuckets,
+		},
+		[]string{"type", "channel"},
+	)
+	metricHidQueueWait = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "jetkvm_hidrpc_queue_wait_seconds",
+			Help:    "The time a HID RPC message waits in its queue",
+			Buckets: hidLatencyBuckets,
+		},
+		[]string{"type", "channel"},
+	)
+	metricHidQueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "jetkvm_hidrpc_queue_depth",
+			Help: "The number of HID RPC messages waiting in the queue",
+		},
+		[]string{"queue"},
+	)
+	metricHidMessageDropCount = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jetkvm_hidrpc_messages_dropped_total",
+			Help: "The number of HID RPC messages that were dropped or timed out",
+		},
+		[]string{"reason"},
+	)
+	metricKeepAliveJitter = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "jetkvm_hidrpc_keepalive_jitter_seconds",
+			Help:    "The deviation of the keep-alive interval from the expected rate",
+			Buckets: hidLatencyBuckets,
+		},
+	)
+	metricKeepAliveRejectCount = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "jetkvm_hidrpc_keepalive_rejected_total",
+			Help: "The number of keep-alives that arrived too late to extend the key hold",
+		},
+	)
+)
+
+// latencyStats keeps the same buckets as the Prometheus histograms for the diagnostics
+type latencyStats struct {
+	count   uint64
+	sum     time.Duration
+	max     time.Duration
+	buckets [12]uint64 // hidLatencyBuckets and +Inf
+}
+
+func (s *latencyStats) observe(d time.Duration) {
+	s.count++
+	s.sum += d
+	s.max = max(s.max, d)
+	i, _ := slices.BinarySearch(hidLatencyBuckets, d.Seconds())
+	s.buckets[i]++
+}
+
+// quantile returns the upper bound of the bucket that contains the quantile
+func (s *latencyStats) quantile(q float64) time.Duration {
+	target := uint64(q * float64(s.count))
+	var seen uint64
+	for i, n := range s.buckets {
+		seen += n
+		if seen > target && i < len(hidLatencyBuckets) {
+			return min(s.max, time.Duration(hidLatencyBuckets[i]*float64(time.Second)))
+		}
+	}
+	return s.max
+}
+
+type HidLatencySummary struct {
+	Count uint64  `json:"count"`
+	AvgMs float64 `json:"avgMs"`
+	P50Ms float64 `json:"p50Ms"`
+	P95Ms float64 `json:"p95Ms"`
+	P99Ms float64 `json:"p99Ms"`
+	MaxMs float64 `json:"maxMs"`
+}
+
+func toMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func (s *latencyStats) summary() HidLatencySummary {
+	if s.count == 0 {
+		return HidLatencySummary{}
+	}
+	return HidLatencySummary{
+		Count: s.count,
+		AvgMs: toMs(s.sum / time.Duration(s.count)),
+		P50Ms: toMs(s.quantile(0.5)),
+		P95Ms: toMs(s.quantile(0.95)),
+		P99Ms: toMs(s.quantile(0.99)),
+		MaxMs: toMs(s.max),
+	}
+}
+
+type hidStatsKey struct {
+	messageType string
+	channel     string
+}
+
+type hidMessageStats struct {
+	latency   latencyStats
+	queueWait latencyStats
+}
+
+var (
+	hidStatsLock         sync.Mutex
+	hidStats             = make(map[hidStatsKey]*hidMessageStats)
+	hidDropped           = make(map[string]uint64)
+	hidKeepAliveJitter   latencyStats
+	hidKeepAliveRejected uint64
+)
+
+func getHidMessageStats(messageType, channel string) *hidMessageStats {
+	key := hidStatsKey{messageType, channel}
+	stats, ok := hidStats[key]
+	if !ok {
+		stats = &hidMessageStats{}
+		hidStats[key] = stats
+	}
+	return stats
+}
+
+func observeHidQueueWait(messageType, channel string, d time.Duration) {
+	metricHidQueueWait.WithLabelValues(messageType, channel).Observe(d.Seconds())
+
+	hidStatsLock.Lock()
+	defer hidStatsLock.Unlock()
+	getHidMessageStats(messageType, channel).queueWait.observe(d)
+}
+
+func observeHidMessageLatency(messageType, channel string, d time.Duration) {
+	metricHidMessageLatency.WithLabelValues(messageType, channel).Observe(d.Seconds())
+
+	hidStatsLock.Lock()
+	defer hidStatsLock.Unlock()
+	getHidMessageStats(messageType, channel).latency.observe(d)
+}
+
+func setHidQueueDepth(queue int, depth int) {
+	metricHidQueueDepth.WithLabelValues(strconv.Itoa(queue)).Set(float64(depth))
+}
+
+func recordHidMessageDropped(reason string) {
+	metricHidMessageDropCount.WithLabelValues(reason).Inc()
+
+	hidStatsLock.Lock()
+	defer hidStatsLock.Unlock()
+	hidDropped[reason]++
+}
+
+func observeKeepAliveJitter(lateness time.Duration, rejected bool) {
+	jitter := lateness.Abs()
+	metricKeepAliveJitter.Observe(jitter.Seconds())
+	if rejected {
+		metricKeepAliveRejectCount.Inc()
+	}
+
+	hidStatsLock.Lock()
+	defer hidStatsLock.Unlock()
+	hidKeepAliveJitter.observe(jitter)
+	if rejected {
+		hidKeepAliveRejected++
+	}
+}
+
+type HidMessageDiagnostics struct {
+	Type      string            `json:"type"`
+	Channel   string            `json:"channel"`
+	Latency   HidLatencySummary `json:"latency"`
+	QueueWait HidLatencySummary `json:"queueWait"`
+}
+
+type HidQueueDiagnostics struct {
+	Index    int `json:"index"`
+	Depth    int `json:"depth"`
+	Capacity int `json:"capacity"`
+}
+
+type HidDiagnostics struct {
+	Messages          []HidMessageDiagnostics            `json:"messages"`
+	Queues            []HidQueueDiagnostics              `json:"queues"`
+	Dropped           map[string]uint64                  `json:"dropped"`
+	KeepAliveJitter   HidLatencySummary                  `json:"keepAliveJitter"`
+	KeepAliveRejected uint64                             `json:"keepAliveRejected"`
+	Writes            map[string]usbgadget.HidWriteStats `json:"writes"`
+}
+
+func rpcGetHidDiagnostics() (HidDiagnostics, error) {
+	diagnostics := HidDiagnostics{
+		Messages: []HidMessageDiagnostics{},
+		Queues:   []HidQueueDiagnostics{},
+		Dropped:  make(map[string]uint64),
+		Writes:   usbgadget.GetHidWriteStats(),
+	}
+
+	hidStatsLock.Lock()
+	for key, stats := range hidStats {
+		diagnostics.Messages = append(diagnostics.Messages, HidMessageDiagnostics{
+			Type:      key.messageType,
+			Channel:   key.channel,
+			Latency:   stats.latency.summary(),
+			QueueWait: stats.queueWait.summary(),
+		})
+	}
+	for reason, n := range hidDropped {
+		diagnostics.Dropped[reason] = n
+	}
+	diagnostics.KeepAliveJitter = hidKeepAliveJitter.summary()
+	diagnostics.KeepAliveRejected = hidKeepAliveRejected
+	hidStatsLock.Unlock()
+
+	slices.SortFunc(diagnostics.Messages, func(a, b HidMessageDiagnostics) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.Channel, b.Channel))
+	})
+
+	if session := currentSession; session != nil {
+		session.hidQueueLock.Lock()
+		for i, q := range session.hidQueue {
+			diagnostics.Queues = append(diagnostics.Queues, HidQueueDiagnostics{Index: i, Depth: len(q), Capacity: cap(q)})
+		}
+		session.hidQueueLock.Unlock()
+	}
+	return diagnostics, nil
+}
@@ -0,0 +1,406 @@
+package kvm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jetkvm/kvm/internal/hidrecord"
+	"github.com/jetkvm/kvm/internal/hidrpc"
+)
+
+const (
+	hidRecordingsFolder = "/userdata/jetkvm/hid_recordings"
+	hidRecordingExt     = ".jsonl"
+	// the events of the recording in progress, they're moved behind the header when it stops
+	hidRecordingEventsExt = ".events"
+)
+
+var errHidRecordingNotFound = errors.New("HID recording does not exist")
+
+var (
+	hidRecorderLock   sync.Mutex
+	hidRecorder       *hidrecord.Recorder
+	hidRecorderEvents *os.File
+)
+
+type HidRecording struct {
+	ID string `json:"id"`
+	hidrecord.Header
+}
+
+type HidRecordingState struct {
+	Recording bool   `json:"recording"`
+	Name      string `json:"name,omitempty"`
+	Events    int    `json:"events"`
+}
+
+func activeHidRecorder() *hidrecord.Recorder {
+	hidRecorderLock.Lock()
+	defer hidRecorderLock.Unlock()
+	return hidRecorder
+}
+
+// recordHidEvent adds the input of the user to the active recording, if there is one.
+func recordHidEvent(event hidrecord.Event) {
+	if recorder := activeHidRecorder(); recorder != nil {
+		recorder.Record(event)
+	}
+}
+
+func recordHidRPCMessage(message hidrpc.Message) {
+	if activeHidRecorder() == nil {
+		return
+	}
+
+	switch message.Type() {
+	case hidrpc.TypeKeyboardReport:
+		if report, err := message.KeyboardReport(); err == nil {
+			recordHidEvent(hidrecord.Event{
+				Type:     hidrecord.EventKeyboard,
+				Modifier: report.Modifier,
+				Keys:     hidrecord.KeysFromBytes(report.Keys),
+			})
+		}
+	case hidrpc.TypeKeypressReport:
+		if report, err := message.KeypressReport(); err == nil {
+			recordHidEvent(hidrecord.Event{Type: hidrecord.EventKeypress, Key: report.Key, Press: report.Press})
+		}
+	case hidrpc.TypeKeypressKeepAliveReport:
+		recordHidEvent(hidrecord.Event{Type: hidrecord.EventKeepAlive})
+	case hidrpc.TypePointerReport:
+		if report, err := message.PointerReport(); err == nil {
+			recordHidEvent(hidrecord.Event{Type: hidrecord.EventAbsMouse, X: report.X, Y: report.Y, Buttons: report.Button})
+		}
+	case hidrpc.TypeMouseReport:
+		if report, err := message.MouseReport(); err == nil {
+			recordHidEvent(hidrecord.Event{
+				Type:    hidrecord.EventRelMouse,
+				X:       int(report.DX),
+				Y:       int(report.DY),
+				Buttons: report.Button,
+			})
+		}
+	case hidrpc.TypeWheelReport:
+		if report, err := message.WheelReport(); err == nil {
+			recordHidEvent(hidrecord.Event{Type: hidrecord.EventWheel, Y: int(report.WheelY)})
+		}
+	case hidrpc.TypeKeyboardMacroReport:
+		if report, err := message.KeyboardMacroReport(); err == nil {
+			recordHidEvent(hidrecord.MacroEvent(report.Steps))
+		}
+	}
+}
+
+var jsonRPCHidEvents = map[string]hidrecord.EventType{
+	"keyboardReport": hidrecord.EventKeyboard,
+	"keypressReport": hidrecord.EventKeypress,
+	"absMouseReport": hidrecord.EventAbsMouse,
+	"relMouseReport": hidrecord.EventRelMouse,
+	"wheelReport":    hidrecord.EventWheel,
+}
+
+// recordJSONRPCHidEvent records the HID input of clients that don't use HID RPC.
+func recordJSONRPCHidEvent(method string, params map[string]any) {
+	eventType, ok := jsonRPCHidEvents[method]
+	if !ok || activeHidRecorder() == nil {
+		return
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+	var p struct {
+		Modifier byte  `json:"modifier"`
+		Keys     []int `json:"keys"`
+		Key      byte  `json:"key"`
+		Press    bool  `json:"press"`
+		X        int   `json:"x"`
+		Y        int   `json:"y"`
+		Dx       int   `json:"dx"`
+		Dy       int   `json:"dy"`
+		WheelY   int   `json:"wheelY"`
+		Buttons  uint8 `json:"buttons"`
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return
+	}
+
+	event := hidrecord.Event{Type: eventType, Buttons: p.Buttons}
+	switch eventType {
+	case hidrecord.EventKeyboard:
+		event.Modifier, event.Keys = p.Modifier, p.Keys
+	case hidrecord.EventKeypress:
+		event.Key, event.Press = p.Key, p.Press
+	case hidrecord.EventAbsMouse:
+		event.X, event.Y = p.X, p.Y
+	case hidrecord.EventRelMouse:
+		event.X, event.Y = p.Dx, p.Dy
+	case hidrecord.EventWheel:
+		event.Y = p.WheelY
+	}
+	recordHidEvent(event)
+}
+
+func reportHidRecordingState() {
+	state, _ := rpcGetHidRecordingState()
+	go func() {
+		if currentSession == nil {
+			return
+		}
+		writeJSONRPCEvent("hidRecordingState", state, currentSession)
+	}()
+}
+
+func getHidRecordingPath(id string) (string, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return "", fmt.Errorf("invalid HID recording ID: %s", id)
+	}
+	fullPath := filepath.Join(hidRecordingsFolder, id+hidRecordingExt)
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s", errHidRecordingNotFound, id)
+	}
+	return fullPath, nil
+}
+
+// createHidRecordingEvents creates the file the events are written to while recording,
+// the files of recordings that were interrupted by a restart are removed.
+func createHidRecordingEvents() (*os.File, error) {
+	if err := os.MkdirAll(hidRecordingsFolder, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create recordings folder: %w", err)
+	}
+
+	stale, _ := filepath.Glob(filepath.Join(hidRecordingsFolder, "*"+hidRecordingEventsExt))
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil {
+			logger.Warn().Err(err).Str("path", path).Msg("failed to remove interrupted HID recording")
+		}
+	}
+
+	f, err := os.Create(filepath.Join(hidRecordingsFolder, uuid.New().String()+hidRecordingEventsExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+	return f, nil
+}
+
+// saveHidRecording writes the header followed by the recorded events and removes the events file.
+func saveHidRecording(header hidrecord.Header, events *os.File) (*HidRecording, error) {
+	defer os.Remove(events.Name())
+	defer events.Close()
+
+	if _, err := events.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read recorded events: %w", err)
+	}
+
+	id := strings.TrimSuffix(filepath.Base(events.Name()), hidRecordingEventsExt)
+	f, err := os.Create(filepath.Join(hidRecordingsFolder, id+hidRecordingExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+	defer f.Close()
+
+	if err := hidrecord.Write(f, header, events); err != nil {
+		return nil, fmt.Errorf("failed to write recording: %w", err)
+	}
+	return &HidRecording{ID: id, Header: header}, f.Sync()
+}
+
+func rpcStartHidRecording(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("recording name cannot be empty")
+	}
+
+	hidRecorderLock.Lock()
+	defer hidRecorderLock.Unlock()
+	if hidRecorder != nil {
+		return errors.New("a recording is already in progress")
+	}
+	events, err := createHidRecordingEvents()
+	if err != nil {
+		return err
+	}
+	hidRecorder = hidrecord.NewRecorder(name, events)
+	hidRecorderEvents = events
+
+	logger.Info().Str("name", name).Msg("started HID recording")
+	go reportHidRecordingState()
+	return nil
+}
+
+// rpcStopHidRecording stops the recording and saves it.
+func rpcStopHidRecording() (*HidRecording, error) {
+	hidRecorderLock.Lock()
+	recorder, events := hidRecorder, hidRecorderEvents
+	hidRecorder, hidRecorderEvents = nil, nil
+	hidRecorderLock.Unlock()
+
+	if recorder == nil {
+		return nil, errors.New("no recording in progress")
+	}
+	defer reportHidRecordingState()
+
+	header, err := recorder.Stop()
+	if err != nil {
+		logger.Warn().Err(err).Msg("HID recording is incomplete")
+	}
+	saved, err := saveHidRecording(header, events)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info().Str("id", saved.ID).Int("events", saved.Events).Msg("saved HID recording")
+	return saved, nil
+}
+
+func rpcGetHidRecordingState() (HidRecordingState, error) {
+	hidRecorderLock.Lock()
+	defer hidRecorderLock.Unlock()
+
+	if hidRecorder == nil {
+		return HidRecordingState{}, nil
+	}
+	return HidRecordingState{Recording: true, Name: hidRecorder.Name(), Events: hidRecorder.Events()}, nil
+}
+
+func rpcGetHidRecordings() ([]HidRecording, error) {
+	files, err := os.ReadDir(hidRecordingsFolder)
+	if errors.Is(err, os.ErrNotExist) {
+		return []HidRecording{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %v", err)
+	}
+
+	recordings := make([]HidRecording, 0)
+	for _, file := range files {
+		id, ok := strings.CutSuffix(file.Name(), hidRecordingExt)
+		if file.IsDir() || !ok {
+			continue
+		}
+
+		f, err := os.Open(filepath.Join(hidRecordingsFolder, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		header, err := hidrecord.ReadHeader(f)
+		f.Close()
+		if err != nil {
+			logger.Warn().Err(err).Str("id", id).Msg("skipping invalid HID recording")
+			continue
+		}
+		recordings = append(recordings, HidRecording{ID: id, Header: *header})
+	}
+
+	slices.SortFunc(recordings, func(a, b HidRecording) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return recordings, nil
+}
+
+func rpcDeleteHidRecording(id string) error {
+	fullPath, err := getHidRecordingPath(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil {
+		return fmt.Errorf("failed to delete HID recording: %v", err)
+	}
+	return nil
+}
+
+// hidReplayDevice replays recordings through the same paths as live input,
+// it tracks the mouse buttons so they can be released when the replay ends.
+type hidReplayDevice struct {
+	pointer macroPointer
+}
+
+func (d *hidReplayDevice) KeyboardReport(modifier byte, keys []byte) error {
+	return rpcKeyboardReport(modifier, keys)
+}
+
+func (d *hidReplayDevice) KeypressReport(key byte, press bool) error {
+	return rpcKeypressReport(key, press)
+}
+
+func (d *hidReplayDevice) KeypressKeepAlive() error {
+	gadget.DelayAutoReleaseWithDuration(baseExtension)
+	return nil
+}
+
+func (d *hidReplayDevice) AbsMouseReport(x, y int, buttons uint8) error {
+	d.pointer = macroPointer{x: x, y: y, absolute: true, buttons: buttons}
+	return rpcAbsMouseReport(x, y, buttons)
+}
+
+func (d *hidReplayDevice) RelMouseReport(dx, dy int8, buttons uint8) error {
+	d.pointer = macroPointer{buttons: buttons}
+	return rpcRelMouseReport(dx, dy, buttons)
+}
+
+func (d *hidReplayDevice) WheelReport(wheelY int8) error {
+	return rpcWheelReport(wheelY)
+}
+
+func (d *hidReplayDevice) Macro(ctx context.Context, steps []hidrpc.KeyboardMacroStep) error {
+	return rpcDoExecuteKeyboardMacro(ctx, steps)
+}
+
+// reset releases the keys and buttons that are still held
+func (d *hidReplayDevice) reset() {
+	if err := rpcKeyboardReport(0, keyboardClearStateKeys); err != nil {
+		logger.Warn().Err(err).Msg("failed to reset keyboard state")
+	}
+	gadget.UpdateKeysDown(0, keyboardClearStateKeys)
+	if err := d.pointer.release(); err != nil {
+		logger.Warn().Err(err).Msg("failed to release mouse buttons")
+	}
+}
+
+// rpcReplayHidRecording replays the recording in the background, speed scales the timing (2 is twice as fast).
+// It can be cancelled like a keyboard macro.
+func rpcReplayHidRecording(id string, speed float64) error {
+	fullPath, err := getHidRecordingPath(id)
+	if err != nil {
+		return err
+	}
+	if speed == 0 {
+		speed = 1
+	}
+	if speed < hidrecord.MinSpeed || speed > hidrecord.MaxSpeed {
+		return fmt.Errorf("speed must be between %g and %g", hidrecord.MinSpeed, hidrecord.MaxSpeed)
+	}
+
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return err
+	}
+	rec, err := hidrecord.Read(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		logger.Info().Str("id", id).Float64("speed", speed).Msg("replaying HID recording")
+		err := runCancellableKeyboardInput(func(ctx context.Context) error {
+			dev := &hidReplayDevice{}
+			defer dev.reset()
+			return hidrecord.Replay(ctx, rec, speed, dev)
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logger.Warn().Err(err).Str("id", id).Msg("failed to replay HID recording")
+		}
+	}()
+	return nil
+}
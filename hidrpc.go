@@ -14,6 +14,8 @@ import (
 func handleHidRPCMessage(message hidrpc.Message, session *Session) {
 	var rpcErr error
 
+	recordHidRPCMessage(message)
+
 	switch message.Type() {
 	case hidrpc.TypeHandshake:
 		message, err := hidrpc.NewHandshakeMessage().Marshal()
@@ -34,10 +36,17 @@ func handleHidRPCMessage(message hidrpc.Message, session *Session) {
 			logger.Warn().Err(err).Msg("failed to get keyboard macro report")
 			return
 		}
-		rpcErr = rpcExecuteKeyboardMacro(keyboardMacroReport.Steps)
+		rpcErr = rpcExecuteKeyboardMacro(keyboardMacroReport.Steps, keyboardMacroReport.SyncLockKeys)
 	case hidrpc.TypeCancelKeyboardMacroReport:
 		rpcCancelKeyboardMacro()
 		return
+	case hidrpc.TypeRunKeyboardScriptReport:
+		id, err := message.RunKeyboardScriptReport()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to get run keyboard script report")
+			return
+		}
+		rpcErr = rpcRunKeyboardScript(id)
 	case hidrpc.TypeKeypressKeepAliveReport:
 		rpcErr = handleHidRPCKeypressKeepAlive(session)
 	case hidrpc.TypePointerReport:
@@ -54,6 +63,13 @@ func handleHidRPCMessage(message hidrpc.Message, session *Session) {
 			return
 		}
 		rpcErr = rpcRelMouseReport(mouseReport.DX, mouseReport.DY, mouseReport.Button)
+	case hidrpc.TypeWheelReport:
+		wheelReport, err := message.WheelReport()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to get wheel report")
+			return
+		}
+		rpcErr = rpcWheelReport(wheelReport.WheelY)
 	default:
 		logger.Warn().Uint8("type", uint8(message.Type())).Msg("unknown HID RPC message type")
 	}
@@ -89,6 +105,8 @@ func onHidMessage(msg hidQueueMessage, session *Session) {
 	}
 
 	t := time.Now()
+	messageType := message.Type().String()
+	observeHidQueueWait(messageType, msg.channel, t.Sub(msg.received))
 
 	r := make(chan interface{})
 	go func() {
@@ -97,12 +115,23 @@ func onHidMessage(msg hidQueueMessage, session *Session) {
 	}()
 	select {
 	case <-time.After(1 * time.Second):
+		// macros and scripts take as long as they take, they keep running and aren't dropped
+		if isLongRunningHidMessage(message.Type()) {
+			scopedLogger.Debug().Msg("HID RPC message is still running")
+			return
+		}
 		scopedLogger.Warn().Msg("HID RPC message timed out")
+		recordHidMessageDropped("timeout")
 	case <-r:
 		scopedLogger.Debug().Dur("duration", time.Since(t)).Msg("HID RPC message handled")
+		observeHidMessageLatency(messageType, msg.channel, time.Since(msg.received))
 	}
 }
 
+func isLongRunningHidMessage(t hidrpc.MessageType) bool {
+	return t == hidrpc.TypeKeyboardMacroReport || t == hidrpc.TypeRunKeyboardScriptReport
+}
+
 // Tunables
 // Keep in mind
 // macOS default: 15 * 15 = 225ms https://discussions.apple.com/thread/1316947?sortBy=rank
@@ -150,6 +179,7 @@ func handleHidRPCKeypressKeepAlive(session *Session) error {
 				validTick = false
 			}
 		}
+		observeKeepAliveJitter(lateness, !validTick)
 	}
 
 	if !validTick {
@@ -174,14 +204,14 @@ func handleHidRPCKeyboardInput(message hidrpc.Message) error {
 			logger.Warn().Err(err).Msg("failed to get keypress report")
 			return err
 		}
-		return rpcKeypressReport(keypressReport.Key, keypressReport.Press)
+		return rpcRemappedKeypressReport(keypressReport.Key, keypressReport.Press)
 	case hidrpc.TypeKeyboardReport:
 		keyboardReport, err := message.KeyboardReport()
 		if err != nil {
 			logger.Warn().Err(err).Msg("failed to get keyboard report")
 			return err
 		}
-		return rpcKeyboardReport(keyboardReport.Modifier, keyboardReport.Keys)
+		return rpcRemappedKeyboardReport(keyboardReport.Modifier, keyboardReport.Keys)
 	}
 
 	return fmt.Errorf("unknown HID RPC message type: %d", message.Type())
@@ -257,3 +287,15 @@ func (s *Session) reportHidRPCKeyboardMacroState(state hidrpc.KeyboardMacroState
 	}
 	reportHidRPC(state, s)
 }
+
+func rpcGetHidCoalescing() (hidrpc.CoalesceOptions, error) {
+	return config.HidCoalescing, nil
+}
+
+func rpcSetHidCoalescing(options hidrpc.CoalesceOptions) error {
+	config.HidCoalescing = options
+	if err := SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package activehours checks whether a time falls into weekly windows like "Monday 09:00 to 17:30".
+package activehours
+
+import (
+	"fmt"
+	"time"
+)
+
+// endOfDay is accepted as the end of a window, "23:59" would leave out the last minute.
+const endOfDay = "24:00"
+
+// Window is a time range on a weekday, in the timezone passed to Active.
+type Window struct {
+	Weekday time.Weekday `json:"weekday"` // 0 is Sunday
+	Start   string       `json:"start"`   // "09:00"
+	End     string       `json:"end"`     // "17:30", "24:00" is the end of the day
+}
+
+// ParseTimeOfDay returns the minutes since midnight of "HH:MM".
+func ParseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err == nil {
+		return t.Hour()*60 + t.Minute(), nil
+	}
+	if s == endOfDay {
+		return 24 * 60, nil
+	}
+	return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+}
+
+// Validate checks the weekday and that the window starts before it ends, windows don't wrap past midnight.
+func (w Window) Validate() error {
+	if w.Weekday < time.Sunday || w.Weekday > time.Saturday {
+		return fmt.Errorf("invalid weekday %d", w.Weekday)
+	}
+	start, err := ParseTimeOfDay(w.Start)
+	if err != nil {
+		return err
+	}
+	end, err := ParseTimeOfDay(w.End)
+	if err != nil {
+		return err
+	}
+	if start >= end {
+		return fmt.Errorf("active hours on %s must start before they end", w.Weekday)
+	}
+	return nil
+}
+
+// Contains returns true if t is in the window, the start is inclusive and the end exclusive.
+// t is used in its own location.
+func (w Window) Contains(t time.Time) bool {
+	if t.Weekday() != w.Weekday {
+		return false
+	}
+	start, err := ParseTimeOfDay(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := ParseTimeOfDay(w.End)
+	if err != nil {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	return minute >= start && minute < end
+}
+
+// Active returns true if there are no windows or now falls into one of them in loc.
+func Active(windows []Window, now time.Time, loc *time.Location) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	now = now.In(loc)
+	for _, w := range windows {
+		if w.Contains(now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package activehours
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		in      string
+		minutes int
+		wantErr bool
+	}{
+		{in: "00:00", minutes: 0},
+		{in: "09:30", minutes: 9*60 + 30},
+		{in: "23:59", minutes: 23*60 + 59},
+		{in: "24:00", minutes: 24 * 60},
+		{in: "24:01", wantErr: true},
+		{in: "25:00", wantErr: true},
+		{in: "9:30", minutes: 9*60 + 30},
+		{in: "", wantErr: true},
+		{in: "9am", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			minutes, err := ParseTimeOfDay(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.minutes, minutes)
+		})
+	}
+}
+
+func TestWindowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  Window
+		wantErr bool
+	}{
+		{name: "office hours", window: Window{Weekday: time.Monday, Start: "09:00", End: "17:30"}},
+		{name: "whole day", window: Window{Weekday: time.Sunday, Start: "00:00", End: "24:00"}},
+		{name: "wraps past midnight", window: Window{Weekday: time.Friday, Start: "22:00", End: "02:00"}, wantErr: true},
+		{name: "empty", window: Window{Weekday: time.Friday, Start: "10:00", End: "10:00"}, wantErr: true},
+		{name: "invalid weekday", window: Window{Weekday: 7, Start: "09:00", End: "17:00"}, wantErr: true},
+		{name: "invalid time", window: Window{Weekday: time.Monday, Start: "9am", End: "17:00"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWindowContains(t *testing.T) {
+	monday := Window{Weekday: time.Monday, Start: "09:00", End: "17:30"}
+	sunday := Window{Weekday: time.Sunday, Start: "20:00", End: "24:00"}
+
+	// 2024-01-01 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		window Window
+		t      time.Time
+		want   bool
+	}{
+		{name: "start is inclusive", window: monday, t: at(1, 9, 0), want: true},
+		{name: "inside", window: monday, t: at(1, 12, 15), want: true},
+		{name: "end is exclusive", window: monday, t: at(1, 17, 30)},
+		{name: "before", window: monday, t: at(1, 8, 59)},
+		{name: "other weekday", window: monday, t: at(2, 12, 0)},
+		{name: "last minute of the day", window: sunday, t: at(7, 23, 59), want: true},
+		{name: "midnight belongs to the next day", window: sunday, t: at(8, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.window.Contains(tt.t))
+		})
+	}
+}
+
+func TestActive(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	windows := []Window{{Weekday: time.Monday, Start: "09:00", End: "17:00"}}
+
+	tests := []struct {
+		name    string
+		windows []Window
+		now     time.Time
+		loc     *time.Location
+		want    bool
+	}{
+		{name: "no windows", now: time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC), loc: time.UTC, want: true},
+		{name: "in utc", windows: windows, now: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), loc: time.UTC, want: true},
+		// 08:30 UTC is 09:30 in Berlin in winter
+		{name: "in the timezone", windows: windows, now: time.Date(2024, 1, 1, 8, 30, 0, 0, time.UTC), loc: berlin, want: true},
+		{name: "outside in the timezone", windows: windows, now: time.Date(2024, 1, 1, 16, 30, 0, 0, time.UTC), loc: berlin},
+		// 23:30 UTC on Sunday is already Monday in Berlin
+		{name: "weekday in the timezone", windows: []Window{{Weekday: time.Monday, Start: "00:00", End: "01:00"}}, now: time.Date(2023, 12, 31, 23, 30, 0, 0, time.UTC), loc: berlin, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Active(tt.windows, tt.now, tt.loc))
+		})
+	}
+}
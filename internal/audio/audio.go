@@ -0,0 +1,8 @@
+// Package audio streams PCM audio from and to ALSA devices and encodes it with Opus.
+package audio
+
+import "errors"
+
+// ErrOpusUnavailable is returned when the binary is built without cgo, libopus is built
+// statically along with the native library and only linked with cgo.
+var ErrOpusUnavailable = errors.New("opus: built without libopus")
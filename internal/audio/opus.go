@@ -0,0 +1,102 @@
+//go:build cgo
+
+package audio
+
+/*
+#cgo CFLAGS: -I${SRCDIR}/../native/cgo/include
+#cgo LDFLAGS: -L${SRCDIR}/../native/cgo/lib -lopus -lm
+#include <opus/opus.h>
+
+// the ctl calls are variadic macros, cgo can't call them directly
+static int jetkvm_opus_set_bitrate(OpusEncoder *enc, opus_int32 bitrate) {
+	return opus_encoder_ctl(enc, OPUS_SET_BITRATE(bitrate));
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Encoder encodes interleaved 16 bit samples to Opus packets.
+type Encoder struct {
+	enc      *C.OpusEncoder
+	channels int
+}
+
+// NewEncoder creates an Opus encoder tuned for music and other general audio.
+func NewEncoder(sampleRate, channels, bitrate int) (*Encoder, error) {
+	var errCode C.int
+	enc := C.opus_encoder_create(C.opus_int32(sampleRate), C.int(channels), C.OPUS_APPLICATION_AUDIO, &errCode)
+	if errCode != C.OPUS_OK {
+		return nil, opusError("failed to create encoder", errCode)
+	}
+	if errCode = C.jetkvm_opus_set_bitrate(enc, C.opus_int32(bitrate)); errCode != C.OPUS_OK {
+		C.opus_encoder_destroy(enc)
+		return nil, opusError("failed to set bitrate", errCode)
+	}
+	return &Encoder{enc: enc, channels: channels}, nil
+}
+
+// Encode encodes one frame of pcm into data and returns the length of the packet.
+func (e *Encoder) Encode(pcm []int16, data []byte) (int, error) {
+	n := C.opus_encode(
+		e.enc,
+		(*C.opus_int16)(unsafe.Pointer(&pcm[0])),
+		C.int(len(pcm)/e.channels),
+		(*C.uchar)(unsafe.Pointer(&data[0])),
+		C.opus_int32(len(data)),
+	)
+	if n < 0 {
+		return 0, opusError("failed to encode", C.int(n))
+	}
+	return int(n), nil
+}
+
+func (e *Encoder) Close() {
+	C.opus_encoder_destroy(e.enc)
+}
+
+// Decoder decodes Opus packets to interleaved 16 bit samples.
+type Decoder struct {
+	dec      *C.OpusDecoder
+	channels int
+}
+
+func NewDecoder(sampleRate, channels int) (*Decoder, error) {
+	var errCode C.int
+	dec := C.opus_decoder_create(C.opus_int32(sampleRate), C.int(channels), &errCode)
+	if errCode != C.OPUS_OK {
+		return nil, opusError("failed to create decoder", errCode)
+	}
+	return &Decoder{dec: dec, channels: channels}, nil
+}
+
+// Decode decodes a packet into pcm and returns the number of frames.
+func (d *Decoder) Decode(data []byte, pcm []int16) (int, error) {
+	var packet *C.uchar
+	if len(data) > 0 {
+		packet = (*C.uchar)(unsafe.Pointer(&data[0]))
+	}
+	n := C.opus_decode(
+		d.dec,
+		packet,
+		C.opus_int32(len(data)),
+		(*C.opus_int16)(unsafe.Pointer(&pcm[0])),
+		C.int(len(pcm)/d.channels),
+		0,
+	)
+	if n < 0 {
+		return 0, opusError("failed to decode", n)
+	}
+	return int(n), nil
+}
+
+func (d *Decoder) Close() {
+	C.opus_decoder_destroy(d.dec)
+}
+
+func opusError(msg string, code C.int) error {
+	return fmt.Errorf("opus: %s: %s", msg, C.GoString(C.opus_strerror(code)))
+}
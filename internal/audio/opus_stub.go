@@ -0,0 +1,27 @@
+//go:build !cgo
+
+package audio
+
+type Encoder struct{}
+
+func NewEncoder(sampleRate, channels, bitrate int) (*Encoder, error) {
+	return nil, ErrOpusUnavailable
+}
+
+func (e *Encoder) Encode(pcm []int16, data []byte) (int, error) {
+	return 0, ErrOpusUnavailable
+}
+
+func (e *Encoder) Close() {}
+
+type Decoder struct{}
+
+func NewDecoder(sampleRate, channels int) (*Decoder, error) {
+	return nil, ErrOpusUnavailable
+}
+
+func (d *Decoder) Decode(data []byte, pcm []int16) (int, error) {
+	return 0, ErrOpusUnavailable
+}
+
+func (d *Decoder) Close() {}
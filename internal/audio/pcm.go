@@ -0,0 +1,260 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// the subset of the ALSA PCM ioctl interface (sound/asound.h) that is needed to
+// read and write interleaved 16 bit samples, alsa-lib isn't available on the device
+
+const (
+	sndPCMAccessRWInterleaved = 3
+	sndPCMFormatS16LE         = 2
+	sndPCMSubformatStd        = 0
+
+	sndPCMHwParamAccess    = 0
+	sndPCMHwParamFormat    = 1
+	sndPCMHwParamSubformat = 2
+
+	sndPCMHwParamFirstInterval = 8
+	sndPCMHwParamChannels      = 10
+	sndPCMHwParamRate          = 11
+	sndPCMHwParamPeriodSize    = 13
+	sndPCMHwParamPeriods       = 15
+
+	sndIntervalInteger = 1 << 2
+
+	iocWrite = 1
+	iocRead  = 2
+)
+
+type sndMask struct {
+	bits [8]uint32
+}
+
+type sndInterval struct {
+	min, max, flags uint32
+}
+
+type sndPCMHwParams struct {
+	flags     uint32
+	masks     [3]sndMask
+	mres      [5]sndMask
+	intervals [12]sndInterval
+	ires      [9]sndInterval
+	rmask     uint32
+	cmask     uint32
+	info      uint32
+	msbits    uint32
+	rateNum   uint32
+	rateDen   uint32
+	fifoSize  uint // snd_pcm_uframes_t
+	reserved  [64]byte
+}
+
+type sndXferi struct {
+	result int // snd_pcm_sframes_t
+	buf    unsafe.Pointer
+	frames uint // snd_pcm_uframes_t
+}
+
+func ioc(dir, nr, size uintptr) uintptr {
+	return dir<<30 | size<<16 | 'A'<<8 | nr
+}
+
+var (
+	ioctlHwParams    = ioc(iocRead|iocWrite, 0x11, unsafe.Sizeof(sndPCMHwParams{}))
+	ioctlPrepare     = ioc(0, 0x40, 0)
+	ioctlStart       = ioc(0, 0x42, 0)
+	ioctlDrop        = ioc(0, 0x43, 0)
+	ioctlWriteFrames = ioc(iocWrite, 0x50, unsafe.Sizeof(sndXferi{}))
+	ioctlReadFrames  = ioc(iocRead, 0x51, unsafe.Sizeof(sndXferi{}))
+)
+
+// pollTimeout bounds how long Read and Write wait, so the caller can check whether it should stop.
+const pollTimeout = 200 * time.Millisecond
+
+// Params are the hardware parameters of a PCM stream, samples are always signed 16 bit little endian.
+type Params struct {
+	Rate         int
+	Channels     int
+	PeriodFrames int
+	Periods      int
+}
+
+// PCM is an open ALSA PCM device. It's not safe for concurrent use.
+type PCM struct {
+	fd      int
+	path    string
+	capture bool
+	params  Params
+}
+
+// OpenPCM opens the capture or playback stream of an ALSA PCM device and configures it.
+func OpenPCM(card, device int, capture bool, params Params) (*PCM, error) {
+	direction := "p"
+	if capture {
+		direction = "c"
+	}
+	path := fmt.Sprintf("/dev/snd/pcmC%dD%d%s", card, device, direction)
+
+	// the device is used in non-blocking mode so reads and writes can time out
+	fd, err := unix.Open(path, unix.O_RDWR|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	p := &PCM{fd: fd, path: path, capture: capture, params: params}
+	if err := p.setHwParams(); err != nil {
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("failed to configure %s: %w", path, err)
+	}
+	if err := p.prepare(); err != nil {
+		_ = unix.Close(fd)
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *PCM) ioctl(req uintptr, arg unsafe.Pointer) error {
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(p.fd), req, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func (p *PCM) setHwParams() error {
+	var hw sndPCMHwParams
+	for i := range hw.masks {
+		for j := range hw.masks[i].bits {
+			hw.masks[i].bits[j] = ^uint32(0)
+		}
+	}
+	for i := range hw.intervals {
+		hw.intervals[i].max = ^uint32(0)
+	}
+	hw.rmask = ^uint32(0)
+	hw.info = ^uint32(0)
+
+	setMask := func(param, bit int) {
+		hw.masks[param].bits = [8]uint32{}
+		hw.masks[param].bits[bit/32] = 1 << (bit % 32)
+	}
+	setInterval := func(param, value int) {
+		i := &hw.intervals[param-sndPCMHwParamFirstInterval]
+		i.min = uint32(value)
+		i.max = uint32(value)
+		i.flags = sndIntervalInteger
+	}
+
+	setMask(sndPCMHwParamAccess, sndPCMAccessRWInterleaved)
+	setMask(sndPCMHwParamFormat, sndPCMFormatS16LE)
+	setMask(sndPCMHwParamSubformat, sndPCMSubformatStd)
+	setInterval(sndPCMHwParamChannels, p.params.Channels)
+	setInterval(sndPCMHwParamRate, p.params.Rate)
+	setInterval(sndPCMHwParamPeriodSize, p.params.PeriodFrames)
+	setInterval(sndPCMHwParamPeriods, p.params.Periods)
+
+	return p.ioctl(ioctlHwParams, unsafe.Pointer(&hw))
+}
+
+// prepare (re)starts the stream, capture streams have to be started explicitly
+// because there's nothing to poll for otherwise.
+func (p *PCM) prepare() error {
+	if err := p.ioctl(ioctlPrepare, nil); err != nil {
+		return fmt.Errorf("failed to prepare %s: %w", p.path, err)
+	}
+	if p.capture {
+		if err := p.ioctl(ioctlStart, nil); err != nil {
+			return fmt.Errorf("failed to start %s: %w", p.path, err)
+		}
+	}
+	return nil
+}
+
+func (p *PCM) wait() error {
+	events := int16(unix.POLLOUT)
+	if p.capture {
+		events = unix.POLLIN
+	}
+	fds := []unix.PollFd{{Fd: int32(p.fd), Events: events}}
+	n, err := unix.Poll(fds, int(pollTimeout/time.Millisecond))
+	if errors.Is(err, unix.EINTR) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return os.ErrDeadlineExceeded
+	}
+	// errors like xruns are reported and handled by the transfer
+	return nil
+}
+
+// transfer moves frames between buf and the device, recovering from over- and underruns.
+func (p *PCM) transfer(buf []int16) (int, error) {
+	frames := len(buf) / p.params.Channels
+	done := 0
+	for done < frames {
+		if err := p.wait(); err != nil {
+			return done, err
+		}
+
+		xfer := sndXferi{
+			buf:    unsafe.Pointer(&buf[done*p.params.Channels]),
+			frames: uint(frames - done),
+		}
+		req := ioctlWriteFrames
+		if p.capture {
+			req = ioctlReadFrames
+		}
+		err := p.ioctl(req, unsafe.Pointer(&xfer))
+		runtime.KeepAlive(buf)
+
+		switch {
+		case err == nil:
+			done += xfer.result
+		case errors.Is(err, unix.EAGAIN):
+		case errors.Is(err, unix.EPIPE), errors.Is(err, unix.ESTRPIPE):
+			// overrun or underrun, the samples in between are lost
+			if err := p.prepare(); err != nil {
+				return done, err
+			}
+		default:
+			return done, fmt.Errorf("failed to transfer frames on %s: %w", p.path, err)
+		}
+	}
+	return done, nil
+}
+
+// Read fills buf with interleaved samples and returns the number of frames read,
+// it returns os.ErrDeadlineExceeded if no samples arrive in time, e.g. because the host isn't playing anything.
+func (p *PCM) Read(buf []int16) (int, error) {
+	if !p.capture {
+		return 0, errors.New("not a capture stream")
+	}
+	return p.transfer(buf)
+}
+
+// Write plays the interleaved samples in buf and returns the number of frames written.
+func (p *PCM) Write(buf []int16) (int, error) {
+	if p.capture {
+		return 0, errors.New("not a playback stream")
+	}
+	return p.transfer(buf)
+}
+
+// Close stops the stream and closes the device.
+func (p *PCM) Close() error {
+	_ = p.ioctl(ioctlDrop, nil)
+	return unix.Close(p.fd)
+}
@@ -0,0 +1,25 @@
+package audio
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIoctlNumbers(t *testing.T) {
+	// the structs follow the kernel ABI, which depends on the size of a long
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		assert.Equal(t, uintptr(608), unsafe.Sizeof(sndPCMHwParams{}))
+		assert.Equal(t, uintptr(0xc2604111), ioctlHwParams)
+		assert.Equal(t, uintptr(0x80184151), ioctlReadFrames)
+		assert.Equal(t, uintptr(0x40184150), ioctlWriteFrames)
+	} else {
+		assert.Equal(t, uintptr(604), unsafe.Sizeof(sndPCMHwParams{}))
+		assert.Equal(t, uintptr(0xc25c4111), ioctlHwParams)
+		assert.Equal(t, uintptr(0x800c4151), ioctlReadFrames)
+		assert.Equal(t, uintptr(0x400c4150), ioctlWriteFrames)
+	}
+	assert.Equal(t, uintptr(0x4140), ioctlPrepare)
+	assert.Equal(t, uintptr(0x4143), ioctlDrop)
+}
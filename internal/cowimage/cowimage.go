@@ -0,0 +1,301 @@
+// Package cowimage implements a block level copy-on-write overlay on top of a disk image,
+// so the image can be written to while the base file stays untouched until the changes are committed.
+package cowimage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// DefaultBlockSize is the granularity the overlay tracks changes with.
+const DefaultBlockSize = 64 * 1024
+
+const mapHeaderSize = 16 // image size and block size, both uint64
+
+var ErrOutOfRange = errors.New("write beyond the end of the image")
+
+// Image is a disk image with a copy-on-write overlay.
+//
+// The overlay consists of two files: a sparse data file the size of the base image
+// and a map file recording which blocks of the data file hold changed data.
+type Image struct {
+	mu sync.RWMutex
+
+	basePath    string
+	overlayPath string
+	base        *os.File
+	overlay     *os.File
+
+	size      int64
+	blockSize int64
+	dirty     []byte // bitmap of the blocks that are stored in the overlay
+}
+
+func mapPath(overlayPath string) string {
+	return overlayPath + ".map"
+}
+
+// Open opens the base image read-only and the overlay at overlayPath, creating the overlay if needed.
+// An existing overlay is reused unless it was created for a base image of a different size.
+func Open(basePath string, overlayPath string, blockSize int64) (*Image, error) {
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+
+	base, err := os.Open(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open base image: %w", err)
+	}
+
+	info, err := base.Stat()
+	if err != nil {
+		_ = base.Close()
+		return nil, fmt.Errorf("failed to stat base image: %w", err)
+	}
+
+	overlay, err := os.OpenFile(overlayPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		_ = base.Close()
+		return nil, fmt.Errorf("failed to open overlay: %w", err)
+	}
+
+	img := &Image{
+		basePath:    basePath,
+		overlayPath: overlayPath,
+		base:        base,
+		overlay:     overlay,
+		size:        info.Size(),
+		blockSize:   blockSize,
+		dirty:       make([]byte, (blocks(info.Size(), blockSize)+7)/8),
+	}
+
+	if !img.loadMap() {
+		// the overlay doesn't belong to this image (anymore), start over
+		if err := img.reset(); err != nil {
+			_ = img.closeFiles()
+			return nil, err
+		}
+	}
+
+	return img, nil
+}
+
+// Remove deletes the overlay files at overlayPath.
+func Remove(overlayPath string) error {
+	for _, p := range []string{overlayPath, mapPath(overlayPath)} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func blocks(size int64, blockSize int64) int64 {
+	return (size + blockSize - 1) / blockSize
+}
+
+func (img *Image) isDirty(block int64) bool {
+	return img.dirty[block/8]&(1<<(block%8)) != 0
+}
+
+func (img *Image) setDirty(block int64) {
+	img.dirty[block/8] |= 1 << (block % 8)
+}
+
+func (img *Image) loadMap() bool {
+	data, err := os.ReadFile(mapPath(img.overlayPath))
+	if err != nil || len(data) != mapHeaderSize+len(img.dirty) {
+		return false
+	}
+	if int64(binary.LittleEndian.Uint64(data[0:8])) != img.size ||
+		int64(binary.LittleEndian.Uint64(data[8:16])) != img.blockSize {
+		return false
+	}
+	copy(img.dirty, data[mapHeaderSize:])
+	return true
+}
+
+func (img *Image) saveMap() error {
+	data := make([]byte, mapHeaderSize+len(img.dirty))
+	binary.LittleEndian.PutUint64(data[0:8], uint64(img.size))
+	binary.LittleEndian.PutUint64(data[8:16], uint64(img.blockSize))
+	copy(data[mapHeaderSize:], img.dirty)
+
+	// write it atomically, a torn map would expose stale overlay data
+	tmpPath := mapPath(img.overlayPath) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write overlay map: %w", err)
+	}
+	return os.Rename(tmpPath, mapPath(img.overlayPath))
+}
+
+// reset drops all changes, truncating the data file releases the space it used.
+func (img *Image) reset() error {
+	clear(img.dirty)
+	if err := img.overlay.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate overlay: %w", err)
+	}
+	if err := img.overlay.Truncate(img.size); err != nil {
+		return fmt.Errorf("failed to resize overlay: %w", err)
+	}
+	return img.saveMap()
+}
+
+// Size returns the size of the image.
+func (img *Image) Size() (int64, error) {
+	return img.size, nil
+}
+
+// ReadAt reads from the overlay for changed blocks and from the base image otherwise.
+func (img *Image) ReadAt(p []byte, off int64) (int, error) {
+	img.mu.RLock()
+	defer img.mu.RUnlock()
+
+	if off >= img.size {
+		return 0, io.EOF
+	}
+
+	var eof error
+	if remaining := img.size - off; int64(len(p)) > remaining {
+		p = p[:remaining]
+		eof = io.EOF
+	}
+
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		block := pos / img.blockSize
+		chunk := min(int64(len(p)-n), (block+1)*img.blockSize-pos)
+
+		f := img.base
+		if img.isDirty(block) {
+			f = img.overlay
+		}
+		read, err := f.ReadAt(p[n:n+int(chunk)], pos)
+		n += read
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, eof
+}
+
+// WriteAt writes to the overlay, blocks that are only partially written are copied from the base image first.
+func (img *Image) WriteAt(p []byte, off int64) (int, error) {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	if off < 0 || off+int64(len(p)) > img.size {
+		return 0, ErrOutOfRange
+	}
+
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		block := pos / img.blockSize
+		blockStart := block * img.blockSize
+		chunk := min(int64(len(p)-n), blockStart+img.blockSize-pos)
+
+		if !img.isDirty(block) && chunk < img.blockSize {
+			if err := img.copyBlock(img.base, img.overlay, block); err != nil {
+				return n, err
+			}
+		}
+
+		written, err := img.overlay.WriteAt(p[n:n+int(chunk)], pos)
+		n += written
+		if err != nil {
+			return n, err
+		}
+		img.setDirty(block)
+	}
+	return n, nil
+}
+
+func (img *Image) copyBlock(src *os.File, dst *os.File, block int64) error {
+	blockStart := block * img.blockSize
+	buf := make([]byte, min(img.blockSize, img.size-blockStart))
+	if _, err := src.ReadAt(buf, blockStart); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read block %d: %w", block, err)
+	}
+	if _, err := dst.WriteAt(buf, blockStart); err != nil {
+		return fmt.Errorf("failed to write block %d: %w", block, err)
+	}
+	return nil
+}
+
+// Sync flushes the overlay to disk.
+func (img *Image) Sync() error {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	if err := img.overlay.Sync(); err != nil {
+		return err
+	}
+	return img.saveMap()
+}
+
+// DirtyBytes returns the amount of data stored in the overlay.
+func (img *Image) DirtyBytes() int64 {
+	img.mu.RLock()
+	defer img.mu.RUnlock()
+
+	var n int64
+	for block := range blocks(img.size, img.blockSize) {
+		if img.isDirty(block) {
+			n += min(img.blockSize, img.size-block*img.blockSize)
+		}
+	}
+	return n
+}
+
+// Commit writes the changed blocks to the base image and empties the overlay.
+func (img *Image) Commit() error {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	base, err := os.OpenFile(img.basePath, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open base image for writing: %w", err)
+	}
+	defer base.Close()
+
+	for block := range blocks(img.size, img.blockSize) {
+		if !img.isDirty(block) {
+			continue
+		}
+		if err := img.copyBlock(img.overlay, base, block); err != nil {
+			return err
+		}
+	}
+	if err := base.Sync(); err != nil {
+		return fmt.Errorf("failed to sync base image: %w", err)
+	}
+
+	return img.reset()
+}
+
+// Discard drops all changes stored in the overlay.
+func (img *Image) Discard() error {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	return img.reset()
+}
+
+func (img *Image) closeFiles() error {
+	return errors.Join(img.overlay.Close(), img.base.Close())
+}
+
+// Close syncs the overlay and closes the image, the overlay is kept for the next Open.
+func (img *Image) Close() error {
+	if err := img.Sync(); err != nil {
+		_ = img.closeFiles()
+		return err
+	}
+	return img.closeFiles()
+}
@@ -0,0 +1,131 @@
+package cowimage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBlockSize = 16
+
+func newTestImage(t *testing.T, size int) (string, string, []byte) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.img")
+	overlayPath := filepath.Join(dir, "base.img.overlay")
+
+	data := bytes.Repeat([]byte{0xaa}, size)
+	require.NoError(t, os.WriteFile(basePath, data, 0644))
+	return basePath, overlayPath, data
+}
+
+func readAll(t *testing.T, img *Image) []byte {
+	size, err := img.Size()
+	require.NoError(t, err)
+	buf := make([]byte, size)
+	n, err := img.ReadAt(buf, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int(size), n)
+	return buf
+}
+
+func TestWriteKeepsBaseUntouched(t *testing.T) {
+	basePath, overlayPath, data := newTestImage(t, 50)
+
+	img, err := Open(basePath, overlayPath, testBlockSize)
+	require.NoError(t, err)
+	defer img.Close()
+
+	// spans a partial block, a full block and another partial block
+	n, err := img.WriteAt(bytes.Repeat([]byte{0x55}, 30), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 30, n)
+
+	expected := append([]byte{}, data...)
+	copy(expected[10:40], bytes.Repeat([]byte{0x55}, 30))
+	assert.Equal(t, expected, readAll(t, img))
+	assert.Equal(t, int64(48), img.DirtyBytes())
+
+	base, err := os.ReadFile(basePath)
+	require.NoError(t, err)
+	assert.Equal(t, data, base)
+}
+
+func TestReadPastEnd(t *testing.T) {
+	basePath, overlayPath, _ := newTestImage(t, 20)
+
+	img, err := Open(basePath, overlayPath, testBlockSize)
+	require.NoError(t, err)
+	defer img.Close()
+
+	buf := make([]byte, 10)
+	n, err := img.ReadAt(buf, 15)
+	assert.Equal(t, 5, n)
+	assert.Error(t, err)
+
+	_, err = img.WriteAt(buf, 15)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestCommit(t *testing.T) {
+	basePath, overlayPath, data := newTestImage(t, 40)
+
+	img, err := Open(basePath, overlayPath, testBlockSize)
+	require.NoError(t, err)
+	defer img.Close()
+
+	_, err = img.WriteAt([]byte{1, 2, 3}, 35)
+	require.NoError(t, err)
+	require.NoError(t, img.Commit())
+	assert.Equal(t, int64(0), img.DirtyBytes())
+
+	expected := append([]byte{}, data...)
+	copy(expected[35:], []byte{1, 2, 3})
+	base, err := os.ReadFile(basePath)
+	require.NoError(t, err)
+	assert.Equal(t, expected, base)
+	assert.Equal(t, expected, readAll(t, img))
+}
+
+func TestDiscard(t *testing.T) {
+	basePath, overlayPath, data := newTestImage(t, 40)
+
+	img, err := Open(basePath, overlayPath, testBlockSize)
+	require.NoError(t, err)
+	defer img.Close()
+
+	_, err = img.WriteAt([]byte{1, 2, 3}, 0)
+	require.NoError(t, err)
+	require.NoError(t, img.Discard())
+
+	assert.Equal(t, int64(0), img.DirtyBytes())
+	assert.Equal(t, data, readAll(t, img))
+}
+
+func TestReopenKeepsChanges(t *testing.T) {
+	basePath, overlayPath, _ := newTestImage(t, 40)
+
+	img, err := Open(basePath, overlayPath, testBlockSize)
+	require.NoError(t, err)
+	_, err = img.WriteAt([]byte{1, 2, 3}, 20)
+	require.NoError(t, err)
+	require.NoError(t, img.Close())
+
+	img, err = Open(basePath, overlayPath, testBlockSize)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, readAll(t, img)[20:23])
+	require.NoError(t, img.Close())
+
+	// a different block size invalidates the overlay
+	img, err = Open(basePath, overlayPath, 2*testBlockSize)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), img.DirtyBytes())
+	require.NoError(t, img.Close())
+
+	require.NoError(t, Remove(overlayPath))
+	_, err = os.Stat(overlayPath)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,247 @@
+// Package dhcpd is a minimal DHCPv4 server for point-to-point links,
+// it hands out a single address to whatever is on the other end of the link.
+package dhcpd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	serverPort = 67
+	clientPort = 68
+)
+
+// Options are the options of the DHCP server.
+type Options struct {
+	InterfaceName string
+	ServerIP      net.IP
+	ClientIP      net.IP
+	SubnetMask    net.IPMask
+	LeaseTime     time.Duration
+	Logger        *zerolog.Logger
+}
+
+// Lease is the lease handed out to the client.
+type Lease struct {
+	HardwareAddr string    `json:"hardware_addr"`
+	IP           string    `json:"ip"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// Server is a DHCP server serving a single client.
+type Server struct {
+	opts Options
+	l    *zerolog.Logger
+
+	conn     net.PacketConn
+	connLock sync.Mutex
+
+	lease     *Lease
+	leaseLock sync.Mutex
+}
+
+var defaultLogger = zerolog.New(os.Stdout).Level(zerolog.InfoLevel)
+
+// NewServer creates a new DHCP server.
+func NewServer(opts *Options) (*Server, error) {
+	if opts.ServerIP.To4() == nil || opts.ClientIP.To4() == nil {
+		return nil, errors.New("server and client addresses must be IPv4")
+	}
+	if opts.SubnetMask == nil {
+		return nil, errors.New("subnet mask can not be empty")
+	}
+	if opts.LeaseTime <= 0 {
+		opts.LeaseTime = 24 * time.Hour
+	}
+	if opts.Logger == nil {
+		opts.Logger = &defaultLogger
+	}
+
+	l := opts.Logger.With().Str("interface", opts.InterfaceName).Logger()
+	return &Server{
+		opts: *opts,
+		l:    &l,
+	}, nil
+}
+
+// Start starts serving requests on the interface.
+func (s *Server) Start() error {
+	conn, err := listen(s.opts.InterfaceName, serverPort)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.opts.InterfaceName, err)
+	}
+	s.connLock.Lock()
+	s.conn = conn
+	s.connLock.Unlock()
+
+	s.l.Info().
+		Str("server_ip", s.opts.ServerIP.String()).
+		Str("client_ip", s.opts.ClientIP.String()).
+		Msg("DHCP server started")
+
+	go s.serve(conn)
+	return nil
+}
+
+// Stop stops the server.
+func (s *Server) Stop() error {
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// GetLease returns the current lease, or nil if there's none.
+func (s *Server) GetLease() *Lease {
+	s.leaseLock.Lock()
+	defer s.leaseLock.Unlock()
+
+	if s.lease == nil || time.Now().After(s.lease.ExpiresAt) {
+		return nil
+	}
+	lease := *s.lease
+	return &lease
+}
+
+func (s *Server) serve(conn net.PacketConn) {
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				s.l.Info().Msg("DHCP server stopped")
+				return
+			}
+			s.l.Warn().Err(err).Msg("failed to read DHCP packet")
+			continue
+		}
+
+		request, err := ParsePacket(buf[:n])
+		if err != nil {
+			s.l.Debug().Err(err).Msg("ignoring malformed DHCP packet")
+			continue
+		}
+
+		reply := s.handle(request)
+		if reply == nil {
+			continue
+		}
+
+		// the client doesn't have an address yet, and it's the only host on the link
+		dst := &net.UDPAddr{IP: net.IPv4bcast, Port: clientPort}
+		if _, err := conn.WriteTo(reply.Marshal(), dst); err != nil {
+			s.l.Warn().Err(err).Msg("failed to send DHCP reply")
+		}
+	}
+}
+
+func (s *Server) handle(request *Packet) *Packet {
+	if request.Op != opBootRequest {
+		return nil
+	}
+
+	l := s.l.With().
+		Str("hardware_addr", request.CHAddr.String()).
+		Uint8("message_type", uint8(request.MessageType())).
+		Logger()
+
+	switch request.MessageType() {
+	case MessageTypeDiscover:
+		l.Debug().Msg("offering address")
+		return s.newReply(request, MessageTypeOffer)
+	case MessageTypeRequest:
+		// the client has selected another server
+		if serverID := request.IPOption(OptionServerIdentifier); serverID != nil && !serverID.Equal(s.opts.ServerIP) {
+			return nil
+		}
+
+		requestedIP := request.IPOption(OptionRequestedIP)
+		if requestedIP == nil && !request.CIAddr.IsUnspecified() {
+			requestedIP = request.CIAddr
+		}
+		if requestedIP != nil && !requestedIP.Equal(s.opts.ClientIP) {
+			l.Info().Str("requested_ip", requestedIP.String()).Msg("rejecting request for unknown address")
+			return s.newReply(request, MessageTypeNak)
+		}
+
+		s.leaseLock.Lock()
+		s.lease = &Lease{
+			HardwareAddr: request.CHAddr.String(),
+			IP:           s.opts.ClientIP.String(),
+			ExpiresAt:    time.Now().Add(s.opts.LeaseTime),
+		}
+		s.leaseLock.Unlock()
+
+		l.Info().Str("ip", s.opts.ClientIP.String()).Msg("address leased")
+		return s.newReply(request, MessageTypeAck)
+	case MessageTypeRelease:
+		s.leaseLock.Lock()
+		s.lease = nil
+		s.leaseLock.Unlock()
+
+		l.Info().Msg("address released")
+		return nil
+	case MessageTypeDecline:
+		l.Warn().Msg("client declined the address, it's probably in use on the host already")
+		return nil
+	case MessageTypeInform:
+		reply := s.newReply(request, MessageTypeAck)
+		reply.YIAddr = net.IPv4zero
+		delete(reply.Options, OptionLeaseTime)
+		delete(reply.Options, OptionRenewalTime)
+		delete(reply.Options, OptionRebindingTime)
+		return reply
+	default:
+		return nil
+	}
+}
+
+func (s *Server) newReply(request *Packet, messageType MessageType) *Packet {
+	reply := &Packet{
+		Op:      opBootReply,
+		XID:     request.XID,
+		Flags:   request.Flags,
+		CIAddr:  net.IPv4zero,
+		YIAddr:  s.opts.ClientIP,
+		SIAddr:  s.opts.ServerIP,
+		GIAddr:  request.GIAddr,
+		CHAddr:  request.CHAddr,
+		Options: make(map[byte][]byte),
+	}
+
+	reply.Options[OptionMessageType] = []byte{byte(messageType)}
+	reply.Options[OptionServerIdentifier] = s.opts.ServerIP.To4()
+
+	if messageType == MessageTypeNak {
+		reply.YIAddr = net.IPv4zero
+		reply.SIAddr = net.IPv4zero
+		return reply
+	}
+
+	// no router or DNS options, the link must not become the default route of the host
+	reply.Options[OptionSubnetMask] = []byte(s.opts.SubnetMask)
+	reply.Options[OptionLeaseTime] = durationOption(s.opts.LeaseTime)
+	reply.Options[OptionRenewalTime] = durationOption(s.opts.LeaseTime / 2)
+	reply.Options[OptionRebindingTime] = durationOption(s.opts.LeaseTime * 7 / 8)
+
+	return reply
+}
+
+func durationOption(d time.Duration) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(d/time.Second))
+	return b
+}
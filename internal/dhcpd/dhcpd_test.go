@@ -0,0 +1,96 @@
+package dhcpd
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T) *Server {
+	s, err := NewServer(&Options{
+		InterfaceName: "usb0",
+		ServerIP:      net.IPv4(172, 31, 255, 1),
+		ClientIP:      net.IPv4(172, 31, 255, 2),
+		SubnetMask:    net.CIDRMask(30, 32),
+		LeaseTime:     time.Hour,
+	})
+	assert.NoError(t, err)
+	return s
+}
+
+func newTestRequest(messageType MessageType) *Packet {
+	return &Packet{
+		Op:      opBootRequest,
+		XID:     0xdeadbeef,
+		CIAddr:  net.IPv4zero,
+		YIAddr:  net.IPv4zero,
+		SIAddr:  net.IPv4zero,
+		GIAddr:  net.IPv4zero,
+		CHAddr:  net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01},
+		Options: map[byte][]byte{OptionMessageType: {byte(messageType)}},
+	}
+}
+
+func TestPacketRoundTrip(t *testing.T) {
+	request := newTestRequest(MessageTypeDiscover)
+	request.Options[OptionParameterRequest] = []byte{OptionSubnetMask, OptionRouter}
+
+	parsed, err := ParsePacket(request.Marshal())
+	assert.NoError(t, err)
+	assert.Equal(t, request.XID, parsed.XID)
+	assert.Equal(t, request.CHAddr, parsed.CHAddr)
+	assert.Equal(t, MessageTypeDiscover, parsed.MessageType())
+	assert.Equal(t, []byte{OptionSubnetMask, OptionRouter}, parsed.Options[OptionParameterRequest])
+}
+
+func TestParsePacketInvalid(t *testing.T) {
+	_, err := ParsePacket([]byte{1, 2, 3})
+	assert.Error(t, err)
+
+	data := newTestRequest(MessageTypeDiscover).Marshal()
+	data[headerLength] = 0
+	_, err = ParsePacket(data)
+	assert.Error(t, err)
+}
+
+func TestHandleDiscoverAndRequest(t *testing.T) {
+	s := newTestServer(t)
+
+	offer := s.handle(newTestRequest(MessageTypeDiscover))
+	assert.NotNil(t, offer)
+	assert.Equal(t, MessageTypeOffer, offer.MessageType())
+	assert.True(t, offer.YIAddr.Equal(s.opts.ClientIP))
+	assert.Nil(t, offer.Options[OptionRouter], "the link must not advertise a router")
+	assert.Nil(t, s.GetLease(), "an offer doesn't create a lease")
+
+	request := newTestRequest(MessageTypeRequest)
+	request.Options[OptionRequestedIP] = s.opts.ClientIP.To4()
+	request.Options[OptionServerIdentifier] = s.opts.ServerIP.To4()
+	ack := s.handle(request)
+	assert.NotNil(t, ack)
+	assert.Equal(t, MessageTypeAck, ack.MessageType())
+
+	lease := s.GetLease()
+	assert.NotNil(t, lease)
+	assert.Equal(t, "172.31.255.2", lease.IP)
+	assert.Equal(t, "02:00:00:00:00:01", lease.HardwareAddr)
+
+	s.handle(newTestRequest(MessageTypeRelease))
+	assert.Nil(t, s.GetLease())
+}
+
+func TestHandleRequestUnknownAddress(t *testing.T) {
+	s := newTestServer(t)
+
+	request := newTestRequest(MessageTypeRequest)
+	request.Options[OptionRequestedIP] = net.IPv4(192, 168, 1, 10).To4()
+	nak := s.handle(request)
+	assert.NotNil(t, nak)
+	assert.Equal(t, MessageTypeNak, nak.MessageType())
+
+	// requests for another server are ignored
+	request.Options[OptionServerIdentifier] = net.IPv4(192, 168, 1, 1).To4()
+	assert.Nil(t, s.handle(request))
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package dhcpd
+
+import (
+	"errors"
+	"net"
+)
+
+func listen(interfaceName string, port int) (net.PacketConn, error) {
+	return nil, errors.New("not supported")
+}
@@ -0,0 +1,165 @@
+package dhcpd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// MessageType is the DHCP message type (option 53)
+type MessageType byte
+
+const (
+	MessageTypeDiscover MessageType = 1
+	MessageTypeOffer    MessageType = 2
+	MessageTypeRequest  MessageType = 3
+	MessageTypeDecline  MessageType = 4
+	MessageTypeAck      MessageType = 5
+	MessageTypeNak      MessageType = 6
+	MessageTypeRelease  MessageType = 7
+	MessageTypeInform   MessageType = 8
+)
+
+// https://www.rfc-editor.org/rfc/rfc2132
+const (
+	OptionSubnetMask       byte = 1
+	OptionRouter           byte = 3
+	OptionDNS              byte = 6
+	OptionRequestedIP      byte = 50
+	OptionLeaseTime        byte = 51
+	OptionMessageType      byte = 53
+	OptionServerIdentifier byte = 54
+	OptionParameterRequest byte = 55
+	OptionRenewalTime      byte = 58
+	OptionRebindingTime    byte = 59
+	OptionClientIdentifier byte = 61
+	OptionEnd              byte = 255
+	OptionPad              byte = 0
+)
+
+const (
+	opBootRequest            byte = 1
+	opBootReply              byte = 2
+	hardwareTypeEthernet     byte = 1
+	hardwareAddressMaxLength      = 16
+	headerLength                  = 236
+	flagBroadcast                 = 0x8000
+)
+
+var magicCookie = []byte{99, 130, 83, 99}
+
+// Packet is a DHCPv4 packet, only the fields we need are decoded.
+type Packet struct {
+	Op      byte
+	XID     uint32
+	Flags   uint16
+	CIAddr  net.IP
+	YIAddr  net.IP
+	SIAddr  net.IP
+	GIAddr  net.IP
+	CHAddr  net.HardwareAddr
+	Options map[byte][]byte
+}
+
+// ParsePacket parses a DHCPv4 packet.
+func ParsePacket(data []byte) (*Packet, error) {
+	if len(data) < headerLength+len(magicCookie) {
+		return nil, fmt.Errorf("packet too short: %d", len(data))
+	}
+
+	hlen := int(data[2])
+	if hlen > hardwareAddressMaxLength {
+		return nil, fmt.Errorf("invalid hardware address length: %d", hlen)
+	}
+
+	p := &Packet{
+		Op:      data[0],
+		XID:     binary.BigEndian.Uint32(data[4:8]),
+		Flags:   binary.BigEndian.Uint16(data[10:12]),
+		CIAddr:  net.IP(append([]byte(nil), data[12:16]...)),
+		YIAddr:  net.IP(append([]byte(nil), data[16:20]...)),
+		SIAddr:  net.IP(append([]byte(nil), data[20:24]...)),
+		GIAddr:  net.IP(append([]byte(nil), data[24:28]...)),
+		CHAddr:  net.HardwareAddr(append([]byte(nil), data[28:28+hlen]...)),
+		Options: make(map[byte][]byte),
+	}
+
+	if string(data[headerLength:headerLength+4]) != string(magicCookie) {
+		return nil, errors.New("invalid magic cookie")
+	}
+
+	options := data[headerLength+4:]
+	for i := 0; i < len(options); {
+		code := options[i]
+		if code == OptionEnd {
+			break
+		}
+		if code == OptionPad {
+			i++
+			continue
+		}
+		if i+1 >= len(options) {
+			return nil, fmt.Errorf("truncated option %d", code)
+		}
+		length := int(options[i+1])
+		if i+2+length > len(options) {
+			return nil, fmt.Errorf("truncated option %d", code)
+		}
+		p.Options[code] = append(p.Options[code], options[i+2:i+2+length]...)
+		i += 2 + length
+	}
+
+	return p, nil
+}
+
+// MessageType returns the DHCP message type of the packet, or 0 if it's missing.
+func (p *Packet) MessageType() MessageType {
+	v, ok := p.Options[OptionMessageType]
+	if !ok || len(v) != 1 {
+		return 0
+	}
+	return MessageType(v[0])
+}
+
+// IPOption returns the IPv4 address stored in the given option, or nil if it's missing.
+func (p *Packet) IPOption(code byte) net.IP {
+	v, ok := p.Options[code]
+	if !ok || len(v) != net.IPv4len {
+		return nil
+	}
+	return net.IP(v)
+}
+
+// Broadcast returns true if the client asked for the reply to be broadcasted.
+func (p *Packet) Broadcast() bool {
+	return p.Flags&flagBroadcast != 0
+}
+
+// Marshal serializes the packet, options are written in ascending order.
+func (p *Packet) Marshal() []byte {
+	data := make([]byte, headerLength, headerLength+len(magicCookie)+64)
+	data[0] = p.Op
+	data[1] = hardwareTypeEthernet
+	data[2] = byte(len(p.CHAddr))
+	binary.BigEndian.PutUint32(data[4:8], p.XID)
+	binary.BigEndian.PutUint16(data[10:12], p.Flags)
+	copy(data[12:16], p.CIAddr.To4())
+	copy(data[16:20], p.YIAddr.To4())
+	copy(data[20:24], p.SIAddr.To4())
+	copy(data[24:28], p.GIAddr.To4())
+	copy(data[28:28+hardwareAddressMaxLength], p.CHAddr)
+
+	data = append(data, magicCookie...)
+	for code := 1; code < int(OptionEnd); code++ {
+		v, ok := p.Options[byte(code)]
+		if !ok {
+			continue
+		}
+		data = append(data, byte(code), byte(len(v)))
+		data = append(data, v...)
+	}
+	data = append(data, OptionEnd)
+
+	return data
+}
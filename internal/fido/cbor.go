@@ -0,0 +1,279 @@
+package fido
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// This is a minimal CBOR implementation covering what CTAP2 uses: integers, byte and text strings,
+// arrays, maps and booleans. Maps are encoded in the CTAP2 canonical form.
+
+const (
+	cborMajorUnsigned = 0
+	cborMajorNegative = 1
+	cborMajorBytes    = 2
+	cborMajorText     = 3
+	cborMajorArray    = 4
+	cborMajorMap      = 5
+	cborMajorSimple   = 7
+
+	cborFalse = 0xf4
+	cborTrue  = 0xf5
+	cborNull  = 0xf6
+
+	cborMaxDepth = 8
+)
+
+var errCBORTruncated = errors.New("cbor: truncated input")
+
+func cborAppendHead(buf []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= 0xff:
+		return append(buf, major|24, byte(n))
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), n)
+	}
+}
+
+func cborAppendInt(buf []byte, n int64) []byte {
+	if n < 0 {
+		return cborAppendHead(buf, cborMajorNegative, uint64(-1-n))
+	}
+	return cborAppendHead(buf, cborMajorUnsigned, uint64(n))
+}
+
+func cborAppend(buf []byte, v any) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case nil:
+		return append(buf, cborNull), nil
+	case bool:
+		if v {
+			return append(buf, cborTrue), nil
+		}
+		return append(buf, cborFalse), nil
+	case int:
+		return cborAppendInt(buf, int64(v)), nil
+	case int64:
+		return cborAppendInt(buf, v), nil
+	case uint32:
+		return cborAppendHead(buf, cborMajorUnsigned, uint64(v)), nil
+	case uint64:
+		return cborAppendHead(buf, cborMajorUnsigned, v), nil
+	case []byte:
+		return append(cborAppendHead(buf, cborMajorBytes, uint64(len(v))), v...), nil
+	case string:
+		return append(cborAppendHead(buf, cborMajorText, uint64(len(v))), v...), nil
+	case []any:
+		buf = cborAppendHead(buf, cborMajorArray, uint64(len(v)))
+		for _, item := range v {
+			if buf, err = cborAppend(buf, item); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case map[int]any:
+		entries := make([][2]any, 0, len(v))
+		for k, item := range v {
+			entries = append(entries, [2]any{k, item})
+		}
+		return cborAppendMap(buf, entries)
+	case map[string]any:
+		entries := make([][2]any, 0, len(v))
+		for k, item := range v {
+			entries = append(entries, [2]any{k, item})
+		}
+		return cborAppendMap(buf, entries)
+	default:
+		return nil, fmt.Errorf("cbor: unsupported type %T", v)
+	}
+}
+
+// cborAppendMap sorts the entries by their encoded keys, shorter keys first, as required by CTAP2.
+func cborAppendMap(buf []byte, entries [][2]any) ([]byte, error) {
+	type encodedEntry struct {
+		key   []byte
+		value any
+	}
+
+	encoded := make([]encodedEntry, 0, len(entries))
+	for _, entry := range entries {
+		key, err := cborAppend(nil, entry[0])
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, encodedEntry{key, entry[1]})
+	}
+	sort.Slice(encoded, func(i, j int) bool {
+		if len(encoded[i].key) != len(encoded[j].key) {
+			return len(encoded[i].key) < len(encoded[j].key)
+		}
+		return bytes.Compare(encoded[i].key, encoded[j].key) < 0
+	})
+
+	var err error
+	buf = cborAppendHead(buf, cborMajorMap, uint64(len(encoded)))
+	for _, entry := range encoded {
+		buf = append(buf, entry.key...)
+		if buf, err = cborAppend(buf, entry.value); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// cborMarshal encodes nil, bool, int, int64, uint32, uint64, []byte, string, []any, map[int]any and map[string]any.
+func cborMarshal(v any) ([]byte, error) {
+	return cborAppend(nil, v)
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) readHead() (byte, uint64, error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, errCBORTruncated
+	}
+	initial := d.data[d.pos]
+	d.pos++
+
+	major := initial >> 5
+	info := initial & 0x1f
+
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, 0, fmt.Errorf("cbor: unsupported additional info %d", info)
+	}
+
+	if d.pos+size > len(d.data) {
+		return 0, 0, errCBORTruncated
+	}
+	var n uint64
+	for _, b := range d.data[d.pos : d.pos+size] {
+		n = n<<8 | uint64(b)
+	}
+	d.pos += size
+	return major, n, nil
+}
+
+func (d *cborDecoder) decode(depth int) (any, error) {
+	if depth > cborMaxDepth {
+		return nil, errors.New("cbor: nesting too deep")
+	}
+
+	if d.pos < len(d.data) && d.data[d.pos]>>5 == cborMajorSimple {
+		simple := d.data[d.pos]
+		d.pos++
+		switch simple {
+		case cborFalse:
+			return false, nil
+		case cborTrue:
+			return true, nil
+		case cborNull:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("cbor: unsupported simple value 0x%02x", simple)
+		}
+	}
+
+	major, n, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborMajorUnsigned:
+		if n > 1<<63-1 {
+			return nil, errors.New("cbor: integer overflow")
+		}
+		return int64(n), nil
+	case cborMajorNegative:
+		if n > 1<<63-1 {
+			return nil, errors.New("cbor: integer overflow")
+		}
+		return -1 - int64(n), nil
+	case cborMajorBytes, cborMajorText:
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, errCBORTruncated
+		}
+		b := d.data[d.pos : d.pos+int(n)]
+		d.pos += int(n)
+		if major == cborMajorText {
+			return string(b), nil
+		}
+		return bytes.Clone(b), nil
+	case cborMajorArray:
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, errCBORTruncated
+		}
+		items := make([]any, 0, n)
+		for range n {
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case cborMajorMap:
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, errCBORTruncated
+		}
+		m := make(map[any]any, n)
+		for range n {
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, fmt.Errorf("cbor: unsupported map key type %T", key)
+			}
+			value, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("cbor: unsupported major type %d", major)
+	}
+}
+
+// cborUnmarshal decodes a single item, integers are returned as int64 and maps as map[any]any.
+func cborUnmarshal(data []byte) (any, error) {
+	d := &cborDecoder{data: data}
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(data) {
+		return nil, errors.New("cbor: trailing data")
+	}
+	return v, nil
+}
@@ -0,0 +1,63 @@
+package fido
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCBORCanonicalMapOrder(t *testing.T) {
+	// CTAP2 canonical form: shorter keys first, then bytewise
+	encoded, err := cborMarshal(map[int]any{
+		-1: 1,
+		1:  2,
+		3:  -7,
+		-2: []byte{0xaa},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xa4, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x41, 0xaa}, encoded)
+
+	encoded, err = cborMarshal(map[string]any{"rk": true, "up": false, "plat": true})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{
+		0xa3,
+		0x62, 'r', 'k', 0xf5,
+		0x62, 'u', 'p', 0xf4,
+		0x64, 'p', 'l', 'a', 't', 0xf5,
+	}, encoded)
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	encoded, err := cborMarshal(map[int]any{
+		1: "public-key",
+		2: []any{int64(1), int64(-500), int64(70000)},
+		3: map[string]any{"id": []byte{1, 2, 3}},
+		4: uint64(1) << 40,
+	})
+	require.NoError(t, err)
+
+	decoded, err := cborUnmarshal(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, map[any]any{
+		int64(1): "public-key",
+		int64(2): []any{int64(1), int64(-500), int64(70000)},
+		int64(3): map[any]any{"id": []byte{1, 2, 3}},
+		int64(4): int64(1) << 40,
+	}, decoded)
+}
+
+func TestCBORInvalidInput(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"truncated string":    {0x45, 0x01},
+		"truncated map":       {0xa2, 0x01, 0x02},
+		"huge array":          {0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"trailing data":       {0x01, 0x02},
+		"indefinite length":   {0x5f},
+		"float":               {0xfa, 0x00, 0x00, 0x00, 0x00},
+		"unsupported map key": {0xa1, 0x40, 0x01},
+	} {
+		_, err := cborUnmarshal(data)
+		assert.Error(t, err, name)
+	}
+}
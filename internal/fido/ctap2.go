@@ -0,0 +1,599 @@
+package fido
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CTAP2 commands, see FIDO CTAP 2.0 section 5.
+const (
+	ctapMakeCredential   byte = 0x01
+	ctapGetAssertion     byte = 0x02
+	ctapGetInfo          byte = 0x04
+	ctapReset            byte = 0x07
+	ctapGetNextAssertion byte = 0x08
+)
+
+// ctapStatus is a CTAP2 status code, handlers return it as error.
+type ctapStatus byte
+
+const (
+	ctapOK                       ctapStatus = 0x00
+	ctap1ErrInvalidCommand       ctapStatus = 0x01
+	ctap1ErrInvalidLength        ctapStatus = 0x03
+	ctap2ErrCBORUnexpectedType   ctapStatus = 0x11
+	ctap2ErrInvalidCBOR          ctapStatus = 0x12
+	ctap2ErrMissingParameter     ctapStatus = 0x14
+	ctap2ErrCredentialExcluded   ctapStatus = 0x19
+	ctap2ErrUnsupportedAlgorithm ctapStatus = 0x26
+	ctap2ErrOperationDenied      ctapStatus = 0x27
+	ctap2ErrKeyStoreFull         ctapStatus = 0x28
+	ctap2ErrUnsupportedOption    ctapStatus = 0x2b
+	ctap2ErrInvalidOption        ctapStatus = 0x2c
+	ctap2ErrKeepaliveCancel      ctapStatus = 0x2d
+	ctap2ErrNoCredentials        ctapStatus = 0x2e
+	ctap2ErrUserActionTimeout    ctapStatus = 0x2f
+	ctap2ErrNotAllowed           ctapStatus = 0x30
+	ctap2ErrPinNotSet            ctapStatus = 0x35
+	ctap1ErrOther                ctapStatus = 0x7f
+)
+
+func (s ctapStatus) Error() string {
+	return fmt.Sprintf("CTAP status 0x%02x", byte(s))
+}
+
+const (
+	coseAlgES256 = -7
+
+	authDataFlagUP = 0x01
+	authDataFlagAT = 0x40
+
+	maxMsgSize = 1200
+
+	// PresenceTimeout is how long the user has to confirm their presence
+	PresenceTimeout = 30 * time.Second
+	// getNextAssertion has to follow getAssertion within this time
+	nextAssertionTimeout = 30 * time.Second
+)
+
+// AAGUID identifies the authenticator model, it's the same for all JetKVM devices.
+var AAGUID = []byte{
+	0x6a, 0x65, 0x74, 0x6b, 0x76, 0x6d, 0x2d, 0x66,
+	0x69, 0x64, 0x6f, 0x2d, 0x76, 0x31, 0x00, 0x01,
+}
+
+// PresenceRequest describes the operation the user is asked to confirm.
+type PresenceRequest struct {
+	Operation string `json:"operation"` // register, authenticate or reset
+	RPID      string `json:"rpId,omitempty"`
+	UserName  string `json:"userName,omitempty"`
+}
+
+// PresenceFunc asks the user to confirm their presence, it returns false if the user declined.
+// The context is cancelled when the host cancels the request or the request times out.
+type PresenceFunc func(ctx context.Context, req PresenceRequest) (bool, error)
+
+type pendingAssertions struct {
+	credentials    []Credential
+	rpIDHash       []byte
+	clientDataHash []byte
+	flags          byte
+	expires        time.Time
+}
+
+// Authenticator is a software CTAP2 authenticator that also speaks U2F (CTAP1).
+type Authenticator struct {
+	store    *Store
+	presence PresenceFunc
+
+	mu      sync.Mutex
+	pending *pendingAssertions
+	u2f     u2fPresence
+}
+
+// NewAuthenticator creates an authenticator keeping its credentials in the store.
+func NewAuthenticator(store *Store, presence PresenceFunc) *Authenticator {
+	return &Authenticator{
+		store:    store,
+		presence: presence,
+	}
+}
+
+// HandleCBOR handles a CTAP2 request and returns the status code followed by the CBOR encoded response.
+// upNeeded is called before the user is asked for their presence.
+func (a *Authenticator) HandleCBOR(ctx context.Context, req []byte, upNeeded func()) []byte {
+	if len(req) == 0 {
+		return []byte{byte(ctap1ErrInvalidLength)}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	resp, err := a.handleCommand(ctx, req[0], req[1:], upNeeded)
+	if err != nil {
+		var status ctapStatus
+		if !errors.As(err, &status) {
+			status = ctap1ErrOther
+		}
+		return []byte{byte(status)}
+	}
+
+	encoded, err := cborMarshal(resp)
+	if err != nil {
+		return []byte{byte(ctap1ErrOther)}
+	}
+	return append([]byte{byte(ctapOK)}, encoded...)
+}
+
+func (a *Authenticator) handleCommand(ctx context.Context, cmd byte, data []byte, upNeeded func()) (map[int]any, error) {
+	if cmd != ctapGetNextAssertion {
+		a.pending = nil
+	}
+
+	var params map[any]any
+	if cmd == ctapMakeCredential || cmd == ctapGetAssertion {
+		decoded, err := cborUnmarshal(data)
+		if err != nil {
+			return nil, ctap2ErrInvalidCBOR
+		}
+		var ok bool
+		if params, ok = decoded.(map[any]any); !ok {
+			return nil, ctap2ErrCBORUnexpectedType
+		}
+	}
+
+	switch cmd {
+	case ctapMakeCredential:
+		return a.makeCredential(ctx, params, upNeeded)
+	case ctapGetAssertion:
+		return a.getAssertion(ctx, params, upNeeded)
+	case ctapGetNextAssertion:
+		return a.getNextAssertion()
+	case ctapGetInfo:
+		return a.getInfo(), nil
+	case ctapReset:
+		return a.reset(ctx, upNeeded)
+	default:
+		return nil, ctap1ErrInvalidCommand
+	}
+}
+
+func (a *Authenticator) getInfo() map[int]any {
+	return map[int]any{
+		0x01: []any{"U2F_V2", "FIDO_2_0"},
+		0x03: AAGUID,
+		0x04: map[string]any{
+			"rk":   true,
+			"up":   true,
+			"plat": false,
+		},
+		0x05: maxMsgSize,
+	}
+}
+
+// requestPresence asks the user to confirm the operation and maps the outcome to a CTAP status.
+func (a *Authenticator) requestPresence(ctx context.Context, req PresenceRequest, upNeeded func()) error {
+	if a.presence == nil {
+		return ctap2ErrOperationDenied
+	}
+	if upNeeded != nil {
+		upNeeded()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, PresenceTimeout)
+	defer cancel()
+
+	approved, err := a.presence(ctx, req)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ctap2ErrUserActionTimeout
+	case errors.Is(err, context.Canceled):
+		return ctap2ErrKeepaliveCancel
+	case err != nil:
+		return ctap2ErrOperationDenied
+	case !approved:
+		return ctap2ErrOperationDenied
+	}
+	return nil
+}
+
+func (a *Authenticator) makeCredential(ctx context.Context, params map[any]any, upNeeded func()) (map[int]any, error) {
+	clientDataHash, err := getBytes(params, int64(1), true)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := getMap(params, int64(2), true)
+	if err != nil {
+		return nil, err
+	}
+	rpID, err := getString(rp, "id", true)
+	if err != nil {
+		return nil, err
+	}
+	user, err := getMap(params, int64(3), true)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := getBytes(user, "id", true)
+	if err != nil {
+		return nil, err
+	}
+	userName, err := getString(user, "name", false)
+	if err != nil {
+		return nil, err
+	}
+	userDisplayName, err := getString(user, "displayName", false)
+	if err != nil {
+		return nil, err
+	}
+
+	credParams, err := getArray(params, int64(4), true)
+	if err != nil {
+		return nil, err
+	}
+	if !supportsES256(credParams) {
+		return nil, ctap2ErrUnsupportedAlgorithm
+	}
+
+	options, err := getMap(params, int64(7), false)
+	if err != nil {
+		return nil, err
+	}
+	rk, err := getBool(options, "rk", false)
+	if err != nil {
+		return nil, err
+	}
+	if uv, err := getBool(options, "uv", false); err != nil || uv {
+		return nil, ctap2ErrUnsupportedOption
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	presenceReq := PresenceRequest{Operation: "register", RPID: rpID, UserName: userName}
+
+	if _, ok := params[int64(8)]; ok {
+		return nil, a.pinNotSet(ctx, params[int64(8)], presenceReq, upNeeded)
+	}
+
+	excludeList, err := getArray(params, int64(5), false)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range credentialIDs(excludeList) {
+		if _, ok := a.store.Get(id, rpIDHash[:]); ok {
+			// the user has to confirm before the host learns the credential exists
+			if err := a.requestPresence(ctx, presenceReq, upNeeded); err != nil {
+				return nil, err
+			}
+			return nil, ctap2ErrCredentialExcluded
+		}
+	}
+
+	if err := a.requestPresence(ctx, presenceReq, upNeeded); err != nil {
+		return nil, err
+	}
+
+	cred, key, err := newCredential(rpIDHash[:])
+	if err != nil {
+		return nil, err
+	}
+	cred.RPID = rpID
+	cred.UserID = userID
+	cred.UserName = userName
+	cred.UserDisplayName = userDisplayName
+	cred.Resident = rk
+
+	if err := a.store.Add(cred); err != nil {
+		if errors.Is(err, ErrStoreFull) {
+			return nil, ctap2ErrKeyStoreFull
+		}
+		return nil, err
+	}
+
+	coseKey, err := cborMarshal(coseKeyES256(&key.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+	attestedCredentialData := make([]byte, 0, len(AAGUID)+2+len(cred.ID)+len(coseKey))
+	attestedCredentialData = append(attestedCredentialData, AAGUID...)
+	attestedCredentialData = binary.BigEndian.AppendUint16(attestedCredentialData, uint16(len(cred.ID)))
+	attestedCredentialData = append(attestedCredentialData, cred.ID...)
+	attestedCredentialData = append(attestedCredentialData, coseKey...)
+
+	authData := makeAuthData(rpIDHash[:], authDataFlagUP|authDataFlagAT, cred.SignCount, attestedCredentialData)
+
+	// packed self attestation, signed with the credential key itself
+	sig, err := sign(key, authData, clientDataHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[int]any{
+		0x01: "packed",
+		0x02: authData,
+		0x03: map[string]any{
+			"alg": coseAlgES256,
+			"sig": sig,
+		},
+	}, nil
+}
+
+func (a *Authenticator) getAssertion(ctx context.Context, params map[any]any, upNeeded func()) (map[int]any, error) {
+	rpID, err := getString(params, int64(1), true)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash, err := getBytes(params, int64(2), true)
+	if err != nil {
+		return nil, err
+	}
+	allowList, err := getArray(params, int64(3), false)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := getMap(params, int64(5), false)
+	if err != nil {
+		return nil, err
+	}
+	up, err := getBool(options, "up", true)
+	if err != nil {
+		return nil, err
+	}
+	if uv, err := getBool(options, "uv", false); err != nil || uv {
+		return nil, ctap2ErrUnsupportedOption
+	}
+	if _, ok := options["rk"]; ok {
+		return nil, ctap2ErrInvalidOption
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	presenceReq := PresenceRequest{Operation: "authenticate", RPID: rpID}
+
+	if _, ok := params[int64(6)]; ok {
+		return nil, a.pinNotSet(ctx, params[int64(6)], presenceReq, upNeeded)
+	}
+
+	var credentials []Credential
+	if len(allowList) > 0 {
+		// only the first matching credential is used when the host knows which ones it accepts
+		for _, id := range credentialIDs(allowList) {
+			if cred, ok := a.store.Get(id, rpIDHash[:]); ok {
+				credentials = []Credential{*cred}
+				break
+			}
+		}
+	} else {
+		credentials = a.store.Resident(rpIDHash[:])
+	}
+	if len(credentials) == 0 {
+		return nil, ctap2ErrNoCredentials
+	}
+
+	var flags byte
+	if up {
+		if len(credentials) == 1 {
+			presenceReq.UserName = credentials[0].UserName
+		}
+		if err := a.requestPresence(ctx, presenceReq, upNeeded); err != nil {
+			return nil, err
+		}
+		flags |= authDataFlagUP
+	}
+
+	resp, err := a.assertion(&credentials[0], rpIDHash[:], clientDataHash, flags, len(allowList) == 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(credentials) > 1 {
+		resp[0x05] = len(credentials)
+		a.pending = &pendingAssertions{
+			credentials:    credentials[1:],
+			rpIDHash:       rpIDHash[:],
+			clientDataHash: clientDataHash,
+			flags:          flags,
+			expires:        time.Now().Add(nextAssertionTimeout),
+		}
+	}
+	return resp, nil
+}
+
+func (a *Authenticator) getNextAssertion() (map[int]any, error) {
+	pending := a.pending
+	if pending == nil || len(pending.credentials) == 0 || time.Now().After(pending.expires) {
+		a.pending = nil
+		return nil, ctap2ErrNotAllowed
+	}
+
+	cred := pending.credentials[0]
+	pending.credentials = pending.credentials[1:]
+	pending.expires = time.Now().Add(nextAssertionTimeout)
+	return a.assertion(&cred, pending.rpIDHash, pending.clientDataHash, pending.flags, true)
+}
+
+func (a *Authenticator) assertion(cred *Credential, rpIDHash []byte, clientDataHash []byte, flags byte, includeUser bool) (map[int]any, error) {
+	key, err := cred.privateKey()
+	if err != nil {
+		return nil, err
+	}
+	signCount, err := a.store.IncrementSignCount(cred.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	authData := makeAuthData(rpIDHash, flags, signCount, nil)
+	sig, err := sign(key, authData, clientDataHash)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := map[int]any{
+		0x01: map[string]any{
+			"id":   cred.ID,
+			"type": "public-key",
+		},
+		0x02: authData,
+		0x03: sig,
+	}
+	// without user verification, only the user handle may be disclosed
+	if includeUser && cred.UserID != nil {
+		resp[0x04] = map[string]any{"id": cred.UserID}
+	}
+	return resp, nil
+}
+
+func (a *Authenticator) reset(ctx context.Context, upNeeded func()) (map[int]any, error) {
+	if err := a.requestPresence(ctx, PresenceRequest{Operation: "reset"}, upNeeded); err != nil {
+		return nil, err
+	}
+	if err := a.store.Reset(); err != nil {
+		return nil, err
+	}
+	return map[int]any{}, nil
+}
+
+// pinNotSet answers requests carrying a PIN auth parameter, no PIN can be set on this authenticator.
+// An empty parameter is how platforms ask the user to select an authenticator, which needs a touch first.
+func (a *Authenticator) pinNotSet(ctx context.Context, pinAuth any, req PresenceRequest, upNeeded func()) error {
+	if b, ok := pinAuth.([]byte); ok && len(b) == 0 {
+		if err := a.requestPresence(ctx, req, upNeeded); err != nil {
+			return err
+		}
+	}
+	return ctap2ErrPinNotSet
+}
+
+func supportsES256(credParams []any) bool {
+	for _, p := range credParams {
+		m, ok := p.(map[any]any)
+		if !ok {
+			continue
+		}
+		alg, _ := m["alg"].(int64)
+		typ, _ := m["type"].(string)
+		if alg == coseAlgES256 && typ == "public-key" {
+			return true
+		}
+	}
+	return false
+}
+
+// credentialIDs returns the IDs of the public key credential descriptors in the list.
+func credentialIDs(list []any) [][]byte {
+	ids := make([][]byte, 0, len(list))
+	for _, d := range list {
+		m, ok := d.(map[any]any)
+		if !ok {
+			continue
+		}
+		id, _ := m["id"].([]byte)
+		typ, _ := m["type"].(string)
+		if id != nil && typ == "public-key" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func coseKeyES256(pub *ecdsa.PublicKey) map[int]any {
+	return map[int]any{
+		1:  2,            // kty: EC2
+		3:  coseAlgES256, // alg
+		-1: 1,            // crv: P-256
+		-2: pub.X.FillBytes(make([]byte, 32)),
+		-3: pub.Y.FillBytes(make([]byte, 32)),
+	}
+}
+
+func makeAuthData(rpIDHash []byte, flags byte, signCount uint32, attestedCredentialData []byte) []byte {
+	authData := make([]byte, 0, 37+len(attestedCredentialData))
+	authData = append(authData, rpIDHash...)
+	authData = append(authData, flags)
+	authData = binary.BigEndian.AppendUint32(authData, signCount)
+	return append(authData, attestedCredentialData...)
+}
+
+// sign returns the ASN.1 encoded ECDSA signature over the SHA-256 hash of the concatenated parts.
+func sign(key *ecdsa.PrivateKey, parts ...[]byte) ([]byte, error) {
+	digest := sha256.Sum256(bytes.Join(parts, nil))
+	return ecdsa.SignASN1(rand.Reader, key, digest[:])
+}
+
+func getValue(m map[any]any, key any, required bool) (any, error) {
+	v, ok := m[key]
+	if !ok {
+		if required {
+			return nil, ctap2ErrMissingParameter
+		}
+		return nil, nil
+	}
+	return v, nil
+}
+
+func getBytes(m map[any]any, key any, required bool) ([]byte, error) {
+	v, err := getValue(m, key, required)
+	if v == nil {
+		return nil, err
+	}
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, ctap2ErrCBORUnexpectedType
+	}
+	return b, nil
+}
+
+func getString(m map[any]any, key any, required bool) (string, error) {
+	v, err := getValue(m, key, required)
+	if v == nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", ctap2ErrCBORUnexpectedType
+	}
+	return s, nil
+}
+
+func getMap(m map[any]any, key any, required bool) (map[any]any, error) {
+	v, err := getValue(m, key, required)
+	if v == nil {
+		return nil, err
+	}
+	mv, ok := v.(map[any]any)
+	if !ok {
+		return nil, ctap2ErrCBORUnexpectedType
+	}
+	return mv, nil
+}
+
+func getArray(m map[any]any, key any, required bool) ([]any, error) {
+	v, err := getValue(m, key, required)
+	if v == nil {
+		return nil, err
+	}
+	a, ok := v.([]any)
+	if !ok {
+		return nil, ctap2ErrCBORUnexpectedType
+	}
+	return a, nil
+}
+
+// getBool returns the option with the given name, or the default if it's not set.
+func getBool(options map[any]any, key string, defaultValue bool) (bool, error) {
+	v, ok := options[key]
+	if !ok {
+		return defaultValue, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, ctap2ErrCBORUnexpectedType
+	}
+	return b, nil
+}
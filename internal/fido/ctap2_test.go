@@ -0,0 +1,283 @@
+package fido
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *zerolog.Logger {
+	l := zerolog.Nop()
+	return &l
+}
+
+func newTestAuthenticator(t *testing.T, approve bool) (*Authenticator, *[]PresenceRequest) {
+	store, err := OpenStore(filepath.Join(t.TempDir(), "credentials"))
+	require.NoError(t, err)
+
+	requests := &[]PresenceRequest{}
+	return NewAuthenticator(store, func(ctx context.Context, req PresenceRequest) (bool, error) {
+		*requests = append(*requests, req)
+		return approve, nil
+	}), requests
+}
+
+func ctapRequest(t *testing.T, a *Authenticator, cmd byte, params map[int]any) (ctapStatus, map[any]any) {
+	req := []byte{cmd}
+	if params != nil {
+		encoded, err := cborMarshal(params)
+		require.NoError(t, err)
+		req = append(req, encoded...)
+	}
+
+	resp := a.HandleCBOR(t.Context(), req, nil)
+	require.NotEmpty(t, resp)
+	if resp[0] != byte(ctapOK) {
+		return ctapStatus(resp[0]), nil
+	}
+
+	decoded, err := cborUnmarshal(resp[1:])
+	require.NoError(t, err)
+	return ctapOK, decoded.(map[any]any)
+}
+
+func makeCredentialParams(rk bool) map[int]any {
+	return map[int]any{
+		1: make([]byte, 32),
+		2: map[string]any{"id": "example.com", "name": "Example"},
+		3: map[string]any{"id": []byte{0x42}, "name": "alice", "displayName": "Alice"},
+		4: []any{map[string]any{"alg": -7, "type": "public-key"}},
+		7: map[string]any{"rk": rk},
+	}
+}
+
+// parseAttestedCredential returns the credential ID and public key from the authenticator data.
+func parseAttestedCredential(t *testing.T, authData []byte) ([]byte, *ecdsa.PublicKey) {
+	require.Greater(t, len(authData), 55)
+	assert.Equal(t, byte(authDataFlagUP|authDataFlagAT), authData[32])
+	assert.Equal(t, AAGUID, authData[37:53])
+
+	idLen := int(binary.BigEndian.Uint16(authData[53:55]))
+	id := authData[55 : 55+idLen]
+
+	decoded, err := cborUnmarshal(authData[55+idLen:])
+	require.NoError(t, err)
+	coseKey := decoded.(map[any]any)
+	assert.Equal(t, int64(coseAlgES256), coseKey[int64(3)])
+
+	return id, &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(coseKey[int64(-2)].([]byte)),
+		Y:     new(big.Int).SetBytes(coseKey[int64(-3)].([]byte)),
+	}
+}
+
+func verify(t *testing.T, pub *ecdsa.PublicKey, sig []byte, parts ...[]byte) {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write(p)
+	}
+	assert.True(t, ecdsa.VerifyASN1(pub, h.Sum(nil), sig), "signature must verify")
+}
+
+func TestGetInfo(t *testing.T) {
+	a, _ := newTestAuthenticator(t, true)
+
+	status, info := ctapRequest(t, a, ctapGetInfo, nil)
+	require.Equal(t, ctapOK, status)
+	assert.Equal(t, []any{"U2F_V2", "FIDO_2_0"}, info[int64(1)])
+	assert.Equal(t, AAGUID, info[int64(3)])
+}
+
+func TestMakeCredentialAndGetAssertion(t *testing.T) {
+	a, requests := newTestAuthenticator(t, true)
+
+	status, resp := ctapRequest(t, a, ctapMakeCredential, makeCredentialParams(false))
+	require.Equal(t, ctapOK, status)
+	assert.Equal(t, "packed", resp[int64(1)])
+	authData := resp[int64(2)].([]byte)
+	id, pub := parseAttestedCredential(t, authData)
+
+	attStmt := resp[int64(3)].(map[any]any)
+	verify(t, pub, attStmt["sig"].([]byte), authData, make([]byte, 32))
+
+	clientDataHash := []byte("0123456789abcdef0123456789abcdef")
+	status, resp = ctapRequest(t, a, ctapGetAssertion, map[int]any{
+		1: "example.com",
+		2: clientDataHash,
+		3: []any{map[string]any{"id": id, "type": "public-key"}},
+	})
+	require.Equal(t, ctapOK, status)
+	assert.Equal(t, id, resp[int64(1)].(map[any]any)["id"])
+
+	authData = resp[int64(2)].([]byte)
+	assert.Equal(t, byte(authDataFlagUP), authData[32])
+	assert.Equal(t, uint32(1), binary.BigEndian.Uint32(authData[33:37]))
+	verify(t, pub, resp[int64(3)].([]byte), authData, clientDataHash)
+
+	assert.Equal(t, []PresenceRequest{
+		{Operation: "register", RPID: "example.com", UserName: "alice"},
+		{Operation: "authenticate", RPID: "example.com", UserName: "alice"},
+	}, *requests)
+
+	// the credential is bound to its relying party
+	status, _ = ctapRequest(t, a, ctapGetAssertion, map[int]any{
+		1: "evil.com",
+		2: clientDataHash,
+		3: []any{map[string]any{"id": id, "type": "public-key"}},
+	})
+	assert.Equal(t, ctap2ErrNoCredentials, status)
+}
+
+func TestResidentCredentials(t *testing.T) {
+	a, _ := newTestAuthenticator(t, true)
+
+	params := makeCredentialParams(true)
+	status, _ := ctapRequest(t, a, ctapMakeCredential, params)
+	require.Equal(t, ctapOK, status)
+
+	// a second user for the same relying party
+	params[3] = map[string]any{"id": []byte{0x43}, "name": "bob"}
+	status, _ = ctapRequest(t, a, ctapMakeCredential, params)
+	require.Equal(t, ctapOK, status)
+
+	status, resp := ctapRequest(t, a, ctapGetAssertion, map[int]any{1: "example.com", 2: make([]byte, 32)})
+	require.Equal(t, ctapOK, status)
+	assert.Equal(t, int64(2), resp[int64(5)])
+	assert.Equal(t, map[any]any{"id": []byte{0x43}}, resp[int64(4)], "most recent credential first")
+
+	status, resp = ctapRequest(t, a, ctapGetNextAssertion, nil)
+	require.Equal(t, ctapOK, status)
+	assert.Equal(t, map[any]any{"id": []byte{0x42}}, resp[int64(4)])
+
+	status, _ = ctapRequest(t, a, ctapGetNextAssertion, nil)
+	assert.Equal(t, ctap2ErrNotAllowed, status)
+}
+
+func TestPresenceDenied(t *testing.T) {
+	a, _ := newTestAuthenticator(t, false)
+
+	status, _ := ctapRequest(t, a, ctapMakeCredential, makeCredentialParams(false))
+	assert.Equal(t, ctap2ErrOperationDenied, status)
+	assert.Empty(t, a.store.List())
+}
+
+func TestPresenceCancelled(t *testing.T) {
+	a, _ := newTestAuthenticator(t, true)
+	a.presence = func(ctx context.Context, req PresenceRequest) (bool, error) {
+		<-ctx.Done()
+		return false, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	encoded, err := cborMarshal(makeCredentialParams(false))
+	require.NoError(t, err)
+	resp := a.HandleCBOR(ctx, append([]byte{ctapMakeCredential}, encoded...), nil)
+	assert.Equal(t, []byte{byte(ctap2ErrKeepaliveCancel)}, resp)
+}
+
+func TestUnsupportedAlgorithm(t *testing.T) {
+	a, requests := newTestAuthenticator(t, true)
+
+	params := makeCredentialParams(false)
+	params[4] = []any{map[string]any{"alg": -257, "type": "public-key"}}
+	status, _ := ctapRequest(t, a, ctapMakeCredential, params)
+	assert.Equal(t, ctap2ErrUnsupportedAlgorithm, status)
+	assert.Empty(t, *requests, "the user must not be prompted")
+}
+
+func TestU2FRegisterAndAuthenticate(t *testing.T) {
+	a, requests := newTestAuthenticator(t, true)
+
+	// the first request prompts the user in the background, the retries succeed once they approved
+	retry := func(apdu []byte) []byte {
+		var resp []byte
+		require.Eventually(t, func() bool {
+			resp = a.HandleU2F(apdu)
+			return len(resp) > 2
+		}, time.Second, time.Millisecond)
+		return resp
+	}
+
+	challenge := make([]byte, u2fChallengeSize)
+	appID := sha256.Sum256([]byte("https://example.com"))
+	apdu := append([]byte{0x00, u2fRegister, 0x00, 0x00, 64}, challenge...)
+	apdu = append(apdu, appID[:]...)
+
+	assert.Equal(t, []byte{0x69, 0x85}, a.HandleU2F(apdu))
+	resp := retry(apdu)
+	require.Equal(t, []byte{0x90, 0x00}, resp[len(resp)-2:])
+	require.Equal(t, u2fRegisterReserved, resp[0])
+
+	require.Equal(t, byte(0x04), resp[1], "uncompressed point")
+	pubKey := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(resp[2:34]),
+		Y:     new(big.Int).SetBytes(resp[34:66]),
+	}
+	keyHandle := resp[67 : 67+int(resp[66])]
+
+	// the certificate is followed by the signature, its length is in the DER header
+	rest := resp[67+len(keyHandle) : len(resp)-2]
+	require.Equal(t, []byte{0x30, 0x82}, rest[:2])
+	certLen := 4 + int(binary.BigEndian.Uint16(rest[2:4]))
+	cert, err := x509.ParseCertificate(rest[:certLen])
+	require.NoError(t, err)
+	verify(t, cert.PublicKey.(*ecdsa.PublicKey), rest[certLen:], []byte{0}, appID[:], challenge, keyHandle, resp[1:66])
+
+	authAPDU := append([]byte{0x00, u2fAuthenticate, u2fEnforcePresence, 0x00, byte(65 + len(keyHandle))}, challenge...)
+	authAPDU = append(authAPDU, appID[:]...)
+	authAPDU = append(authAPDU, byte(len(keyHandle)))
+	authAPDU = append(authAPDU, keyHandle...)
+
+	authAPDU[2] = u2fCheckOnly
+	assert.Equal(t, []byte{0x69, 0x85}, a.HandleU2F(authAPDU), "known key handle")
+
+	authAPDU[2] = u2fEnforcePresence
+	assert.Equal(t, []byte{0x69, 0x85}, a.HandleU2F(authAPDU))
+	resp = retry(authAPDU)
+	require.Equal(t, []byte{0x90, 0x00}, resp[len(resp)-2:])
+	assert.Equal(t, byte(authDataFlagUP), resp[0])
+	verify(t, pubKey, resp[5:len(resp)-2], appID[:], resp[0:1], resp[1:5], challenge)
+
+	assert.Len(t, *requests, 2)
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	store, err := OpenStore(path)
+	require.NoError(t, err)
+
+	cred, _, err := newCredential(make([]byte, 32))
+	require.NoError(t, err)
+	cred.RPID = "example.com"
+	require.NoError(t, store.Add(cred))
+
+	// the keys are only protected by the file permissions
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	store, err = OpenStore(path)
+	require.NoError(t, err)
+	infos := store.List()
+	require.Len(t, infos, 1)
+	assert.Equal(t, "example.com", infos[0].RPID)
+
+	require.NoError(t, store.Delete(infos[0].ID))
+	assert.Empty(t, store.List())
+	assert.ErrorIs(t, store.Delete(infos[0].ID), ErrCredentialNotFound)
+}
@@ -0,0 +1,376 @@
+package fido
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// CTAPHID framing, see FIDO CTAP 2.0 section 8.1.
+const (
+	ReportLength = 64
+
+	initDataLength = ReportLength - 7 // CID, CMD and BCNT
+	contDataLength = ReportLength - 5 // CID and SEQ
+	maxSeq         = 0x7f
+	maxMessageSize = initDataLength + (maxSeq+1)*contDataLength
+
+	broadcastCID = 0xffffffff
+
+	// messages have to be completed within this time, otherwise the channel is freed again
+	messageTimeout = 3 * time.Second
+	// keepalives are sent while a request is processed so the host doesn't time out
+	keepaliveInterval = 100 * time.Millisecond
+)
+
+const (
+	cmdPing      byte = 0x01
+	cmdMsg       byte = 0x03
+	cmdInit      byte = 0x06
+	cmdWink      byte = 0x08
+	cmdCBOR      byte = 0x10
+	cmdCancel    byte = 0x11
+	cmdKeepalive byte = 0x3b
+	cmdError     byte = 0x3f
+)
+
+const (
+	errInvalidCmd     byte = 0x01
+	errInvalidLen     byte = 0x03
+	errInvalidSeq     byte = 0x04
+	errMsgTimeout     byte = 0x05
+	errChannelBusy    byte = 0x06
+	errInvalidChannel byte = 0x0b
+)
+
+const (
+	keepaliveProcessing byte = 0x01
+	keepaliveUpNeeded   byte = 0x02
+)
+
+const (
+	ctaphidProtocolVersion = 2
+
+	capabilityWink = 0x01
+	capabilityCBOR = 0x04
+
+	deviceVersionMajor = 1
+	deviceVersionMinor = 0
+	deviceVersionBuild = 0
+)
+
+type message struct {
+	cid  uint32
+	cmd  byte
+	data []byte
+}
+
+// fragmentMessage splits a message into an initialization packet and as many continuation packets as needed.
+func fragmentMessage(cid uint32, cmd byte, data []byte) [][]byte {
+	reports := make([][]byte, 0, 1+len(data)/contDataLength)
+
+	report := make([]byte, ReportLength)
+	binary.BigEndian.PutUint32(report[0:4], cid)
+	report[4] = 0x80 | cmd
+	binary.BigEndian.PutUint16(report[5:7], uint16(len(data)))
+	n := copy(report[7:], data)
+	reports = append(reports, report)
+
+	for seq := byte(0); n < len(data); seq++ {
+		report = make([]byte, ReportLength)
+		binary.BigEndian.PutUint32(report[0:4], cid)
+		report[4] = seq
+		n += copy(report[5:], data[n:])
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// assembler reassembles a fragmented request, only one request can be in flight at a time.
+type assembler struct {
+	msg     *message
+	size    int
+	seq     byte
+	started time.Time
+}
+
+func (a *assembler) active() bool {
+	return a.msg != nil && time.Since(a.started) < messageTimeout
+}
+
+func (a *assembler) reset() {
+	a.msg = nil
+}
+
+// start begins a new message from an initialization packet, it returns the message if it's already complete.
+func (a *assembler) start(cid uint32, report []byte) *message {
+	size := int(binary.BigEndian.Uint16(report[5:7]))
+	data := report[7:]
+	a.msg = &message{cid: cid, cmd: report[4] &^ 0x80, data: make([]byte, 0, size)}
+	a.size = size
+	a.seq = 0
+	a.started = time.Now()
+	return a.append(data)
+}
+
+// next adds a continuation packet, it returns the message once it's complete.
+func (a *assembler) next(report []byte) (*message, byte) {
+	if report[4] != a.seq {
+		a.reset()
+		return nil, errInvalidSeq
+	}
+	a.seq++
+	return a.append(report[5:]), 0
+}
+
+func (a *assembler) append(data []byte) *message {
+	remaining := a.size - len(a.msg.data)
+	a.msg.data = append(a.msg.data, data[:min(remaining, len(data))]...)
+	if len(a.msg.data) < a.size {
+		return nil
+	}
+	msg := a.msg
+	a.reset()
+	return msg
+}
+
+type transaction struct {
+	cid    uint32
+	cancel context.CancelFunc
+}
+
+// Server implements the CTAPHID transport on top of a HID device that reads and writes 64 byte reports.
+type Server struct {
+	rw   io.ReadWriter
+	auth *Authenticator
+	log  *zerolog.Logger
+
+	writeLock sync.Mutex
+	lastCID   atomic.Uint32
+
+	// the reader and the request handler share these
+	lock      sync.Mutex
+	assembler assembler
+	busy      *transaction
+}
+
+// NewServer creates a CTAPHID server answering the requests with the authenticator.
+func NewServer(rw io.ReadWriter, auth *Authenticator, logger *zerolog.Logger) *Server {
+	return &Server{
+		rw:   rw,
+		auth: auth,
+		log:  logger,
+	}
+}
+
+// Serve reads and handles reports until reading fails, e.g. because the device has been closed.
+func (s *Server) Serve(ctx context.Context) error {
+	report := make([]byte, ReportLength)
+	for {
+		n, err := s.rw.Read(report)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if n < 7 {
+			s.log.Trace().Int("length", n).Msg("ignoring short CTAPHID report")
+			continue
+		}
+		clear(report[n:])
+		s.handleReport(ctx, report)
+	}
+}
+
+func (s *Server) handleReport(ctx context.Context, report []byte) {
+	cid := binary.BigEndian.Uint32(report[0:4])
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// continuation packet
+	if report[4]&0x80 == 0 {
+		if !s.assembler.active() || s.assembler.msg.cid != cid {
+			// spurious continuation packets are ignored
+			return
+		}
+		msg, errCode := s.assembler.next(report)
+		if errCode != 0 {
+			s.writeError(cid, errCode)
+			return
+		}
+		if msg != nil {
+			s.dispatch(ctx, msg)
+		}
+		return
+	}
+
+	cmd := report[4] &^ 0x80
+	if cid == 0 || (cid == broadcastCID && cmd != cmdInit) {
+		s.writeError(cid, errInvalidChannel)
+		return
+	}
+
+	if s.assembler.msg != nil && !s.assembler.active() {
+		s.writeError(s.assembler.msg.cid, errMsgTimeout)
+		s.assembler.reset()
+	}
+
+	switch cmd {
+	case cmdCancel:
+		if s.busy != nil && s.busy.cid == cid {
+			s.busy.cancel()
+		}
+		return
+	case cmdInit:
+		// INIT is handled even when busy, it aborts a pending message on the same channel
+		if s.assembler.active() && s.assembler.msg.cid == cid {
+			s.assembler.reset()
+		}
+	default:
+		if s.busy != nil || (s.assembler.active() && s.assembler.msg.cid != cid) {
+			s.writeError(cid, errChannelBusy)
+			return
+		}
+		if cid != broadcastCID && cid > s.lastCID.Load() {
+			s.writeError(cid, errInvalidChannel)
+			return
+		}
+	}
+
+	if int(binary.BigEndian.Uint16(report[5:7])) > maxMessageSize {
+		s.writeError(cid, errInvalidLen)
+		return
+	}
+
+	if cmd == cmdInit {
+		// INIT always fits in a single packet, don't let it disturb other channels
+		var a assembler
+		if msg := a.start(cid, report); msg != nil {
+			s.handleInit(msg)
+		} else {
+			s.writeError(cid, errInvalidLen)
+		}
+		return
+	}
+
+	if msg := s.assembler.start(cid, report); msg != nil {
+		s.dispatch(ctx, msg)
+	}
+}
+
+// dispatch must be called with the lock held.
+func (s *Server) dispatch(ctx context.Context, msg *message) {
+	switch msg.cmd {
+	case cmdPing:
+		s.writeMessage(msg.cid, cmdPing, msg.data)
+	case cmdWink:
+		s.writeMessage(msg.cid, cmdWink, nil)
+	case cmdMsg, cmdCBOR:
+		reqCtx, cancel := context.WithCancel(ctx)
+		s.busy = &transaction{cid: msg.cid, cancel: cancel}
+		go s.process(reqCtx, cancel, msg)
+	default:
+		s.writeError(msg.cid, errInvalidCmd)
+	}
+}
+
+func (s *Server) process(ctx context.Context, cancel context.CancelFunc, msg *message) {
+	defer func() {
+		cancel()
+		s.lock.Lock()
+		s.busy = nil
+		s.lock.Unlock()
+	}()
+
+	if msg.cmd == cmdMsg {
+		s.writeMessage(msg.cid, cmdMsg, s.auth.HandleU2F(msg.data))
+		return
+	}
+
+	var status atomic.Uint32
+	status.Store(uint32(keepaliveProcessing))
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(keepaliveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				s.writeMessage(msg.cid, cmdKeepalive, []byte{byte(status.Load())})
+			}
+		}
+	}()
+
+	resp := s.auth.HandleCBOR(ctx, msg.data, func() {
+		status.Store(uint32(keepaliveUpNeeded))
+	})
+	close(done)
+	s.writeMessage(msg.cid, cmdCBOR, resp)
+}
+
+func (s *Server) handleInit(msg *message) {
+	if len(msg.data) != 8 {
+		s.writeError(msg.cid, errInvalidLen)
+		return
+	}
+
+	cid := msg.cid
+	if cid == broadcastCID {
+		cid = s.allocateCID()
+	}
+
+	resp := make([]byte, 0, 17)
+	resp = append(resp, msg.data...) // nonce
+	resp = binary.BigEndian.AppendUint32(resp, cid)
+	resp = append(resp,
+		ctaphidProtocolVersion,
+		deviceVersionMajor,
+		deviceVersionMinor,
+		deviceVersionBuild,
+		capabilityWink|capabilityCBOR,
+	)
+	s.writeMessage(msg.cid, cmdInit, resp)
+}
+
+func (s *Server) allocateCID() uint32 {
+	for {
+		cid := s.lastCID.Add(1)
+		if cid != 0 && cid != broadcastCID {
+			return cid
+		}
+		// wrapped around, start over with a random channel
+		var b [4]byte
+		_, _ = rand.Read(b[:])
+		s.lastCID.Store(binary.BigEndian.Uint32(b[:]) >> 1)
+	}
+}
+
+func (s *Server) writeError(cid uint32, code byte) {
+	s.writeMessage(cid, cmdError, []byte{code})
+}
+
+func (s *Server) writeMessage(cid uint32, cmd byte, data []byte) {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	for _, report := range fragmentMessage(cid, cmd, data) {
+		if _, err := s.rw.Write(report); err != nil {
+			if !errors.Is(err, io.ErrClosedPipe) {
+				s.log.Warn().Err(err).Uint32("cid", cid).Msg("failed to write CTAPHID report")
+			}
+			return
+		}
+	}
+}
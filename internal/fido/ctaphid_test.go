@@ -0,0 +1,97 @@
+package fido
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFragmentAndAssemble(t *testing.T) {
+	data := make([]byte, initDataLength+2*contDataLength+10)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	reports := fragmentMessage(0x01020304, cmdCBOR, data)
+	require.Len(t, reports, 4)
+	for _, report := range reports {
+		assert.Len(t, report, ReportLength)
+	}
+	assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04, 0x80 | cmdCBOR}, reports[0][:5])
+	assert.Equal(t, byte(2), reports[3][4])
+
+	var a assembler
+	assert.Nil(t, a.start(0x01020304, reports[0]))
+	for i, report := range reports[1:] {
+		msg, errCode := a.next(report)
+		assert.Zero(t, errCode)
+		if i < 2 {
+			assert.Nil(t, msg)
+			continue
+		}
+		require.NotNil(t, msg)
+		assert.Equal(t, uint32(0x01020304), msg.cid)
+		assert.Equal(t, cmdCBOR, msg.cmd)
+		assert.Equal(t, data, msg.data)
+	}
+	assert.False(t, a.active())
+}
+
+func TestAssembleInvalidSequence(t *testing.T) {
+	reports := fragmentMessage(1, cmdMsg, make([]byte, 100))
+
+	var a assembler
+	assert.Nil(t, a.start(1, reports[0]))
+	reports[1][4] = 1
+	msg, errCode := a.next(reports[1])
+	assert.Nil(t, msg)
+	assert.Equal(t, errInvalidSeq, errCode)
+	assert.False(t, a.active())
+}
+
+// reportPipe feeds requests to the server and collects the responses.
+type reportPipe struct {
+	requests  [][]byte
+	responses bytes.Buffer
+}
+
+func (p *reportPipe) Read(b []byte) (int, error) {
+	if len(p.requests) == 0 {
+		return 0, bytes.ErrTooLarge
+	}
+	n := copy(b, p.requests[0])
+	p.requests = p.requests[1:]
+	return n, nil
+}
+
+func (p *reportPipe) Write(b []byte) (int, error) {
+	return p.responses.Write(b)
+}
+
+func TestServerInitAndPing(t *testing.T) {
+	nonce := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	pipe := &reportPipe{}
+	pipe.requests = append(pipe.requests, fragmentMessage(broadcastCID, cmdInit, nonce)...)
+	pipe.requests = append(pipe.requests, fragmentMessage(1, cmdPing, []byte("hello"))...)
+	pipe.requests = append(pipe.requests, fragmentMessage(7, cmdPing, nil)...)
+
+	s := NewServer(pipe, nil, testLogger())
+	require.Error(t, s.Serve(t.Context()))
+
+	responses := pipe.responses.Bytes()
+	require.Len(t, responses, 3*ReportLength)
+
+	init := responses[:ReportLength]
+	assert.Equal(t, []byte{0xff, 0xff, 0xff, 0xff, 0x80 | cmdInit, 0, 17}, init[:7])
+	assert.Equal(t, nonce, init[7:15])
+	assert.Equal(t, []byte{0, 0, 0, 1}, init[15:19], "first allocated channel")
+
+	ping := responses[ReportLength : 2*ReportLength]
+	assert.Equal(t, []byte{0, 0, 0, 1, 0x80 | cmdPing, 0, 5, 'h', 'e', 'l', 'l', 'o'}, ping[:12])
+
+	// channel 7 was never allocated
+	invalid := responses[2*ReportLength:]
+	assert.Equal(t, []byte{0, 0, 0, 7, 0x80 | cmdError, 0, 1, errInvalidChannel}, invalid[:8])
+}
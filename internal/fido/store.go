@@ -0,0 +1,314 @@
+package fido
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MaxCredentials limits the number of credentials in the store, every registration creates one.
+const MaxCredentials = 256
+
+const (
+	storeFileMode  = 0600
+	credentialSize = 32
+)
+
+var (
+	ErrStoreFull          = errors.New("credential store is full")
+	ErrCredentialNotFound = errors.New("credential not found")
+)
+
+// Credential is a credential including its private key, it never leaves the store.
+type Credential struct {
+	ID              []byte    `json:"id"`
+	RPID            string    `json:"rp_id,omitempty"` // empty for U2F registrations, they only know the hash
+	RPIDHash        []byte    `json:"rp_id_hash"`
+	UserID          []byte    `json:"user_id,omitempty"`
+	UserName        string    `json:"user_name,omitempty"`
+	UserDisplayName string    `json:"user_display_name,omitempty"`
+	PrivateKey      []byte    `json:"private_key"` // SEC 1 DER
+	SignCount       uint32    `json:"sign_count"`
+	Resident        bool      `json:"resident"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// CredentialInfo describes a credential without its key material.
+type CredentialInfo struct {
+	ID              string    `json:"id"` // base64url
+	RPID            string    `json:"rpId,omitempty"`
+	UserName        string    `json:"userName,omitempty"`
+	UserDisplayName string    `json:"userDisplayName,omitempty"`
+	SignCount       uint32    `json:"signCount"`
+	Resident        bool      `json:"resident"`
+	U2F             bool      `json:"u2f"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+func (c *Credential) privateKey() (*ecdsa.PrivateKey, error) {
+	return x509.ParseECPrivateKey(c.PrivateKey)
+}
+
+type storeData struct {
+	Credentials     []Credential `json:"credentials"`
+	AttestationKey  []byte       `json:"attestation_key,omitempty"`
+	AttestationCert []byte       `json:"attestation_cert,omitempty"`
+}
+
+// Store keeps the credentials in a file only readable by the owner. The keys aren't encrypted at rest,
+// the device has no place to keep an encryption key that isn't just as readable as the file itself.
+type Store struct {
+	path string
+
+	mu   sync.Mutex
+	data storeData
+}
+
+// OpenStore loads the store at path, a missing file results in an empty store.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credential store: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.data); err != nil {
+		return nil, fmt.Errorf("failed to parse credential store: %w", err)
+	}
+	return s, nil
+}
+
+// save must be called with the lock held.
+func (s *Store) save() error {
+	data, err := json.Marshal(s.data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create credential store directory: %w", err)
+	}
+	// write it atomically, a torn file would lose all credentials
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, storeFileMode); err != nil {
+		return fmt.Errorf("failed to write credential store: %w", err)
+	}
+	return os.Rename(tmpPath, s.path)
+}
+
+func (s *Store) indexOf(id []byte) int {
+	return slices.IndexFunc(s.data.Credentials, func(c Credential) bool {
+		return bytes.Equal(c.ID, id)
+	})
+}
+
+// newCredential generates the key pair and the ID of a new credential, it isn't added to the store yet.
+func newCredential(rpIDHash []byte) (*Credential, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id := make([]byte, credentialSize)
+	if _, err := rand.Read(id); err != nil {
+		return nil, nil, err
+	}
+
+	return &Credential{
+		ID:         id,
+		RPIDHash:   bytes.Clone(rpIDHash),
+		PrivateKey: der,
+		CreatedAt:  time.Now(),
+	}, key, nil
+}
+
+// Add stores the credential, a resident credential replaces the one of the same user for the same relying party.
+func (s *Store) Add(cred *Credential) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	credentials := s.data.Credentials
+	if cred.Resident {
+		credentials = slices.DeleteFunc(slices.Clone(credentials), func(c Credential) bool {
+			return c.Resident && bytes.Equal(c.RPIDHash, cred.RPIDHash) && bytes.Equal(c.UserID, cred.UserID)
+		})
+	}
+	if len(credentials) >= MaxCredentials {
+		return ErrStoreFull
+	}
+
+	previous := s.data.Credentials
+	s.data.Credentials = append(credentials, *cred)
+	if err := s.save(); err != nil {
+		s.data.Credentials = previous
+		return err
+	}
+	return nil
+}
+
+// Get returns a copy of the credential with the given ID if it belongs to the relying party.
+func (s *Store) Get(id []byte, rpIDHash []byte) (*Credential, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(id)
+	if i < 0 || !bytes.Equal(s.data.Credentials[i].RPIDHash, rpIDHash) {
+		return nil, false
+	}
+	cred := s.data.Credentials[i]
+	return &cred, true
+}
+
+// Resident returns the resident credentials of the relying party, the most recent one first.
+func (s *Store) Resident(rpIDHash []byte) []Credential {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	credentials := make([]Credential, 0)
+	for _, c := range s.data.Credentials {
+		if c.Resident && bytes.Equal(c.RPIDHash, rpIDHash) {
+			credentials = append(credentials, c)
+		}
+	}
+	slices.SortStableFunc(credentials, func(a, b Credential) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return credentials
+}
+
+// IncrementSignCount increments and returns the signature counter of the credential.
+func (s *Store) IncrementSignCount(id []byte) (uint32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(id)
+	if i < 0 {
+		return 0, ErrCredentialNotFound
+	}
+	s.data.Credentials[i].SignCount++
+	if err := s.save(); err != nil {
+		s.data.Credentials[i].SignCount--
+		return 0, err
+	}
+	return s.data.Credentials[i].SignCount, nil
+}
+
+// List returns the credentials without their key material.
+func (s *Store) List() []CredentialInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]CredentialInfo, 0, len(s.data.Credentials))
+	for _, c := range s.data.Credentials {
+		infos = append(infos, CredentialInfo{
+			ID:              base64.RawURLEncoding.EncodeToString(c.ID),
+			RPID:            c.RPID,
+			UserName:        c.UserName,
+			UserDisplayName: c.UserDisplayName,
+			SignCount:       c.SignCount,
+			Resident:        c.Resident,
+			U2F:             c.RPID == "",
+			CreatedAt:       c.CreatedAt,
+		})
+	}
+	return infos
+}
+
+// Delete removes the credential with the given base64url encoded ID.
+func (s *Store) Delete(id string) error {
+	rawID, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return fmt.Errorf("invalid credential id: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(rawID)
+	if i < 0 {
+		return ErrCredentialNotFound
+	}
+	previous := s.data.Credentials
+	s.data.Credentials = slices.Delete(slices.Clone(previous), i, i+1)
+	if err := s.save(); err != nil {
+		s.data.Credentials = previous
+		return err
+	}
+	return nil
+}
+
+// Reset deletes all credentials and the attestation key.
+func (s *Store) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data = storeData{}
+	return s.save()
+}
+
+// attestation returns the U2F attestation key and certificate, they're generated on first use.
+func (s *Store) attestation() (*ecdsa.PrivateKey, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.AttestationKey != nil {
+		key, err := x509.ParseECPrivateKey(s.data.AttestationKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, s.data.AttestationCert, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 63))
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "JetKVM U2F Attestation"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(20, 0, 0),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.data.AttestationKey = der
+	s.data.AttestationCert = cert
+	if err := s.save(); err != nil {
+		s.data.AttestationKey = nil
+		s.data.AttestationCert = nil
+		return nil, nil, err
+	}
+	return key, cert, nil
+}
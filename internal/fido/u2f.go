@@ -0,0 +1,251 @@
+package fido
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// U2F raw messages, see FIDO U2F Raw Message Formats.
+const (
+	u2fRegister     byte = 0x01
+	u2fAuthenticate byte = 0x02
+	u2fVersion      byte = 0x03
+
+	u2fCheckOnly        byte = 0x07
+	u2fEnforcePresence  byte = 0x03
+	u2fDontEnforce      byte = 0x08
+	u2fRegisterReserved byte = 0x05
+
+	u2fChallengeSize = 32
+	u2fAppIDSize     = 32
+)
+
+const (
+	swNoError                = 0x9000
+	swConditionsNotSatisfied = 0x6985
+	swWrongData              = 0x6a80
+	swWrongLength            = 0x6700
+	swClaNotSupported        = 0x6e00
+	swInsNotSupported        = 0x6d00
+)
+
+// an approval is only valid for the retry of the request it was given for
+const u2fPresenceValidity = 10 * time.Second
+
+// u2fPresence tracks the presence prompt of U2F requests. U2F has no keepalives, the authenticator
+// answers "conditions not satisfied" until the user approved and the host keeps retrying the request.
+type u2fPresence struct {
+	mu         sync.Mutex
+	key        string
+	pending    bool
+	approved   bool
+	approvedAt time.Time
+}
+
+// checkPresence returns true if the user approved the request, otherwise it prompts the user in the background.
+func (a *Authenticator) checkPresence(operation string, appID []byte) bool {
+	p := &a.u2f
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := operation + ":" + hex.EncodeToString(appID)
+	if p.key == key && p.approved && time.Since(p.approvedAt) < u2fPresenceValidity {
+		p.approved = false
+		return true
+	}
+	if p.pending || a.presence == nil {
+		return false
+	}
+
+	p.key = key
+	p.pending = true
+	p.approved = false
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), PresenceTimeout)
+		defer cancel()
+
+		// U2F only knows the hash of the application ID
+		approved, err := a.presence(ctx, PresenceRequest{Operation: operation, RPID: hex.EncodeToString(appID)})
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.pending = false
+		p.approved = err == nil && approved
+		p.approvedAt = time.Now()
+	}()
+	return false
+}
+
+// HandleU2F handles a U2F request APDU and returns the response APDU.
+func (a *Authenticator) HandleU2F(apdu []byte) []byte {
+	if len(apdu) < 4 {
+		return u2fStatus(swWrongLength)
+	}
+	if apdu[0] != 0 {
+		return u2fStatus(swClaNotSupported)
+	}
+
+	data, err := apduData(apdu)
+	if err != nil {
+		return u2fStatus(swWrongLength)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var resp []byte
+	switch apdu[1] {
+	case u2fRegister:
+		resp, err = a.u2fRegister(data)
+	case u2fAuthenticate:
+		resp, err = a.u2fAuthenticate(apdu[2], data)
+	case u2fVersion:
+		if len(data) != 0 {
+			return u2fStatus(swWrongLength)
+		}
+		resp = []byte("U2F_V2")
+	default:
+		return u2fStatus(swInsNotSupported)
+	}
+
+	if err != nil {
+		var sw u2fStatusWord
+		if errors.As(err, &sw) {
+			return u2fStatus(sw)
+		}
+		return u2fStatus(swWrongData)
+	}
+	return append(resp, u2fStatus(swNoError)...)
+}
+
+type u2fStatusWord uint16
+
+func (sw u2fStatusWord) Error() string {
+	return "U2F status " + hex.EncodeToString(u2fStatus(sw))
+}
+
+func u2fStatus(sw u2fStatusWord) []byte {
+	return binary.BigEndian.AppendUint16(nil, uint16(sw))
+}
+
+// apduData returns the request data of a short or extended length encoded APDU.
+func apduData(apdu []byte) ([]byte, error) {
+	body := apdu[4:]
+	switch {
+	case len(body) == 0:
+		return nil, nil
+	case body[0] == 0 && len(body) >= 3:
+		// extended length, optionally followed by two bytes of Le
+		lc := int(binary.BigEndian.Uint16(body[1:3]))
+		if len(body) != 3+lc && len(body) != 5+lc && !(lc == 0 && len(body) == 3) {
+			return nil, errors.New("invalid extended length")
+		}
+		return body[3 : 3+lc], nil
+	default:
+		lc := int(body[0])
+		if len(body) != 1+lc && len(body) != 2+lc {
+			return nil, errors.New("invalid length")
+		}
+		return body[1 : 1+lc], nil
+	}
+}
+
+func (a *Authenticator) u2fRegister(data []byte) ([]byte, error) {
+	if len(data) != u2fChallengeSize+u2fAppIDSize {
+		return nil, u2fStatusWord(swWrongLength)
+	}
+	challenge := data[:u2fChallengeSize]
+	appID := data[u2fChallengeSize:]
+
+	if !a.checkPresence("register", appID) {
+		return nil, u2fStatusWord(swConditionsNotSatisfied)
+	}
+
+	attestationKey, attestationCert, err := a.store.attestation()
+	if err != nil {
+		return nil, err
+	}
+
+	cred, key, err := newCredential(appID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.store.Add(cred); err != nil {
+		return nil, err
+	}
+
+	// the ECDH encoding of the public key is the uncompressed point U2F expects
+	ecdhKey, err := key.PublicKey.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	pub := ecdhKey.Bytes()
+	sig, err := sign(attestationKey, []byte{0x00}, appID, challenge, cred.ID, pub)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]byte, 0, 1+len(pub)+1+len(cred.ID)+len(attestationCert)+len(sig))
+	resp = append(resp, u2fRegisterReserved)
+	resp = append(resp, pub...)
+	resp = append(resp, byte(len(cred.ID)))
+	resp = append(resp, cred.ID...)
+	resp = append(resp, attestationCert...)
+	return append(resp, sig...), nil
+}
+
+func (a *Authenticator) u2fAuthenticate(control byte, data []byte) ([]byte, error) {
+	if len(data) < u2fChallengeSize+u2fAppIDSize+1 {
+		return nil, u2fStatusWord(swWrongLength)
+	}
+	challenge := data[:u2fChallengeSize]
+	appID := data[u2fChallengeSize : u2fChallengeSize+u2fAppIDSize]
+	keyHandle := data[u2fChallengeSize+u2fAppIDSize+1:]
+	if len(keyHandle) != int(data[u2fChallengeSize+u2fAppIDSize]) {
+		return nil, u2fStatusWord(swWrongLength)
+	}
+
+	cred, ok := a.store.Get(keyHandle, appID)
+	if !ok {
+		return nil, u2fStatusWord(swWrongData)
+	}
+
+	var flags byte
+	switch control {
+	case u2fCheckOnly:
+		// the key handle is valid, that's all the host wants to know
+		return nil, u2fStatusWord(swConditionsNotSatisfied)
+	case u2fEnforcePresence:
+		if !a.checkPresence("authenticate", appID) {
+			return nil, u2fStatusWord(swConditionsNotSatisfied)
+		}
+		flags = authDataFlagUP
+	case u2fDontEnforce:
+	default:
+		return nil, u2fStatusWord(swWrongData)
+	}
+
+	key, err := cred.privateKey()
+	if err != nil {
+		return nil, err
+	}
+	counter, err := a.store.IncrementSignCount(cred.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	counterBytes := binary.BigEndian.AppendUint32(nil, counter)
+	sig, err := sign(key, appID, []byte{flags}, counterBytes, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]byte, 0, 1+len(counterBytes)+len(sig))
+	resp = append(resp, flags)
+	resp = append(resp, counterBytes...)
+	return append(resp, sig...), nil
+}
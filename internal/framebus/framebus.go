@@ -0,0 +1,181 @@
+// Package framebus fans the encoded video frames out to any number of consumers, like WebRTC
+// sessions, recorders and snapshotters. Every consumer has its own bounded buffer, so a slow
+// consumer loses frames instead of blocking the capture.
+package framebus
+
+import (
+	"sync"
+	"time"
+)
+
+const DefaultBuffer = 32
+
+// Frame is an H.264 access unit, consumers share it and must not modify Data.
+type Frame struct {
+	Data     []byte
+	Duration time.Duration
+	Keyframe bool
+	Received time.Time
+}
+
+// DropPolicy decides which frames a consumer loses when its buffer is full.
+type DropPolicy int
+
+const (
+	// DropUntilKeyframe drops the frame that doesn't fit and all frames after it until the next
+	// keyframe, so the consumer always gets a stream it can decode.
+	DropUntilKeyframe DropPolicy = iota
+	// DropOldest makes room by dropping the oldest buffered frame, for consumers that only care
+	// about the latest frames, e.g. a snapshotter of keyframes.
+	DropOldest
+)
+
+type Options struct {
+	// Name identifies the consumer in the stats
+	Name string
+	// Buffer is the number of frames the consumer can fall behind, DefaultBuffer if 0
+	Buffer int
+	Policy DropPolicy
+	// WaitForKeyframe joins the stream at the next keyframe instead of the next frame
+	WaitForKeyframe bool
+	// KeyframesOnly only delivers keyframes
+	KeyframesOnly bool
+}
+
+type Stats struct {
+	Name      string `json:"name"`
+	Delivered uint64 `json:"delivered"`
+	Dropped   uint64 `json:"dropped"`
+	Buffered  int    `json:"buffered"`
+}
+
+// Subscriber receives the frames of the bus until it's closed.
+type Subscriber struct {
+	bus       *Bus
+	opts      Options
+	frames    chan Frame
+	waiting   bool // for a keyframe
+	delivered uint64
+	dropped   uint64
+}
+
+// Frames returns the channel of the frames, it's closed when the subscriber is closed.
+func (s *Subscriber) Frames() <-chan Frame {
+	return s.frames
+}
+
+// Close stops the delivery of frames, it's safe to call more than once.
+func (s *Subscriber) Close() {
+	b := s.bus
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, ok := b.subscribers[s]; ok {
+		delete(b.subscribers, s)
+		close(s.frames)
+	}
+}
+
+func (s *Subscriber) Stats() Stats {
+	s.bus.lock.Lock()
+	defer s.bus.lock.Unlock()
+	return s.stats()
+}
+
+func (s *Subscriber) stats() Stats {
+	return Stats{Name: s.opts.Name, Delivered: s.delivered, Dropped: s.dropped, Buffered: len(s.frames)}
+}
+
+// deliver never blocks, it's called with the lock of the bus held.
+func (s *Subscriber) deliver(frame Frame) {
+	if s.opts.KeyframesOnly && !frame.Keyframe {
+		return
+	}
+	if s.waiting {
+		if !frame.Keyframe {
+			s.dropped++
+			return
+		}
+		s.waiting = false
+	}
+
+	select {
+	case s.frames <- frame:
+		s.delivered++
+		return
+	default:
+	}
+
+	s.dropped++
+	switch s.opts.Policy {
+	case DropUntilKeyframe:
+		s.waiting = true
+	case DropOldest:
+		select {
+		case <-s.frames:
+		default:
+		}
+		select {
+		case s.frames <- frame:
+			s.delivered++
+		default:
+			s.dropped++
+		}
+	}
+}
+
+// Bus distributes the frames of one video source.
+type Bus struct {
+	lock        sync.Mutex
+	subscribers map[*Subscriber]struct{}
+}
+
+func New() *Bus {
+	return &Bus{subscribers: make(map[*Subscriber]struct{})}
+}
+
+// Subscribe adds a consumer, it gets the frames published from now on.
+func (b *Bus) Subscribe(opts Options) *Subscriber {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultBuffer
+	}
+	s := &Subscriber{
+		bus:     b,
+		opts:    opts,
+		frames:  make(chan Frame, opts.Buffer),
+		waiting: opts.WaitForKeyframe,
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.subscribers[s] = struct{}{}
+	return s
+}
+
+// Publish hands the frame to every subscriber without waiting for any of them.
+func (b *Bus) Publish(data []byte, duration time.Duration) {
+	frame := Frame{
+		Data:     data,
+		Duration: duration,
+		Keyframe: IsKeyframe(data),
+		Received: time.Now(),
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for s := range b.subscribers {
+		s.deliver(frame)
+	}
+}
+
+// Stats returns the stats of every subscriber.
+func (b *Bus) Stats() []Stats {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	stats := make([]Stats, 0, len(b.subscribers))
+	for s := range b.subscribers {
+		stats = append(stats, s.stats())
+	}
+	return stats
+}
@@ -0,0 +1,166 @@
+package framebus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	keyframe   = []byte{0, 0, 0, 1, 0x67, 0x42, 0, 0, 0, 1, 0x68, 0xce, 0, 0, 0, 1, 0x65, 0x88}
+	deltaFrame = []byte{0, 0, 0, 1, 0x41, 0x9a}
+)
+
+// receive returns the buffered frames
+func receive(s *Subscriber) []Frame {
+	var frames []Frame
+	for {
+		select {
+		case frame := <-s.Frames():
+			frames = append(frames, frame)
+		default:
+			return frames
+		}
+	}
+}
+
+func TestIsKeyframe(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"idr with parameter sets", keyframe, true},
+		{"idr with 3 byte start code", []byte{0, 0, 1, 0x65, 0x88}, true},
+		{"sei before idr", []byte{0, 0, 0, 1, 0x06, 0x05, 0, 0, 1, 0x25, 0x88}, true},
+		{"non-idr slice", deltaFrame, false},
+		{"parameter sets only", []byte{0, 0, 0, 1, 0x67, 0x42, 0, 0, 0, 1, 0x68, 0xce}, false},
+		{"empty", nil, false},
+		{"no start code", []byte{0x65, 0x88}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsKeyframe(tt.data))
+		})
+	}
+}
+
+func TestPublish(t *testing.T) {
+	bus := New()
+	a := bus.Subscribe(Options{Name: "a"})
+	b := bus.Subscribe(Options{Name: "b"})
+
+	bus.Publish(deltaFrame, 33*time.Millisecond)
+	bus.Publish(keyframe, 33*time.Millisecond)
+
+	for _, s := range []*Subscriber{a, b} {
+		frames := receive(s)
+		require.Len(t, frames, 2)
+		assert.False(t, frames[0].Keyframe)
+		assert.True(t, frames[1].Keyframe)
+		assert.Equal(t, 33*time.Millisecond, frames[1].Duration)
+	}
+}
+
+func TestWaitForKeyframe(t *testing.T) {
+	bus := New()
+	s := bus.Subscribe(Options{WaitForKeyframe: true})
+
+	bus.Publish(deltaFrame, 0)
+	bus.Publish(keyframe, 0)
+	bus.Publish(deltaFrame, 0)
+
+	frames := receive(s)
+	require.Len(t, frames, 2)
+	assert.True(t, frames[0].Keyframe)
+	assert.Equal(t, Stats{Delivered: 2, Dropped: 1}, s.Stats())
+}
+
+func TestKeyframesOnly(t *testing.T) {
+	bus := New()
+	s := bus.Subscribe(Options{KeyframesOnly: true})
+
+	bus.Publish(keyframe, 0)
+	bus.Publish(deltaFrame, 0)
+	bus.Publish(keyframe, 0)
+
+	frames := receive(s)
+	require.Len(t, frames, 2)
+	for _, frame := range frames {
+		assert.True(t, frame.Keyframe)
+	}
+}
+
+func TestDropUntilKeyframe(t *testing.T) {
+	bus := New()
+	s := bus.Subscribe(Options{Buffer: 2})
+
+	bus.Publish(keyframe, 0)
+	bus.Publish(deltaFrame, 0)
+	// the buffer is full, the frames are dropped until the next keyframe
+	bus.Publish(deltaFrame, 0)
+	require.Len(t, receive(s), 2)
+	bus.Publish(deltaFrame, 0)
+	assert.Empty(t, receive(s))
+
+	bus.Publish(keyframe, 0)
+	bus.Publish(deltaFrame, 0)
+	frames := receive(s)
+	require.Len(t, frames, 2)
+	assert.True(t, frames[0].Keyframe)
+	assert.Equal(t, Stats{Delivered: 4, Dropped: 2}, s.Stats())
+}
+
+func TestDropOldest(t *testing.T) {
+	bus := New()
+	s := bus.Subscribe(Options{Buffer: 2, Policy: DropOldest})
+
+	for i := range 4 {
+		bus.Publish([]byte{byte(i)}, 0)
+	}
+
+	frames := receive(s)
+	require.Len(t, frames, 2)
+	assert.Equal(t, []byte{2}, frames[0].Data)
+	assert.Equal(t, []byte{3}, frames[1].Data)
+	assert.Equal(t, uint64(2), s.Stats().Dropped)
+}
+
+func TestClose(t *testing.T) {
+	bus := New()
+	s := bus.Subscribe(Options{Name: "closed"})
+	other := bus.Subscribe(Options{Name: "open"})
+
+	s.Close()
+	s.Close()
+	bus.Publish(keyframe, 0)
+
+	_, ok := <-s.Frames()
+	assert.False(t, ok)
+	assert.Len(t, receive(other), 1)
+
+	stats := bus.Stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, "open", stats[0].Name)
+}
+
+func TestPublishDoesNotBlock(t *testing.T) {
+	bus := New()
+	s := bus.Subscribe(Options{Buffer: 1})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			bus.Publish(keyframe, 0)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a subscriber that doesn't read")
+	}
+	assert.Equal(t, uint64(99), s.Stats().Dropped)
+}
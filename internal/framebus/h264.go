@@ -0,0 +1,24 @@
+package framebus
+
+// H.264 NAL unit types, see ITU-T H.264 table 7-1
+const (
+	nalSlice    = 1
+	nalSliceIDR = 5
+)
+
+// IsKeyframe reports whether the H.264 access unit in Annex B format starts with an IDR picture,
+// a decoder can start decoding the stream at such a frame.
+func IsKeyframe(data []byte) bool {
+	for i := 0; i+3 < len(data); i++ {
+		if data[i] != 0 || data[i+1] != 0 || data[i+2] != 1 {
+			continue
+		}
+		nalType := data[i+3] & 0x1f
+		// the picture starts with its first slice, the parameter sets and SEI come before it
+		if nalType >= nalSlice && nalType <= nalSliceIDR {
+			return nalType == nalSliceIDR
+		}
+		i += 3
+	}
+	return false
+}
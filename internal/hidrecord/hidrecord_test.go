@@ -0,0 +1,131 @@
+package hidrecord
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jetkvm/kvm/internal/hidrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDevice struct {
+	calls []string
+	times []time.Time
+}
+
+func (d *fakeDevice) add(call string) error {
+	d.calls = append(d.calls, call)
+	d.times = append(d.times, time.Now())
+	return nil
+}
+
+func (d *fakeDevice) KeyboardReport(modifier byte, keys []byte) error {
+	return d.add(fmt.Sprintf("keyboard %d %v", modifier, keys))
+}
+
+func (d *fakeDevice) KeypressReport(key byte, press bool) error {
+	return d.add(fmt.Sprintf("keypress %d %v", key, press))
+}
+
+func (d *fakeDevice) KeypressKeepAlive() error {
+	return d.add("keepAlive")
+}
+
+func (d *fakeDevice) AbsMouseReport(x, y int, buttons uint8) error {
+	return d.add(fmt.Sprintf("abs %d %d %d", x, y, buttons))
+}
+
+func (d *fakeDevice) RelMouseReport(dx, dy int8, buttons uint8) error {
+	return d.add(fmt.Sprintf("rel %d %d %d", dx, dy, buttons))
+}
+
+func (d *fakeDevice) WheelReport(wheelY int8) error {
+	return d.add(fmt.Sprintf("wheel %d", wheelY))
+}
+
+func (d *fakeDevice) Macro(ctx context.Context, steps []hidrpc.KeyboardMacroStep) error {
+	return d.add(fmt.Sprintf("macro %d", len(steps)))
+}
+
+func TestWriteRead(t *testing.T) {
+	var events bytes.Buffer
+	r := NewRecorder("bios", &events)
+	r.Record(Event{Type: EventKeyboard, Modifier: 2, Keys: KeysFromBytes([]byte{4, 0, 0, 0, 0, 0})})
+	r.Record(Event{Type: EventAbsMouse, X: 100, Y: 200, Buttons: 1})
+	r.Record(MacroEvent([]hidrpc.KeyboardMacroStep{
+		{Modifier: 1, Keys: []byte{5, 0, 0, 0, 0, 0}, Delay: 20},
+		{Kind: hidrpc.MacroStepAbsMove, X: 10, Y: 20, Delay: 50},
+	}))
+	header, err := r.Stop()
+	require.NoError(t, err)
+	assert.Equal(t, 3, header.Events)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, header, &events))
+
+	readHeader, err := ReadHeader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, header, *readHeader)
+
+	read, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "bios", read.Name)
+	require.Len(t, read.Events, 3)
+	assert.Equal(t, header.Duration, read.Events[2].Time)
+	assert.Equal(t, Event{Type: EventAbsMouse, Time: read.Events[1].Time, X: 100, Y: 200, Buttons: 1}, read.Events[1])
+	assert.Equal(t, []hidrpc.KeyboardMacroStep{
+		{Modifier: 1, Keys: []byte{5, 0, 0, 0, 0, 0}, Delay: 20},
+		{Kind: hidrpc.MacroStepAbsMove, Keys: make([]byte, 6), X: 10, Y: 20, Delay: 50},
+	}, read.Events[2].MacroSteps())
+}
+
+func TestReadUnsupportedVersion(t *testing.T) {
+	_, err := Read(bytes.NewBufferString(`{"version":2,"name":"future"}` + "\n"))
+	assert.ErrorContains(t, err, "unsupported recording version 2")
+}
+
+func TestReplay(t *testing.T) {
+	rec := &Recording{Events: []Event{
+		{Time: 0, Type: EventKeypress, Key: 4, Press: true},
+		{Time: 50, Type: EventKeepAlive},
+		{Time: 100, Type: EventKeypress, Key: 4},
+		{Time: 200, Type: EventRelMouse, X: 300, Y: -5},
+		{Time: 200, Type: EventWheel, Y: -1},
+		{Time: 300, Type: EventMacro, Macro: []MacroStep{{Delay: 10}}},
+	}}
+
+	dev := &fakeDevice{}
+	start := time.Now()
+	require.NoError(t, Replay(context.Background(), rec, 2, dev))
+
+	assert.Equal(t, []string{
+		"keypress 4 true",
+		"keepAlive",
+		"keypress 4 false",
+		"rel 127 -5 0",
+		"wheel -1",
+		"macro 1",
+	}, dev.calls)
+	// at double speed the last event is sent after 150ms
+	assert.InDelta(t, 150, dev.times[5].Sub(start).Milliseconds(), 40)
+}
+
+func TestReplayCancel(t *testing.T) {
+	rec := &Recording{Events: []Event{
+		{Time: 0, Type: EventKeypress, Key: 4, Press: true},
+		{Time: 60000, Type: EventKeypress, Key: 4},
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	dev := &fakeDevice{}
+	assert.ErrorIs(t, Replay(ctx, rec, 1, dev), context.DeadlineExceeded)
+	assert.Equal(t, []string{"keypress 4 true"}, dev.calls)
+
+	assert.ErrorContains(t, Replay(context.Background(), rec, 20, dev), "speed must be between")
+}
@@ -0,0 +1,264 @@
+// Package hidrecord records HID input with its timing and replays it.
+//
+// A recording file is JSON lines: a Header, followed by one Event per line.
+package hidrecord
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jetkvm/kvm/internal/hidrpc"
+)
+
+// FormatVersion is the version of the file format, it's increased on incompatible changes.
+const FormatVersion = 1
+
+// MaxEvents limits the size of a recording, a busy pointer sends about 60 events per second,
+// so it's about half an hour of constant pointer movement. Replaying loads all events in memory.
+const MaxEvents = 100000
+
+var ErrTooManyEvents = fmt.Errorf("recording is limited to %d events", MaxEvents)
+
+type EventType string
+
+const (
+	EventKeyboard EventType = "keyboard"
+	EventKeypress EventType = "keypress"
+	// EventKeepAlive keeps the pressed keys from being released automatically
+	EventKeepAlive EventType = "keepAlive"
+	EventAbsMouse  EventType = "absMouse"
+	EventRelMouse  EventType = "relMouse"
+	EventWheel     EventType = "wheel"
+	EventMacro     EventType = "macro"
+)
+
+// MacroStep is a hidrpc.KeyboardMacroStep in the file format.
+type MacroStep struct {
+	Kind     hidrpc.MacroStepKind `json:"kind,omitempty"`
+	Modifier byte                 `json:"modifier,omitempty"`
+	Keys     []int                `json:"keys,omitempty"`
+	X        int16                `json:"x,omitempty"`
+	Y        int16                `json:"y,omitempty"`
+	Button   uint8                `json:"button,omitempty"`
+	Delay    uint16               `json:"delay"`
+}
+
+// Event is a single HID input, the fields that are used depend on the type.
+type Event struct {
+	// Time is the offset from the start of the recording in milliseconds
+	Time int64     `json:"t"`
+	Type EventType `json:"type"`
+
+	Modifier byte  `json:"modifier,omitempty"`
+	Keys     []int `json:"keys,omitempty"`
+	Key      byte  `json:"key,omitempty"`
+	Press    bool  `json:"press,omitempty"`
+
+	// absolute position, relative movement or wheel movement in Y
+	X       int   `json:"x,omitempty"`
+	Y       int   `json:"y,omitempty"`
+	Buttons uint8 `json:"buttons,omitempty"`
+
+	Macro []MacroStep `json:"macro,omitempty"`
+}
+
+// Header is the first line of a recording file.
+type Header struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Duration is the time of the last event in milliseconds
+	Duration int64 `json:"duration"`
+	Events   int   `json:"events"`
+}
+
+type Recording struct {
+	Header
+	Events []Event
+}
+
+// KeysFromBytes converts HID key codes to the file format.
+func KeysFromBytes(keys []byte) []int {
+	if len(keys) == 0 {
+		return nil
+	}
+	ints := make([]int, len(keys))
+	for i, k := range keys {
+		ints[i] = int(k)
+	}
+	return ints
+}
+
+func keysToBytes(keys []int) []byte {
+	b := make([]byte, len(keys))
+	for i, k := range keys {
+		b[i] = byte(k)
+	}
+	return b
+}
+
+// MacroEvent returns the event of a macro report.
+func MacroEvent(steps []hidrpc.KeyboardMacroStep) Event {
+	macro := make([]MacroStep, len(steps))
+	for i, s := range steps {
+		macro[i] = MacroStep{
+			Kind:     s.Kind,
+			Modifier: s.Modifier,
+			Keys:     KeysFromBytes(s.Keys),
+			X:        s.X,
+			Y:        s.Y,
+			Button:   s.Button,
+			Delay:    s.Delay,
+		}
+	}
+	return Event{Type: EventMacro, Macro: macro}
+}
+
+// MacroSteps returns the steps of a macro event.
+func (e Event) MacroSteps() []hidrpc.KeyboardMacroStep {
+	steps := make([]hidrpc.KeyboardMacroStep, len(e.Macro))
+	for i, s := range e.Macro {
+		keys := make([]byte, hidrpc.HidKeyBufferSize)
+		copy(keys, keysToBytes(s.Keys))
+		steps[i] = hidrpc.KeyboardMacroStep{
+			Kind:     s.Kind,
+			Modifier: s.Modifier,
+			Keys:     keys,
+			X:        s.X,
+			Y:        s.Y,
+			Button:   s.Button,
+			Delay:    s.Delay,
+		}
+	}
+	return steps
+}
+
+// Recorder writes the events as they come in, so a long recording doesn't take up memory.
+// The header holds the totals, it's written in front of the events by Write once the recording stops.
+// It's safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	header  Header
+	start   time.Time
+	w       *bufio.Writer
+	enc     *json.Encoder
+	stopped bool
+	err     error
+}
+
+// NewRecorder returns a recorder that writes the events to w.
+func NewRecorder(name string, w io.Writer) *Recorder {
+	now := time.Now()
+	bw := bufio.NewWriter(w)
+	return &Recorder{
+		header: Header{Version: FormatVersion, Name: name, CreatedAt: now.UTC()},
+		start:  now,
+		w:      bw,
+		enc:    json.NewEncoder(bw),
+	}
+}
+
+// Record adds the event with the time since the recording started, the events after an error are dropped.
+func (r *Recorder) Record(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil || r.stopped {
+		return
+	}
+	if r.header.Events >= MaxEvents {
+		r.err = ErrTooManyEvents
+		return
+	}
+	e.Time = time.Since(r.start).Milliseconds()
+	if err := r.enc.Encode(e); err != nil {
+		r.err = fmt.Errorf("failed to write event: %w", err)
+		return
+	}
+	r.header.Events++
+	r.header.Duration = e.Time
+}
+
+// Name returns the name of the recording.
+func (r *Recorder) Name() string {
+	return r.header.Name
+}
+
+// Events returns the number of recorded events.
+func (r *Recorder) Events() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.header.Events
+}
+
+// Stop flushes the events and returns the header of the recording,
+// the error is set if events were dropped.
+func (r *Recorder) Stop() (Header, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stopped = true
+	if err := r.w.Flush(); err != nil && r.err == nil {
+		r.err = fmt.Errorf("failed to write events: %w", err)
+	}
+	return r.header, r.err
+}
+
+// Write writes a recording in the file format, events are the lines written by a Recorder.
+func Write(w io.Writer, header Header, events io.Reader) error {
+	bw := bufio.NewWriter(w)
+	if err := json.NewEncoder(bw).Encode(header); err != nil {
+		return err
+	}
+	if _, err := io.Copy(bw, events); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func decodeHeader(dec *json.Decoder) (*Header, error) {
+	var header Header
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("invalid recording header: %w", err)
+	}
+	if header.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported recording version %d", header.Version)
+	}
+	return &header, nil
+}
+
+// ReadHeader reads only the header of a recording.
+func ReadHeader(r io.Reader) (*Header, error) {
+	return decodeHeader(json.NewDecoder(r))
+}
+
+// Read reads a recording in the file format.
+func Read(r io.Reader) (*Recording, error) {
+	dec := json.NewDecoder(r)
+	header, err := decodeHeader(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &Recording{Header: *header}
+	for {
+		var e Event
+		err := dec.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid event %d: %w", len(rec.Events)+1, err)
+		}
+		if len(rec.Events) >= MaxEvents {
+			return nil, ErrTooManyEvents
+		}
+		rec.Events = append(rec.Events, e)
+	}
+	return rec, nil
+}
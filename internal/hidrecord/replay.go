@@ -0,0 +1,85 @@
+package hidrecord
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jetkvm/kvm/internal/hidrpc"
+)
+
+const (
+	MinSpeed = 0.1
+	MaxSpeed = 10.0
+)
+
+// Device receives the replayed events.
+type Device interface {
+	KeyboardReport(modifier byte, keys []byte) error
+	KeypressReport(key byte, press bool) error
+	KeypressKeepAlive() error
+	AbsMouseReport(x, y int, buttons uint8) error
+	RelMouseReport(dx, dy int8, buttons uint8) error
+	WheelReport(wheelY int8) error
+	// Macro runs a macro and returns when it's done or ctx is cancelled
+	Macro(ctx context.Context, steps []hidrpc.KeyboardMacroStep) error
+}
+
+func clampInt8(v int) int8 {
+	return int8(max(-127, min(127, v)))
+}
+
+func replayEvent(ctx context.Context, dev Device, e Event) error {
+	switch e.Type {
+	case EventKeyboard:
+		return dev.KeyboardReport(e.Modifier, keysToBytes(e.Keys))
+	case EventKeypress:
+		return dev.KeypressReport(e.Key, e.Press)
+	case EventKeepAlive:
+		return dev.KeypressKeepAlive()
+	case EventAbsMouse:
+		return dev.AbsMouseReport(e.X, e.Y, e.Buttons)
+	case EventRelMouse:
+		return dev.RelMouseReport(clampInt8(e.X), clampInt8(e.Y), e.Buttons)
+	case EventWheel:
+		return dev.WheelReport(clampInt8(e.Y))
+	case EventMacro:
+		return dev.Macro(ctx, e.MacroSteps())
+	default:
+		return fmt.Errorf("unknown event type: %q", e.Type)
+	}
+}
+
+// Replay sends the events to the device with their original timing divided by speed.
+// Events that are late because a macro took longer are sent right away.
+func Replay(ctx context.Context, rec *Recording, speed float64, dev Device) error {
+	if speed < MinSpeed || speed > MaxSpeed {
+		return fmt.Errorf("speed must be between %g and %g", MinSpeed, MaxSpeed)
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for i, e := range rec.Events {
+		at := start.Add(time.Duration(float64(e.Time) * float64(time.Millisecond) / speed))
+		if wait := time.Until(at); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := replayEvent(ctx, dev, e); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("event %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
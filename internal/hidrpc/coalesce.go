@@ -0,0 +1,70 @@
+package hidrpc
+
+import "math"
+
+// CoalesceOptions selects the reports that are merged while they wait in a queue.
+type CoalesceOptions struct {
+	// Pointer keeps only the newest of consecutive absolute pointer moves with the same buttons
+	Pointer bool `json:"pointer"`
+	// Wheel sums the deltas of consecutive wheel reports
+	Wheel bool `json:"wheel"`
+}
+
+func isPointerReport(data []byte) bool {
+	return len(data) == 10 && MessageType(data[0]) == TypePointerReport
+}
+
+func isWheelReport(data []byte) bool {
+	return len(data) == 2 && MessageType(data[0]) == TypeWheelReport
+}
+
+// Coalesce merges the stale pointer and wheel reports of a batch of queued messages.
+// data returns the encoded message of an item and replace returns the item with other data.
+//
+// A pointer report that changes the buttons is never dropped, so clicks and drags happen at the
+// position the user intended, and all other messages keep their order.
+func Coalesce[T any](batch []T, opts CoalesceOptions, data func(T) []byte, replace func(T, []byte) T) []T {
+	if !opts.Pointer && !opts.Wheel {
+		return batch
+	}
+
+	out := make([]T, 0, len(batch))
+	// lastMove is true when the last item of out is a pointer report that didn't change the buttons,
+	// the buttons before the first pointer report of the batch are unknown
+	lastMove := false
+	lastButtons := -1
+
+	for _, item := range batch {
+		d := data(item)
+
+		if opts.Pointer && isPointerReport(d) {
+			buttons := int(d[9])
+			if lastMove && buttons == lastButtons {
+				out[len(out)-1] = item
+				continue
+			}
+			out = append(out, item)
+			lastMove = buttons == lastButtons
+			lastButtons = buttons
+			continue
+		}
+		lastMove = false
+		if !isWheelReport(d) {
+			// other mouse reports can change the buttons too
+			lastButtons = -1
+		}
+
+		if opts.Wheel && isWheelReport(d) && len(out) > 0 {
+			prev := data(out[len(out)-1])
+			if isWheelReport(prev) {
+				sum := int(int8(prev[1])) + int(int8(d[1]))
+				if sum >= math.MinInt8 && sum <= math.MaxInt8 {
+					out[len(out)-1] = replace(item, []byte{byte(TypeWheelReport), byte(int8(sum))})
+					continue
+				}
+			}
+		}
+		out = append(out, item)
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package hidrpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var allCoalescing = CoalesceOptions{Pointer: true, Wheel: true}
+
+func pointer(x, y int, buttons byte) []byte {
+	return []byte{byte(TypePointerReport), 0, 0, 0, byte(x), 0, 0, 0, byte(y), buttons}
+}
+
+func wheel(y int8) []byte {
+	return []byte{byte(TypeWheelReport), byte(y)}
+}
+
+func coalesce(batch [][]byte, opts CoalesceOptions) [][]byte {
+	return Coalesce(batch, opts,
+		func(d []byte) []byte { return d },
+		func(_ []byte, d []byte) []byte { return d },
+	)
+}
+
+func TestCoalescePointerMoves(t *testing.T) {
+	batch := [][]byte{pointer(1, 1, 0), pointer(2, 2, 0), pointer(3, 3, 0), pointer(4, 4, 0)}
+
+	// the first report may release a button of the previous batch, so it's kept
+	assert.Equal(t, [][]byte{pointer(1, 1, 0), pointer(4, 4, 0)}, coalesce(batch, allCoalescing))
+}
+
+func TestCoalesceKeepsButtonTransitions(t *testing.T) {
+	batch := [][]byte{
+		pointer(1, 1, 0),
+		pointer(2, 2, 0),
+		pointer(3, 3, 1), // press
+		pointer(4, 4, 1),
+		pointer(5, 5, 1),
+		pointer(6, 6, 0), // release
+		pointer(7, 7, 0),
+	}
+
+	assert.Equal(t, [][]byte{
+		pointer(1, 1, 0),
+		pointer(2, 2, 0),
+		pointer(3, 3, 1),
+		pointer(5, 5, 1),
+		pointer(6, 6, 0),
+		pointer(7, 7, 0),
+	}, coalesce(batch, allCoalescing))
+}
+
+func TestCoalesceKeepsOrderOfOtherMessages(t *testing.T) {
+	relative := []byte{byte(TypeMouseReport), 1, 1, 1}
+	batch := [][]byte{pointer(1, 1, 0), pointer(2, 2, 0), relative, pointer(3, 3, 0), pointer(4, 4, 0)}
+
+	assert.Equal(t, batch, coalesce(batch, allCoalescing), "the buttons are unknown after a relative report")
+}
+
+func TestCoalesceWheel(t *testing.T) {
+	batch := [][]byte{wheel(1), wheel(2), wheel(3), pointer(1, 1, 0), wheel(-1), wheel(-2)}
+
+	assert.Equal(t, [][]byte{wheel(6), pointer(1, 1, 0), wheel(-3)}, coalesce(batch, allCoalescing))
+}
+
+func TestCoalesceWheelOverflow(t *testing.T) {
+	batch := [][]byte{wheel(100), wheel(27), wheel(1), wheel(-128)}
+
+	assert.Equal(t, [][]byte{wheel(127), wheel(-127)}, coalesce(batch, allCoalescing))
+}
+
+func TestCoalesceDisabled(t *testing.T) {
+	batch := [][]byte{pointer(1, 1, 0), pointer(2, 2, 0), pointer(3, 3, 0), wheel(1), wheel(1)}
+
+	assert.Equal(t, batch, coalesce(batch, CoalesceOptions{}))
+	assert.Equal(t, [][]byte{pointer(1, 1, 0), pointer(3, 3, 0), wheel(1), wheel(1)}, coalesce(batch, CoalesceOptions{Pointer: true}))
+	assert.Equal(t, [][]byte{pointer(1, 1, 0), pointer(2, 2, 0), pointer(3, 3, 0), wheel(2)}, coalesce(batch, CoalesceOptions{Wheel: true}))
+}
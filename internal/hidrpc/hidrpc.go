@@ -19,11 +19,45 @@ const (
 	TypeMouseReport               MessageType = 0x06
 	TypeKeyboardMacroReport       MessageType = 0x07
 	TypeCancelKeyboardMacroReport MessageType = 0x08
+	TypeRunKeyboardScriptReport   MessageType = 0x0A
 	TypeKeyboardLedState          MessageType = 0x32
 	TypeKeydownState              MessageType = 0x33
 	TypeKeyboardMacroState        MessageType = 0x34
 )
 
+func (t MessageType) String() string {
+	switch t {
+	case TypeHandshake:
+		return "handshake"
+	case TypeKeyboardReport:
+		return "keyboard"
+	case TypePointerReport:
+		return "pointer"
+	case TypeWheelReport:
+		return "wheel"
+	case TypeKeypressReport:
+		return "keypress"
+	case TypeKeypressKeepAliveReport:
+		return "keypress_keepalive"
+	case TypeMouseReport:
+		return "mouse"
+	case TypeKeyboardMacroReport:
+		return "keyboard_macro"
+	case TypeCancelKeyboardMacroReport:
+		return "cancel_keyboard_macro"
+	case TypeRunKeyboardScriptReport:
+		return "run_keyboard_script"
+	case TypeKeyboardLedState:
+		return "keyboard_led_state"
+	case TypeKeydownState:
+		return "keydown_state"
+	case TypeKeyboardMacroState:
+		return "keyboard_macro_state"
+	default:
+		return "unknown"
+	}
+}
+
 const (
 	Version byte = 0x01 // Version of the HID RPC protocol
 )
@@ -33,7 +67,7 @@ func GetQueueIndex(messageType MessageType) int {
 	switch messageType {
 	case TypeHandshake:
 		return 0
-	case TypeKeyboardReport, TypeKeypressReport, TypeKeyboardMacroReport, TypeKeyboardLedState, TypeKeydownState, TypeKeyboardMacroState:
+	case TypeKeyboardReport, TypeKeypressReport, TypeKeyboardMacroReport, TypeRunKeyboardScriptReport, TypeKeyboardLedState, TypeKeydownState, TypeKeyboardMacroState:
 		return 1
 	case TypePointerReport, TypeMouseReport, TypeWheelReport:
 		return 2
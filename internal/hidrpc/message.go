@@ -44,13 +44,20 @@ func (m *Message) String() string {
 			return fmt.Sprintf("MouseReport{Malformed: %v}", m.d)
 		}
 		return fmt.Sprintf("MouseReport{DX: %d, DY: %d, Button: %d}", m.d[0], m.d[1], m.d[2])
+	case TypeWheelReport:
+		if len(m.d) < 1 {
+			return fmt.Sprintf("WheelReport{Malformed: %v}", m.d)
+		}
+		return fmt.Sprintf("WheelReport{WheelY: %d}", int8(m.d[0]))
 	case TypeKeypressKeepAliveReport:
 		return "KeypressKeepAliveReport"
 	case TypeKeyboardMacroReport:
 		if len(m.d) < 5 {
 			return fmt.Sprintf("KeyboardMacroReport{Malformed: %v}", m.d)
 		}
-		return fmt.Sprintf("KeyboardMacroReport{IsPaste: %v, Length: %d}", m.d[0] == uint8(1), binary.BigEndian.Uint32(m.d[1:5]))
+		return fmt.Sprintf("KeyboardMacroReport{IsPaste: %v, Length: %d}", m.d[0]&MacroFlagPaste != 0, binary.BigEndian.Uint32(m.d[1:5]))
+	case TypeRunKeyboardScriptReport:
+		return fmt.Sprintf("RunKeyboardScriptReport{ID: %q}", m.d)
 	default:
 		return fmt.Sprintf("Unknown{Type: %d, Data: %v}", m.t, m.d)
 	}
@@ -92,32 +99,70 @@ func (m *Message) KeyboardReport() (KeyboardReport, error) {
 	}, nil
 }
 
+// MacroStepKind is the kind of a macro step, keyboard steps are the only kind in reports without the step kinds flag.
+type MacroStepKind byte
+
+const (
+	MacroStepKeyboard   MacroStepKind = 0x00
+	MacroStepAbsMove    MacroStepKind = 0x01 // X and Y are absolute coordinates, 0 to 32767
+	MacroStepRelMove    MacroStepKind = 0x02 // X and Y are relative, -127 to 127
+	MacroStepButtonDown MacroStepKind = 0x03
+	MacroStepButtonUp   MacroStepKind = 0x04
+	MacroStepWheel      MacroStepKind = 0x05 // Y is the wheel movement, -127 to 127
+)
+
+// Flags of the keyboard macro report
+const (
+	MacroFlagPaste        byte = 0x01
+	MacroFlagStepKinds    byte = 0x02 // every step starts with its MacroStepKind
+	MacroFlagSyncLockKeys byte = 0x04 // Caps Lock and Num Lock are set to a known state while the macro runs
+)
+
 // Macro ..
 type KeyboardMacroStep struct {
-	Modifier byte   // 1 byte
-	Keys     []byte // 6 bytes: hidKeyBufferSize
-	Delay    uint16 // 2 bytes
+	Kind     MacroStepKind // 1 byte, only present with MacroFlagStepKinds
+	Modifier byte          // 1 byte
+	Keys     []byte        // 6 bytes: hidKeyBufferSize
+	Delay    uint16        // 2 bytes
+
+	// pointer steps use the 7 bytes of the modifier and the keys:
+	// X (2 bytes), Y (2 bytes), Button (1 byte) and 2 unused bytes
+	X      int16
+	Y      int16
+	Button uint8
 }
 type KeyboardMacroReport struct {
-	IsPaste   bool
-	StepCount uint32
-	Steps     []KeyboardMacroStep
+	IsPaste      bool
+	SyncLockKeys bool
+	StepCount    uint32
+	Steps        []KeyboardMacroStep
 }
 
 // HidKeyBufferSize is the size of the keys buffer in the keyboard report.
 const HidKeyBufferSize = 6
 
+const macroStepSize = 1 + HidKeyBufferSize + 2
+
 // KeyboardMacroReport returns the keyboard macro report from the message.
 func (m *Message) KeyboardMacroReport() (KeyboardMacroReport, error) {
 	if m.t != TypeKeyboardMacroReport {
 		return KeyboardMacroReport{}, fmt.Errorf("invalid message type: %d", m.t)
 	}
+	if len(m.d) < 5 {
+		return KeyboardMacroReport{}, fmt.Errorf("invalid length: %d", len(m.d))
+	}
 
-	isPaste := m.d[0] == uint8(1)
+	flags := m.d[0]
+	isPaste := flags&MacroFlagPaste != 0
 	stepCount := binary.BigEndian.Uint32(m.d[1:5])
 
+	stepSize := macroStepSize
+	if flags&MacroFlagStepKinds != 0 {
+		stepSize++
+	}
+
 	// check total length
-	expectedLength := int(stepCount)*9 + 5
+	expectedLength := int(stepCount)*stepSize + 5
 	if len(m.d) != expectedLength {
 		return KeyboardMacroReport{}, fmt.Errorf("invalid length: %d, expected: %d", len(m.d), expectedLength)
 	}
@@ -125,22 +170,50 @@ func (m *Message) KeyboardMacroReport() (KeyboardMacroReport, error) {
 	steps := make([]KeyboardMacroStep, 0, int(stepCount))
 	offset := 5
 	for i := 0; i < int(stepCount); i++ {
-		steps = append(steps, KeyboardMacroStep{
-			Modifier: m.d[offset],
-			Keys:     m.d[offset+1 : offset+7],
-			Delay:    binary.BigEndian.Uint16(m.d[offset+7 : offset+9]),
-		})
+		d := m.d[offset : offset+stepSize]
+		var step KeyboardMacroStep
+		if stepSize > macroStepSize {
+			step.Kind = MacroStepKind(d[0])
+			d = d[1:]
+		}
+		step.Delay = binary.BigEndian.Uint16(d[7:9])
+
+		switch step.Kind {
+		case MacroStepKeyboard:
+			step.Modifier = d[0]
+			step.Keys = d[1 : 1+HidKeyBufferSize]
+		case MacroStepAbsMove, MacroStepRelMove, MacroStepButtonDown, MacroStepButtonUp, MacroStepWheel:
+			step.X = int16(binary.BigEndian.Uint16(d[0:2]))
+			step.Y = int16(binary.BigEndian.Uint16(d[2:4]))
+			step.Button = d[4]
+		default:
+			return KeyboardMacroReport{}, fmt.Errorf("invalid macro step kind %d at step %d", step.Kind, i)
+		}
+		steps = append(steps, step)
 
-		offset += 1 + HidKeyBufferSize + 2
+		offset += stepSize
 	}
 
 	return KeyboardMacroReport{
-		IsPaste:   isPaste,
-		Steps:     steps,
-		StepCount: stepCount,
+		IsPaste:      isPaste,
+		SyncLockKeys: flags&MacroFlagSyncLockKeys != 0,
+		Steps:        steps,
+		StepCount:    stepCount,
 	}, nil
 }
 
+// RunKeyboardScriptReport returns the ID of the stored keyboard script to run.
+func (m *Message) RunKeyboardScriptReport() (string, error) {
+	if m.t != TypeRunKeyboardScriptReport {
+		return "", fmt.Errorf("invalid message type: %d", m.t)
+	}
+	if len(m.d) == 0 {
+		return "", fmt.Errorf("missing keyboard script ID")
+	}
+
+	return string(m.d), nil
+}
+
 // PointerReport ..
 type PointerReport struct {
 	X      int
@@ -205,3 +278,23 @@ func (m *Message) KeyboardMacroState() (KeyboardMacroState, error) {
 		IsPaste: m.d[1] == uint8(1),
 	}, nil
 }
+
+// WheelReport ..
+type WheelReport struct {
+	WheelY int8
+}
+
+// WheelReport returns the wheel report from the message.
+func (m *Message) WheelReport() (WheelReport, error) {
+	if m.t != TypeWheelReport {
+		return WheelReport{}, fmt.Errorf("invalid message type: %d", m.t)
+	}
+
+	if len(m.d) != 1 {
+		return WheelReport{}, fmt.Errorf("invalid message length: %d", len(m.d))
+	}
+
+	return WheelReport{
+		WheelY: int8(m.d[0]),
+	}, nil
+}
@@ -0,0 +1,85 @@
+package hidrpc
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// marshalMacro encodes the macro like the UI does, the step kinds are only sent when a step isn't a keyboard step.
+func marshalMacro(flags byte, steps []KeyboardMacroStep) []byte {
+	data := []byte{byte(TypeKeyboardMacroReport), flags}
+	data = binary.BigEndian.AppendUint32(data, uint32(len(steps)))
+	for _, step := range steps {
+		if flags&MacroFlagStepKinds != 0 {
+			data = append(data, byte(step.Kind))
+		}
+		if step.Kind == MacroStepKeyboard {
+			data = append(data, step.Modifier)
+			data = append(data, step.Keys...)
+		} else {
+			data = binary.BigEndian.AppendUint16(data, uint16(step.X))
+			data = binary.BigEndian.AppendUint16(data, uint16(step.Y))
+			data = append(data, step.Button, 0, 0)
+		}
+		data = binary.BigEndian.AppendUint16(data, step.Delay)
+	}
+	return data
+}
+
+func unmarshalMacro(t *testing.T, data []byte) (KeyboardMacroReport, error) {
+	var message Message
+	require.NoError(t, Unmarshal(data, &message))
+	return message.KeyboardMacroReport()
+}
+
+func TestKeyboardMacroReportStepKinds(t *testing.T) {
+	steps := []KeyboardMacroStep{
+		{Kind: MacroStepKeyboard, Modifier: 0x02, Keys: []byte{0x04, 0x05, 0, 0, 0, 0}, Delay: 20},
+		{Kind: MacroStepAbsMove, X: 32767, Y: 1024, Delay: 50},
+		{Kind: MacroStepButtonDown, Button: 1, Delay: 100},
+		{Kind: MacroStepRelMove, X: -127, Y: 42},
+		{Kind: MacroStepButtonUp, Button: 1, Delay: 65535},
+		{Kind: MacroStepWheel, Y: -3, Delay: 10},
+	}
+
+	data := marshalMacro(MacroFlagStepKinds|MacroFlagSyncLockKeys, steps)
+	assert.Len(t, data, 6+len(steps)*(macroStepSize+1))
+
+	report, err := unmarshalMacro(t, data)
+	require.NoError(t, err)
+	assert.False(t, report.IsPaste)
+	assert.True(t, report.SyncLockKeys)
+	assert.Equal(t, uint32(len(steps)), report.StepCount)
+	assert.Equal(t, steps, report.Steps)
+}
+
+func TestKeyboardMacroReportWithoutStepKinds(t *testing.T) {
+	steps := []KeyboardMacroStep{
+		{Modifier: 0, Keys: []byte{0x0b, 0, 0, 0, 0, 0}, Delay: 20},
+		{Modifier: 0, Keys: []byte{0, 0, 0, 0, 0, 0}, Delay: 0},
+	}
+
+	data := marshalMacro(MacroFlagPaste, steps)
+	assert.Len(t, data, 6+len(steps)*macroStepSize)
+
+	report, err := unmarshalMacro(t, data)
+	require.NoError(t, err)
+	assert.True(t, report.IsPaste)
+	assert.Equal(t, steps, report.Steps)
+
+	// the steps are one byte longer with the step kinds
+	data[1] |= MacroFlagStepKinds
+	_, err = unmarshalMacro(t, data)
+	assert.Error(t, err)
+}
+
+func TestKeyboardMacroReportInvalidStepKind(t *testing.T) {
+	data := marshalMacro(MacroFlagStepKinds, []KeyboardMacroStep{{Kind: MacroStepWheel}})
+	data[6] = 0x7f
+
+	_, err := unmarshalMacro(t, data)
+	assert.Error(t, err)
+}
@@ -0,0 +1,210 @@
+package kbmacro
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jetkvm/kvm/internal/hidrpc"
+	"github.com/jetkvm/kvm/internal/keyboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func keyMacro(id string, keys ...string) Macro {
+	return Macro{ID: id, Name: "macro " + id, Steps: []Step{{Keys: keys, Delay: MinStepDelay}}}
+}
+
+func TestLimitsValidate(t *testing.T) {
+	limits := DefaultLimits
+	assert.NoError(t, limits.Validate())
+
+	limits.MaxKeysPerStep = MaxKeysPerStep + 1
+	assert.Error(t, limits.Validate())
+}
+
+func TestStepValidate(t *testing.T) {
+	step := Step{Keys: []string{"KeyA", "Digit1"}, Modifiers: []string{"ShiftLeft"}}
+	require.NoError(t, step.Validate(DefaultLimits))
+	assert.Equal(t, MinStepDelay, step.Delay)
+
+	for _, step := range []Step{
+		{Keys: []string{"KeyA", "KeyB", "KeyC", "KeyD", "KeyE", "KeyF", "KeyG"}},
+		{Keys: []string{"NoSuchKey"}},
+		{Modifiers: []string{"Hyper"}},
+		{Keys: []string{"KeyA"}, Mouse: &Mouse{Action: MouseDown, Button: "left"}},
+		{Mouse: &Mouse{Action: MouseDown, Button: "fourth"}},
+	} {
+		assert.Error(t, step.Validate(DefaultLimits), "%+v", step)
+	}
+
+	// the stricter of the configured limit and the report size applies
+	limits := DefaultLimits
+	limits.MaxKeysPerStep = 1
+	step = Step{Keys: []string{"KeyA", "KeyB"}}
+	assert.Error(t, step.Validate(limits))
+}
+
+func TestMacroReport(t *testing.T) {
+	macro := Macro{Steps: []Step{
+		{Keys: []string{"KeyA"}, Modifiers: []string{"ControlLeft"}, Delay: 100},
+		{Mouse: &Mouse{Action: MouseMoveBy, X: -10, Y: 5}, Delay: 50},
+	}}
+
+	assert.Equal(t, []hidrpc.KeyboardMacroStep{
+		{Modifier: keyboard.ModifierControlLeft, Keys: []byte{keyboard.Keys["KeyA"], 0, 0, 0, 0, 0}, Delay: 20},
+		{Keys: make([]byte, hidrpc.HidKeyBufferSize), Delay: 100},
+		{Kind: hidrpc.MacroStepRelMove, X: -10, Y: 5, Delay: 50},
+	}, macro.Report())
+
+	// older versions allowed more keys than a report holds, the extra ones aren't pressed
+	macro = keyMacro("a", "KeyA", "KeyB", "KeyC", "KeyD", "KeyE", "KeyF", "KeyG", "KeyH")
+	steps := macro.Report()
+	require.Len(t, steps, 2)
+	assert.Equal(t, []byte{
+		keyboard.Keys["KeyA"], keyboard.Keys["KeyB"], keyboard.Keys["KeyC"],
+		keyboard.Keys["KeyD"], keyboard.Keys["KeyE"], keyboard.Keys["KeyF"],
+	}, steps[0].Keys)
+}
+
+func TestMacroNormalize(t *testing.T) {
+	macro := Macro{Name: "legacy", Steps: []Step{
+		{Keys: []string{"KeyA", "KeyB", "KeyC", "KeyD", "KeyE", "KeyF", "KeyG", "KeyH", "KeyI", "KeyJ"}, Delay: 100},
+		{Keys: []string{"KeyA", "NoSuchKey"}, Modifiers: []string{"ShiftLeft", "Hyper"}, Delay: 10},
+		{Keys: []string{"KeyA"}, Mouse: &Mouse{Action: MouseDown, Button: "left"}, Delay: 5000},
+		{Keys: []string{"KeyA"}, Delay: 100},
+	}}
+
+	changes := macro.Normalize()
+	assert.Len(t, changes, 6)
+	assert.Equal(t, []string{"KeyA", "KeyB", "KeyC", "KeyD", "KeyE", "KeyF"}, macro.Steps[0].Keys)
+	assert.Equal(t, []string{"KeyA"}, macro.Steps[1].Keys)
+	assert.Equal(t, []string{"ShiftLeft"}, macro.Steps[1].Modifiers)
+	assert.Equal(t, MinStepDelay, macro.Steps[1].Delay)
+	assert.Empty(t, macro.Steps[2].Keys)
+	assert.Equal(t, MaxStepDelay, macro.Steps[2].Delay)
+	assert.NoError(t, macro.Validate(DefaultLimits))
+
+	// a valid macro is left as it is
+	valid := keyMacro("a", "KeyA")
+	assert.Empty(t, valid.Normalize())
+	assert.Equal(t, keyMacro("a", "KeyA"), valid)
+}
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyboard_macros.json")
+	legacy := []Macro{keyMacro("a", "KeyA"), keyMacro("b", "KeyB")}
+
+	// nothing to move doesn't create the store
+	migrated, _, err := NewStore(path).Migrate(nil)
+	require.NoError(t, err)
+	assert.False(t, migrated)
+	assert.NoFileExists(t, path)
+
+	migrated, warnings, err := NewStore(path).Migrate(legacy)
+	require.NoError(t, err)
+	assert.True(t, migrated)
+	assert.Empty(t, warnings)
+
+	s := NewStore(path)
+	macros, err := s.Macros()
+	require.NoError(t, err)
+	assert.Equal(t, legacy, macros)
+
+	// the store exists, the macros of the config aren't moved again
+	migrated, _, err = s.Migrate([]Macro{keyMacro("c", "KeyC")})
+	require.NoError(t, err)
+	assert.False(t, migrated)
+
+	macros, err = NewStore(path).Macros()
+	require.NoError(t, err)
+	assert.Equal(t, legacy, macros)
+}
+
+func TestMigrateNormalizes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyboard_macros.json")
+	// older versions allowed up to 10 keys per step
+	legacy := []Macro{keyMacro("a", "KeyA", "KeyB", "KeyC", "KeyD", "KeyE", "KeyF", "KeyG")}
+
+	migrated, warnings, err := NewStore(path).Migrate(legacy)
+	require.NoError(t, err)
+	assert.True(t, migrated)
+	assert.Len(t, warnings, 1)
+	assert.Len(t, legacy[0].Steps[0].Keys, 7, "the macros passed in are left untouched")
+
+	// the migrated macros can be saved again as a whole
+	s := NewStore(path)
+	macros, err := s.Macros()
+	require.NoError(t, err)
+	require.NoError(t, macros[0].Validate(DefaultLimits))
+	assert.NoError(t, s.Set(macros, DefaultLimits))
+}
+
+func TestImportIDCollisions(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "keyboard_macros.json"))
+	existing := keyMacro("a", "KeyA")
+	existing.SortOrder = 3
+	require.NoError(t, s.Set([]Macro{existing}, DefaultLimits))
+
+	imported, err := s.Import(File{Version: FileVersion, Macros: []Macro{
+		keyMacro("a", "KeyB"),
+		keyMacro("", "KeyC"),
+		keyMacro("d", "KeyD"),
+	}}, false, DefaultLimits)
+	require.NoError(t, err)
+	assert.Equal(t, 3, imported)
+
+	macros, err := s.Macros()
+	require.NoError(t, err)
+	require.Len(t, macros, 4)
+
+	ids := make(map[string]bool)
+	for i, m := range macros {
+		assert.NotEmpty(t, m.ID)
+		assert.False(t, ids[m.ID], "duplicate ID %s", m.ID)
+		ids[m.ID] = true
+		assert.Equal(t, 3+i, m.SortOrder)
+	}
+	assert.Equal(t, []string{"KeyA"}, macros[0].Steps[0].Keys)
+	assert.NotEqual(t, "a", macros[1].ID)
+	assert.Equal(t, []string{"KeyB"}, macros[1].Steps[0].Keys)
+	assert.Equal(t, "d", macros[3].ID)
+
+	// replacing keeps the IDs, there's nothing to collide with
+	_, err = s.Import(File{Version: FileVersion, Macros: []Macro{keyMacro("a", "KeyB")}}, true, DefaultLimits)
+	require.NoError(t, err)
+	macros, err = s.Macros()
+	require.NoError(t, err)
+	require.Len(t, macros, 1)
+	assert.Equal(t, "a", macros[0].ID)
+}
+
+func TestImportRejectsInvalidMacros(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "keyboard_macros.json"))
+	limits := DefaultLimits
+	limits.MaxMacros = 1
+
+	_, err := s.Import(File{Version: FileVersion, Macros: []Macro{keyMacro("a", "NoSuchKey")}}, false, limits)
+	assert.Error(t, err)
+	_, err = s.Import(File{Version: FileVersion, Macros: []Macro{keyMacro("a", "KeyA"), keyMacro("b", "KeyB")}}, false, limits)
+	assert.Error(t, err)
+
+	macros, err := s.Macros()
+	require.NoError(t, err)
+	assert.Empty(t, macros)
+}
+
+func TestVersionRejection(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(filepath.Join(dir, "import.json"))
+
+	for _, version := range []int{0, FileVersion + 1} {
+		_, err := s.Import(File{Version: version, Macros: []Macro{keyMacro("a", "KeyA")}}, false, DefaultLimits)
+		assert.ErrorContains(t, err, "unsupported macro file version")
+	}
+
+	path := filepath.Join(dir, "keyboard_macros.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version":2,"macros":[]}`), 0644))
+	_, err := NewStore(path).Macros()
+	assert.ErrorContains(t, err, "unsupported macro file version: 2")
+}
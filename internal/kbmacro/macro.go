@@ -0,0 +1,267 @@
+// Package kbmacro validates and stores the keyboard macros of the user, and translates them
+// into the steps the device runs.
+package kbmacro
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/jetkvm/kvm/internal/hidrpc"
+	"github.com/jetkvm/kvm/internal/keyboard"
+)
+
+const (
+	MinStepDelay = 50
+	MaxStepDelay = 2000
+
+	// MaxKeysPerStep is the most keys a step can press at once, it's what a keyboard report holds
+	MaxKeysPerStep = hidrpc.HidKeyBufferSize
+
+	// the limits can be configured up to these
+	maxMacrosLimit = 1000
+	maxStepsLimit  = 10000
+)
+
+// Limits are the configurable limits of the stored macros.
+type Limits struct {
+	MaxMacros        int `json:"max_macros"`
+	MaxStepsPerMacro int `json:"max_steps_per_macro"`
+	MaxKeysPerStep   int `json:"max_keys_per_step"`
+}
+
+var DefaultLimits = Limits{
+	MaxMacros:        100,
+	MaxStepsPerMacro: 500,
+	MaxKeysPerStep:   MaxKeysPerStep,
+}
+
+func (l *Limits) Validate() error {
+	if l.MaxMacros < 1 || l.MaxMacros > maxMacrosLimit {
+		return fmt.Errorf("max macros must be between 1 and %d", maxMacrosLimit)
+	}
+	if l.MaxStepsPerMacro < 1 || l.MaxStepsPerMacro > maxStepsLimit {
+		return fmt.Errorf("max steps per macro must be between 1 and %d", maxStepsLimit)
+	}
+	if l.MaxKeysPerStep < 1 || l.MaxKeysPerStep > MaxKeysPerStep {
+		return fmt.Errorf("max keys per step must be between 1 and %d", MaxKeysPerStep)
+	}
+	return nil
+}
+
+// Mouse actions of macro steps
+const (
+	MouseMove   = "move"   // absolute, X and Y from 0 to 32767
+	MouseMoveBy = "moveBy" // relative, X and Y from -127 to 127
+	MouseDown   = "down"
+	MouseUp     = "up"
+	MouseWheel  = "wheel" // Y from -127 to 127
+)
+
+var mouseButtons = map[string]uint8{
+	"left":   1,
+	"right":  2,
+	"middle": 4,
+}
+
+var mouseStepKinds = map[string]hidrpc.MacroStepKind{
+	MouseMove:   hidrpc.MacroStepAbsMove,
+	MouseMoveBy: hidrpc.MacroStepRelMove,
+	MouseDown:   hidrpc.MacroStepButtonDown,
+	MouseUp:     hidrpc.MacroStepButtonUp,
+	MouseWheel:  hidrpc.MacroStepWheel,
+}
+
+type Mouse struct {
+	Action string `json:"action"`
+	X      int    `json:"x,omitempty"`
+	Y      int    `json:"y,omitempty"`
+	Button string `json:"button,omitempty"`
+}
+
+func (m *Mouse) Validate() error {
+	inRange := func(v, minValue, maxValue int) bool { return v >= minValue && v <= maxValue }
+
+	switch m.Action {
+	case MouseMove:
+		if !inRange(m.X, 0, 32767) || !inRange(m.Y, 0, 32767) {
+			return fmt.Errorf("mouse position must be between 0 and 32767")
+		}
+	case MouseMoveBy:
+		if !inRange(m.X, -127, 127) || !inRange(m.Y, -127, 127) {
+			return fmt.Errorf("mouse movement must be between -127 and 127")
+		}
+	case MouseDown, MouseUp:
+		if _, ok := mouseButtons[m.Button]; !ok {
+			return fmt.Errorf("invalid mouse button: %q", m.Button)
+		}
+	case MouseWheel:
+		if !inRange(m.Y, -127, 127) {
+			return fmt.Errorf("wheel movement must be between -127 and 127")
+		}
+	default:
+		return fmt.Errorf("invalid mouse action: %q", m.Action)
+	}
+	return nil
+}
+
+func (m *Mouse) step(delay int) hidrpc.KeyboardMacroStep {
+	return hidrpc.KeyboardMacroStep{
+		Kind:   mouseStepKinds[m.Action],
+		X:      int16(m.X),
+		Y:      int16(m.Y),
+		Button: mouseButtons[m.Button],
+		Delay:  uint16(delay),
+	}
+}
+
+// Step presses the keys and modifiers, the names are the ones of the UI like KeyA or ControlLeft.
+type Step struct {
+	Keys      []string `json:"keys"`
+	Modifiers []string `json:"modifiers"`
+	Delay     int      `json:"delay"`
+	// Mouse makes this a mouse step, its keys and modifiers are ignored
+	Mouse *Mouse `json:"mouse,omitempty"`
+}
+
+// Validate checks the step and clamps its delay.
+func (s *Step) Validate(limits Limits) error {
+	if maxKeys := min(limits.MaxKeysPerStep, MaxKeysPerStep); len(s.Keys) > maxKeys {
+		return fmt.Errorf("too many keys in step (max %d)", maxKeys)
+	}
+	for _, name := range s.Keys {
+		if _, ok := keyboard.Keys[name]; !ok {
+			return fmt.Errorf("unknown key: %q", name)
+		}
+	}
+	for _, name := range s.Modifiers {
+		if _, ok := keyboard.Modifiers[name]; !ok {
+			return fmt.Errorf("unknown modifier: %q", name)
+		}
+	}
+
+	if s.Mouse != nil {
+		if len(s.Keys) > 0 || len(s.Modifiers) > 0 {
+			return fmt.Errorf("a mouse step can't have keys or modifiers")
+		}
+		if err := s.Mouse.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.Delay < MinStepDelay {
+		s.Delay = MinStepDelay
+	} else if s.Delay > MaxStepDelay {
+		s.Delay = MaxStepDelay
+	}
+
+	return nil
+}
+
+type Macro struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Steps     []Step `json:"steps"`
+	SortOrder int    `json:"sortOrder,omitempty"`
+}
+
+func (m *Macro) Validate(limits Limits) error {
+	if m.Name == "" {
+		return fmt.Errorf("macro name cannot be empty")
+	}
+
+	if len(m.Steps) == 0 {
+		return fmt.Errorf("macro must have at least one step")
+	}
+
+	if len(m.Steps) > limits.MaxStepsPerMacro {
+		return fmt.Errorf("too many steps in macro (max %d)", limits.MaxStepsPerMacro)
+	}
+
+	for i := range m.Steps {
+		if err := m.Steps[i].Validate(limits); err != nil {
+			return fmt.Errorf("invalid step %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// Normalize fixes the steps older versions accepted but this one doesn't: unknown keys and modifiers
+// are dropped, only the first MaxKeysPerStep keys are kept, mouse steps lose their keys and the delays
+// are clamped. It returns a description of every change.
+func (m *Macro) Normalize() []string {
+	var changes []string
+	for i := range m.Steps {
+		step := &m.Steps[i]
+		if step.Mouse != nil && (len(step.Keys) > 0 || len(step.Modifiers) > 0) {
+			step.Keys = nil
+			step.Modifiers = nil
+			changes = append(changes, fmt.Sprintf("step %d: dropped the keys of the mouse step", i+1))
+		}
+
+		keys := slices.DeleteFunc(slices.Clone(step.Keys), func(name string) bool {
+			_, ok := keyboard.Keys[name]
+			return !ok
+		})
+		if len(keys) < len(step.Keys) {
+			changes = append(changes, fmt.Sprintf("step %d: dropped %d unknown keys", i+1, len(step.Keys)-len(keys)))
+		}
+		if len(keys) > MaxKeysPerStep {
+			changes = append(changes, fmt.Sprintf("step %d: kept the first %d of %d keys", i+1, MaxKeysPerStep, len(keys)))
+			keys = keys[:MaxKeysPerStep]
+		}
+		if len(keys) < len(step.Keys) {
+			step.Keys = keys
+		}
+
+		modifiers := slices.DeleteFunc(slices.Clone(step.Modifiers), func(name string) bool {
+			_, ok := keyboard.Modifiers[name]
+			return !ok
+		})
+		if len(modifiers) < len(step.Modifiers) {
+			changes = append(changes, fmt.Sprintf("step %d: dropped %d unknown modifiers", i+1, len(step.Modifiers)-len(modifiers)))
+			step.Modifiers = modifiers
+		}
+
+		if delay := min(max(step.Delay, MinStepDelay), MaxStepDelay); delay != step.Delay {
+			changes = append(changes, fmt.Sprintf("step %d: changed the delay from %d to %d ms", i+1, step.Delay, delay))
+			step.Delay = delay
+		}
+	}
+	return changes
+}
+
+// Report translates the macro into the steps the device runs, the same way the UI does:
+// every key step is held for 20ms and released, the delay of a step is the pause afterwards.
+// Unknown keys of macros stored before they were validated are skipped and only the keys
+// a keyboard report holds are pressed.
+func (m *Macro) Report() []hidrpc.KeyboardMacroStep {
+	steps := make([]hidrpc.KeyboardMacroStep, 0, len(m.Steps)*2)
+	for _, step := range m.Steps {
+		if step.Mouse != nil {
+			steps = append(steps, step.Mouse.step(step.Delay))
+			continue
+		}
+
+		var modifier byte
+		for _, name := range step.Modifiers {
+			modifier |= keyboard.Modifiers[name]
+		}
+		keys := make([]byte, 0, hidrpc.HidKeyBufferSize)
+		for _, name := range step.Keys {
+			if key, ok := keyboard.Keys[name]; ok && len(keys) < hidrpc.HidKeyBufferSize {
+				keys = append(keys, key)
+			}
+		}
+
+		if len(keys) == 0 && modifier == 0 {
+			continue
+		}
+		keys = append(keys, make([]byte, hidrpc.HidKeyBufferSize-len(keys))...)
+		steps = append(steps,
+			hidrpc.KeyboardMacroStep{Modifier: modifier, Keys: keys, Delay: 20},
+			hidrpc.KeyboardMacroStep{Keys: make([]byte, hidrpc.HidKeyBufferSize), Delay: uint16(step.Delay)},
+		)
+	}
+	return steps
+}
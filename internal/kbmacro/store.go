@@ -0,0 +1,246 @@
+package kbmacro
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// FileVersion is the version of the macro store and of exported macros.
+const FileVersion = 1
+
+// File is the format of the macro store and of exported macros.
+type File struct {
+	Version    int        `json:"version"`
+	ExportedAt *time.Time `json:"exportedAt,omitempty"`
+	Macros     []Macro    `json:"macros"`
+}
+
+func (f *File) checkVersion() error {
+	if f.Version < 1 || f.Version > FileVersion {
+		return fmt.Errorf("unsupported macro file version: %d", f.Version)
+	}
+	return nil
+}
+
+var ErrNotFound = errors.New("keyboard macro not found")
+
+// NewID returns an ID for a new macro.
+func NewID() string {
+	return fmt.Sprintf("macro-%d", time.Now().UnixNano())
+}
+
+// Store keeps the macros in a file, they're read on first use. It's safe for concurrent use.
+type Store struct {
+	path string
+
+	mu     sync.Mutex
+	macros []Macro
+	loaded bool
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+func readFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse macros: %w", err)
+	}
+	if err := file.checkVersion(); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// write replaces the store, it's written to a temporary file first so a power loss can't leave
+// it half written. The caller must hold mu.
+func (s *Store) write(macros []Macro) error {
+	data, err := json.MarshalIndent(File{Version: FileVersion, Macros: macros}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return err
+	}
+
+	s.macros = macros
+	s.loaded = true
+	return nil
+}
+
+// load reads the store once, a missing file is an empty store. The caller must hold mu.
+func (s *Store) load() error {
+	if s.loaded {
+		return nil
+	}
+
+	file, err := readFile(s.path)
+	switch {
+	case err == nil:
+		s.macros = file.Macros
+	case errors.Is(err, os.ErrNotExist):
+		s.macros = nil
+	default:
+		return err
+	}
+
+	if s.macros == nil {
+		s.macros = []Macro{}
+	}
+	s.loaded = true
+	return nil
+}
+
+// Migrate moves the macros of older versions, which were kept in the config, into the store.
+// It does nothing if the store already exists, it returns true if the macros were moved.
+// The macros are normalized on the way, the returned warnings describe what was changed.
+func (s *Store) Migrate(macros []Macro) (bool, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(macros) == 0 {
+		return false, nil, nil
+	}
+	if _, err := os.Stat(s.path); !errors.Is(err, os.ErrNotExist) {
+		return false, nil, err
+	}
+
+	var warnings []string
+	normalized := make([]Macro, 0, len(macros))
+	for _, macro := range macros {
+		macro.Steps = slices.Clone(macro.Steps)
+		for _, change := range macro.Normalize() {
+			warnings = append(warnings, fmt.Sprintf("macro %q: %s", macro.Name, change))
+		}
+		normalized = append(normalized, macro)
+	}
+
+	if err := s.write(normalized); err != nil {
+		return false, nil, err
+	}
+	return true, warnings, nil
+}
+
+// Macros returns a copy of the stored macros.
+func (s *Store) Macros() ([]Macro, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return slices.Clone(s.macros), nil
+}
+
+// Find returns the macro with the given ID.
+func (s *Store) Find(id string) (*Macro, error) {
+	macros, err := s.Macros()
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(macros, func(m Macro) bool { return m.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return &macros[i], nil
+}
+
+// Set replaces all macros, they have to be validated by the caller.
+func (s *Store) Set(macros []Macro, limits Limits) error {
+	if len(macros) > limits.MaxMacros {
+		return fmt.Errorf("too many macros (max %d)", limits.MaxMacros)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.write(macros); err != nil {
+		return fmt.Errorf("failed to save keyboard macros: %w", err)
+	}
+	return nil
+}
+
+// Export returns the stored macros in the export format.
+func (s *Store) Export() (File, error) {
+	macros, err := s.Macros()
+	if err != nil {
+		return File{}, err
+	}
+	now := time.Now().UTC()
+	return File{Version: FileVersion, ExportedAt: &now, Macros: macros}, nil
+}
+
+// Import adds the macros of an export after the stored ones, or replaces all macros with them.
+// Macros get a new ID when theirs is already taken. It returns the number of imported macros.
+func (s *Store) Import(file File, replace bool, limits Limits) (int, error) {
+	if err := file.checkVersion(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var macros []Macro
+	if !replace {
+		if err := s.load(); err != nil {
+			return 0, err
+		}
+		macros = slices.Clone(s.macros)
+	}
+
+	sortOrder := 0
+	for _, m := range macros {
+		sortOrder = max(sortOrder, m.SortOrder)
+	}
+
+	for i, macro := range file.Macros {
+		if err := macro.Validate(limits); err != nil {
+			return 0, fmt.Errorf("invalid macro at index %d: %w", i, err)
+		}
+		if macro.ID == "" || slices.ContainsFunc(macros, func(m Macro) bool { return m.ID == macro.ID }) {
+			macro.ID = fmt.Sprintf("%s-%d", NewID(), i)
+		}
+		sortOrder++
+		macro.SortOrder = sortOrder
+		macros = append(macros, macro)
+	}
+
+	if len(macros) > limits.MaxMacros {
+		return 0, fmt.Errorf("too many macros (max %d)", limits.MaxMacros)
+	}
+	if err := s.write(macros); err != nil {
+		return 0, fmt.Errorf("failed to save keyboard macros: %w", err)
+	}
+	return len(file.Macros), nil
+}
@@ -0,0 +1,181 @@
+// Package keyboard translates text into HID key strokes for the keyboard layout of the target computer.
+// The layouts are ported from the UI (ui/src/keyboardLayouts), keep them in sync.
+package keyboard
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Modifier bits of the HID keyboard report.
+const (
+	ModifierControlLeft  byte = 0x01
+	ModifierShiftLeft    byte = 0x02
+	ModifierAltLeft      byte = 0x04
+	ModifierMetaLeft     byte = 0x08
+	ModifierControlRight byte = 0x10
+	ModifierShiftRight   byte = 0x20
+	ModifierAltRight     byte = 0x40
+	ModifierMetaRight    byte = 0x80
+)
+
+// Modifiers maps the modifier names used by the UI to their bits in the HID report.
+var Modifiers = map[string]byte{
+	"ControlLeft":  ModifierControlLeft,
+	"ControlRight": ModifierControlRight,
+	"ShiftLeft":    ModifierShiftLeft,
+	"ShiftRight":   ModifierShiftRight,
+	"AltLeft":      ModifierAltLeft,
+	"AltRight":     ModifierAltRight,
+	"MetaLeft":     ModifierMetaLeft,
+	"MetaRight":    ModifierMetaRight,
+	"AltGr":        ModifierAltRight,
+}
+
+// KeyInfo is a key and the modifiers that have to be held while it's pressed.
+type KeyInfo struct {
+	Key      string
+	Shift    bool
+	AltRight bool
+}
+
+// KeyCombo describes how a character is typed.
+type KeyCombo struct {
+	KeyInfo
+	// DeadKey is set for accents, they only appear after another key, a space is typed to emit them alone
+	DeadKey bool
+	// AccentKey is the dead key that has to be typed before the key, e.g. for accented letters
+	AccentKey *KeyInfo
+}
+
+// Layout maps the characters a keyboard layout can type to their keys.
+type Layout struct {
+	ISOCode string
+	Name    string
+	Chars   map[rune]KeyCombo
+}
+
+// Stroke is a single key press with its modifiers.
+type Stroke struct {
+	Modifier byte
+	Key      byte
+}
+
+func withChars(base map[rune]KeyCombo, chars map[rune]KeyCombo) map[rune]KeyCombo {
+	merged := maps.Clone(base)
+	maps.Copy(merged, chars)
+	return merged
+}
+
+var layouts = map[string]*Layout{
+	csCZ.ISOCode: &csCZ,
+	daDK.ISOCode: &daDK,
+	deCH.ISOCode: &deCH,
+	deDE.ISOCode: &deDE,
+	enUK.ISOCode: &enUK,
+	enUS.ISOCode: &enUS,
+	esES.ISOCode: &esES,
+	frBE.ISOCode: &frBE,
+	frCH.ISOCode: &frCH,
+	frFR.ISOCode: &frFR,
+	itIT.ISOCode: &itIT,
+	nbNO.ISOCode: &nbNO,
+	svSE.ISOCode: &svSE,
+}
+
+// DefaultLayout is used when no layout is configured.
+const DefaultLayout = "en-US"
+
+// GetLayout returns the layout with the given ISO code, e.g. "de-DE". An empty code returns the
+// default layout, an unknown one is an error so text isn't typed with the wrong layout.
+func GetLayout(isoCode string) (*Layout, error) {
+	if isoCode == "" {
+		isoCode = DefaultLayout
+	}
+	layout, ok := layouts[isoCode]
+	if !ok {
+		return nil, fmt.Errorf("unknown keyboard layout: %s", isoCode)
+	}
+	return layout, nil
+}
+
+// LayoutCodes returns the ISO codes of all layouts.
+func LayoutCodes() []string {
+	return slices.Sorted(maps.Keys(layouts))
+}
+
+func (k KeyInfo) stroke() (Stroke, error) {
+	code, ok := Keys[k.Key]
+	if !ok {
+		return Stroke{}, fmt.Errorf("unknown key: %s", k.Key)
+	}
+	s := Stroke{Key: code}
+	if k.Shift {
+		s.Modifier |= ModifierShiftLeft
+	}
+	if k.AltRight {
+		s.Modifier |= ModifierAltRight
+	}
+	return s, nil
+}
+
+// Strokes returns the key strokes that type the character.
+func (l *Layout) Strokes(r rune) ([]Stroke, error) {
+	combo, ok := l.Chars[r]
+	if !ok {
+		// not every layout lists the whitespace that is typed with a dedicated key
+		switch r {
+		case '\t':
+			combo = KeyCombo{KeyInfo: KeyInfo{Key: "Tab"}}
+		case '\n':
+			combo = KeyCombo{KeyInfo: KeyInfo{Key: "Enter"}}
+		default:
+			return nil, fmt.Errorf("character %q can't be typed with the %s layout", r, l.ISOCode)
+		}
+	}
+
+	strokes := make([]Stroke, 0, 3)
+	if combo.AccentKey != nil {
+		accent, err := combo.AccentKey.stroke()
+		if err != nil {
+			return nil, err
+		}
+		strokes = append(strokes, accent)
+	}
+
+	key, err := combo.stroke()
+	if err != nil {
+		return nil, err
+	}
+	strokes = append(strokes, key)
+
+	if combo.DeadKey {
+		strokes = append(strokes, Stroke{Key: Keys["Space"]})
+	}
+	return strokes, nil
+}
+
+// TextStrokes returns the key strokes that type the text. Carriage returns are dropped,
+// line breaks are typed as Enter. It fails if any character can't be typed.
+func (l *Layout) TextStrokes(text string) ([]Stroke, error) {
+	var strokes []Stroke
+	var invalid []rune
+	for _, r := range text {
+		if r == '\r' {
+			continue
+		}
+		s, err := l.Strokes(r)
+		if err != nil {
+			if !slices.Contains(invalid, r) {
+				invalid = append(invalid, r)
+			}
+			continue
+		}
+		strokes = append(strokes, s...)
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("characters %q can't be typed with the %s layout", string(invalid), l.ISOCode)
+	}
+	return strokes, nil
+}
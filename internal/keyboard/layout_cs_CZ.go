@@ -0,0 +1,246 @@
+package keyboard
+
+var (
+	csCZKeyTrema   = &KeyInfo{Key: "Backslash"}                           // tréma (umlaut), two dots placed above a vowel
+	csCZKeyAcute   = &KeyInfo{Key: "Equal"}                               // accent aigu (acute accent), mark ´ placed above the letter
+	csCZKeyHat     = &KeyInfo{Key: "Digit3", Shift: true, AltRight: true} // accent circonflexe (accent hat), mark ^ placed above the letter
+	csCZKeyCaron   = &KeyInfo{Key: "Equal", Shift: true}                  // caron or haček (inverted hat), mark ˇ placed above the letter
+	csCZKeyGrave   = &KeyInfo{Key: "Digit7", Shift: true, AltRight: true} // accent grave, mark ` placed above the letter
+	csCZKeyTilde   = &KeyInfo{Key: "Digit1", Shift: true, AltRight: true} // tilde, mark ~ placed above the letter
+	csCZKeyRing    = &KeyInfo{Key: "Backquote", Shift: true}              // kroužek (little ring), mark ° placed above the letter
+	csCZKeyOverdot = &KeyInfo{Key: "Digit8", Shift: true, AltRight: true} // overdot (dot above), mark ˙ placed above the letter
+	csCZKeyHook    = &KeyInfo{Key: "Digit6", Shift: true, AltRight: true} // ogonoek (little hook), mark ˛ placed beneath a letter
+	csCZKeyCedille = &KeyInfo{Key: "Equal", Shift: true, AltRight: true}  // accent cedille (cedilla), mark ¸ placed beneath a letter
+)
+
+var csCZ = Layout{
+	ISOCode: "cs-CZ",
+	Name:    "Čeština",
+	Chars: map[rune]KeyCombo{
+		'0':  {KeyInfo: KeyInfo{Key: "Digit0", Shift: true}},
+		'1':  {KeyInfo: KeyInfo{Key: "Digit1", Shift: true}},
+		'2':  {KeyInfo: KeyInfo{Key: "Digit2", Shift: true}},
+		'3':  {KeyInfo: KeyInfo{Key: "Digit3", Shift: true}},
+		'4':  {KeyInfo: KeyInfo{Key: "Digit4", Shift: true}},
+		'5':  {KeyInfo: KeyInfo{Key: "Digit5", Shift: true}},
+		'6':  {KeyInfo: KeyInfo{Key: "Digit6", Shift: true}},
+		'7':  {KeyInfo: KeyInfo{Key: "Digit7", Shift: true}},
+		'8':  {KeyInfo: KeyInfo{Key: "Digit8", Shift: true}},
+		'9':  {KeyInfo: KeyInfo{Key: "Digit9", Shift: true}},
+		'A':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}},
+		'Ä':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: csCZKeyTrema},
+		'Á':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: csCZKeyAcute},
+		'Â':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: csCZKeyHat},
+		'À':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: csCZKeyGrave},
+		'Ã':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: csCZKeyTilde},
+		'Ȧ':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: csCZKeyOverdot},
+		'Ą':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: csCZKeyHook},
+		'B':  {KeyInfo: KeyInfo{Key: "KeyB", Shift: true}},
+		'Ḃ':  {KeyInfo: KeyInfo{Key: "KeyB", Shift: true}},
+		'C':  {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}},
+		'Č':  {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}, AccentKey: csCZKeyCaron},
+		'Ċ':  {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}, AccentKey: csCZKeyOverdot},
+		'Ç':  {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}, AccentKey: csCZKeyCedille},
+		'D':  {KeyInfo: KeyInfo{Key: "KeyD", Shift: true}},
+		'Ď':  {KeyInfo: KeyInfo{Key: "KeyD", Shift: true}, AccentKey: csCZKeyCaron},
+		'Ḋ':  {KeyInfo: KeyInfo{Key: "KeyD", Shift: true}, AccentKey: csCZKeyOverdot},
+		'E':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}},
+		'Ë':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: csCZKeyTrema},
+		'É':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: csCZKeyAcute},
+		'Ê':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: csCZKeyHat},
+		'Ě':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: csCZKeyCaron},
+		'È':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: csCZKeyGrave},
+		'Ẽ':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: csCZKeyTilde},
+		'Ė':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}},
+		'Ę':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: csCZKeyHook},
+		'F':  {KeyInfo: KeyInfo{Key: "KeyF", Shift: true}},
+		'Ḟ':  {KeyInfo: KeyInfo{Key: "KeyF", Shift: true}, AccentKey: csCZKeyOverdot},
+		'G':  {KeyInfo: KeyInfo{Key: "KeyG", Shift: true}},
+		'Ġ':  {KeyInfo: KeyInfo{Key: "KeyG", Shift: true}, AccentKey: csCZKeyOverdot},
+		'H':  {KeyInfo: KeyInfo{Key: "KeyH", Shift: true}},
+		'Ḣ':  {KeyInfo: KeyInfo{Key: "KeyH", Shift: true}, AccentKey: csCZKeyOverdot},
+		'I':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}},
+		'Ï':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: csCZKeyTrema},
+		'Í':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: csCZKeyAcute},
+		'Î':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: csCZKeyHat},
+		'Ì':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: csCZKeyGrave},
+		'Ĩ':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: csCZKeyTilde},
+		'İ':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: csCZKeyOverdot},
+		'Į':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: csCZKeyHook},
+		'J':  {KeyInfo: KeyInfo{Key: "KeyJ", Shift: true}},
+		'K':  {KeyInfo: KeyInfo{Key: "KeyK", Shift: true}},
+		'L':  {KeyInfo: KeyInfo{Key: "KeyL", Shift: true}},
+		'Ŀ':  {KeyInfo: KeyInfo{Key: "KeyL", Shift: true}},
+		'M':  {KeyInfo: KeyInfo{Key: "KeyM", Shift: true}},
+		'Ṁ':  {KeyInfo: KeyInfo{Key: "KeyM", Shift: true}},
+		'N':  {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}},
+		'Ň':  {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}, AccentKey: csCZKeyCaron},
+		'Ñ':  {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}, AccentKey: csCZKeyTilde},
+		'Ṅ':  {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}},
+		'O':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}},
+		'Ö':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: csCZKeyTrema},
+		'Ó':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: csCZKeyAcute},
+		'Ô':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: csCZKeyHat},
+		'Ò':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: csCZKeyGrave},
+		'Õ':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: csCZKeyTilde},
+		'Ȯ':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: csCZKeyOverdot},
+		'Ǫ':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: csCZKeyHook},
+		'P':  {KeyInfo: KeyInfo{Key: "KeyP", Shift: true}},
+		'Ṗ':  {KeyInfo: KeyInfo{Key: "KeyP", Shift: true}, AccentKey: csCZKeyOverdot},
+		'Q':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}},
+		'R':  {KeyInfo: KeyInfo{Key: "KeyR", Shift: true}},
+		'Ř':  {KeyInfo: KeyInfo{Key: "KeyR", Shift: true}, AccentKey: csCZKeyCaron},
+		'Ṙ':  {KeyInfo: KeyInfo{Key: "KeyR", Shift: true}, AccentKey: csCZKeyOverdot},
+		'S':  {KeyInfo: KeyInfo{Key: "KeyS", Shift: true}},
+		'Š':  {KeyInfo: KeyInfo{Key: "KeyS", Shift: true}, AccentKey: csCZKeyCaron},
+		'Ṡ':  {KeyInfo: KeyInfo{Key: "KeyS", Shift: true}, AccentKey: csCZKeyOverdot},
+		'T':  {KeyInfo: KeyInfo{Key: "KeyT", Shift: true}},
+		'Ť':  {KeyInfo: KeyInfo{Key: "KeyT", Shift: true}, AccentKey: csCZKeyCaron},
+		'Ṫ':  {KeyInfo: KeyInfo{Key: "KeyT", Shift: true}, AccentKey: csCZKeyOverdot},
+		'U':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}},
+		'Ü':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: csCZKeyTrema},
+		'Ú':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: csCZKeyAcute},
+		'Û':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: csCZKeyHat},
+		'Ù':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: csCZKeyGrave},
+		'Ũ':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: csCZKeyTilde},
+		'Ů':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: csCZKeyRing},
+		'Ų':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: csCZKeyHook},
+		'V':  {KeyInfo: KeyInfo{Key: "KeyV", Shift: true}},
+		'W':  {KeyInfo: KeyInfo{Key: "KeyW", Shift: true}},
+		'Ẇ':  {KeyInfo: KeyInfo{Key: "KeyW", Shift: true}, AccentKey: csCZKeyOverdot},
+		'X':  {KeyInfo: KeyInfo{Key: "KeyX", Shift: true}},
+		'Ẋ':  {KeyInfo: KeyInfo{Key: "KeyX", Shift: true}, AccentKey: csCZKeyOverdot},
+		'Y':  {KeyInfo: KeyInfo{Key: "KeyY", Shift: true}},
+		'Ý':  {KeyInfo: KeyInfo{Key: "KeyY", Shift: true}, AccentKey: csCZKeyAcute},
+		'Ẏ':  {KeyInfo: KeyInfo{Key: "KeyY", Shift: true}, AccentKey: csCZKeyOverdot},
+		'Z':  {KeyInfo: KeyInfo{Key: "KeyZ", Shift: true}},
+		'Ż':  {KeyInfo: KeyInfo{Key: "KeyZ", Shift: true}, AccentKey: csCZKeyOverdot},
+		'a':  {KeyInfo: KeyInfo{Key: "KeyA"}},
+		'ä':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: csCZKeyTrema},
+		'â':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: csCZKeyHat},
+		'à':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: csCZKeyGrave},
+		'ã':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: csCZKeyTilde},
+		'ȧ':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: csCZKeyOverdot},
+		'ą':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: csCZKeyHook},
+		'b':  {KeyInfo: KeyInfo{Key: "KeyB"}},
+		'{':  {KeyInfo: KeyInfo{Key: "KeyB", AltRight: true}},
+		'ḃ':  {KeyInfo: KeyInfo{Key: "KeyB"}, AccentKey: csCZKeyOverdot},
+		'c':  {KeyInfo: KeyInfo{Key: "KeyC"}},
+		'&':  {KeyInfo: KeyInfo{Key: "KeyC", AltRight: true}},
+		'ç':  {KeyInfo: KeyInfo{Key: "KeyC"}, AccentKey: csCZKeyCedille},
+		'ċ':  {KeyInfo: KeyInfo{Key: "KeyC"}, AccentKey: csCZKeyOverdot},
+		'd':  {KeyInfo: KeyInfo{Key: "KeyD"}},
+		'ď':  {KeyInfo: KeyInfo{Key: "KeyD"}, AccentKey: csCZKeyCaron},
+		'ḋ':  {KeyInfo: KeyInfo{Key: "KeyD"}, AccentKey: csCZKeyOverdot},
+		'Đ':  {KeyInfo: KeyInfo{Key: "KeyD", AltRight: true}},
+		'e':  {KeyInfo: KeyInfo{Key: "KeyE"}},
+		'ë':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: csCZKeyTrema},
+		'ê':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: csCZKeyHat},
+		'ẽ':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: csCZKeyTilde},
+		'è':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: csCZKeyGrave},
+		'ė':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: csCZKeyOverdot},
+		'ę':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: csCZKeyHook},
+		'€':  {KeyInfo: KeyInfo{Key: "KeyE", AltRight: true}},
+		'f':  {KeyInfo: KeyInfo{Key: "KeyF"}},
+		'ḟ':  {KeyInfo: KeyInfo{Key: "KeyF"}, AccentKey: csCZKeyOverdot},
+		'[':  {KeyInfo: KeyInfo{Key: "KeyF", AltRight: true}},
+		'g':  {KeyInfo: KeyInfo{Key: "KeyG"}},
+		'ġ':  {KeyInfo: KeyInfo{Key: "KeyG"}, AccentKey: csCZKeyOverdot},
+		']':  {KeyInfo: KeyInfo{Key: "KeyF", AltRight: true}},
+		'h':  {KeyInfo: KeyInfo{Key: "KeyH"}},
+		'ḣ':  {KeyInfo: KeyInfo{Key: "KeyH"}, AccentKey: csCZKeyOverdot},
+		'i':  {KeyInfo: KeyInfo{Key: "KeyI"}},
+		'ï':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: csCZKeyTrema},
+		'î':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: csCZKeyHat},
+		'ì':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: csCZKeyGrave},
+		'ĩ':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: csCZKeyTilde},
+		'ı':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: csCZKeyOverdot},
+		'į':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: csCZKeyHook},
+		'j':  {KeyInfo: KeyInfo{Key: "KeyJ"}},
+		'ȷ':  {KeyInfo: KeyInfo{Key: "KeyJ"}, AccentKey: csCZKeyOverdot},
+		'k':  {KeyInfo: KeyInfo{Key: "KeyK"}},
+		'ł':  {KeyInfo: KeyInfo{Key: "KeyK", AltRight: true}},
+		'l':  {KeyInfo: KeyInfo{Key: "KeyL"}},
+		'ŀ':  {KeyInfo: KeyInfo{Key: "KeyL"}, AccentKey: csCZKeyOverdot},
+		'Ł':  {KeyInfo: KeyInfo{Key: "KeyL", AltRight: true}},
+		'm':  {KeyInfo: KeyInfo{Key: "KeyM"}},
+		'ṁ':  {KeyInfo: KeyInfo{Key: "KeyM"}, AccentKey: csCZKeyOverdot},
+		'n':  {KeyInfo: KeyInfo{Key: "KeyN"}},
+		'}':  {KeyInfo: KeyInfo{Key: "KeyN", AltRight: true}},
+		'ň':  {KeyInfo: KeyInfo{Key: "KeyN"}, AccentKey: csCZKeyCaron},
+		'ñ':  {KeyInfo: KeyInfo{Key: "KeyN"}, AccentKey: csCZKeyTilde},
+		'ṅ':  {KeyInfo: KeyInfo{Key: "KeyN"}, AccentKey: csCZKeyOverdot},
+		'o':  {KeyInfo: KeyInfo{Key: "KeyO"}},
+		'ö':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: csCZKeyTrema},
+		'ó':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: csCZKeyAcute},
+		'ô':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: csCZKeyHat},
+		'ò':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: csCZKeyGrave},
+		'õ':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: csCZKeyTilde},
+		'ȯ':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: csCZKeyOverdot},
+		'ǫ':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: csCZKeyHook},
+		'p':  {KeyInfo: KeyInfo{Key: "KeyP"}},
+		'ṗ':  {KeyInfo: KeyInfo{Key: "KeyP"}, AccentKey: csCZKeyOverdot},
+		'q':  {KeyInfo: KeyInfo{Key: "KeyQ"}},
+		'r':  {KeyInfo: KeyInfo{Key: "KeyR"}},
+		'ṙ':  {KeyInfo: KeyInfo{Key: "KeyR"}, AccentKey: csCZKeyOverdot},
+		's':  {KeyInfo: KeyInfo{Key: "KeyS"}},
+		'ṡ':  {KeyInfo: KeyInfo{Key: "KeyS"}, AccentKey: csCZKeyOverdot},
+		'đ':  {KeyInfo: KeyInfo{Key: "KeyS", AltRight: true}},
+		't':  {KeyInfo: KeyInfo{Key: "KeyT"}},
+		'ť':  {KeyInfo: KeyInfo{Key: "KeyT"}, AccentKey: csCZKeyCaron},
+		'ṫ':  {KeyInfo: KeyInfo{Key: "KeyT"}, AccentKey: csCZKeyOverdot},
+		'u':  {KeyInfo: KeyInfo{Key: "KeyU"}},
+		'ü':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: csCZKeyTrema},
+		'û':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: csCZKeyHat},
+		'ù':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: csCZKeyGrave},
+		'ũ':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: csCZKeyTilde},
+		'ų':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: csCZKeyHook},
+		'v':  {KeyInfo: KeyInfo{Key: "KeyV"}},
+		'@':  {KeyInfo: KeyInfo{Key: "KeyV", AltRight: true}},
+		'w':  {KeyInfo: KeyInfo{Key: "KeyW"}},
+		'ẇ':  {KeyInfo: KeyInfo{Key: "KeyW"}, AccentKey: csCZKeyOverdot},
+		'x':  {KeyInfo: KeyInfo{Key: "KeyX"}},
+		'#':  {KeyInfo: KeyInfo{Key: "KeyX", AltRight: true}},
+		'ẋ':  {KeyInfo: KeyInfo{Key: "KeyX"}, AccentKey: csCZKeyOverdot},
+		'y':  {KeyInfo: KeyInfo{Key: "KeyY"}},
+		'ẏ':  {KeyInfo: KeyInfo{Key: "KeyY"}, AccentKey: csCZKeyOverdot},
+		'z':  {KeyInfo: KeyInfo{Key: "KeyZ"}},
+		'ż':  {KeyInfo: KeyInfo{Key: "KeyZ"}, AccentKey: csCZKeyOverdot},
+		';':  {KeyInfo: KeyInfo{Key: "Backquote"}},
+		'°':  {KeyInfo: KeyInfo{Key: "Backquote", Shift: true}, DeadKey: true},
+		'+':  {KeyInfo: KeyInfo{Key: "Digit1"}},
+		'ě':  {KeyInfo: KeyInfo{Key: "Digit2"}},
+		'š':  {KeyInfo: KeyInfo{Key: "Digit3"}},
+		'č':  {KeyInfo: KeyInfo{Key: "Digit4"}},
+		'ř':  {KeyInfo: KeyInfo{Key: "Digit5"}},
+		'ž':  {KeyInfo: KeyInfo{Key: "Digit6"}},
+		'ý':  {KeyInfo: KeyInfo{Key: "Digit7"}},
+		'á':  {KeyInfo: KeyInfo{Key: "Digit8"}},
+		'í':  {KeyInfo: KeyInfo{Key: "Digit9"}},
+		'é':  {KeyInfo: KeyInfo{Key: "Digit0"}},
+		'=':  {KeyInfo: KeyInfo{Key: "Minus"}},
+		'%':  {KeyInfo: KeyInfo{Key: "Minus", Shift: true}},
+		'ú':  {KeyInfo: KeyInfo{Key: "BracketLeft"}},
+		'/':  {KeyInfo: KeyInfo{Key: "BracketLeft", Shift: true}},
+		')':  {KeyInfo: KeyInfo{Key: "BracketRight"}},
+		'(':  {KeyInfo: KeyInfo{Key: "BracketRight", Shift: true}},
+		'ů':  {KeyInfo: KeyInfo{Key: "Semicolon"}},
+		'"':  {KeyInfo: KeyInfo{Key: "Semicolon", Shift: true}},
+		'§':  {KeyInfo: KeyInfo{Key: "Quote"}},
+		'!':  {KeyInfo: KeyInfo{Key: "Quote", Shift: true}},
+		'\'': {KeyInfo: KeyInfo{Key: "Backslash", Shift: true}},
+		',':  {KeyInfo: KeyInfo{Key: "Comma"}},
+		'?':  {KeyInfo: KeyInfo{Key: "Comma", Shift: true}},
+		'<':  {KeyInfo: KeyInfo{Key: "Comma", AltRight: true}},
+		'.':  {KeyInfo: KeyInfo{Key: "Period"}},
+		':':  {KeyInfo: KeyInfo{Key: "Period", Shift: true}},
+		'>':  {KeyInfo: KeyInfo{Key: "Period", AltRight: true}},
+		'-':  {KeyInfo: KeyInfo{Key: "Slash"}},
+		'_':  {KeyInfo: KeyInfo{Key: "Slash", Shift: true}},
+		'*':  {KeyInfo: KeyInfo{Key: "Slash", AltRight: true}},
+		'\\': {KeyInfo: KeyInfo{Key: "IntlBackslash"}},
+		'|':  {KeyInfo: KeyInfo{Key: "IntlBackslash", Shift: true}},
+		' ':  {KeyInfo: KeyInfo{Key: "Space"}},
+		'\n': {KeyInfo: KeyInfo{Key: "Enter"}},
+	},
+}
@@ -0,0 +1,175 @@
+package keyboard
+
+var (
+	daDKKeyTrema = &KeyInfo{Key: "BracketRight"}
+	daDKKeyAcute = &KeyInfo{Key: "Equal", AltRight: true}
+	daDKKeyHat   = &KeyInfo{Key: "BracketRight", Shift: true}
+	daDKKeyGrave = &KeyInfo{Key: "Equal", Shift: true}
+	daDKKeyTilde = &KeyInfo{Key: "BracketRight", AltRight: true}
+)
+
+var daDK = Layout{
+	ISOCode: "da-DK",
+	Name:    "Dansk",
+	Chars: map[rune]KeyCombo{
+		'0':  {KeyInfo: KeyInfo{Key: "Digit0"}},
+		'1':  {KeyInfo: KeyInfo{Key: "Digit1"}},
+		'2':  {KeyInfo: KeyInfo{Key: "Digit2"}},
+		'3':  {KeyInfo: KeyInfo{Key: "Digit3"}},
+		'4':  {KeyInfo: KeyInfo{Key: "Digit4"}},
+		'5':  {KeyInfo: KeyInfo{Key: "Digit5"}},
+		'6':  {KeyInfo: KeyInfo{Key: "Digit6"}},
+		'7':  {KeyInfo: KeyInfo{Key: "Digit7"}},
+		'8':  {KeyInfo: KeyInfo{Key: "Digit8"}},
+		'9':  {KeyInfo: KeyInfo{Key: "Digit9"}},
+		'A':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}},
+		'Ä':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: daDKKeyTrema},
+		'Á':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: daDKKeyAcute},
+		'Â':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: daDKKeyHat},
+		'À':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: daDKKeyGrave},
+		'Ã':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: daDKKeyTilde},
+		'B':  {KeyInfo: KeyInfo{Key: "KeyB", Shift: true}},
+		'C':  {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}},
+		'D':  {KeyInfo: KeyInfo{Key: "KeyD", Shift: true}},
+		'E':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}},
+		'Ë':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: daDKKeyTrema},
+		'É':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: daDKKeyAcute},
+		'Ê':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: daDKKeyHat},
+		'È':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: daDKKeyGrave},
+		'Ẽ':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: daDKKeyTilde},
+		'F':  {KeyInfo: KeyInfo{Key: "KeyF", Shift: true}},
+		'G':  {KeyInfo: KeyInfo{Key: "KeyG", Shift: true}},
+		'H':  {KeyInfo: KeyInfo{Key: "KeyH", Shift: true}},
+		'I':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}},
+		'Ï':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: daDKKeyTrema},
+		'Í':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: daDKKeyAcute},
+		'Î':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: daDKKeyHat},
+		'Ì':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: daDKKeyGrave},
+		'Ĩ':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: daDKKeyTilde},
+		'J':  {KeyInfo: KeyInfo{Key: "KeyJ", Shift: true}},
+		'K':  {KeyInfo: KeyInfo{Key: "KeyK", Shift: true}},
+		'L':  {KeyInfo: KeyInfo{Key: "KeyL", Shift: true}},
+		'M':  {KeyInfo: KeyInfo{Key: "KeyM", Shift: true}},
+		'N':  {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}},
+		'O':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}},
+		'Ö':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: daDKKeyTrema},
+		'Ó':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: daDKKeyAcute},
+		'Ô':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: daDKKeyHat},
+		'Ò':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: daDKKeyGrave},
+		'Õ':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: daDKKeyTilde},
+		'P':  {KeyInfo: KeyInfo{Key: "KeyP", Shift: true}},
+		'Q':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}},
+		'R':  {KeyInfo: KeyInfo{Key: "KeyR", Shift: true}},
+		'S':  {KeyInfo: KeyInfo{Key: "KeyS", Shift: true}},
+		'T':  {KeyInfo: KeyInfo{Key: "KeyT", Shift: true}},
+		'U':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}},
+		'Ü':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: daDKKeyTrema},
+		'Ú':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: daDKKeyAcute},
+		'Û':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: daDKKeyHat},
+		'Ù':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: daDKKeyGrave},
+		'Ũ':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: daDKKeyTilde},
+		'V':  {KeyInfo: KeyInfo{Key: "KeyV", Shift: true}},
+		'W':  {KeyInfo: KeyInfo{Key: "KeyW", Shift: true}},
+		'X':  {KeyInfo: KeyInfo{Key: "KeyX", Shift: true}},
+		'Y':  {KeyInfo: KeyInfo{Key: "KeyY", Shift: true}},
+		'Z':  {KeyInfo: KeyInfo{Key: "KeyZ", Shift: true}},
+		'a':  {KeyInfo: KeyInfo{Key: "KeyA"}},
+		'ä':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: daDKKeyTrema},
+		'á':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: daDKKeyAcute},
+		'â':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: daDKKeyHat},
+		'à':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: daDKKeyGrave},
+		'ã':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: daDKKeyTilde},
+		'b':  {KeyInfo: KeyInfo{Key: "KeyB"}},
+		'c':  {KeyInfo: KeyInfo{Key: "KeyC"}},
+		'd':  {KeyInfo: KeyInfo{Key: "KeyD"}},
+		'e':  {KeyInfo: KeyInfo{Key: "KeyE"}},
+		'ë':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: daDKKeyTrema},
+		'é':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: daDKKeyAcute},
+		'ê':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: daDKKeyHat},
+		'è':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: daDKKeyGrave},
+		'ẽ':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: daDKKeyTilde},
+		'€':  {KeyInfo: KeyInfo{Key: "KeyE", AltRight: true}},
+		'f':  {KeyInfo: KeyInfo{Key: "KeyF"}},
+		'g':  {KeyInfo: KeyInfo{Key: "KeyG"}},
+		'h':  {KeyInfo: KeyInfo{Key: "KeyH"}},
+		'i':  {KeyInfo: KeyInfo{Key: "KeyI"}},
+		'ï':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: daDKKeyTrema},
+		'í':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: daDKKeyAcute},
+		'î':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: daDKKeyHat},
+		'ì':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: daDKKeyGrave},
+		'ĩ':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: daDKKeyTilde},
+		'j':  {KeyInfo: KeyInfo{Key: "KeyJ"}},
+		'k':  {KeyInfo: KeyInfo{Key: "KeyK"}},
+		'l':  {KeyInfo: KeyInfo{Key: "KeyL"}},
+		'm':  {KeyInfo: KeyInfo{Key: "KeyM"}},
+		'n':  {KeyInfo: KeyInfo{Key: "KeyN"}},
+		'o':  {KeyInfo: KeyInfo{Key: "KeyO"}},
+		'ö':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: daDKKeyTrema},
+		'ó':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: daDKKeyAcute},
+		'ô':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: daDKKeyHat},
+		'ò':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: daDKKeyGrave},
+		'õ':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: daDKKeyTilde},
+		'p':  {KeyInfo: KeyInfo{Key: "KeyP"}},
+		'q':  {KeyInfo: KeyInfo{Key: "KeyQ"}},
+		'r':  {KeyInfo: KeyInfo{Key: "KeyR"}},
+		's':  {KeyInfo: KeyInfo{Key: "KeyS"}},
+		't':  {KeyInfo: KeyInfo{Key: "KeyT"}},
+		'u':  {KeyInfo: KeyInfo{Key: "KeyU"}},
+		'ü':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: daDKKeyTrema},
+		'ú':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: daDKKeyAcute},
+		'û':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: daDKKeyHat},
+		'ù':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: daDKKeyGrave},
+		'ũ':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: daDKKeyTilde},
+		'v':  {KeyInfo: KeyInfo{Key: "KeyV"}},
+		'w':  {KeyInfo: KeyInfo{Key: "KeyW"}},
+		'x':  {KeyInfo: KeyInfo{Key: "KeyX"}},
+		'y':  {KeyInfo: KeyInfo{Key: "KeyY"}}, // <-- corrected
+		'z':  {KeyInfo: KeyInfo{Key: "KeyZ"}}, // <-- corrected
+		'½':  {KeyInfo: KeyInfo{Key: "Backquote"}},
+		'§':  {KeyInfo: KeyInfo{Key: "Backquote", Shift: true}},
+		'!':  {KeyInfo: KeyInfo{Key: "Digit1", Shift: true}},
+		'"':  {KeyInfo: KeyInfo{Key: "Digit2", Shift: true}},
+		'@':  {KeyInfo: KeyInfo{Key: "Digit2", AltRight: true}},
+		'#':  {KeyInfo: KeyInfo{Key: "Digit3", Shift: true}},
+		'£':  {KeyInfo: KeyInfo{Key: "Digit3", AltRight: true}},
+		'¤':  {KeyInfo: KeyInfo{Key: "Digit4", Shift: true}},
+		'$':  {KeyInfo: KeyInfo{Key: "Digit4", AltRight: true}},
+		'%':  {KeyInfo: KeyInfo{Key: "Digit5", Shift: true}},
+		'&':  {KeyInfo: KeyInfo{Key: "Digit6", Shift: true}},
+		'/':  {KeyInfo: KeyInfo{Key: "Digit7", Shift: true}},
+		'{':  {KeyInfo: KeyInfo{Key: "Digit7", AltRight: true}},
+		'(':  {KeyInfo: KeyInfo{Key: "Digit8", Shift: true}},
+		'[':  {KeyInfo: KeyInfo{Key: "Digit8", AltRight: true}},
+		')':  {KeyInfo: KeyInfo{Key: "Digit9", Shift: true}},
+		']':  {KeyInfo: KeyInfo{Key: "Digit9", AltRight: true}},
+		'=':  {KeyInfo: KeyInfo{Key: "Digit0", Shift: true}},
+		'}':  {KeyInfo: KeyInfo{Key: "Digit0", AltRight: true}},
+		'+':  {KeyInfo: KeyInfo{Key: "Minus"}},
+		'?':  {KeyInfo: KeyInfo{Key: "Minus", Shift: true}},
+		'\\': {KeyInfo: KeyInfo{Key: "Equal"}},
+		'å':  {KeyInfo: KeyInfo{Key: "BracketLeft"}},
+		'Å':  {KeyInfo: KeyInfo{Key: "BracketLeft", Shift: true}},
+		'ø':  {KeyInfo: KeyInfo{Key: "Semicolon"}},
+		'Ø':  {KeyInfo: KeyInfo{Key: "Semicolon", Shift: true}},
+		'æ':  {KeyInfo: KeyInfo{Key: "Quote"}},
+		'Æ':  {KeyInfo: KeyInfo{Key: "Quote", Shift: true}},
+		'\'': {KeyInfo: KeyInfo{Key: "Backslash"}},
+		'*':  {KeyInfo: KeyInfo{Key: "Backslash", Shift: true}},
+		',':  {KeyInfo: KeyInfo{Key: "Comma"}},
+		';':  {KeyInfo: KeyInfo{Key: "Comma", Shift: true}},
+		'.':  {KeyInfo: KeyInfo{Key: "Period"}},
+		':':  {KeyInfo: KeyInfo{Key: "Period", Shift: true}},
+		'-':  {KeyInfo: KeyInfo{Key: "Slash"}},
+		'_':  {KeyInfo: KeyInfo{Key: "Slash", Shift: true}},
+		'<':  {KeyInfo: KeyInfo{Key: "IntlBackslash"}},
+		'>':  {KeyInfo: KeyInfo{Key: "IntlBackslash", Shift: true}},
+		'~':  {KeyInfo: KeyInfo{Key: "BracketRight", AltRight: true}, DeadKey: true},
+		'^':  {KeyInfo: KeyInfo{Key: "BracketRight", Shift: true}, DeadKey: true},
+		'¨':  {KeyInfo: KeyInfo{Key: "BracketRight"}, DeadKey: true},
+		'|':  {KeyInfo: KeyInfo{Key: "Equal", AltRight: true}, DeadKey: true},
+		'`':  {KeyInfo: KeyInfo{Key: "Equal", Shift: true}, DeadKey: true},
+		'´':  {KeyInfo: KeyInfo{Key: "Equal"}, DeadKey: true},
+		' ':  {KeyInfo: KeyInfo{Key: "Space"}},
+		'\n': {KeyInfo: KeyInfo{Key: "Enter"}},
+	},
+}
@@ -0,0 +1,167 @@
+package keyboard
+
+var (
+	deCHKeyTrema = &KeyInfo{Key: "BracketRight"}          // tréma (umlaut), two dots placed above a vowel
+	deCHKeyAcute = &KeyInfo{Key: "Minus", AltRight: true} // accent aigu (acute accent), mark ´ placed above the letter
+	deCHKeyHat   = &KeyInfo{Key: "Equal"}                 // accent circonflexe (accent hat), mark ^ placed above the letter
+	deCHKeyGrave = &KeyInfo{Key: "Equal", Shift: true}    // accent grave, mark ` placed above the letter
+	deCHKeyTilde = &KeyInfo{Key: "Equal", AltRight: true} // tilde, mark ~ placed above the letter
+)
+
+var deCH = Layout{
+	ISOCode: "de-CH",
+	Name:    "Schwiizerdütsch",
+	Chars: map[rune]KeyCombo{
+		'0':  {KeyInfo: KeyInfo{Key: "Digit0"}},
+		'1':  {KeyInfo: KeyInfo{Key: "Digit1"}},
+		'2':  {KeyInfo: KeyInfo{Key: "Digit2"}},
+		'3':  {KeyInfo: KeyInfo{Key: "Digit3"}},
+		'4':  {KeyInfo: KeyInfo{Key: "Digit4"}},
+		'5':  {KeyInfo: KeyInfo{Key: "Digit5"}},
+		'6':  {KeyInfo: KeyInfo{Key: "Digit6"}},
+		'7':  {KeyInfo: KeyInfo{Key: "Digit7"}},
+		'8':  {KeyInfo: KeyInfo{Key: "Digit8"}},
+		'9':  {KeyInfo: KeyInfo{Key: "Digit9"}},
+		'A':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}},
+		'Ä':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: deCHKeyTrema},
+		'Á':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: deCHKeyAcute},
+		'Â':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: deCHKeyHat},
+		'À':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: deCHKeyGrave},
+		'Ã':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: deCHKeyTilde},
+		'B':  {KeyInfo: KeyInfo{Key: "KeyB", Shift: true}},
+		'C':  {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}},
+		'D':  {KeyInfo: KeyInfo{Key: "KeyD", Shift: true}},
+		'E':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}},
+		'Ë':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: deCHKeyTrema},
+		'É':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: deCHKeyAcute},
+		'Ê':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: deCHKeyHat},
+		'È':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: deCHKeyGrave},
+		'Ẽ':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: deCHKeyTilde},
+		'F':  {KeyInfo: KeyInfo{Key: "KeyF", Shift: true}},
+		'G':  {KeyInfo: KeyInfo{Key: "KeyG", Shift: true}},
+		'H':  {KeyInfo: KeyInfo{Key: "KeyH", Shift: true}},
+		'I':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}},
+		'Ï':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: deCHKeyTrema},
+		'Í':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: deCHKeyAcute},
+		'Î':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: deCHKeyHat},
+		'Ì':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: deCHKeyGrave},
+		'Ĩ':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: deCHKeyTilde},
+		'J':  {KeyInfo: KeyInfo{Key: "KeyJ", Shift: true}},
+		'K':  {KeyInfo: KeyInfo{Key: "KeyK", Shift: true}},
+		'L':  {KeyInfo: KeyInfo{Key: "KeyL", Shift: true}},
+		'M':  {KeyInfo: KeyInfo{Key: "KeyM", Shift: true}},
+		'N':  {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}},
+		'O':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}},
+		'Ö':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: deCHKeyTrema},
+		'Ó':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: deCHKeyAcute},
+		'Ô':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: deCHKeyHat},
+		'Ò':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: deCHKeyGrave},
+		'Õ':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: deCHKeyTilde},
+		'P':  {KeyInfo: KeyInfo{Key: "KeyP", Shift: true}},
+		'Q':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}},
+		'R':  {KeyInfo: KeyInfo{Key: "KeyR", Shift: true}},
+		'S':  {KeyInfo: KeyInfo{Key: "KeyS", Shift: true}},
+		'T':  {KeyInfo: KeyInfo{Key: "KeyT", Shift: true}},
+		'U':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}},
+		'Ü':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: deCHKeyTrema},
+		'Ú':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: deCHKeyAcute},
+		'Û':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: deCHKeyHat},
+		'Ù':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: deCHKeyGrave},
+		'Ũ':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: deCHKeyTilde},
+		'V':  {KeyInfo: KeyInfo{Key: "KeyV", Shift: true}},
+		'W':  {KeyInfo: KeyInfo{Key: "KeyW", Shift: true}},
+		'X':  {KeyInfo: KeyInfo{Key: "KeyX", Shift: true}},
+		'Y':  {KeyInfo: KeyInfo{Key: "KeyZ", Shift: true}},
+		'Z':  {KeyInfo: KeyInfo{Key: "KeyY", Shift: true}},
+		'a':  {KeyInfo: KeyInfo{Key: "KeyA"}},
+		'á':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: deCHKeyAcute},
+		'â':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: deCHKeyHat},
+		'ã':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: deCHKeyTilde},
+		'b':  {KeyInfo: KeyInfo{Key: "KeyB"}},
+		'c':  {KeyInfo: KeyInfo{Key: "KeyC"}},
+		'd':  {KeyInfo: KeyInfo{Key: "KeyD"}},
+		'e':  {KeyInfo: KeyInfo{Key: "KeyE"}},
+		'ë':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: deCHKeyTrema},
+		'ê':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: deCHKeyHat},
+		'ẽ':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: deCHKeyTilde},
+		'€':  {KeyInfo: KeyInfo{Key: "KeyE", AltRight: true}},
+		'f':  {KeyInfo: KeyInfo{Key: "KeyF"}},
+		'g':  {KeyInfo: KeyInfo{Key: "KeyG"}},
+		'h':  {KeyInfo: KeyInfo{Key: "KeyH"}},
+		'i':  {KeyInfo: KeyInfo{Key: "KeyI"}},
+		'ï':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: deCHKeyTrema},
+		'í':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: deCHKeyAcute},
+		'î':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: deCHKeyHat},
+		'ì':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: deCHKeyGrave},
+		'ĩ':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: deCHKeyTilde},
+		'j':  {KeyInfo: KeyInfo{Key: "KeyJ"}},
+		'k':  {KeyInfo: KeyInfo{Key: "KeyK"}},
+		'l':  {KeyInfo: KeyInfo{Key: "KeyL"}},
+		'm':  {KeyInfo: KeyInfo{Key: "KeyM"}},
+		'n':  {KeyInfo: KeyInfo{Key: "KeyN"}},
+		'o':  {KeyInfo: KeyInfo{Key: "KeyO"}},
+		'ó':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: deCHKeyAcute},
+		'ô':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: deCHKeyHat},
+		'ò':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: deCHKeyGrave},
+		'õ':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: deCHKeyTilde},
+		'p':  {KeyInfo: KeyInfo{Key: "KeyP"}},
+		'q':  {KeyInfo: KeyInfo{Key: "KeyQ"}},
+		'r':  {KeyInfo: KeyInfo{Key: "KeyR"}},
+		's':  {KeyInfo: KeyInfo{Key: "KeyS"}},
+		't':  {KeyInfo: KeyInfo{Key: "KeyT"}},
+		'u':  {KeyInfo: KeyInfo{Key: "KeyU"}},
+		'ú':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: deCHKeyAcute},
+		'û':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: deCHKeyHat},
+		'ù':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: deCHKeyGrave},
+		'ũ':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: deCHKeyTilde},
+		'v':  {KeyInfo: KeyInfo{Key: "KeyV"}},
+		'w':  {KeyInfo: KeyInfo{Key: "KeyW"}},
+		'x':  {KeyInfo: KeyInfo{Key: "KeyX"}},
+		'y':  {KeyInfo: KeyInfo{Key: "KeyZ"}},
+		'z':  {KeyInfo: KeyInfo{Key: "KeyY"}},
+		'§':  {KeyInfo: KeyInfo{Key: "Backquote"}},
+		'°':  {KeyInfo: KeyInfo{Key: "Backquote", Shift: true}},
+		'+':  {KeyInfo: KeyInfo{Key: "Digit1", Shift: true}},
+		'|':  {KeyInfo: KeyInfo{Key: "Digit1", AltRight: true}},
+		'"':  {KeyInfo: KeyInfo{Key: "Digit2", Shift: true}},
+		'@':  {KeyInfo: KeyInfo{Key: "Digit2", AltRight: true}},
+		'*':  {KeyInfo: KeyInfo{Key: "Digit3", Shift: true}},
+		'#':  {KeyInfo: KeyInfo{Key: "Digit3", AltRight: true}},
+		'ç':  {KeyInfo: KeyInfo{Key: "Digit4", Shift: true}},
+		'%':  {KeyInfo: KeyInfo{Key: "Digit5", Shift: true}},
+		'&':  {KeyInfo: KeyInfo{Key: "Digit6", Shift: true}},
+		'/':  {KeyInfo: KeyInfo{Key: "Digit7", Shift: true}},
+		'(':  {KeyInfo: KeyInfo{Key: "Digit8", Shift: true}},
+		')':  {KeyInfo: KeyInfo{Key: "Digit9", Shift: true}},
+		'=':  {KeyInfo: KeyInfo{Key: "Digit0", Shift: true}},
+		'\'': {KeyInfo: KeyInfo{Key: "Minus"}},
+		'?':  {KeyInfo: KeyInfo{Key: "Minus", Shift: true}},
+		'^':  {KeyInfo: KeyInfo{Key: "Equal"}, DeadKey: true},
+		'`':  {KeyInfo: KeyInfo{Key: "Equal", Shift: true}},
+		'~':  {KeyInfo: KeyInfo{Key: "Equal", AltRight: true}, DeadKey: true},
+		'ü':  {KeyInfo: KeyInfo{Key: "BracketLeft"}},
+		'è':  {KeyInfo: KeyInfo{Key: "BracketLeft", Shift: true}},
+		'[':  {KeyInfo: KeyInfo{Key: "BracketLeft", AltRight: true}},
+		'!':  {KeyInfo: KeyInfo{Key: "BracketRight", Shift: true}},
+		']':  {KeyInfo: KeyInfo{Key: "BracketRight", AltRight: true}},
+		'ö':  {KeyInfo: KeyInfo{Key: "Semicolon"}},
+		'é':  {KeyInfo: KeyInfo{Key: "Semicolon", Shift: true}},
+		'ä':  {KeyInfo: KeyInfo{Key: "Quote"}},
+		'à':  {KeyInfo: KeyInfo{Key: "Quote", Shift: true}},
+		'{':  {KeyInfo: KeyInfo{Key: "Quote", AltRight: true}},
+		'$':  {KeyInfo: KeyInfo{Key: "Backslash"}},
+		'£':  {KeyInfo: KeyInfo{Key: "Backslash", Shift: true}},
+		'}':  {KeyInfo: KeyInfo{Key: "Backslash", AltRight: true}},
+		',':  {KeyInfo: KeyInfo{Key: "Comma"}},
+		';':  {KeyInfo: KeyInfo{Key: "Comma", Shift: true}},
+		'.':  {KeyInfo: KeyInfo{Key: "Period"}},
+		':':  {KeyInfo: KeyInfo{Key: "Period", Shift: true}},
+		'-':  {KeyInfo: KeyInfo{Key: "Slash"}},
+		'_':  {KeyInfo: KeyInfo{Key: "Slash", Shift: true}},
+		'<':  {KeyInfo: KeyInfo{Key: "IntlBackslash"}},
+		'>':  {KeyInfo: KeyInfo{Key: "IntlBackslash", Shift: true}},
+		'\\': {KeyInfo: KeyInfo{Key: "IntlBackslash", AltRight: true}},
+		' ':  {KeyInfo: KeyInfo{Key: "Space"}},
+		'\n': {KeyInfo: KeyInfo{Key: "Enter"}},
+	},
+}
@@ -0,0 +1,188 @@
+package keyboard
+
+var (
+	deDEKeyAcute = &KeyInfo{Key: "Equal"}              // accent aigu (acute accent), mark ´ placed above the letter
+	deDEKeyHat   = &KeyInfo{Key: "Backquote"}          // accent circonflexe (accent hat), mark ^ placed above the letter
+	deDEKeyGrave = &KeyInfo{Key: "Equal", Shift: true} // accent grave, mark ` placed above the letter
+)
+
+var deDE = Layout{
+	ISOCode: "de-DE",
+	Name:    "Deutsch",
+	Chars: map[rune]KeyCombo{
+		'0':      {KeyInfo: KeyInfo{Key: "Digit0"}},
+		'1':      {KeyInfo: KeyInfo{Key: "Digit1"}},
+		'2':      {KeyInfo: KeyInfo{Key: "Digit2"}},
+		'3':      {KeyInfo: KeyInfo{Key: "Digit3"}},
+		'4':      {KeyInfo: KeyInfo{Key: "Digit4"}},
+		'5':      {KeyInfo: KeyInfo{Key: "Digit5"}},
+		'6':      {KeyInfo: KeyInfo{Key: "Digit6"}},
+		'7':      {KeyInfo: KeyInfo{Key: "Digit7"}},
+		'8':      {KeyInfo: KeyInfo{Key: "Digit8"}},
+		'9':      {KeyInfo: KeyInfo{Key: "Digit9"}},
+		'a':      {KeyInfo: KeyInfo{Key: "KeyA"}},
+		'á':      {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: deDEKeyAcute},
+		'â':      {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: deDEKeyHat},
+		'à':      {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: deDEKeyGrave},
+		'A':      {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}},
+		'Á':      {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: deDEKeyAcute},
+		'Â':      {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: deDEKeyHat},
+		'À':      {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: deDEKeyGrave},
+		'☺':      {KeyInfo: KeyInfo{Key: "KeyA", AltRight: true}}, // white smiling face ☺
+		'b':      {KeyInfo: KeyInfo{Key: "KeyB"}},
+		'B':      {KeyInfo: KeyInfo{Key: "KeyB", Shift: true}},
+		'‹':      {KeyInfo: KeyInfo{Key: "KeyB", AltRight: true}}, // single left-pointing angle quotation mark, ‹
+		'c':      {KeyInfo: KeyInfo{Key: "KeyC"}},
+		'C':      {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}},
+		'\u202f': {KeyInfo: KeyInfo{Key: "KeyC", AltRight: true}}, // narrow no-break space
+		'd':      {KeyInfo: KeyInfo{Key: "KeyD"}},
+		'D':      {KeyInfo: KeyInfo{Key: "KeyD", Shift: true}},
+		'′':      {KeyInfo: KeyInfo{Key: "KeyD", AltRight: true}}, // prime, mark ′ placed above the letter
+		'e':      {KeyInfo: KeyInfo{Key: "KeyE"}},
+		'é':      {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: deDEKeyAcute},
+		'ê':      {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: deDEKeyHat},
+		'è':      {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: deDEKeyGrave},
+		'€':      {KeyInfo: KeyInfo{Key: "KeyE", AltRight: true}},
+		'E':      {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}},
+		'É':      {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: deDEKeyAcute},
+		'Ê':      {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: deDEKeyHat},
+		'È':      {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: deDEKeyGrave},
+		'f':      {KeyInfo: KeyInfo{Key: "KeyF"}},
+		'F':      {KeyInfo: KeyInfo{Key: "KeyF", Shift: true}},
+		'˟':      {KeyInfo: KeyInfo{Key: "KeyF", AltRight: true}, DeadKey: true}, // modifier letter cross accent, ˟
+		'G':      {KeyInfo: KeyInfo{Key: "KeyG", Shift: true}},
+		'g':      {KeyInfo: KeyInfo{Key: "KeyG"}},
+		'ẞ':      {KeyInfo: KeyInfo{Key: "KeyG", AltRight: true}}, // capital sharp S, ẞ
+		'h':      {KeyInfo: KeyInfo{Key: "KeyH"}},
+		'H':      {KeyInfo: KeyInfo{Key: "KeyH", Shift: true}},
+		'ˍ':      {KeyInfo: KeyInfo{Key: "KeyH", AltRight: true}, DeadKey: true}, // modifier letter low macron, ˍ
+		'i':      {KeyInfo: KeyInfo{Key: "KeyI"}},
+		'í':      {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: deDEKeyAcute},
+		'î':      {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: deDEKeyHat},
+		'ì':      {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: deDEKeyGrave},
+		'I':      {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}},
+		'Í':      {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: deDEKeyAcute},
+		'Î':      {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: deDEKeyHat},
+		'Ì':      {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: deDEKeyGrave},
+		'˜':      {KeyInfo: KeyInfo{Key: "KeyI", AltRight: true}, DeadKey: true}, // tilde accent, mark ˜ placed above the letter
+		'j':      {KeyInfo: KeyInfo{Key: "KeyJ"}},
+		'J':      {KeyInfo: KeyInfo{Key: "KeyJ", Shift: true}},
+		'¸':      {KeyInfo: KeyInfo{Key: "KeyJ", AltRight: true}, DeadKey: true}, // cedilla accent, mark ¸ placed below the letter
+		'k':      {KeyInfo: KeyInfo{Key: "KeyK"}},
+		'K':      {KeyInfo: KeyInfo{Key: "KeyK", Shift: true}},
+		'l':      {KeyInfo: KeyInfo{Key: "KeyL"}},
+		'L':      {KeyInfo: KeyInfo{Key: "KeyL", Shift: true}},
+		'ˏ':      {KeyInfo: KeyInfo{Key: "KeyL", AltRight: true}, DeadKey: true}, // modifier letter reversed comma, ˏ
+		'm':      {KeyInfo: KeyInfo{Key: "KeyM"}},
+		'M':      {KeyInfo: KeyInfo{Key: "KeyM", Shift: true}},
+		'µ':      {KeyInfo: KeyInfo{Key: "KeyM", AltRight: true}},
+		'n':      {KeyInfo: KeyInfo{Key: "KeyN"}},
+		'N':      {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}},
+		'–':      {KeyInfo: KeyInfo{Key: "KeyN", AltRight: true}}, // en dash, –
+		'o':      {KeyInfo: KeyInfo{Key: "KeyO"}},
+		'ó':      {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: deDEKeyAcute},
+		'ô':      {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: deDEKeyHat},
+		'ò':      {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: deDEKeyGrave},
+		'O':      {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}},
+		'Ó':      {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: deDEKeyAcute},
+		'Ô':      {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: deDEKeyHat},
+		'Ò':      {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: deDEKeyGrave},
+		'˚':      {KeyInfo: KeyInfo{Key: "KeyO", AltRight: true}, DeadKey: true}, // ring above, ˚
+		'p':      {KeyInfo: KeyInfo{Key: "KeyP"}},
+		'P':      {KeyInfo: KeyInfo{Key: "KeyP", Shift: true}},
+		'ˀ':      {KeyInfo: KeyInfo{Key: "KeyP", AltRight: true}, DeadKey: true}, // modifier letter apostrophe, ʾ
+		'q':      {KeyInfo: KeyInfo{Key: "KeyQ"}},
+		'Q':      {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}},
+		'@':      {KeyInfo: KeyInfo{Key: "KeyQ", AltRight: true}},
+		'R':      {KeyInfo: KeyInfo{Key: "KeyR", Shift: true}},
+		'r':      {KeyInfo: KeyInfo{Key: "KeyR"}},
+		'˝':      {KeyInfo: KeyInfo{Key: "KeyR", AltRight: true}, DeadKey: true}, // double acute accent, mark ˝ placed above the letter
+		'S':      {KeyInfo: KeyInfo{Key: "KeyS", Shift: true}},
+		's':      {KeyInfo: KeyInfo{Key: "KeyS"}},
+		'″':      {KeyInfo: KeyInfo{Key: "KeyS", AltRight: true}}, // double prime, mark ″ placed above the letter
+		'T':      {KeyInfo: KeyInfo{Key: "KeyT", Shift: true}},
+		't':      {KeyInfo: KeyInfo{Key: "KeyT"}},
+		'ˇ':      {KeyInfo: KeyInfo{Key: "KeyT", AltRight: true}, DeadKey: true}, // caron/hacek accent, mark ˇ placed above the letter
+		'u':      {KeyInfo: KeyInfo{Key: "KeyU"}},
+		'ú':      {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: deDEKeyAcute},
+		'û':      {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: deDEKeyHat},
+		'ù':      {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: deDEKeyGrave},
+		'U':      {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}},
+		'Ú':      {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: deDEKeyAcute},
+		'Û':      {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: deDEKeyHat},
+		'Ù':      {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: deDEKeyGrave},
+		'˘':      {KeyInfo: KeyInfo{Key: "KeyU", AltRight: true}, DeadKey: true}, // breve accent, ˘ placed above the letter
+		'v':      {KeyInfo: KeyInfo{Key: "KeyV"}},
+		'V':      {KeyInfo: KeyInfo{Key: "KeyV", Shift: true}},
+		'«':      {KeyInfo: KeyInfo{Key: "KeyV", AltRight: true}}, // left-pointing double angle quotation mark, «
+		'w':      {KeyInfo: KeyInfo{Key: "KeyW"}},
+		'W':      {KeyInfo: KeyInfo{Key: "KeyW", Shift: true}},
+		'¯':      {KeyInfo: KeyInfo{Key: "KeyW", AltRight: true}, DeadKey: true}, // macron accent, mark ¯ placed above the letter
+		'x':      {KeyInfo: KeyInfo{Key: "KeyX"}},
+		'X':      {KeyInfo: KeyInfo{Key: "KeyX", Shift: true}},
+		'»':      {KeyInfo: KeyInfo{Key: "KeyX", AltRight: true}},
+		'y':      {KeyInfo: KeyInfo{Key: "KeyZ"}},
+		'Y':      {KeyInfo: KeyInfo{Key: "KeyZ", Shift: true}},
+		'›':      {KeyInfo: KeyInfo{Key: "KeyZ", AltRight: true}}, // single right-pointing angle quotation mark, ›
+		'z':      {KeyInfo: KeyInfo{Key: "KeyY"}},
+		'Z':      {KeyInfo: KeyInfo{Key: "KeyY", Shift: true}},
+		'¨':      {KeyInfo: KeyInfo{Key: "KeyY", AltRight: true}, DeadKey: true}, // diaeresis accent, mark ¨ placed above the letter
+		'°':      {KeyInfo: KeyInfo{Key: "Backquote", Shift: true}},
+		'^':      {KeyInfo: KeyInfo{Key: "Backquote"}, DeadKey: true},
+		'|':      {KeyInfo: KeyInfo{Key: "Backquote", AltRight: true}},
+		'!':      {KeyInfo: KeyInfo{Key: "Digit1", Shift: true}},
+		'’':      {KeyInfo: KeyInfo{Key: "Digit1", AltRight: true}}, // single quote, mark ’ placed above the letter
+		'"':      {KeyInfo: KeyInfo{Key: "Digit2", Shift: true}},
+		'²':      {KeyInfo: KeyInfo{Key: "Digit2", AltRight: true}},
+		'<':      {KeyInfo: KeyInfo{Key: "Digit2", AltRight: true}}, // non-US < and >
+		'§':      {KeyInfo: KeyInfo{Key: "Digit3", Shift: true}},
+		'³':      {KeyInfo: KeyInfo{Key: "Digit3", AltRight: true}},
+		'>':      {KeyInfo: KeyInfo{Key: "Digit3", AltRight: true}}, // non-US < and >
+		'$':      {KeyInfo: KeyInfo{Key: "Digit4", Shift: true}},
+		'—':      {KeyInfo: KeyInfo{Key: "Digit4", AltRight: true}}, // em dash, —
+		'%':      {KeyInfo: KeyInfo{Key: "Digit5", Shift: true}},
+		'¡':      {KeyInfo: KeyInfo{Key: "Digit5", AltRight: true}}, // inverted exclamation mark, ¡
+		'&':      {KeyInfo: KeyInfo{Key: "Digit6", Shift: true}},
+		'¿':      {KeyInfo: KeyInfo{Key: "Digit6", AltRight: true}}, // inverted question mark, ¿
+		'/':      {KeyInfo: KeyInfo{Key: "Digit7", Shift: true}},
+		'{':      {KeyInfo: KeyInfo{Key: "Digit7", AltRight: true}},
+		'(':      {KeyInfo: KeyInfo{Key: "Digit8", Shift: true}},
+		'[':      {KeyInfo: KeyInfo{Key: "Digit8", AltRight: true}},
+		')':      {KeyInfo: KeyInfo{Key: "Digit9", Shift: true}},
+		']':      {KeyInfo: KeyInfo{Key: "Digit9", AltRight: true}},
+		'=':      {KeyInfo: KeyInfo{Key: "Digit0", Shift: true}},
+		'}':      {KeyInfo: KeyInfo{Key: "Digit0", AltRight: true}},
+		'ß':      {KeyInfo: KeyInfo{Key: "Minus"}},
+		'?':      {KeyInfo: KeyInfo{Key: "Minus", Shift: true}},
+		'\\':     {KeyInfo: KeyInfo{Key: "Minus", AltRight: true}},
+		'´':      {KeyInfo: KeyInfo{Key: "Equal"}, DeadKey: true},                 // accent acute, mark ´ placed above the letter
+		'`':      {KeyInfo: KeyInfo{Key: "Equal", Shift: true}, DeadKey: true},    // accent grave, mark ` placed above the letter
+		'˙':      {KeyInfo: KeyInfo{Key: "Equal", AltRight: true}, DeadKey: true}, // acute accent, mark ˙ placed above the letter
+		'ü':      {KeyInfo: KeyInfo{Key: "BracketLeft"}},
+		'Ü':      {KeyInfo: KeyInfo{Key: "BracketLeft", Shift: true}},
+		'ʼ':      {KeyInfo: KeyInfo{Key: "BracketLeft", AltRight: true}}, // modifier letter apostrophe, ʼ
+		'+':      {KeyInfo: KeyInfo{Key: "BracketRight"}},
+		'*':      {KeyInfo: KeyInfo{Key: "BracketRight", Shift: true}},
+		'~':      {KeyInfo: KeyInfo{Key: "BracketRight", AltRight: true}},
+		'ö':      {KeyInfo: KeyInfo{Key: "Semicolon"}},
+		'Ö':      {KeyInfo: KeyInfo{Key: "Semicolon", Shift: true}},
+		'ˌ':      {KeyInfo: KeyInfo{Key: "Semicolon", AltRight: true}}, // modifier letter low vertical line, ˌ
+		'ä':      {KeyInfo: KeyInfo{Key: "Quote"}},
+		'Ä':      {KeyInfo: KeyInfo{Key: "Quote", Shift: true}},
+		'˗':      {KeyInfo: KeyInfo{Key: "Quote", AltRight: true}, DeadKey: true}, // modifier letter minus sign, ˗
+		'#':      {KeyInfo: KeyInfo{Key: "Backslash"}},
+		'\'':     {KeyInfo: KeyInfo{Key: "Backslash", Shift: true}},
+		'−':      {KeyInfo: KeyInfo{Key: "Backslash", AltRight: true}}, // minus sign, −
+		',':      {KeyInfo: KeyInfo{Key: "Comma"}},
+		';':      {KeyInfo: KeyInfo{Key: "Comma", Shift: true}},
+		'‑':      {KeyInfo: KeyInfo{Key: "Comma", AltRight: true}}, // non-breaking hyphen, ‑
+		'.':      {KeyInfo: KeyInfo{Key: "Period"}},
+		':':      {KeyInfo: KeyInfo{Key: "Period", Shift: true}},
+		'·':      {KeyInfo: KeyInfo{Key: "Period", AltRight: true}}, // middle dot, ·
+		'-':      {KeyInfo: KeyInfo{Key: "Slash"}},
+		'_':      {KeyInfo: KeyInfo{Key: "Slash", Shift: true}},
+		'\u00ad': {KeyInfo: KeyInfo{Key: "Slash", AltRight: true}}, // soft hyphen, ­
+		' ':      {KeyInfo: KeyInfo{Key: "Space"}},
+		'\n':     {KeyInfo: KeyInfo{Key: "Enter"}},
+	},
+}
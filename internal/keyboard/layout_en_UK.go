@@ -0,0 +1,107 @@
+package keyboard
+
+var enUK = Layout{
+	ISOCode: "en-UK",
+	Name:    "English (UK)",
+	Chars: map[rune]KeyCombo{
+		'0':  {KeyInfo: KeyInfo{Key: "Digit0"}},
+		'1':  {KeyInfo: KeyInfo{Key: "Digit1"}},
+		'2':  {KeyInfo: KeyInfo{Key: "Digit2"}},
+		'3':  {KeyInfo: KeyInfo{Key: "Digit3"}},
+		'4':  {KeyInfo: KeyInfo{Key: "Digit4"}},
+		'5':  {KeyInfo: KeyInfo{Key: "Digit5"}},
+		'6':  {KeyInfo: KeyInfo{Key: "Digit6"}},
+		'7':  {KeyInfo: KeyInfo{Key: "Digit7"}},
+		'8':  {KeyInfo: KeyInfo{Key: "Digit8"}},
+		'9':  {KeyInfo: KeyInfo{Key: "Digit9"}},
+		'A':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}},
+		'B':  {KeyInfo: KeyInfo{Key: "KeyB", Shift: true}},
+		'C':  {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}},
+		'D':  {KeyInfo: KeyInfo{Key: "KeyD", Shift: true}},
+		'E':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}},
+		'F':  {KeyInfo: KeyInfo{Key: "KeyF", Shift: true}},
+		'G':  {KeyInfo: KeyInfo{Key: "KeyG", Shift: true}},
+		'H':  {KeyInfo: KeyInfo{Key: "KeyH", Shift: true}},
+		'I':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}},
+		'J':  {KeyInfo: KeyInfo{Key: "KeyJ", Shift: true}},
+		'K':  {KeyInfo: KeyInfo{Key: "KeyK", Shift: true}},
+		'L':  {KeyInfo: KeyInfo{Key: "KeyL", Shift: true}},
+		'M':  {KeyInfo: KeyInfo{Key: "KeyM", Shift: true}},
+		'N':  {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}},
+		'O':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}},
+		'P':  {KeyInfo: KeyInfo{Key: "KeyP", Shift: true}},
+		'Q':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}},
+		'R':  {KeyInfo: KeyInfo{Key: "KeyR", Shift: true}},
+		'S':  {KeyInfo: KeyInfo{Key: "KeyS", Shift: true}},
+		'T':  {KeyInfo: KeyInfo{Key: "KeyT", Shift: true}},
+		'U':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}},
+		'V':  {KeyInfo: KeyInfo{Key: "KeyV", Shift: true}},
+		'W':  {KeyInfo: KeyInfo{Key: "KeyW", Shift: true}},
+		'X':  {KeyInfo: KeyInfo{Key: "KeyX", Shift: true}},
+		'Y':  {KeyInfo: KeyInfo{Key: "KeyY", Shift: true}},
+		'Z':  {KeyInfo: KeyInfo{Key: "KeyZ", Shift: true}},
+		'a':  {KeyInfo: KeyInfo{Key: "KeyA"}},
+		'b':  {KeyInfo: KeyInfo{Key: "KeyB"}},
+		'c':  {KeyInfo: KeyInfo{Key: "KeyC"}},
+		'd':  {KeyInfo: KeyInfo{Key: "KeyD"}},
+		'e':  {KeyInfo: KeyInfo{Key: "KeyE"}},
+		'f':  {KeyInfo: KeyInfo{Key: "KeyF"}},
+		'g':  {KeyInfo: KeyInfo{Key: "KeyG"}},
+		'h':  {KeyInfo: KeyInfo{Key: "KeyH"}},
+		'i':  {KeyInfo: KeyInfo{Key: "KeyI"}},
+		'j':  {KeyInfo: KeyInfo{Key: "KeyJ"}},
+		'k':  {KeyInfo: KeyInfo{Key: "KeyK"}},
+		'l':  {KeyInfo: KeyInfo{Key: "KeyL"}},
+		'm':  {KeyInfo: KeyInfo{Key: "KeyM"}},
+		'n':  {KeyInfo: KeyInfo{Key: "KeyN"}},
+		'o':  {KeyInfo: KeyInfo{Key: "KeyO"}},
+		'p':  {KeyInfo: KeyInfo{Key: "KeyP"}},
+		'q':  {KeyInfo: KeyInfo{Key: "KeyQ"}},
+		'r':  {KeyInfo: KeyInfo{Key: "KeyR"}},
+		's':  {KeyInfo: KeyInfo{Key: "KeyS"}},
+		't':  {KeyInfo: KeyInfo{Key: "KeyT"}},
+		'u':  {KeyInfo: KeyInfo{Key: "KeyU"}},
+		'v':  {KeyInfo: KeyInfo{Key: "KeyV"}},
+		'w':  {KeyInfo: KeyInfo{Key: "KeyW"}},
+		'x':  {KeyInfo: KeyInfo{Key: "KeyX"}},
+		'y':  {KeyInfo: KeyInfo{Key: "KeyY"}},
+		'z':  {KeyInfo: KeyInfo{Key: "KeyZ"}},
+		'!':  {KeyInfo: KeyInfo{Key: "Digit1", Shift: true}},
+		'"':  {KeyInfo: KeyInfo{Key: "Digit2", Shift: true}},
+		'£':  {KeyInfo: KeyInfo{Key: "Digit3", Shift: true}},
+		'$':  {KeyInfo: KeyInfo{Key: "Digit4", Shift: true}},
+		'€':  {KeyInfo: KeyInfo{Key: "Digit4", AltRight: true}},
+		'%':  {KeyInfo: KeyInfo{Key: "Digit5", Shift: true}},
+		'^':  {KeyInfo: KeyInfo{Key: "Digit6", Shift: true}},
+		'&':  {KeyInfo: KeyInfo{Key: "Digit7", Shift: true}},
+		'*':  {KeyInfo: KeyInfo{Key: "Digit8", Shift: true}},
+		'(':  {KeyInfo: KeyInfo{Key: "Digit9", Shift: true}},
+		')':  {KeyInfo: KeyInfo{Key: "Digit0", Shift: true}},
+		'-':  {KeyInfo: KeyInfo{Key: "Minus"}},
+		'_':  {KeyInfo: KeyInfo{Key: "Minus", Shift: true}},
+		'=':  {KeyInfo: KeyInfo{Key: "Equal"}},
+		'+':  {KeyInfo: KeyInfo{Key: "Equal", Shift: true}},
+		'\'': {KeyInfo: KeyInfo{Key: "Quote"}},
+		'@':  {KeyInfo: KeyInfo{Key: "Quote", Shift: true}},
+		',':  {KeyInfo: KeyInfo{Key: "Comma"}},
+		'<':  {KeyInfo: KeyInfo{Key: "Comma", Shift: true}},
+		'/':  {KeyInfo: KeyInfo{Key: "Slash"}},
+		'?':  {KeyInfo: KeyInfo{Key: "Slash", Shift: true}},
+		'.':  {KeyInfo: KeyInfo{Key: "Period"}},
+		'>':  {KeyInfo: KeyInfo{Key: "Period", Shift: true}},
+		';':  {KeyInfo: KeyInfo{Key: "Semicolon"}},
+		':':  {KeyInfo: KeyInfo{Key: "Semicolon", Shift: true}},
+		'[':  {KeyInfo: KeyInfo{Key: "BracketLeft"}},
+		'{':  {KeyInfo: KeyInfo{Key: "BracketLeft", Shift: true}},
+		']':  {KeyInfo: KeyInfo{Key: "BracketRight"}},
+		'}':  {KeyInfo: KeyInfo{Key: "BracketRight", Shift: true}},
+		'#':  {KeyInfo: KeyInfo{Key: "Backslash"}},
+		'~':  {KeyInfo: KeyInfo{Key: "Backslash", Shift: true}},
+		'`':  {KeyInfo: KeyInfo{Key: "Backquote"}},
+		'¬':  {KeyInfo: KeyInfo{Key: "Backquote", Shift: true}},
+		'\\': {KeyInfo: KeyInfo{Key: "IntlBackslash"}},
+		'|':  {KeyInfo: KeyInfo{Key: "IntlBackslash", Shift: true}},
+		' ':  {KeyInfo: KeyInfo{Key: "Space"}},
+		'\n': {KeyInfo: KeyInfo{Key: "Enter"}},
+	},
+}
@@ -0,0 +1,170 @@
+package keyboard
+
+var (
+	esESKeyTrema = &KeyInfo{Key: "Quote", Shift: true}        // tréma (umlaut), two dots placed above a vowel
+	esESKeyAcute = &KeyInfo{Key: "Quote"}                     // accent aigu (acute accent), mark ´ placed above the letter
+	esESKeyHat   = &KeyInfo{Key: "BracketRight", Shift: true} // accent circonflexe (accent hat), mark ^ placed above the letter
+	esESKeyGrave = &KeyInfo{Key: "BracketRight"}              // accent grave, mark ` placed above the letter
+	esESKeyTilde = &KeyInfo{Key: "Digit4", AltRight: true}    // tilde, mark ~ placed above the letter
+)
+
+var esES = Layout{
+	ISOCode: "es-ES",
+	Name:    "Español",
+	Chars: map[rune]KeyCombo{
+		'0':  {KeyInfo: KeyInfo{Key: "Digit0"}},
+		'1':  {KeyInfo: KeyInfo{Key: "Digit1"}},
+		'2':  {KeyInfo: KeyInfo{Key: "Digit2"}},
+		'3':  {KeyInfo: KeyInfo{Key: "Digit3"}},
+		'4':  {KeyInfo: KeyInfo{Key: "Digit4"}},
+		'5':  {KeyInfo: KeyInfo{Key: "Digit5"}},
+		'6':  {KeyInfo: KeyInfo{Key: "Digit6"}},
+		'7':  {KeyInfo: KeyInfo{Key: "Digit7"}},
+		'8':  {KeyInfo: KeyInfo{Key: "Digit8"}},
+		'9':  {KeyInfo: KeyInfo{Key: "Digit9"}},
+		'A':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}},
+		'Ä':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: esESKeyTrema},
+		'Á':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: esESKeyAcute},
+		'Â':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: esESKeyHat},
+		'À':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: esESKeyGrave},
+		'Ã':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: esESKeyTilde},
+		'B':  {KeyInfo: KeyInfo{Key: "KeyB", Shift: true}},
+		'C':  {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}},
+		'D':  {KeyInfo: KeyInfo{Key: "KeyD", Shift: true}},
+		'E':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}},
+		'Ë':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: esESKeyTrema},
+		'É':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: esESKeyAcute},
+		'Ê':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: esESKeyHat},
+		'È':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: esESKeyGrave},
+		'Ẽ':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: esESKeyTilde},
+		'F':  {KeyInfo: KeyInfo{Key: "KeyF", Shift: true}},
+		'G':  {KeyInfo: KeyInfo{Key: "KeyG", Shift: true}},
+		'H':  {KeyInfo: KeyInfo{Key: "KeyH", Shift: true}},
+		'I':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}},
+		'Ï':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: esESKeyTrema},
+		'Í':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: esESKeyAcute},
+		'Î':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: esESKeyHat},
+		'Ì':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: esESKeyGrave},
+		'Ĩ':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: esESKeyTilde},
+		'J':  {KeyInfo: KeyInfo{Key: "KeyJ", Shift: true}},
+		'K':  {KeyInfo: KeyInfo{Key: "KeyK", Shift: true}},
+		'L':  {KeyInfo: KeyInfo{Key: "KeyL", Shift: true}},
+		'M':  {KeyInfo: KeyInfo{Key: "KeyM", Shift: true}},
+		'N':  {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}},
+		'O':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}},
+		'Ö':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: esESKeyTrema},
+		'Ó':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: esESKeyAcute},
+		'Ô':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: esESKeyHat},
+		'Ò':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: esESKeyGrave},
+		'Õ':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: esESKeyTilde},
+		'P':  {KeyInfo: KeyInfo{Key: "KeyP", Shift: true}},
+		'Q':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}},
+		'R':  {KeyInfo: KeyInfo{Key: "KeyR", Shift: true}},
+		'S':  {KeyInfo: KeyInfo{Key: "KeyS", Shift: true}},
+		'T':  {KeyInfo: KeyInfo{Key: "KeyT", Shift: true}},
+		'U':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}},
+		'Ü':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: esESKeyTrema},
+		'Ú':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: esESKeyAcute},
+		'Û':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: esESKeyHat},
+		'Ù':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: esESKeyGrave},
+		'Ũ':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: esESKeyTilde},
+		'V':  {KeyInfo: KeyInfo{Key: "KeyV", Shift: true}},
+		'W':  {KeyInfo: KeyInfo{Key: "KeyW", Shift: true}},
+		'X':  {KeyInfo: KeyInfo{Key: "KeyX", Shift: true}},
+		'Y':  {KeyInfo: KeyInfo{Key: "KeyY", Shift: true}},
+		'Z':  {KeyInfo: KeyInfo{Key: "KeyZ", Shift: true}},
+		'a':  {KeyInfo: KeyInfo{Key: "KeyA"}},
+		'ä':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: esESKeyTrema},
+		'á':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: esESKeyAcute},
+		'â':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: esESKeyHat},
+		'à':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: esESKeyGrave},
+		'ã':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: esESKeyTilde},
+		'b':  {KeyInfo: KeyInfo{Key: "KeyB"}},
+		'c':  {KeyInfo: KeyInfo{Key: "KeyC"}},
+		'd':  {KeyInfo: KeyInfo{Key: "KeyD"}},
+		'e':  {KeyInfo: KeyInfo{Key: "KeyE"}},
+		'ë':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: esESKeyTrema},
+		'é':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: esESKeyAcute},
+		'ê':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: esESKeyHat},
+		'è':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: esESKeyGrave},
+		'ẽ':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: esESKeyTilde},
+		'€':  {KeyInfo: KeyInfo{Key: "KeyE", AltRight: true}},
+		'f':  {KeyInfo: KeyInfo{Key: "KeyF"}},
+		'g':  {KeyInfo: KeyInfo{Key: "KeyG"}},
+		'h':  {KeyInfo: KeyInfo{Key: "KeyH"}},
+		'i':  {KeyInfo: KeyInfo{Key: "KeyI"}},
+		'ï':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: esESKeyTrema},
+		'í':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: esESKeyAcute},
+		'î':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: esESKeyHat},
+		'ì':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: esESKeyGrave},
+		'ĩ':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: esESKeyTilde},
+		'j':  {KeyInfo: KeyInfo{Key: "KeyJ"}},
+		'k':  {KeyInfo: KeyInfo{Key: "KeyK"}},
+		'l':  {KeyInfo: KeyInfo{Key: "KeyL"}},
+		'm':  {KeyInfo: KeyInfo{Key: "KeyM"}},
+		'n':  {KeyInfo: KeyInfo{Key: "KeyN"}},
+		'o':  {KeyInfo: KeyInfo{Key: "KeyO"}},
+		'ö':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: esESKeyTrema},
+		'ó':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: esESKeyAcute},
+		'ô':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: esESKeyHat},
+		'ò':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: esESKeyGrave},
+		'õ':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: esESKeyTilde},
+		'p':  {KeyInfo: KeyInfo{Key: "KeyP"}},
+		'q':  {KeyInfo: KeyInfo{Key: "KeyQ"}},
+		'r':  {KeyInfo: KeyInfo{Key: "KeyR"}},
+		's':  {KeyInfo: KeyInfo{Key: "KeyS"}},
+		't':  {KeyInfo: KeyInfo{Key: "KeyT"}},
+		'u':  {KeyInfo: KeyInfo{Key: "KeyU"}},
+		'ü':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: esESKeyTrema},
+		'ú':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: esESKeyAcute},
+		'û':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: esESKeyHat},
+		'ù':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: esESKeyGrave},
+		'ũ':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: esESKeyTilde},
+		'v':  {KeyInfo: KeyInfo{Key: "KeyV"}},
+		'w':  {KeyInfo: KeyInfo{Key: "KeyW"}},
+		'x':  {KeyInfo: KeyInfo{Key: "KeyX"}},
+		'y':  {KeyInfo: KeyInfo{Key: "KeyY"}},
+		'z':  {KeyInfo: KeyInfo{Key: "KeyZ"}},
+		'º':  {KeyInfo: KeyInfo{Key: "Backquote"}},
+		'ª':  {KeyInfo: KeyInfo{Key: "Backquote", Shift: true}},
+		'\\': {KeyInfo: KeyInfo{Key: "Backquote", AltRight: true}},
+		'!':  {KeyInfo: KeyInfo{Key: "Digit1", Shift: true}},
+		'|':  {KeyInfo: KeyInfo{Key: "Digit1", AltRight: true}},
+		'"':  {KeyInfo: KeyInfo{Key: "Digit2", Shift: true}},
+		'@':  {KeyInfo: KeyInfo{Key: "Digit2", AltRight: true}},
+		'·':  {KeyInfo: KeyInfo{Key: "Digit3", Shift: true}},
+		'#':  {KeyInfo: KeyInfo{Key: "Digit3", AltRight: true}},
+		'$':  {KeyInfo: KeyInfo{Key: "Digit4", Shift: true}},
+		'%':  {KeyInfo: KeyInfo{Key: "Digit5", Shift: true}},
+		'&':  {KeyInfo: KeyInfo{Key: "Digit6", Shift: true}},
+		'¬':  {KeyInfo: KeyInfo{Key: "Digit6", AltRight: true}},
+		'/':  {KeyInfo: KeyInfo{Key: "Digit7", Shift: true}},
+		'(':  {KeyInfo: KeyInfo{Key: "Digit8", Shift: true}},
+		')':  {KeyInfo: KeyInfo{Key: "Digit9", Shift: true}},
+		'=':  {KeyInfo: KeyInfo{Key: "Digit0", Shift: true}},
+		'\'': {KeyInfo: KeyInfo{Key: "Minus"}},
+		'?':  {KeyInfo: KeyInfo{Key: "Minus", Shift: true}},
+		'¡':  {KeyInfo: KeyInfo{Key: "Equal"}, DeadKey: true},
+		'¿':  {KeyInfo: KeyInfo{Key: "Equal", Shift: true}},
+		'[':  {KeyInfo: KeyInfo{Key: "BracketLeft", AltRight: true}},
+		'+':  {KeyInfo: KeyInfo{Key: "BracketRight"}},
+		'*':  {KeyInfo: KeyInfo{Key: "BracketRight", Shift: true}},
+		']':  {KeyInfo: KeyInfo{Key: "BracketRight", AltRight: true}},
+		'ñ':  {KeyInfo: KeyInfo{Key: "Semicolon"}},
+		'Ñ':  {KeyInfo: KeyInfo{Key: "Semicolon", Shift: true}},
+		'{':  {KeyInfo: KeyInfo{Key: "Quote", AltRight: true}},
+		'ç':  {KeyInfo: KeyInfo{Key: "Backslash"}},
+		'Ç':  {KeyInfo: KeyInfo{Key: "Backslash", Shift: true}},
+		'}':  {KeyInfo: KeyInfo{Key: "Backslash", AltRight: true}},
+		',':  {KeyInfo: KeyInfo{Key: "Comma"}},
+		';':  {KeyInfo: KeyInfo{Key: "Comma", Shift: true}},
+		'.':  {KeyInfo: KeyInfo{Key: "Period"}},
+		':':  {KeyInfo: KeyInfo{Key: "Period", Shift: true}},
+		'-':  {KeyInfo: KeyInfo{Key: "Slash"}},
+		'_':  {KeyInfo: KeyInfo{Key: "Slash", Shift: true}},
+		'<':  {KeyInfo: KeyInfo{Key: "IntlBackslash"}},
+		'>':  {KeyInfo: KeyInfo{Key: "IntlBackslash", Shift: true}},
+		' ':  {KeyInfo: KeyInfo{Key: "Space"}},
+		'\n': {KeyInfo: KeyInfo{Key: "Enter"}},
+	},
+}
@@ -0,0 +1,169 @@
+package keyboard
+
+var (
+	frBEKeyTrema = &KeyInfo{Key: "BracketLeft", Shift: true}  // tréma (umlaut), two dots placed above a vowel
+	frBEKeyHat   = &KeyInfo{Key: "BracketLeft"}               // accent circonflexe (accent hat), mark ^ placed above the letter
+	frBEKeyAcute = &KeyInfo{Key: "Semicolon", AltRight: true} // accent aigu (acute accent), mark ´ placed above the letter
+	frBEKeyGrave = &KeyInfo{Key: "Quote", Shift: true}        // accent grave, mark ` placed above the letter
+	frBEKeyTilde = &KeyInfo{Key: "Slash", AltRight: true}     // tilde, mark ~ placed above the letter
+)
+
+var frBE = Layout{
+	ISOCode: "nl-BE",
+	Name:    "Belgisch Nederlands",
+	Chars: map[rune]KeyCombo{
+		'0':  {KeyInfo: KeyInfo{Key: "Digit0", Shift: true}},
+		'1':  {KeyInfo: KeyInfo{Key: "Digit1", Shift: true}},
+		'2':  {KeyInfo: KeyInfo{Key: "Digit2", Shift: true}},
+		'3':  {KeyInfo: KeyInfo{Key: "Digit3", Shift: true}},
+		'4':  {KeyInfo: KeyInfo{Key: "Digit4", Shift: true}},
+		'5':  {KeyInfo: KeyInfo{Key: "Digit5", Shift: true}},
+		'6':  {KeyInfo: KeyInfo{Key: "Digit6", Shift: true}},
+		'7':  {KeyInfo: KeyInfo{Key: "Digit7", Shift: true}},
+		'8':  {KeyInfo: KeyInfo{Key: "Digit8", Shift: true}},
+		'9':  {KeyInfo: KeyInfo{Key: "Digit9", Shift: true}},
+		'A':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}},
+		'Ä':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}, AccentKey: frBEKeyTrema},
+		'Â':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}, AccentKey: frBEKeyHat},
+		'Á':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}, AccentKey: frBEKeyAcute},
+		'À':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}, AccentKey: frBEKeyGrave},
+		'Ã':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}, AccentKey: frBEKeyTilde},
+		'B':  {KeyInfo: KeyInfo{Key: "KeyB", Shift: true}},
+		'C':  {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}},
+		'D':  {KeyInfo: KeyInfo{Key: "KeyD", Shift: true}},
+		'E':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}},
+		'Ë':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: frBEKeyTrema},
+		'Ê':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: frBEKeyHat},
+		'É':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: frBEKeyAcute},
+		'È':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: frBEKeyGrave},
+		'Ẽ':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: frBEKeyTilde},
+		'F':  {KeyInfo: KeyInfo{Key: "KeyF", Shift: true}},
+		'G':  {KeyInfo: KeyInfo{Key: "KeyG", Shift: true}},
+		'H':  {KeyInfo: KeyInfo{Key: "KeyH", Shift: true}},
+		'I':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}},
+		'Ï':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: frBEKeyTrema},
+		'Î':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: frBEKeyHat},
+		'Í':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: frBEKeyAcute},
+		'Ì':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: frBEKeyGrave},
+		'Ĩ':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: frBEKeyTilde},
+		'J':  {KeyInfo: KeyInfo{Key: "KeyJ", Shift: true}},
+		'K':  {KeyInfo: KeyInfo{Key: "KeyK", Shift: true}},
+		'L':  {KeyInfo: KeyInfo{Key: "KeyL", Shift: true}},
+		'M':  {KeyInfo: KeyInfo{Key: "Semicolon", Shift: true}},
+		'N':  {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}},
+		'O':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}},
+		'Ö':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: frBEKeyTrema},
+		'Ô':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: frBEKeyHat},
+		'Ó':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: frBEKeyAcute},
+		'Ò':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: frBEKeyGrave},
+		'Õ':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: frBEKeyTilde},
+		'P':  {KeyInfo: KeyInfo{Key: "KeyP", Shift: true}},
+		'Q':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}},
+		'R':  {KeyInfo: KeyInfo{Key: "KeyR", Shift: true}},
+		'S':  {KeyInfo: KeyInfo{Key: "KeyS", Shift: true}},
+		'T':  {KeyInfo: KeyInfo{Key: "KeyT", Shift: true}},
+		'U':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}},
+		'Ü':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: frBEKeyTrema},
+		'Û':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: frBEKeyHat},
+		'Ú':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: frBEKeyAcute},
+		'Ù':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: frBEKeyGrave},
+		'Ũ':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: frBEKeyTilde},
+		'V':  {KeyInfo: KeyInfo{Key: "KeyV", Shift: true}},
+		'W':  {KeyInfo: KeyInfo{Key: "KeyW", Shift: true}},
+		'X':  {KeyInfo: KeyInfo{Key: "KeyX", Shift: true}},
+		'Y':  {KeyInfo: KeyInfo{Key: "KeyZ", Shift: true}},
+		'Z':  {KeyInfo: KeyInfo{Key: "KeyY", Shift: true}},
+		'a':  {KeyInfo: KeyInfo{Key: "KeyQ"}},
+		'ä':  {KeyInfo: KeyInfo{Key: "KeyQ"}, AccentKey: frBEKeyTrema},
+		'â':  {KeyInfo: KeyInfo{Key: "KeyQ"}, AccentKey: frBEKeyHat},
+		'á':  {KeyInfo: KeyInfo{Key: "KeyQ"}, AccentKey: frBEKeyAcute},
+		'ã':  {KeyInfo: KeyInfo{Key: "KeyQ"}, AccentKey: frBEKeyTilde},
+		'b':  {KeyInfo: KeyInfo{Key: "KeyB"}},
+		'c':  {KeyInfo: KeyInfo{Key: "KeyC"}},
+		'd':  {KeyInfo: KeyInfo{Key: "KeyD"}},
+		'e':  {KeyInfo: KeyInfo{Key: "KeyE"}},
+		'ë':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: frBEKeyTrema},
+		'ê':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: frBEKeyHat},
+		'ẽ':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: frBEKeyTilde},
+		'€':  {KeyInfo: KeyInfo{Key: "KeyE", AltRight: true}},
+		'f':  {KeyInfo: KeyInfo{Key: "KeyF"}},
+		'g':  {KeyInfo: KeyInfo{Key: "KeyG"}},
+		'h':  {KeyInfo: KeyInfo{Key: "KeyH"}},
+		'i':  {KeyInfo: KeyInfo{Key: "KeyI"}},
+		'ï':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: frBEKeyTrema},
+		'î':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: frBEKeyHat},
+		'í':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: frBEKeyAcute},
+		'ì':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: frBEKeyGrave},
+		'ĩ':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: frBEKeyTilde},
+		'j':  {KeyInfo: KeyInfo{Key: "KeyJ"}},
+		'k':  {KeyInfo: KeyInfo{Key: "KeyK"}},
+		'l':  {KeyInfo: KeyInfo{Key: "KeyL"}},
+		'm':  {KeyInfo: KeyInfo{Key: "Semicolon"}},
+		'n':  {KeyInfo: KeyInfo{Key: "KeyN"}},
+		'o':  {KeyInfo: KeyInfo{Key: "KeyO"}},
+		'ö':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: frBEKeyTrema},
+		'ó':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: frBEKeyAcute},
+		'ô':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: frBEKeyHat},
+		'ò':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: frBEKeyGrave},
+		'õ':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: frBEKeyTilde},
+		'p':  {KeyInfo: KeyInfo{Key: "KeyP"}},
+		'q':  {KeyInfo: KeyInfo{Key: "KeyA"}},
+		'r':  {KeyInfo: KeyInfo{Key: "KeyR"}},
+		's':  {KeyInfo: KeyInfo{Key: "KeyS"}},
+		't':  {KeyInfo: KeyInfo{Key: "KeyT"}},
+		'u':  {KeyInfo: KeyInfo{Key: "KeyU"}},
+		'ü':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: frBEKeyTrema},
+		'û':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: frBEKeyHat},
+		'ú':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: frBEKeyAcute},
+		'ũ':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: frBEKeyTilde},
+		'v':  {KeyInfo: KeyInfo{Key: "KeyV"}},
+		'w':  {KeyInfo: KeyInfo{Key: "KeyW"}},
+		'x':  {KeyInfo: KeyInfo{Key: "KeyX"}},
+		'y':  {KeyInfo: KeyInfo{Key: "KeyZ"}},
+		'z':  {KeyInfo: KeyInfo{Key: "KeyY"}},
+		'²':  {KeyInfo: KeyInfo{Key: "Backquote"}},
+		'³':  {KeyInfo: KeyInfo{Key: "Backquote", Shift: true}},
+		'&':  {KeyInfo: KeyInfo{Key: "Digit1"}},
+		'|':  {KeyInfo: KeyInfo{Key: "Digit1", AltRight: true}},
+		'é':  {KeyInfo: KeyInfo{Key: "Digit2"}},
+		'@':  {KeyInfo: KeyInfo{Key: "Digit2", AltRight: true}},
+		'"':  {KeyInfo: KeyInfo{Key: "Digit3"}},
+		'#':  {KeyInfo: KeyInfo{Key: "Digit3", AltRight: true}},
+		'\'': {KeyInfo: KeyInfo{Key: "Digit4"}},
+		'(':  {KeyInfo: KeyInfo{Key: "Digit5"}},
+		'§':  {KeyInfo: KeyInfo{Key: "Digit6"}},
+		'^':  {KeyInfo: KeyInfo{Key: "Digit6", AltRight: true}},
+		'è':  {KeyInfo: KeyInfo{Key: "Digit7"}},
+		'!':  {KeyInfo: KeyInfo{Key: "Digit8"}},
+		'ç':  {KeyInfo: KeyInfo{Key: "Digit9"}},
+		'{':  {KeyInfo: KeyInfo{Key: "Digit9", AltRight: true}},
+		'à':  {KeyInfo: KeyInfo{Key: "Digit0"}},
+		'}':  {KeyInfo: KeyInfo{Key: "Digit0", AltRight: true}},
+		')':  {KeyInfo: KeyInfo{Key: "Minus"}},
+		'°':  {KeyInfo: KeyInfo{Key: "Minus", Shift: true}},
+		'-':  {KeyInfo: KeyInfo{Key: "Equal"}, DeadKey: true},
+		'_':  {KeyInfo: KeyInfo{Key: "Equal", Shift: true}},
+		'[':  {KeyInfo: KeyInfo{Key: "BracketLeft", AltRight: true}},
+		'$':  {KeyInfo: KeyInfo{Key: "BracketRight"}},
+		'*':  {KeyInfo: KeyInfo{Key: "BracketRight", AltRight: true}},
+		']':  {KeyInfo: KeyInfo{Key: "BracketRight", AltRight: true}},
+		'ù':  {KeyInfo: KeyInfo{Key: "Quote"}},
+		'%':  {KeyInfo: KeyInfo{Key: "Quote", Shift: true}},
+		'µ':  {KeyInfo: KeyInfo{Key: "Backslash"}},
+		'£':  {KeyInfo: KeyInfo{Key: "Backslash", Shift: true}},
+		',':  {KeyInfo: KeyInfo{Key: "KeyM"}},
+		'?':  {KeyInfo: KeyInfo{Key: "KeyM", Shift: true}},
+		';':  {KeyInfo: KeyInfo{Key: "Comma"}},
+		'.':  {KeyInfo: KeyInfo{Key: "Comma", Shift: true}},
+		':':  {KeyInfo: KeyInfo{Key: "Period"}},
+		'/':  {KeyInfo: KeyInfo{Key: "Period", Shift: true}},
+		'=':  {KeyInfo: KeyInfo{Key: "Slash"}},
+		'+':  {KeyInfo: KeyInfo{Key: "Slash", Shift: true}},
+		'~':  {KeyInfo: KeyInfo{Key: "Slash"}, DeadKey: true},
+		'<':  {KeyInfo: KeyInfo{Key: "IntlBackslash"}},
+		'>':  {KeyInfo: KeyInfo{Key: "IntlBackslash", Shift: true}},
+		'\\': {KeyInfo: KeyInfo{Key: "IntlBackslash", AltRight: true}},
+		' ':  {KeyInfo: KeyInfo{Key: "Space"}},
+		'\n': {KeyInfo: KeyInfo{Key: "Enter"}},
+	},
+}
@@ -0,0 +1,14 @@
+package keyboard
+
+var frCH = Layout{
+	ISOCode: "fr-CH",
+	Name:    "Français de Suisse",
+	Chars: withChars(deCH.Chars, map[rune]KeyCombo{
+		'è': {KeyInfo: KeyInfo{Key: "BracketLeft"}},
+		'ü': {KeyInfo: KeyInfo{Key: "BracketLeft", Shift: true}},
+		'é': {KeyInfo: KeyInfo{Key: "Semicolon"}},
+		'ö': {KeyInfo: KeyInfo{Key: "Semicolon", Shift: true}},
+		'à': {KeyInfo: KeyInfo{Key: "Quote"}},
+		'ä': {KeyInfo: KeyInfo{Key: "Quote", Shift: true}},
+	}),
+}
@@ -0,0 +1,141 @@
+package keyboard
+
+var (
+	frFRKeyTrema = &KeyInfo{Key: "BracketLeft", Shift: true} // tréma (umlaut), two dots placed above a vowel
+	frFRKeyHat   = &KeyInfo{Key: "BracketLeft"}              // accent circonflexe (accent hat), mark ^ placed above the letter
+)
+
+var frFR = Layout{
+	ISOCode: "fr-FR",
+	Name:    "Français",
+	Chars: map[rune]KeyCombo{
+		'0':  {KeyInfo: KeyInfo{Key: "Digit0", Shift: true}},
+		'1':  {KeyInfo: KeyInfo{Key: "Digit1", Shift: true}},
+		'2':  {KeyInfo: KeyInfo{Key: "Digit2", Shift: true}},
+		'3':  {KeyInfo: KeyInfo{Key: "Digit3", Shift: true}},
+		'4':  {KeyInfo: KeyInfo{Key: "Digit4", Shift: true}},
+		'5':  {KeyInfo: KeyInfo{Key: "Digit5", Shift: true}},
+		'6':  {KeyInfo: KeyInfo{Key: "Digit6", Shift: true}},
+		'7':  {KeyInfo: KeyInfo{Key: "Digit7", Shift: true}},
+		'8':  {KeyInfo: KeyInfo{Key: "Digit8", Shift: true}},
+		'9':  {KeyInfo: KeyInfo{Key: "Digit9", Shift: true}},
+		'A':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}},
+		'Ä':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}, AccentKey: frFRKeyTrema},
+		'Â':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}, AccentKey: frFRKeyHat},
+		'B':  {KeyInfo: KeyInfo{Key: "KeyB", Shift: true}},
+		'C':  {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}},
+		'D':  {KeyInfo: KeyInfo{Key: "KeyD", Shift: true}},
+		'E':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}},
+		'Ë':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: frFRKeyTrema},
+		'Ê':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: frFRKeyHat},
+		'F':  {KeyInfo: KeyInfo{Key: "KeyF", Shift: true}},
+		'G':  {KeyInfo: KeyInfo{Key: "KeyG", Shift: true}},
+		'H':  {KeyInfo: KeyInfo{Key: "KeyH", Shift: true}},
+		'I':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}},
+		'Ï':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: frFRKeyTrema},
+		'Î':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: frFRKeyHat},
+		'J':  {KeyInfo: KeyInfo{Key: "KeyJ", Shift: true}},
+		'K':  {KeyInfo: KeyInfo{Key: "KeyK", Shift: true}},
+		'L':  {KeyInfo: KeyInfo{Key: "KeyL", Shift: true}},
+		'M':  {KeyInfo: KeyInfo{Key: "Semicolon", Shift: true}},
+		'N':  {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}},
+		'O':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}},
+		'Ö':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: frFRKeyTrema},
+		'Ô':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: frFRKeyHat},
+		'P':  {KeyInfo: KeyInfo{Key: "KeyP", Shift: true}},
+		'Q':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}},
+		'R':  {KeyInfo: KeyInfo{Key: "KeyR", Shift: true}},
+		'S':  {KeyInfo: KeyInfo{Key: "KeyS", Shift: true}},
+		'T':  {KeyInfo: KeyInfo{Key: "KeyT", Shift: true}},
+		'U':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}},
+		'Ü':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: frFRKeyTrema},
+		'Û':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: frFRKeyHat},
+		'V':  {KeyInfo: KeyInfo{Key: "KeyV", Shift: true}},
+		'W':  {KeyInfo: KeyInfo{Key: "KeyZ", Shift: true}},
+		'X':  {KeyInfo: KeyInfo{Key: "KeyX", Shift: true}},
+		'Y':  {KeyInfo: KeyInfo{Key: "KeyY", Shift: true}},
+		'Z':  {KeyInfo: KeyInfo{Key: "KeyW", Shift: true}},
+		'a':  {KeyInfo: KeyInfo{Key: "KeyQ"}},
+		'ä':  {KeyInfo: KeyInfo{Key: "KeyQ"}, AccentKey: frFRKeyTrema},
+		'â':  {KeyInfo: KeyInfo{Key: "KeyQ"}, AccentKey: frFRKeyHat},
+		'b':  {KeyInfo: KeyInfo{Key: "KeyB"}},
+		'c':  {KeyInfo: KeyInfo{Key: "KeyC"}},
+		'd':  {KeyInfo: KeyInfo{Key: "KeyD"}},
+		'e':  {KeyInfo: KeyInfo{Key: "KeyE"}},
+		'ë':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: frFRKeyTrema},
+		'ê':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: frFRKeyHat},
+		'€':  {KeyInfo: KeyInfo{Key: "KeyE", AltRight: true}},
+		'f':  {KeyInfo: KeyInfo{Key: "KeyF"}},
+		'g':  {KeyInfo: KeyInfo{Key: "KeyG"}},
+		'h':  {KeyInfo: KeyInfo{Key: "KeyH"}},
+		'i':  {KeyInfo: KeyInfo{Key: "KeyI"}},
+		'ï':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: frFRKeyTrema},
+		'î':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: frFRKeyHat},
+		'j':  {KeyInfo: KeyInfo{Key: "KeyJ"}},
+		'k':  {KeyInfo: KeyInfo{Key: "KeyK"}},
+		'l':  {KeyInfo: KeyInfo{Key: "KeyL"}},
+		'm':  {KeyInfo: KeyInfo{Key: "Semicolon"}},
+		'n':  {KeyInfo: KeyInfo{Key: "KeyN"}},
+		'o':  {KeyInfo: KeyInfo{Key: "KeyO"}},
+		'ö':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: frFRKeyTrema},
+		'ô':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: frFRKeyHat},
+		'p':  {KeyInfo: KeyInfo{Key: "KeyP"}},
+		'q':  {KeyInfo: KeyInfo{Key: "KeyA"}},
+		'r':  {KeyInfo: KeyInfo{Key: "KeyR"}},
+		's':  {KeyInfo: KeyInfo{Key: "KeyS"}},
+		't':  {KeyInfo: KeyInfo{Key: "KeyT"}},
+		'u':  {KeyInfo: KeyInfo{Key: "KeyU"}},
+		'ü':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: frFRKeyTrema},
+		'û':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: frFRKeyHat},
+		'v':  {KeyInfo: KeyInfo{Key: "KeyV"}},
+		'w':  {KeyInfo: KeyInfo{Key: "KeyZ"}},
+		'x':  {KeyInfo: KeyInfo{Key: "KeyX"}},
+		'y':  {KeyInfo: KeyInfo{Key: "KeyY"}},
+		'z':  {KeyInfo: KeyInfo{Key: "KeyW"}},
+		'²':  {KeyInfo: KeyInfo{Key: "Backquote"}},
+		'&':  {KeyInfo: KeyInfo{Key: "Digit1"}},
+		'é':  {KeyInfo: KeyInfo{Key: "Digit2"}},
+		'~':  {KeyInfo: KeyInfo{Key: "Digit2", AltRight: true}},
+		'"':  {KeyInfo: KeyInfo{Key: "Digit3"}},
+		'#':  {KeyInfo: KeyInfo{Key: "Digit3", AltRight: true}},
+		'\'': {KeyInfo: KeyInfo{Key: "Digit4"}},
+		'{':  {KeyInfo: KeyInfo{Key: "Digit4", AltRight: true}},
+		'(':  {KeyInfo: KeyInfo{Key: "Digit5"}},
+		'[':  {KeyInfo: KeyInfo{Key: "Digit5", AltRight: true}},
+		'-':  {KeyInfo: KeyInfo{Key: "Digit6"}},
+		'|':  {KeyInfo: KeyInfo{Key: "Digit6", AltRight: true}},
+		'è':  {KeyInfo: KeyInfo{Key: "Digit7"}},
+		'`':  {KeyInfo: KeyInfo{Key: "Digit7", AltRight: true}},
+		'_':  {KeyInfo: KeyInfo{Key: "Digit8"}},
+		'\\': {KeyInfo: KeyInfo{Key: "Digit8", AltRight: true}},
+		'ç':  {KeyInfo: KeyInfo{Key: "Digit9"}},
+		'^':  {KeyInfo: KeyInfo{Key: "Digit9", AltRight: true}},
+		'à':  {KeyInfo: KeyInfo{Key: "Digit0"}},
+		'@':  {KeyInfo: KeyInfo{Key: "Digit0", AltRight: true}},
+		')':  {KeyInfo: KeyInfo{Key: "Minus"}},
+		'°':  {KeyInfo: KeyInfo{Key: "Minus", Shift: true}},
+		']':  {KeyInfo: KeyInfo{Key: "Minus", AltRight: true}},
+		'=':  {KeyInfo: KeyInfo{Key: "Equal"}},
+		'+':  {KeyInfo: KeyInfo{Key: "Equal", Shift: true}},
+		'}':  {KeyInfo: KeyInfo{Key: "Equal", AltRight: true}},
+		'$':  {KeyInfo: KeyInfo{Key: "BracketRight"}},
+		'£':  {KeyInfo: KeyInfo{Key: "BracketRight", Shift: true}},
+		'¤':  {KeyInfo: KeyInfo{Key: "BracketRight", AltRight: true}},
+		'ù':  {KeyInfo: KeyInfo{Key: "Quote"}},
+		'%':  {KeyInfo: KeyInfo{Key: "Quote", Shift: true}},
+		'*':  {KeyInfo: KeyInfo{Key: "Backslash"}},
+		'µ':  {KeyInfo: KeyInfo{Key: "Backslash", Shift: true}},
+		',':  {KeyInfo: KeyInfo{Key: "KeyM"}},
+		'?':  {KeyInfo: KeyInfo{Key: "KeyM", Shift: true}},
+		';':  {KeyInfo: KeyInfo{Key: "Comma"}},
+		'.':  {KeyInfo: KeyInfo{Key: "Comma", Shift: true}},
+		':':  {KeyInfo: KeyInfo{Key: "Period"}},
+		'/':  {KeyInfo: KeyInfo{Key: "Period", Shift: true}},
+		'!':  {KeyInfo: KeyInfo{Key: "Slash"}},
+		'§':  {KeyInfo: KeyInfo{Key: "Slash", Shift: true}},
+		'<':  {KeyInfo: KeyInfo{Key: "IntlBackslash"}},
+		'>':  {KeyInfo: KeyInfo{Key: "IntlBackslash", Shift: true}},
+		' ':  {KeyInfo: KeyInfo{Key: "Space"}},
+		'\n': {KeyInfo: KeyInfo{Key: "Enter"}},
+	},
+}
@@ -0,0 +1,169 @@
+package keyboard
+
+var (
+	nbNOKeyTrema = &KeyInfo{Key: "BracketRight"}                 // tréma (umlaut), two dots placed above a vowel
+	nbNOKeyAcute = &KeyInfo{Key: "Equal", AltRight: true}        // accent aigu (acute accent), mark ´ placed above the letter
+	nbNOKeyHat   = &KeyInfo{Key: "BracketRight", Shift: true}    // accent circonflexe (accent hat), mark ^ placed above the letter
+	nbNOKeyGrave = &KeyInfo{Key: "Equal", Shift: true}           // accent grave, mark ` placed above the letter
+	nbNOKeyTilde = &KeyInfo{Key: "BracketRight", AltRight: true} // tilde, mark ~ placed above the letter
+)
+
+var nbNO = Layout{
+	ISOCode: "nb-NO",
+	Name:    "Norsk bokmål",
+	Chars: map[rune]KeyCombo{
+		'0':  {KeyInfo: KeyInfo{Key: "Digit0"}},
+		'1':  {KeyInfo: KeyInfo{Key: "Digit1"}},
+		'2':  {KeyInfo: KeyInfo{Key: "Digit2"}},
+		'3':  {KeyInfo: KeyInfo{Key: "Digit3"}},
+		'4':  {KeyInfo: KeyInfo{Key: "Digit4"}},
+		'5':  {KeyInfo: KeyInfo{Key: "Digit5"}},
+		'6':  {KeyInfo: KeyInfo{Key: "Digit6"}},
+		'7':  {KeyInfo: KeyInfo{Key: "Digit7"}},
+		'8':  {KeyInfo: KeyInfo{Key: "Digit8"}},
+		'9':  {KeyInfo: KeyInfo{Key: "Digit9"}},
+		'A':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}},
+		'Ä':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: nbNOKeyTrema},
+		'Á':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: nbNOKeyAcute},
+		'Â':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: nbNOKeyHat},
+		'À':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: nbNOKeyGrave},
+		'Ã':  {KeyInfo: KeyInfo{Key: "KeyA", Shift: true}, AccentKey: nbNOKeyTilde},
+		'B':  {KeyInfo: KeyInfo{Key: "KeyB", Shift: true}},
+		'C':  {KeyInfo: KeyInfo{Key: "KeyC", Shift: true}},
+		'D':  {KeyInfo: KeyInfo{Key: "KeyD", Shift: true}},
+		'E':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}},
+		'Ë':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: nbNOKeyTrema},
+		'É':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: nbNOKeyAcute},
+		'Ê':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: nbNOKeyHat},
+		'È':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: nbNOKeyGrave},
+		'Ẽ':  {KeyInfo: KeyInfo{Key: "KeyE", Shift: true}, AccentKey: nbNOKeyTilde},
+		'F':  {KeyInfo: KeyInfo{Key: "KeyF", Shift: true}},
+		'G':  {KeyInfo: KeyInfo{Key: "KeyG", Shift: true}},
+		'H':  {KeyInfo: KeyInfo{Key: "KeyH", Shift: true}},
+		'I':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}},
+		'Ï':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: nbNOKeyTrema},
+		'Í':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: nbNOKeyAcute},
+		'Î':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: nbNOKeyHat},
+		'Ì':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: nbNOKeyGrave},
+		'Ĩ':  {KeyInfo: KeyInfo{Key: "KeyI", Shift: true}, AccentKey: nbNOKeyTilde},
+		'J':  {KeyInfo: KeyInfo{Key: "KeyJ", Shift: true}},
+		'K':  {KeyInfo: KeyInfo{Key: "KeyK", Shift: true}},
+		'L':  {KeyInfo: KeyInfo{Key: "KeyL", Shift: true}},
+		'M':  {KeyInfo: KeyInfo{Key: "KeyM", Shift: true}},
+		'N':  {KeyInfo: KeyInfo{Key: "KeyN", Shift: true}},
+		'O':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}},
+		'Ö':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: nbNOKeyTrema},
+		'Ó':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: nbNOKeyAcute},
+		'Ô':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: nbNOKeyHat},
+		'Ò':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: nbNOKeyGrave},
+		'Õ':  {KeyInfo: KeyInfo{Key: "KeyO", Shift: true}, AccentKey: nbNOKeyTilde},
+		'P':  {KeyInfo: KeyInfo{Key: "KeyP", Shift: true}},
+		'Q':  {KeyInfo: KeyInfo{Key: "KeyQ", Shift: true}},
+		'R':  {KeyInfo: KeyInfo{Key: "KeyR", Shift: true}},
+		'S':  {KeyInfo: KeyInfo{Key: "KeyS", Shift: true}},
+		'T':  {KeyInfo: KeyInfo{Key: "KeyT", Shift: true}},
+		'U':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}},
+		'Ü':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: nbNOKeyTrema},
+		'Ú':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: nbNOKeyAcute},
+		'Û':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: nbNOKeyHat},
+		'Ù':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: nbNOKeyGrave},
+		'Ũ':  {KeyInfo: KeyInfo{Key: "KeyU", Shift: true}, AccentKey: nbNOKeyTilde},
+		'V':  {KeyInfo: KeyInfo{Key: "KeyV", Shift: true}},
+		'W':  {KeyInfo: KeyInfo{Key: "KeyW", Shift: true}},
+		'X':  {KeyInfo: KeyInfo{Key: "KeyX", Shift: true}},
+		'Y':  {KeyInfo: KeyInfo{Key: "KeyZ", Shift: true}},
+		'Z':  {KeyInfo: KeyInfo{Key: "KeyY", Shift: true}},
+		'a':  {KeyInfo: KeyInfo{Key: "KeyA"}},
+		'ä':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: nbNOKeyTrema},
+		'á':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: nbNOKeyAcute},
+		'â':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: nbNOKeyHat},
+		'à':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: nbNOKeyGrave},
+		'ã':  {KeyInfo: KeyInfo{Key: "KeyA"}, AccentKey: nbNOKeyTilde},
+		'b':  {KeyInfo: KeyInfo{Key: "KeyB"}},
+		'c':  {KeyInfo: KeyInfo{Key: "KeyC"}},
+		'd':  {KeyInfo: KeyInfo{Key: "KeyD"}},
+		'e':  {KeyInfo: KeyInfo{Key: "KeyE"}},
+		'ë':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: nbNOKeyTrema},
+		'é':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: nbNOKeyAcute},
+		'ê':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: nbNOKeyHat},
+		'è':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: nbNOKeyGrave},
+		'ẽ':  {KeyInfo: KeyInfo{Key: "KeyE"}, AccentKey: nbNOKeyTilde},
+		'€':  {KeyInfo: KeyInfo{Key: "KeyE", AltRight: true}},
+		'f':  {KeyInfo: KeyInfo{Key: "KeyF"}},
+		'g':  {KeyInfo: KeyInfo{Key: "KeyG"}},
+		'h':  {KeyInfo: KeyInfo{Key: "KeyH"}},
+		'i':  {KeyInfo: KeyInfo{Key: "KeyI"}},
+		'ï':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: nbNOKeyTrema},
+		'í':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: nbNOKeyAcute},
+		'î':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: nbNOKeyHat},
+		'ì':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: nbNOKeyGrave},
+		'ĩ':  {KeyInfo: KeyInfo{Key: "KeyI"}, AccentKey: nbNOKeyTilde},
+		'j':  {KeyInfo: KeyInfo{Key: "KeyJ"}},
+		'k':  {KeyInfo: KeyInfo{Key: "KeyK"}},
+		'l':  {KeyInfo: KeyInfo{Key: "KeyL"}},
+		'm':  {KeyInfo: KeyInfo{Key: "KeyM"}},
+		'n':  {KeyInfo: KeyInfo{Key: "KeyN"}},
+		'o':  {KeyInfo: KeyInfo{Key: "KeyO"}},
+		'ö':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: nbNOKeyTrema},
+		'ó':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: nbNOKeyAcute},
+		'ô':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: nbNOKeyHat},
+		'ò':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: nbNOKeyGrave},
+		'õ':  {KeyInfo: KeyInfo{Key: "KeyO"}, AccentKey: nbNOKeyTilde},
+		'p':  {KeyInfo: KeyInfo{Key: "KeyP"}},
+		'q':  {KeyInfo: KeyInfo{Key: "KeyQ"}},
+		'r':  {KeyInfo: KeyInfo{Key: "KeyR"}},
+		's':  {KeyInfo: KeyInfo{Key: "KeyS"}},
+		't':  {KeyInfo: KeyInfo{Key: "KeyT"}},
+		'u':  {KeyInfo: KeyInfo{Key: "KeyU"}},
+		'ü':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: nbNOKeyTrema},
+		'ú':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: nbNOKeyAcute},
+		'û':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: nbNOKeyHat},
+		'ù':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: nbNOKeyGrave},
+		'ũ':  {KeyInfo: KeyInfo{Key: "KeyU"}, AccentKey: nbNOKeyTilde},
+		'v':  {KeyInfo: KeyInfo{Key: "KeyV"}},
+		'w':  {KeyInfo: KeyInfo{Key: "KeyW"}},
+		'x':  {KeyInfo: KeyInfo{Key: "KeyX"}},
+		'y':  {KeyInfo: KeyInfo{Key: "KeyZ"}},
+		'z':  {KeyInfo: KeyInfo{Key: "KeyY"}},
+		'|':  {KeyInfo: KeyInfo{Key: "Backquote"}},
+		'§':  {KeyInfo: KeyInfo{Key: "Backquote", Shift: true}},
+		'!':  {KeyInfo: KeyInfo{Key: "Digit1", Shift: true}},
+		'"':  {KeyInfo: KeyInfo{Key: "Digit2", Shift: true}},
+		'@':  {KeyInfo: KeyInfo{Key: "Digit2", AltRight: true}},
+		'#':  {KeyInfo: KeyInfo{Key: "Digit3", Shift: true}},
+		'£':  {KeyInfo: KeyInfo{Key: "Digit3", AltRight: true}},
+		'¤':  {KeyInfo: KeyInfo{Key: "Digit4", Shift: true}},
+		'$':  {KeyInfo: KeyInfo{Key: "Digit4", AltRight: true}},
+		'%':  {KeyInfo: KeyInfo{Key: "Digit5", Shift: true}},
+		'&':  {KeyInfo: KeyInfo{Key: "Digit6", Shift: true}},
+		'/':  {KeyInfo: KeyInfo{Key: "Digit7", Shift: true}},
+		'{':  {KeyInfo: KeyInfo{Key: "Digit7", AltRight: true}},
+		'(':  {KeyInfo: KeyInfo{Key: "Digit8", Shift: true}},
+		'[':  {KeyInfo: KeyInfo{Key: "Digit8", AltRight: true}},
+		')':  {KeyInfo: KeyInfo{Key: "Digit9", Shift: true}},
+		']':  {KeyInfo: KeyInfo{Key: "Digit9", AltRight: true}},
+		'=':  {KeyInfo: KeyInfo{Key: "Digit0", Shift: true}},
+		'}':  {KeyInfo: KeyInfo{Key: "Digit0", AltRight: true}},
+		'+':  {KeyInfo: KeyInfo{Key: "Minus"}},
+		'?':  {KeyInfo: KeyInfo{Key: "Minus", Shift: true}},
+		'\\': {KeyInfo: KeyInfo{Key: "Equal"}},
+		'å':  {KeyInfo: KeyInfo{Key: "BracketLeft"}},
+		'Å':  {KeyInfo: KeyInfo{Key: "BracketLeft", Shift: true}},
+		'ø':  {KeyInfo: KeyInfo{Key: "Semicolon"}},
+		'Ø':  {KeyInfo: KeyInfo{Key: "Semicolon", Shift: true}},
+		'æ':  {KeyInfo: KeyInfo{Key: "Quote"}},
+		'Æ':  {KeyInfo: KeyInfo{Key: "Quote", Shift: true}},
+		'\'': {KeyInfo: KeyInfo{Key: "Backslash"}},
+		'*':  {KeyInfo: KeyInfo{Key: "Backslash", Shift: true}},
+		',':  {KeyInfo: KeyInfo{Key: "Comma"}},
+		';':  {KeyInfo: KeyInfo{Key: "Comma", Shift: true}},
+		'.':  {KeyInfo: KeyInfo{Key: "Period"}},
+		':':  {KeyInfo: KeyInfo{Key: "Period", Shift: true}},
+		'-':  {KeyInfo: KeyInfo{Key: "Slash"}},
+		'_':  {KeyInfo: KeyInfo{Key: "Slash", Shift: true}},
+		'<':  {KeyInfo: KeyInfo{Key: "IntlBackslash"}},
+		'>':  {KeyInfo: KeyInfo{Key: "IntlBackslash", Shift: true}},
+		' ':  {KeyInfo: KeyInfo{Key: "Space"}},
+		'\n': {KeyInfo: KeyInfo{Key: "Enter"}},
+	},
+}
@@ -0,0 +1,81 @@
+package keyboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayoutKeysExist(t *testing.T) {
+	for code, layout := range layouts {
+		assert.Equal(t, code, layout.ISOCode)
+		for r, combo := range layout.Chars {
+			_, err := layout.Strokes(r)
+			assert.NoError(t, err, "%s: %q (%s)", code, r, combo.Key)
+		}
+	}
+}
+
+func TestGetLayout(t *testing.T) {
+	layout, err := GetLayout("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLayout, layout.ISOCode)
+
+	_, err = GetLayout("xx-XX")
+	assert.Error(t, err)
+}
+
+func TestTextStrokes(t *testing.T) {
+	layout, err := GetLayout("en-US")
+	require.NoError(t, err)
+
+	strokes, err := layout.TextStrokes("Hi!\r\n")
+	require.NoError(t, err)
+	assert.Equal(t, []Stroke{
+		{Modifier: ModifierShiftLeft, Key: Keys["KeyH"]},
+		{Key: Keys["KeyI"]},
+		{Modifier: ModifierShiftLeft, Key: Keys["Digit1"]},
+		{Key: Keys["Enter"]},
+	}, strokes)
+
+	_, err = layout.TextStrokes("née")
+	assert.ErrorContains(t, err, `"é"`)
+}
+
+func TestDeadKeysAndAltGr(t *testing.T) {
+	layout, err := GetLayout("de-DE")
+	require.NoError(t, err)
+
+	// the accent is typed first, then the letter
+	strokes, err := layout.Strokes('Â')
+	require.NoError(t, err)
+	assert.Equal(t, []Stroke{
+		{Key: Keys["Backquote"]},
+		{Modifier: ModifierShiftLeft, Key: Keys["KeyA"]},
+	}, strokes)
+
+	strokes, err = layout.Strokes('€')
+	require.NoError(t, err)
+	assert.Equal(t, []Stroke{{Modifier: ModifierAltRight, Key: Keys["KeyE"]}}, strokes)
+
+	// a dead key on its own is followed by a space
+	strokes, err = layout.Strokes('˜')
+	require.NoError(t, err)
+	assert.Equal(t, []Stroke{
+		{Modifier: ModifierAltRight, Key: Keys["KeyI"]},
+		{Key: Keys["Space"]},
+	}, strokes)
+}
+
+func TestLayoutInheritance(t *testing.T) {
+	layout, err := GetLayout("fr-CH")
+	require.NoError(t, err)
+
+	assert.Equal(t, KeyInfo{Key: "BracketLeft"}, layout.Chars['è'].KeyInfo)
+	assert.Equal(t, deCH.Chars['z'], layout.Chars['z'])
+	assert.NotEqual(t, deCH.Chars['è'], layout.Chars['è'], "the base layout must not be modified")
+
+	_, err = GetLayout("xx-XX")
+	assert.Error(t, err)
+}
@@ -0,0 +1,235 @@
+// Package keymap remaps HID keyboard usage codes, e.g. so the Command key of a Mac
+// acts as Control on the target computer.
+package keymap
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// HID usage codes of the keys the built-in remaps use
+const (
+	KeyCapsLock     byte = 0x39
+	KeyControlLeft  byte = 0xE0
+	KeyMetaLeft     byte = 0xE3
+	KeyControlRight byte = 0xE4
+	KeyMetaRight    byte = 0xE7
+
+	firstModifierKey byte = 0xE0
+	lastModifierKey  byte = 0xE7
+	errorRollOver    byte = 0x01
+	keyBufferSize         = 6
+)
+
+const MaxMappings = 64
+
+// Mapping sends the To key when the From key is pressed.
+type Mapping struct {
+	From byte `json:"from"`
+	To   byte `json:"to"`
+}
+
+// Profile is a set of remaps, the Mappings are applied after the built-in remaps and override them.
+type Profile struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	SwapMetaCtrl   bool      `json:"swap_meta_ctrl"`
+	CapsLockToCtrl bool      `json:"caps_lock_to_ctrl"`
+	Mappings       []Mapping `json:"mappings"`
+}
+
+func (p *Profile) Validate() error {
+	if p.Name == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	if len(p.Mappings) > MaxMappings {
+		return fmt.Errorf("too many mappings (max %d)", MaxMappings)
+	}
+	for _, m := range p.Mappings {
+		if m.From <= errorRollOver || m.To <= errorRollOver {
+			return fmt.Errorf("invalid mapping from 0x%02x to 0x%02x", m.From, m.To)
+		}
+	}
+	return nil
+}
+
+// Table maps every usage code to the code that is sent instead.
+type Table [256]byte
+
+var identity = NewTable(nil)
+
+// NewTable builds the table of the profile, a nil profile doesn't remap anything.
+func NewTable(p *Profile) *Table {
+	t := &Table{}
+	for i := range t {
+		t[i] = byte(i)
+	}
+	if p == nil {
+		return t
+	}
+
+	if p.SwapMetaCtrl {
+		t[KeyControlLeft], t[KeyMetaLeft] = KeyMetaLeft, KeyControlLeft
+		t[KeyControlRight], t[KeyMetaRight] = KeyMetaRight, KeyControlRight
+	}
+	if p.CapsLockToCtrl {
+		t[KeyCapsLock] = KeyControlLeft
+	}
+	for _, m := range p.Mappings {
+		t[m.From] = m.To
+	}
+	return t
+}
+
+func isModifierKey(key byte) bool {
+	return key >= firstModifierKey && key <= lastModifierKey
+}
+
+// pressedKeys returns the usage codes of the modifier bits and keys of a report
+func pressedKeys(modifier byte, keys []byte) []byte {
+	var pressed []byte
+	for i := range 8 {
+		if modifier&(1<<i) != 0 {
+			pressed = append(pressed, firstModifierKey+byte(i))
+		}
+	}
+	for _, k := range keys {
+		if k > errorRollOver {
+			pressed = append(pressed, k)
+		}
+	}
+	return pressed
+}
+
+// buildReport is the inverse of pressedKeys, keys that don't fit are dropped
+func buildReport(pressed []byte) (byte, []byte) {
+	var modifier byte
+	keys := make([]byte, 0, keyBufferSize)
+	for _, k := range pressed {
+		switch {
+		case isModifierKey(k):
+			modifier |= 1 << (k - firstModifierKey)
+		case len(keys) < keyBufferSize && !contains(keys, k):
+			keys = append(keys, k)
+		}
+	}
+	return modifier, keys
+}
+
+func contains(keys []byte, key byte) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func padKeys(keys []byte, length int) []byte {
+	if len(keys) >= length {
+		return keys
+	}
+	return append(keys, make([]byte, length-len(keys))...)
+}
+
+// Remapper remaps keyboard input and remembers what every pressed key was mapped to,
+// so keys are released correctly even if the table changes while they're held.
+type Remapper struct {
+	mu      sync.Mutex
+	pressed map[byte]byte // original key to the key that was sent
+}
+
+func NewRemapper() *Remapper {
+	return &Remapper{pressed: make(map[byte]byte)}
+}
+
+func (r *Remapper) mapKey(t *Table, key byte) byte {
+	if mapped, ok := r.pressed[key]; ok {
+		return mapped
+	}
+	if t == nil {
+		t = identity
+	}
+	return t[key]
+}
+
+// Keypress returns the key to send for a key press or release.
+func (r *Remapper) Keypress(t *Table, key byte, press bool) byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mapped := r.mapKey(t, key)
+	if press {
+		r.pressed[key] = mapped
+	} else {
+		delete(r.pressed, key)
+	}
+	return mapped
+}
+
+// Report remaps a full keyboard report, the keys keep the length of the input.
+func (r *Remapper) Report(t *Table, modifier byte, keys []byte) (byte, []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(keys) > 0 && keys[0] == errorRollOver {
+		return r.reportModifiersOnly(t, modifier, keys)
+	}
+
+	pressed := make(map[byte]byte)
+	var mapped []byte
+	for _, k := range pressedKeys(modifier, keys) {
+		m := r.mapKey(t, k)
+		pressed[k] = m
+		mapped = append(mapped, m)
+	}
+	r.pressed = pressed
+
+	newModifier, newKeys := buildReport(mapped)
+	return newModifier, padKeys(newKeys, len(keys))
+}
+
+// reportModifiersOnly keeps the roll over error of the keys and only remaps the modifiers
+func (r *Remapper) reportModifiersOnly(t *Table, modifier byte, keys []byte) (byte, []byte) {
+	var newModifier byte
+	for _, k := range pressedKeys(modifier, nil) {
+		if m := r.mapKey(t, k); isModifierKey(m) {
+			newModifier |= 1 << (m - firstModifierKey)
+		}
+	}
+	return newModifier, keys
+}
+
+// Unmap translates the keys that are down on the target back to the keys the user pressed.
+func (r *Remapper) Unmap(modifier byte, keys []byte) (byte, []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pressed) == 0 || (len(keys) > 0 && keys[0] == errorRollOver) {
+		return modifier, keys
+	}
+
+	original := make(map[byte]byte, len(r.pressed))
+	for from, to := range r.pressed {
+		original[to] = from
+	}
+
+	var unmapped []byte
+	for _, k := range pressedKeys(modifier, keys) {
+		if from, ok := original[k]; ok {
+			k = from
+		}
+		unmapped = append(unmapped, k)
+	}
+
+	newModifier, newKeys := buildReport(unmapped)
+	return newModifier, padKeys(newKeys, len(keys))
+}
+
+// Reset forgets the pressed keys, e.g. after all keys were released.
+func (r *Remapper) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.pressed)
+}
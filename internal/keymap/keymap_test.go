@@ -0,0 +1,56 @@
+package keymap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	keyA byte = 0x04
+	keyB byte = 0x05
+	keyC byte = 0x06
+)
+
+func TestKeypressSwapMetaCtrl(t *testing.T) {
+	r := NewRemapper()
+	table := NewTable(&Profile{Name: "mac", SwapMetaCtrl: true})
+
+	assert.Equal(t, KeyControlLeft, r.Keypress(table, KeyMetaLeft, true))
+	assert.Equal(t, keyC, r.Keypress(table, keyC, true))
+
+	modifier, keys := r.Unmap(0x01, []byte{keyC, 0, 0, 0, 0, 0})
+	assert.Equal(t, byte(0x08), modifier, "Control is reported as the Meta key that was pressed")
+	assert.Equal(t, []byte{keyC, 0, 0, 0, 0, 0}, keys)
+
+	// the profile is turned off while the key is held, it's still released correctly
+	assert.Equal(t, KeyControlLeft, r.Keypress(nil, KeyMetaLeft, false))
+	assert.Equal(t, KeyMetaLeft, r.Keypress(nil, KeyMetaLeft, true))
+}
+
+func TestReportMappings(t *testing.T) {
+	r := NewRemapper()
+	table := NewTable(&Profile{
+		Name:           "custom",
+		CapsLockToCtrl: true,
+		Mappings:       []Mapping{{From: keyA, To: keyB}},
+	})
+
+	modifier, keys := r.Report(table, 0x02, []byte{KeyCapsLock, keyA, 0, 0, 0, 0})
+	assert.Equal(t, byte(0x03), modifier)
+	assert.Equal(t, []byte{keyB, 0, 0, 0, 0, 0}, keys)
+
+	modifier, keys = r.Unmap(modifier, keys)
+	assert.Equal(t, byte(0x02), modifier)
+	assert.Equal(t, []byte{KeyCapsLock, keyA, 0, 0, 0, 0}, keys)
+
+	modifier, keys = r.Report(table, 0, []byte{errorRollOver, errorRollOver, errorRollOver, errorRollOver, errorRollOver, errorRollOver})
+	assert.Equal(t, byte(0), modifier)
+	assert.Equal(t, errorRollOver, keys[0])
+}
+
+func TestProfileValidate(t *testing.T) {
+	assert.Error(t, (&Profile{}).Validate())
+	assert.Error(t, (&Profile{Name: "x", Mappings: []Mapping{{From: 0, To: keyA}}}).Validate())
+	assert.NoError(t, (&Profile{Name: "x", Mappings: []Mapping{{From: keyA, To: keyB}}}).Validate())
+}
@@ -0,0 +1,91 @@
+package keyscript
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jetkvm/kvm/internal/keyboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type report struct {
+	modifier byte
+	keys     []byte
+}
+
+type fakeDevice struct {
+	reports  []report
+	usbWaits []time.Duration
+}
+
+func (d *fakeDevice) KeyboardReport(modifier byte, keys []byte) error {
+	d.reports = append(d.reports, report{modifier, keys})
+	return nil
+}
+
+func (d *fakeDevice) WaitForUsbConfigured(ctx context.Context, timeout time.Duration) error {
+	d.usbWaits = append(d.usbWaits, timeout)
+	return nil
+}
+
+func parse(t *testing.T, src string) *Script {
+	t.Helper()
+	layout, err := keyboard.GetLayout("en-US")
+	require.NoError(t, err)
+	script, err := Parse(src, layout)
+	require.NoError(t, err)
+	return script
+}
+
+func TestParseErrors(t *testing.T) {
+	layout, err := keyboard.GetLayout("en-US")
+	require.NoError(t, err)
+
+	_, err = Parse("REM boot menu\nDELAY abc\nENTER\nFOO BAR\nREPEAT 0\nSTRING né", layout)
+	assert.Equal(t, []Error{
+		{Line: 2, Message: `invalid number of milliseconds: "abc"`},
+		{Line: 4, Message: "unknown command or key: FOO"},
+		{Line: 5, Message: "REPEAT needs a count between 1 and 10000"},
+		{Line: 6, Message: `characters "é" can't be typed with the en-US layout`},
+	}, Errors(err))
+
+	_, err = Parse("REPEAT 2", layout)
+	assert.Equal(t, []Error{{Line: 1, Message: "REPEAT without a command to repeat"}}, Errors(err))
+
+	_, err = Parse("CTRL a b c d e f g", layout)
+	assert.ErrorContains(t, err, "too many keys")
+}
+
+func TestRun(t *testing.T) {
+	script := parse(t, "// comment\nSTRING_DELAY 0\nSTRING A b\nCTRL ALT DELETE\nGUI R\nF2\nREPEAT 2\nWAIT_FOR_USB_CONFIGURED")
+
+	dev := &fakeDevice{}
+	require.NoError(t, script.Run(context.Background(), dev))
+
+	release := report{}
+	assert.Equal(t, []report{
+		{keyboard.ModifierShiftLeft, []byte{keyboard.Keys["KeyA"]}}, release,
+		{0, []byte{keyboard.Keys["Space"]}}, release,
+		{0, []byte{keyboard.Keys["KeyB"]}}, release,
+		{keyboard.ModifierControlLeft | keyboard.ModifierAltLeft, []byte{keyboard.Keys["Delete"]}}, release,
+		{keyboard.ModifierMetaLeft, []byte{keyboard.Keys["KeyR"]}}, release,
+		{0, []byte{keyboard.Keys["F2"]}}, release,
+		{0, []byte{keyboard.Keys["F2"]}}, release,
+		{0, []byte{keyboard.Keys["F2"]}}, release,
+	}, dev.reports)
+	assert.Equal(t, []time.Duration{DefaultUsbTimeout}, dev.usbWaits)
+}
+
+func TestRunCancel(t *testing.T) {
+	script := parse(t, "ENTER\nDELAY 60000\nENTER")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	dev := &fakeDevice{}
+	err := script.Run(ctx, dev)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []report{{0, []byte{keyboard.Keys["Enter"]}}, {}, {}}, dev.reports)
+}
@@ -0,0 +1,301 @@
+// Package keyscript implements a small DuckyScript-like language to type on the target computer.
+//
+// Every line is a command:
+//
+//	REM comment, lines starting with // are comments too
+//	STRING text          types the text with the keyboard layout, STRINGLN adds a line break
+//	ENTER, TAB, F2, ...  presses a key
+//	CTRL ALT DELETE      presses a key combination, GUI r
+//	DELAY ms             waits
+//	DEFAULT_DELAY ms     waits after every following command
+//	STRING_DELAY ms      waits after every character typed by STRING
+//	REPEAT n             runs the previous command n more times
+//	WAIT_FOR_USB_CONFIGURED [timeout ms]
+package keyscript
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jetkvm/kvm/internal/keyboard"
+)
+
+const (
+	MaxScriptSize = 64 * 1024
+	MaxDelay      = 10 * time.Minute
+	MaxRepeat     = 10000
+	// MaxComboKeys is the number of keys a boot keyboard report can hold, modifiers don't count
+	MaxComboKeys = 6
+
+	DefaultUsbTimeout = 30 * time.Second
+)
+
+type commandKind int
+
+const (
+	commandKeys commandKind = iota
+	commandDelay
+	commandDefaultDelay
+	commandStringDelay
+	commandWaitForUsb
+)
+
+// chord is a set of keys that are pressed together
+type chord struct {
+	modifier byte
+	keys     []byte
+}
+
+type command struct {
+	line   int
+	kind   commandKind
+	chords []chord // typed one after another
+	delay  time.Duration
+	repeat int
+}
+
+// Script is a parsed script, ready to run.
+type Script struct {
+	commands []command
+}
+
+// Error is a problem with a line of the script.
+type Error struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ErrorList is returned by Parse with every problem in the script.
+type ErrorList []Error
+
+func (l ErrorList) Error() string {
+	messages := make([]string, len(l))
+	for i, e := range l {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Errors returns the line-numbered errors in err, or a single error without a line.
+func Errors(err error) []Error {
+	var list ErrorList
+	if errors.As(err, &list) {
+		return list
+	}
+	var e Error
+	if errors.As(err, &e) {
+		return []Error{e}
+	}
+	return []Error{{Message: err.Error()}}
+}
+
+var modifierNames = map[string]byte{
+	"CTRL":    keyboard.ModifierControlLeft,
+	"CONTROL": keyboard.ModifierControlLeft,
+	"SHIFT":   keyboard.ModifierShiftLeft,
+	"ALT":     keyboard.ModifierAltLeft,
+	"ALTGR":   keyboard.ModifierAltRight,
+	"RALT":    keyboard.ModifierAltRight,
+	"GUI":     keyboard.ModifierMetaLeft,
+	"WINDOWS": keyboard.ModifierMetaLeft,
+	"COMMAND": keyboard.ModifierMetaLeft,
+	"META":    keyboard.ModifierMetaLeft,
+}
+
+// keyNames are the DuckyScript names, the names of the UI (e.g. KeyA or ArrowUp) work as well.
+var keyNames = map[string]string{
+	"ENTER":       "Enter",
+	"ESC":         "Escape",
+	"ESCAPE":      "Escape",
+	"TAB":         "Tab",
+	"SPACE":       "Space",
+	"BACKSPACE":   "Backspace",
+	"DELETE":      "Delete",
+	"DEL":         "Delete",
+	"INSERT":      "Insert",
+	"HOME":        "Home",
+	"END":         "End",
+	"PAGEUP":      "PageUp",
+	"PAGEDOWN":    "PageDown",
+	"UP":          "ArrowUp",
+	"UPARROW":     "ArrowUp",
+	"DOWN":        "ArrowDown",
+	"DOWNARROW":   "ArrowDown",
+	"LEFT":        "ArrowLeft",
+	"LEFTARROW":   "ArrowLeft",
+	"RIGHT":       "ArrowRight",
+	"RIGHTARROW":  "ArrowRight",
+	"CAPSLOCK":    "CapsLock",
+	"NUMLOCK":     "NumLock",
+	"SCROLLLOCK":  "ScrollLock",
+	"PRINTSCREEN": "PrintScreen",
+	"PAUSE":       "Pause",
+	"BREAK":       "Pause",
+	"MENU":        "ContextMenu",
+	"APP":         "ContextMenu",
+}
+
+func lookupKey(name string) (byte, bool) {
+	upper := strings.ToUpper(name)
+	if key, ok := keyNames[upper]; ok {
+		name = key
+	} else if len(upper) >= 2 && upper[0] == 'F' {
+		// function keys, F1 to F24
+		if n, err := strconv.Atoi(upper[1:]); err == nil && n >= 1 && n <= 24 {
+			name = "F" + upper[1:]
+		}
+	}
+	code, ok := keyboard.Keys[name]
+	return code, ok
+}
+
+func parseDuration(args string, max time.Duration) (time.Duration, error) {
+	ms, err := strconv.Atoi(strings.TrimSpace(args))
+	if err != nil {
+		return 0, fmt.Errorf("invalid number of milliseconds: %q", args)
+	}
+	d := time.Duration(ms) * time.Millisecond
+	if ms < 0 || d > max {
+		return 0, fmt.Errorf("must be between 0 and %d ms", max.Milliseconds())
+	}
+	return d, nil
+}
+
+// parseCombo parses a key or a key combination, single characters are looked up in the layout.
+func parseCombo(tokens []string, layout *keyboard.Layout) (command, error) {
+	var modifier byte
+	var keys []byte
+	for _, token := range tokens {
+		if m, ok := modifierNames[strings.ToUpper(token)]; ok {
+			modifier |= m
+			continue
+		}
+
+		if code, ok := lookupKey(token); ok {
+			keys = append(keys, code)
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(token)
+		if size != len(token) {
+			return command{}, fmt.Errorf("unknown command or key: %s", token)
+		}
+		// GUI R means the key, not a capital letter
+		r = unicode.ToLower(r)
+		combo, ok := layout.Chars[r]
+		if !ok || combo.AccentKey != nil || combo.DeadKey {
+			return command{}, fmt.Errorf("key %q can't be pressed with the %s layout", r, layout.ISOCode)
+		}
+		code, ok := keyboard.Keys[combo.Key]
+		if !ok {
+			return command{}, fmt.Errorf("unknown key: %s", combo.Key)
+		}
+		if combo.Shift {
+			modifier |= keyboard.ModifierShiftLeft
+		}
+		if combo.AltRight {
+			modifier |= keyboard.ModifierAltRight
+		}
+		keys = append(keys, code)
+	}
+
+	if len(keys) > MaxComboKeys {
+		return command{}, fmt.Errorf("too many keys in combination (max %d)", MaxComboKeys)
+	}
+	return command{kind: commandKeys, chords: []chord{{modifier: modifier, keys: keys}}}, nil
+}
+
+func parseLine(line string, layout *keyboard.Layout) (command, error) {
+	name, args, _ := strings.Cut(line, " ")
+	switch strings.ToUpper(name) {
+	case "STRING", "STRINGLN":
+		if args == "" {
+			return command{}, fmt.Errorf("%s needs text", strings.ToUpper(name))
+		}
+		if strings.EqualFold(name, "STRINGLN") {
+			args += "\n"
+		}
+		strokes, err := layout.TextStrokes(args)
+		if err != nil {
+			return command{}, err
+		}
+		chords := make([]chord, len(strokes))
+		for i, s := range strokes {
+			chords[i] = chord{modifier: s.Modifier, keys: []byte{s.Key}}
+		}
+		return command{kind: commandKeys, chords: chords}, nil
+	case "DELAY":
+		d, err := parseDuration(args, MaxDelay)
+		return command{kind: commandDelay, delay: d}, err
+	case "DEFAULT_DELAY", "DEFAULTDELAY":
+		d, err := parseDuration(args, MaxDelay)
+		return command{kind: commandDefaultDelay, delay: d}, err
+	case "STRING_DELAY", "STRINGDELAY":
+		d, err := parseDuration(args, MaxDelay)
+		return command{kind: commandStringDelay, delay: d}, err
+	case "WAIT_FOR_USB_CONFIGURED":
+		if strings.TrimSpace(args) == "" {
+			return command{kind: commandWaitForUsb, delay: DefaultUsbTimeout}, nil
+		}
+		d, err := parseDuration(args, MaxDelay)
+		return command{kind: commandWaitForUsb, delay: d}, err
+	default:
+		return parseCombo(strings.Fields(line), layout)
+	}
+}
+
+// Parse parses the script for the keyboard layout, it returns an ErrorList with all invalid lines.
+func Parse(src string, layout *keyboard.Layout) (*Script, error) {
+	if len(src) > MaxScriptSize {
+		return nil, fmt.Errorf("script is too long (max %d bytes)", MaxScriptSize)
+	}
+
+	script := &Script{}
+	var errs ErrorList
+	for i, line := range strings.Split(src, "\n") {
+		lineNumber := i + 1
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") || trimmed == "REM" || strings.HasPrefix(trimmed, "REM ") {
+			continue
+		}
+
+		name, args, _ := strings.Cut(trimmed, " ")
+		if strings.EqualFold(name, "REPEAT") {
+			n, err := strconv.Atoi(strings.TrimSpace(args))
+			switch {
+			case err != nil || n < 1 || n > MaxRepeat:
+				errs = append(errs, Error{lineNumber, fmt.Sprintf("REPEAT needs a count between 1 and %d", MaxRepeat)})
+			case len(script.commands) == 0:
+				errs = append(errs, Error{lineNumber, "REPEAT without a command to repeat"})
+			default:
+				last := &script.commands[len(script.commands)-1]
+				last.repeat = min(last.repeat+n, MaxRepeat)
+			}
+			continue
+		}
+
+		// STRING keeps the spaces of its text, only the indentation is dropped
+		cmd, err := parseLine(strings.TrimLeft(line, " \t"), layout)
+		if err != nil {
+			errs = append(errs, Error{lineNumber, err.Error()})
+			continue
+		}
+		cmd.line = lineNumber
+		script.commands = append(script.commands, cmd)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return script, nil
+}
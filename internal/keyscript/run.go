@@ -0,0 +1,94 @@
+package keyscript
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// keyHold is how long every key is held down
+	keyHold = 20 * time.Millisecond
+	// defaultStringDelay is the pause after every key until STRING_DELAY changes it
+	defaultStringDelay = 20 * time.Millisecond
+)
+
+// Device is the keyboard the script types on.
+type Device interface {
+	KeyboardReport(modifier byte, keys []byte) error
+	// WaitForUsbConfigured returns once the host configured the USB device, or fails after the timeout
+	WaitForUsbConfigured(ctx context.Context, timeout time.Duration) error
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type runner struct {
+	dev          Device
+	defaultDelay time.Duration
+	stringDelay  time.Duration
+}
+
+func (r *runner) press(ctx context.Context, c chord) error {
+	if err := r.dev.KeyboardReport(c.modifier, c.keys); err != nil {
+		return err
+	}
+	if err := sleep(ctx, keyHold); err != nil {
+		return err
+	}
+	if err := r.dev.KeyboardReport(0, nil); err != nil {
+		return err
+	}
+	return sleep(ctx, r.stringDelay)
+}
+
+func (r *runner) run(ctx context.Context, cmd command) error {
+	switch cmd.kind {
+	case commandKeys:
+		for _, c := range cmd.chords {
+			if err := r.press(ctx, c); err != nil {
+				return err
+			}
+		}
+	case commandDelay:
+		return sleep(ctx, cmd.delay)
+	case commandDefaultDelay:
+		r.defaultDelay = cmd.delay
+		return nil
+	case commandStringDelay:
+		r.stringDelay = cmd.delay
+		return nil
+	case commandWaitForUsb:
+		if err := r.dev.WaitForUsbConfigured(ctx, cmd.delay); err != nil {
+			return err
+		}
+	}
+	return sleep(ctx, r.defaultDelay)
+}
+
+// Run types the script on the device. When ctx is cancelled all keys are released and ctx.Err() is returned.
+func (s *Script) Run(ctx context.Context, dev Device) error {
+	r := &runner{dev: dev, stringDelay: defaultStringDelay}
+	for _, cmd := range s.commands {
+		for range cmd.repeat + 1 {
+			if err := r.run(ctx, cmd); err != nil {
+				if ctx.Err() != nil {
+					_ = dev.KeyboardReport(0, nil)
+					return ctx.Err()
+				}
+				return Error{cmd.line, err.Error()}
+			}
+		}
+	}
+	return nil
+}
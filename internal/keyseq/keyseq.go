@@ -0,0 +1,202 @@
+// Package keyseq is a catalog of named key sequences like Ctrl+Alt+Del, that need the keys
+// pressed and released in the right order and with the right timing.
+package keyseq
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+
+	"github.com/jetkvm/kvm/internal/hidrpc"
+	"github.com/jetkvm/kvm/internal/keyboard"
+)
+
+const (
+	MaxSteps = 256
+	// keyGap is the pause between the presses and releases of a combination
+	keyGap = 20
+	// keyHold is how long the last key of a combination is held
+	keyHold = 100
+)
+
+var nameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+// Step changes the keys that are held, the keys are names of the UI like ControlLeft or KeyA.
+type Step struct {
+	Press   []string `json:"press,omitempty"`
+	Release []string `json:"release,omitempty"`
+	// Delay is the pause in milliseconds after the step
+	Delay int `json:"delay"`
+}
+
+// Sequence is a named list of steps, all keys that are still held are released at the end.
+type Sequence struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Steps       []Step `json:"steps"`
+}
+
+// Combo presses the keys one after another and releases them in reverse order.
+func Combo(keys ...string) []Step {
+	steps := make([]Step, 0, len(keys)*2)
+	for i, key := range keys {
+		delay := keyGap
+		if i == len(keys)-1 {
+			delay = keyHold
+		}
+		steps = append(steps, Step{Press: []string{key}, Delay: delay})
+	}
+	for _, key := range slices.Backward(keys) {
+		steps = append(steps, Step{Release: []string{key}, Delay: keyGap})
+	}
+	return steps
+}
+
+// tap presses and releases the key while other keys are held, the delay is the pause afterwards.
+func tap(key string, delay int) []Step {
+	return []Step{
+		{Press: []string{key}, Delay: keyHold},
+		{Release: []string{key}, Delay: delay},
+	}
+}
+
+func reisub() []Step {
+	steps := []Step{
+		{Press: []string{"AltLeft"}, Delay: keyGap},
+		{Press: []string{"PrintScreen"}, Delay: keyHold},
+	}
+	// give the kernel time to terminate and kill the processes and to sync the disks
+	for _, t := range []struct {
+		key   string
+		delay int
+	}{
+		{"KeyR", 1000},
+		{"KeyE", 5000},
+		{"KeyI", 5000},
+		{"KeyS", 3000},
+		{"KeyU", 3000},
+		{"KeyB", 0},
+	} {
+		steps = append(steps, tap(t.key, t.delay)...)
+	}
+	return append(steps,
+		Step{Release: []string{"PrintScreen"}, Delay: keyGap},
+		Step{Release: []string{"AltLeft"}},
+	)
+}
+
+// Builtin returns the key sequences that are always available.
+func Builtin() []Sequence {
+	sequences := []Sequence{
+		{Name: "ctrl-alt-del", Description: "Ctrl+Alt+Delete", Steps: Combo("ControlLeft", "AltLeft", "Delete")},
+		{Name: "ctrl-shift-esc", Description: "Ctrl+Shift+Esc, opens the Windows task manager", Steps: Combo("ControlLeft", "ShiftLeft", "Escape")},
+		{Name: "win-l", Description: "Windows+L, locks Windows", Steps: Combo("MetaLeft", "KeyL")},
+		{Name: "alt-sysrq-reisub", Description: "Alt+SysRq+REISUB, safely reboots Linux", Steps: reisub()},
+	}
+	for i := 1; i <= 12; i++ {
+		sequences = append(sequences, Sequence{
+			Name:        fmt.Sprintf("ctrl-alt-f%d", i),
+			Description: fmt.Sprintf("Ctrl+Alt+F%d, switches to virtual console %d on Linux", i, i),
+			Steps:       Combo("ControlLeft", "AltLeft", fmt.Sprintf("F%d", i)),
+		})
+	}
+	return sequences
+}
+
+// Find returns the sequence with the name, or nil.
+func Find(sequences []Sequence, name string) *Sequence {
+	for i := range sequences {
+		if sequences[i].Name == name {
+			return &sequences[i]
+		}
+	}
+	return nil
+}
+
+type keyState struct {
+	modifier byte
+	keys     []byte
+}
+
+func (s *keyState) change(name string, press bool) error {
+	if bit, ok := keyboard.Modifiers[name]; ok {
+		if press {
+			s.modifier |= bit
+		} else {
+			s.modifier &^= bit
+		}
+		return nil
+	}
+
+	key, ok := keyboard.Keys[name]
+	if !ok {
+		return fmt.Errorf("unknown key %q", name)
+	}
+	i := slices.Index(s.keys, key)
+	switch {
+	case press && i < 0:
+		if len(s.keys) >= hidrpc.HidKeyBufferSize {
+			return fmt.Errorf("can't hold more than %d keys", hidrpc.HidKeyBufferSize)
+		}
+		s.keys = append(s.keys, key)
+	case !press && i >= 0:
+		s.keys = slices.Delete(s.keys, i, i+1)
+	}
+	return nil
+}
+
+func (s *keyState) report(delay int) hidrpc.KeyboardMacroStep {
+	keys := make([]byte, hidrpc.HidKeyBufferSize)
+	copy(keys, s.keys)
+	return hidrpc.KeyboardMacroStep{Modifier: s.modifier, Keys: keys, Delay: uint16(delay)}
+}
+
+// Macro translates the sequence into keyboard reports, it also checks that the sequence is valid.
+func (s *Sequence) Macro() ([]hidrpc.KeyboardMacroStep, error) {
+	if len(s.Steps) == 0 {
+		return nil, errors.New("a key sequence needs at least one step")
+	}
+	if len(s.Steps) > MaxSteps {
+		return nil, fmt.Errorf("too many steps (max %d)", MaxSteps)
+	}
+
+	var state keyState
+	macro := make([]hidrpc.KeyboardMacroStep, 0, len(s.Steps)+1)
+	for i, step := range s.Steps {
+		if step.Delay < 0 || step.Delay > math.MaxUint16 {
+			return nil, fmt.Errorf("step %d: delay must be between 0 and %d", i+1, math.MaxUint16)
+		}
+		for _, key := range step.Release {
+			if err := state.change(key, false); err != nil {
+				return nil, fmt.Errorf("step %d: %w", i+1, err)
+			}
+		}
+		for _, key := range step.Press {
+			if err := state.change(key, true); err != nil {
+				return nil, fmt.Errorf("step %d: %w", i+1, err)
+			}
+		}
+		macro = append(macro, state.report(step.Delay))
+	}
+
+	if state.modifier != 0 || len(state.keys) > 0 {
+		macro = append(macro, (&keyState{}).report(0))
+	}
+	return macro, nil
+}
+
+// Validate checks a custom sequence, its name can't be the name of a builtin sequence.
+func (s *Sequence) Validate() error {
+	if !nameRegexp.MatchString(s.Name) {
+		return fmt.Errorf("invalid name %q, use lowercase letters, digits and dashes", s.Name)
+	}
+	if Find(Builtin(), s.Name) != nil {
+		return fmt.Errorf("%q is the name of a builtin sequence", s.Name)
+	}
+	if _, err := s.Macro(); err != nil {
+		return fmt.Errorf("%s: %w", s.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package keyseq
+
+import (
+	"testing"
+
+	"github.com/jetkvm/kvm/internal/hidrpc"
+	"github.com/jetkvm/kvm/internal/keyboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func report(modifier byte, keys ...byte) hidrpc.KeyboardMacroStep {
+	k := make([]byte, hidrpc.HidKeyBufferSize)
+	copy(k, keys)
+	return hidrpc.KeyboardMacroStep{Modifier: modifier, Keys: k}
+}
+
+func stripDelays(macro []hidrpc.KeyboardMacroStep) []hidrpc.KeyboardMacroStep {
+	for i := range macro {
+		macro[i].Delay = 0
+	}
+	return macro
+}
+
+func TestCtrlAltDelOrdering(t *testing.T) {
+	seq := Find(Builtin(), "ctrl-alt-del")
+	require.NotNil(t, seq)
+
+	macro, err := seq.Macro()
+	require.NoError(t, err)
+
+	ctrl, alt := keyboard.ModifierControlLeft, keyboard.ModifierAltLeft
+	del := keyboard.Keys["Delete"]
+	assert.Equal(t, []hidrpc.KeyboardMacroStep{
+		report(ctrl),
+		report(ctrl | alt),
+		report(ctrl|alt, del),
+		report(ctrl | alt),
+		report(ctrl),
+		report(0),
+	}, stripDelays(macro))
+}
+
+func TestReisubHoldsSysRq(t *testing.T) {
+	macro, err := Find(Builtin(), "alt-sysrq-reisub").Macro()
+	require.NoError(t, err)
+
+	sysrq := keyboard.Keys["PrintScreen"]
+	alt := keyboard.ModifierAltLeft
+	afterR := report(alt, sysrq)
+	afterR.Delay = 1000
+
+	assert.Equal(t, report(alt, sysrq, keyboard.Keys["KeyR"]), stripDelays(macro[2:3])[0])
+	assert.Equal(t, afterR, macro[3], "SysRq stays held and the kernel gets time after R")
+	assert.Equal(t, report(0), macro[len(macro)-1])
+}
+
+func TestBuiltinSequencesAreValid(t *testing.T) {
+	names := map[string]bool{}
+	for _, seq := range Builtin() {
+		_, err := seq.Macro()
+		assert.NoError(t, err, seq.Name)
+		assert.Regexp(t, nameRegexp, seq.Name)
+		assert.False(t, names[seq.Name], "duplicate name %s", seq.Name)
+		names[seq.Name] = true
+	}
+	assert.NotNil(t, Find(Builtin(), "ctrl-alt-f12"))
+}
+
+func TestMacroReleasesHeldKeys(t *testing.T) {
+	seq := Sequence{Name: "hold", Steps: []Step{{Press: []string{"ShiftLeft", "KeyA"}, Delay: 50}}}
+
+	macro, err := seq.Macro()
+	require.NoError(t, err)
+	assert.Equal(t, []hidrpc.KeyboardMacroStep{
+		{Modifier: keyboard.ModifierShiftLeft, Keys: report(0, keyboard.Keys["KeyA"]).Keys, Delay: 50},
+		report(0),
+	}, macro)
+}
+
+func TestValidate(t *testing.T) {
+	valid := Sequence{Name: "alt-tab", Steps: Combo("AltLeft", "Tab")}
+	assert.NoError(t, valid.Validate())
+
+	for name, seq := range map[string]Sequence{
+		"builtin name": {Name: "win-l", Steps: Combo("MetaLeft", "KeyL")},
+		"invalid name": {Name: "Alt Tab", Steps: Combo("AltLeft", "Tab")},
+		"unknown key":  {Name: "unknown", Steps: Combo("AltLeft", "Tabulator")},
+		"no steps":     {Name: "empty"},
+		"bad delay":    {Name: "delay", Steps: []Step{{Press: []string{"KeyA"}, Delay: -1}}},
+		"too many keys": {Name: "many", Steps: []Step{{Press: []string{
+			"KeyA", "KeyB", "KeyC", "KeyD", "KeyE", "KeyF", "KeyG",
+		}}}},
+	} {
+		assert.Error(t, seq.Validate(), name)
+	}
+}
@@ -23,7 +23,7 @@ func (n *Native) handleVideoFrameChan() {
 		now := time.Now()
 		sinceLastFrame := now.Sub(lastFrame)
 		lastFrame = now
-		n.onVideoFrameReceived(frame, sinceLastFrame)
+		n.handleVideoFrame(frame, sinceLastFrame)
 	}
 }
 
@@ -58,11 +58,11 @@ func (n *Native) handleVideoFrameChan() {
 		select {
 		case frame := <-videoFrameChan:
 			log.Printf("Mock: Received video frame of size: %d bytes", len(frame))
-			n.onVideoFrameReceived(frame, time.Since(time.Now()))
+			n.handleVideoFrame(frame, time.Since(time.Now()))
 		case <-time.After(33 * time.Millisecond):
 			// Simulate 30 FPS video frames
 			mockFrame := make([]byte, 1920*1080*3) // Mock RGB frame
-			n.onVideoFrameReceived(mockFrame, 33*time.Millisecond)
+			n.handleVideoFrame(mockFrame, 33*time.Millisecond)
 		}
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/jetkvm/kvm/internal/framebus"
 	"github.com/rs/zerolog"
 )
 
@@ -19,6 +20,7 @@ type Native struct {
 	onVideoFrameReceived func(frame []byte, duration time.Duration)
 	onIndevEvent         func(event string)
 	onRpcEvent           func(event string)
+	frameBus             *framebus.Bus
 	videoLock            sync.Mutex
 	screenLock           sync.Mutex
 }
@@ -41,13 +43,6 @@ func NewNative(opts NativeOptions) *Native {
 		}
 	}
 
-	onVideoFrameReceived := opts.OnVideoFrameReceived
-	if onVideoFrameReceived == nil {
-		onVideoFrameReceived = func(frame []byte, duration time.Duration) {
-			nativeLogger.Info().Interface("frame", frame).Dur("duration", duration).Msg("video frame received")
-		}
-	}
-
 	onIndevEvent := opts.OnIndevEvent
 	if onIndevEvent == nil {
 		onIndevEvent = func(event string) {
@@ -70,14 +65,28 @@ func NewNative(opts NativeOptions) *Native {
 		appVersion:           opts.AppVersion,
 		displayRotation:      opts.DisplayRotation,
 		onVideoStateChange:   onVideoStateChange,
-		onVideoFrameReceived: onVideoFrameReceived,
+		onVideoFrameReceived: opts.OnVideoFrameReceived,
 		onIndevEvent:         onIndevEvent,
 		onRpcEvent:           onRpcEvent,
+		frameBus:             framebus.New(),
 		videoLock:            sync.Mutex{},
 		screenLock:           sync.Mutex{},
 	}
 }
 
+// FrameBus returns the bus of the encoded video frames, every subscriber gets its own buffer.
+// Unlike OnVideoFrameReceived, which runs on the capture path, a slow subscriber never delays the capture.
+func (n *Native) FrameBus() *framebus.Bus {
+	return n.frameBus
+}
+
+func (n *Native) handleVideoFrame(frame []byte, duration time.Duration) {
+	n.frameBus.Publish(frame, duration)
+	if n.onVideoFrameReceived != nil {
+		n.onVideoFrameReceived(frame, duration)
+	}
+}
+
 func (n *Native) Start() {
 	// set up singleton
 	setInstance(n)
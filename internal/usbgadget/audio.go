@@ -0,0 +1,59 @@
+package usbgadget
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// AudioCardName is the name of the ALSA card the UAC2 function creates on the device side.
+	AudioCardName   = "UAC2Gadget"
+	AudioSampleRate = 48000
+	AudioChannels   = 2
+)
+
+// audioConfig exposes a USB Audio Class 2 sound card. From the gadget's point of view
+// "c_" is what the host plays back and "p_" is the host's microphone.
+var audioConfig = gadgetConfigItem{
+	order:      2200,
+	device:     "uac2.usb0",
+	path:       []string{"functions", "uac2.usb0"},
+	configPath: []string{"uac2.usb0"},
+	attrs: gadgetAttributes{
+		"c_chmask": "3", // stereo
+		"c_srate":  strconv.Itoa(AudioSampleRate),
+		"c_ssize":  "2", // 16 bit
+		// the microphone is only exposed when it's enabled, see loadAudioConfig
+		"p_chmask": "0",
+		"p_srate":  strconv.Itoa(AudioSampleRate),
+		"p_ssize":  "2",
+	},
+}
+
+func (u *UsbGadget) loadAudioConfig() {
+	item, ok := u.configMap["audio"]
+	if !ok {
+		return
+	}
+	if u.enabledDevices.AudioMicrophone {
+		item.attrs["p_chmask"] = "3"
+	} else {
+		item.attrs["p_chmask"] = "0"
+	}
+}
+
+// AudioCardNumber returns the number of the ALSA card of the audio function.
+func AudioCardNumber() (int, error) {
+	link, err := os.Readlink(filepath.Join("/proc/asound", AudioCardName))
+	if err != nil {
+		return 0, fmt.Errorf("audio card not found: %w", err)
+	}
+	card, err := strconv.Atoi(strings.TrimPrefix(link, "card"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid audio card %s: %w", link, err)
+	}
+	return card, nil
+}
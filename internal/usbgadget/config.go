@@ -13,6 +13,7 @@ type gadgetConfigItem struct {
 	configAttrs gadgetAttributes
 	configPath  []string
 	reportDesc  []byte
+	parent      string // key of the item that owns this one, e.g. the function of a LUN
 }
 
 type gadgetAttributes map[string]string
@@ -34,7 +35,8 @@ var defaultGadgetConfig = map[string]gadgetConfigItem{
 			"bcdDevice": "0x0100", // USB2
 		},
 		configAttrs: gadgetAttributes{
-			"MaxPower": "250", // in unit of 2mA
+			"MaxPower":     "250",                  // in unit of 2mA
+			"bmAttributes": bmAttributesBusPowered, // remote wakeup is added when it's enabled
 		},
 	},
 	"base_info": {
@@ -56,9 +58,24 @@ var defaultGadgetConfig = map[string]gadgetConfigItem{
 	"absolute_mouse": absoluteMouseConfig,
 	// relative mouse HID
 	"relative_mouse": relativeMouseConfig,
+	// FIDO security key HID
+	"fido": fidoConfig,
 	// mass storage
 	"mass_storage_base": massStorageBaseConfig,
 	"mass_storage_lun0": massStorageLun0Config,
+	"mass_storage_lun1": massStorageLun1Config,
+	"mass_storage_lun2": massStorageLun2Config,
+	"mass_storage_lun3": massStorageLun3Config,
+	// serial console (CDC-ACM)
+	"serial_console": serialConsoleConfig,
+	// printer
+	"printer": printerConfig,
+	// sound card (UAC2)
+	"audio": audioConfig,
+	// network (CDC-ECM, CDC-NCM or RNDIS)
+	"network_ecm":   networkEcmConfig,
+	"network_ncm":   networkNcmConfig,
+	"network_rndis": networkRndisConfig,
 }
 
 func (u *UsbGadget) isGadgetConfigItemEnabled(itemKey string) bool {
@@ -69,16 +86,32 @@ func (u *UsbGadget) isGadgetConfigItemEnabled(itemKey string) bool {
 		return u.enabledDevices.RelativeMouse
 	case "keyboard":
 		return u.enabledDevices.Keyboard
+	case "fido":
+		return u.enabledDevices.Fido
 	case "mass_storage_base":
 		return u.enabledDevices.MassStorage
-	case "mass_storage_lun0":
-		return u.enabledDevices.MassStorage
+	case "mass_storage_lun0", "mass_storage_lun1", "mass_storage_lun2", "mass_storage_lun3":
+		return u.isMassStorageLunEnabled(itemKey)
+	case "serial_console":
+		return u.enabledDevices.SerialConsole
+	case "printer":
+		return u.enabledDevices.Printer
+	case "audio":
+		return u.enabledDevices.Audio
+	case "network_ecm", "network_ncm", "network_rndis":
+		return u.isNetworkConfigItemEnabled(itemKey)
 	default:
 		return true
 	}
 }
 
 func (u *UsbGadget) loadGadgetConfig() {
+	u.loadNetworkConfig()
+	u.loadAudioConfig()
+	u.loadIdentityPreset()
+	u.loadRemoteWakeup()
+	u.loadMassStorageLunModes()
+
 	if u.customConfig.isEmpty {
 		u.log.Trace().Msg("using default gadget config")
 		return
@@ -113,6 +146,13 @@ func (u *UsbGadget) SetGadgetDevices(devices *Devices) {
 	}
 
 	u.enabledDevices = *devices
+
+	// a disabled function isn't detached anymore, it's gone
+	for key := range u.detachedItems {
+		if !u.isGadgetConfigItemEnabled(key) {
+			delete(u.detachedItems, key)
+		}
+	}
 }
 
 // GetConfigPath returns the path to the config item.
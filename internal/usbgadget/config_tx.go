@@ -22,6 +22,7 @@ type UsbGadgetTransaction struct {
 	configC1Path              string
 	orderedConfigItems        orderedGadgetConfigItems
 	isGadgetConfigItemEnabled func(key string) bool
+	identity                  *IdentityPreset
 
 	reorderSymlinkChanges *RequestedFileChange
 }
@@ -44,7 +45,8 @@ func (u *UsbGadget) newUsbGadgetTransaction(lock bool) error {
 		kvmGadgetPath:             u.kvmGadgetPath,
 		configC1Path:              u.configC1Path,
 		orderedConfigItems:        u.getOrderedConfigItems(),
-		isGadgetConfigItemEnabled: u.isGadgetConfigItemEnabled,
+		isGadgetConfigItemEnabled: u.isGadgetConfigItemLinked,
+		identity:                  u.identity,
 	}
 	u.tx = tx
 
@@ -116,8 +118,12 @@ func (u *UsbGadget) getOrderedConfigItems() orderedGadgetConfigItems {
 		items = append(items, gadgetConfigItemWithKey{key, item})
 	}
 
+	// the key breaks ties, so the order doesn't depend on the map iteration
 	sort.Slice(items, func(i, j int) bool {
-		return items[i].item.order < items[j].item.order
+		if items[i].item.order != items[j].item.order {
+			return items[i].item.order < items[j].item.order
+		}
+		return items[i].key < items[j].key
 	})
 
 	return items
@@ -154,7 +160,7 @@ func (tx *UsbGadgetTransaction) WriteGadgetConfig() {
 
 	for _, val := range tx.orderedConfigItems {
 		key := val.key
-		item := val.item
+		item := tx.applyIdentity(key, val.item)
 
 		// check if the item is enabled in the config
 		if !tx.isGadgetConfigItemEnabled(key) {
@@ -182,7 +188,32 @@ func (tx *UsbGadgetTransaction) getDisableKeys() []string {
 	return disableKeys
 }
 
+// getParentDisableKeys returns the key of the change that unlinks the parent function of the item,
+// the item directory can only be created or removed while the function isn't linked to the config.
+func (tx *UsbGadgetTransaction) getParentDisableKeys(item gadgetConfigItem) []string {
+	if item.parent == "" || !tx.isGadgetConfigItemEnabled(item.parent) {
+		return nil
+	}
+	for _, val := range tx.orderedConfigItems {
+		if val.key == item.parent {
+			return []string{fmt.Sprintf("disable-%s", val.item.device)}
+		}
+	}
+	return nil
+}
+
 func (tx *UsbGadgetTransaction) DisableGadgetItemConfig(item gadgetConfigItem) {
+	// remove the item directory if it's owned by an enabled function
+	if parentDisableKeys := tx.getParentDisableKeys(item); parentDisableKeys != nil {
+		_ = tx.addFileChange("gadget", RequestedFileChange{
+			Path:          joinPath(tx.kvmGadgetPath, item.path),
+			ExpectedState: FileStateAbsent,
+			Description:   "remove gadget item directory",
+			BeforeChange:  parentDisableKeys,
+		})
+		return
+	}
+
 	// remove symlink if exists
 	if item.configPath == nil {
 		return
@@ -201,7 +232,13 @@ func (tx *UsbGadgetTransaction) writeGadgetItemConfig(item gadgetConfigItem, dep
 
 	gadgetItemPath := joinPath(tx.kvmGadgetPath, item.path)
 	if gadgetItemPath != tx.kvmGadgetPath {
-		gadgetItemDir := tx.mkdirAll(component, gadgetItemPath, "create gadget item directory", files)
+		gadgetItemDir := tx.addFileChange(component, RequestedFileChange{
+			Path:          gadgetItemPath,
+			ExpectedState: FileStateDirectory,
+			Description:   "create gadget item directory",
+			DependsOn:     files,
+			BeforeChange:  tx.getParentDisableKeys(item),
+		})
 		files = append(files, gadgetItemDir)
 	}
 
@@ -315,6 +352,27 @@ func (tx *UsbGadgetTransaction) addReorderSymlinkChange(path string, target stri
 	})
 }
 
+// WriteFunctionLinks relinks the enabled functions to the config without touching the function directories,
+// so the functions that stay linked keep their state, e.g. the media of the mass storage LUNs.
+func (tx *UsbGadgetTransaction) WriteFunctionLinks() {
+	for _, val := range tx.orderedConfigItems {
+		item := tx.applyIdentity(val.key, val.item)
+		if item.configPath == nil || item.configAttrs != nil {
+			continue
+		}
+		if !tx.isGadgetConfigItemEnabled(val.key) {
+			continue
+		}
+		tx.addReorderSymlinkChange(
+			joinPath(tx.configC1Path, item.configPath),
+			joinPath(tx.kvmGadgetPath, item.path),
+			nil,
+		)
+	}
+
+	tx.WriteUDC()
+}
+
 func (tx *UsbGadgetTransaction) WriteUDC() {
 	// bound the gadget to a UDC (USB Device Controller)
 	path := path.Join(tx.kvmGadgetPath, "UDC")
@@ -0,0 +1,104 @@
+package usbgadget
+
+import (
+	"fmt"
+	"slices"
+)
+
+// functionConfigKeys maps the function names used by DetachFunction and AttachFunction to their config items.
+var functionConfigKeys = map[string]string{
+	"keyboard":       "keyboard",
+	"absolute_mouse": "absolute_mouse",
+	"relative_mouse": "relative_mouse",
+	"fido":           "fido",
+	"mass_storage":   "mass_storage_base",
+	"serial_console": "serial_console",
+	"printer":        "printer",
+	"audio":          "audio",
+}
+
+func (u *UsbGadget) getFunctionConfigKey(function string) (string, error) {
+	if function == "network" {
+		key, ok := networkConfigKeys[u.networkConfig.Function]
+		if !ok {
+			return "", fmt.Errorf("invalid network function: %s", u.networkConfig.Function)
+		}
+		return key, nil
+	}
+
+	key, ok := functionConfigKeys[function]
+	if !ok {
+		return "", fmt.Errorf("unknown function: %s", function)
+	}
+	return key, nil
+}
+
+// isGadgetConfigItemLinked returns true if the item is enabled and not detached,
+// detached items keep their function directory but aren't linked to the config.
+func (u *UsbGadget) isGadgetConfigItemLinked(itemKey string) bool {
+	return u.isGadgetConfigItemEnabled(itemKey) && !u.detachedItems[itemKey]
+}
+
+func (u *UsbGadget) setFunctionDetached(function string, detached bool) error {
+	u.configLock.Lock()
+	defer u.configLock.Unlock()
+
+	key, err := u.getFunctionConfigKey(function)
+	if err != nil {
+		return err
+	}
+	if !u.isGadgetConfigItemEnabled(key) {
+		return fmt.Errorf("function %s is not enabled", function)
+	}
+	if u.detachedItems[key] == detached {
+		return nil
+	}
+
+	if detached {
+		u.detachedItems[key] = true
+	} else {
+		delete(u.detachedItems, key)
+	}
+
+	u.log.Info().Str("function", function).Bool("detached", detached).Msg("updating function link")
+	if err := u.WithTransaction(func() error {
+		u.tx.WriteFunctionLinks()
+		u.tx.RebindUsb(true)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update function %s: %w", function, err)
+	}
+	return nil
+}
+
+// DetachFunction unplugs a single function by removing its config symlink and rebinding the gadget,
+// the host sees the other functions re-enumerate without it.
+func (u *UsbGadget) DetachFunction(function string) error {
+	return u.setFunctionDetached(function, true)
+}
+
+// AttachFunction plugs a detached function back in.
+func (u *UsbGadget) AttachFunction(function string) error {
+	return u.setFunctionDetached(function, false)
+}
+
+// GetDetachedFunctions returns the names of the detached functions.
+func (u *UsbGadget) GetDetachedFunctions() []string {
+	u.configLock.Lock()
+	defer u.configLock.Unlock()
+
+	functions := make([]string, 0)
+	for function := range functionConfigKeys {
+		if u.detachedItems[functionConfigKeys[function]] {
+			functions = append(functions, function)
+		}
+	}
+	for _, key := range networkConfigKeys {
+		if u.detachedItems[key] {
+			functions = append(functions, "network")
+			break
+		}
+	}
+	slices.Sort(functions)
+	return functions
+}
@@ -0,0 +1,58 @@
+package usbgadget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetachedFunctions(t *testing.T) {
+	u := &UsbGadget{
+		configMap:      defaultGadgetConfig,
+		enabledDevices: defaultUsbGadgetDevices,
+		networkConfig:  NetworkConfig{Function: NetworkFunctionNCM},
+		detachedItems:  map[string]bool{"mass_storage_base": true},
+		log:            defaultLogger,
+	}
+
+	assert.False(t, u.isGadgetConfigItemLinked("mass_storage_base"))
+	assert.True(t, u.isGadgetConfigItemLinked("keyboard"))
+	assert.Equal(t, []string{"mass_storage"}, u.GetDetachedFunctions())
+
+	key, err := u.getFunctionConfigKey("network")
+	assert.NoError(t, err)
+	assert.Equal(t, "network_ncm", key)
+
+	_, err = u.getFunctionConfigKey("joystick")
+	assert.Error(t, err)
+
+	// disabling the function drops it from the detached ones
+	devices := defaultUsbGadgetDevices
+	devices.MassStorage = false
+	u.SetGadgetDevices(&devices)
+	assert.Empty(t, u.GetDetachedFunctions())
+}
+
+func TestPlanFunctionLinks(t *testing.T) {
+	g := newUsbGadget("test", cloneGadgetConfigMap(defaultGadgetConfig), nil, nil, nil)
+	require.NotNil(t, g)
+	g.detachedItems["mass_storage_base"] = true
+
+	changes, err := g.WithTransactionPlan(func() error {
+		g.tx.WriteFunctionLinks()
+		g.tx.RebindUsb(true)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// only the links and the UDC are touched, the functions keep their state
+	for _, change := range changes {
+		assert.Contains(t, []string{"gadget-finalize", "udc"}, change.Component, "unexpected change of %s", change.Path)
+	}
+
+	symlinks := findPlannedChange(changes, "SYMLINK_REORDER", "/sys/kernel/config/usb_gadget/test/configs/c.1")
+	require.NotNil(t, symlinks)
+	assert.Contains(t, symlinks.NewContent, "functions/hid.usb0")
+	assert.NotContains(t, symlinks.NewContent, "mass_storage")
+}
@@ -0,0 +1,69 @@
+package usbgadget
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FidoReportLength is the size of the CTAPHID reports in both directions.
+const FidoReportLength = 64
+
+var fidoConfig = gadgetConfigItem{
+	order:      1003,
+	device:     "hid.usb3",
+	path:       []string{"functions", "hid.usb3"},
+	configPath: []string{"hid.usb3"},
+	attrs: gadgetAttributes{
+		"protocol":        "0",
+		"subclass":        "0",
+		"report_length":   strconv.Itoa(FidoReportLength),
+		"no_out_endpoint": "0",
+	},
+	reportDesc: fidoReportDesc,
+}
+
+// from: FIDO CTAP 2.0, section 8.1.8.2 (HID report descriptor)
+var fidoReportDesc = []byte{
+	0x06, 0xd0, 0xf1, // USAGE_PAGE (FIDO Alliance)
+	0x09, 0x01, // USAGE (CTAPHID)
+	0xa1, 0x01, // COLLECTION (Application)
+
+	// input report
+	0x09, 0x20, // USAGE (Input Report Data)
+	0x15, 0x00, // LOGICAL_MINIMUM (0)
+	0x26, 0xff, 0x00, // LOGICAL_MAXIMUM (255)
+	0x75, 0x08, // REPORT_SIZE (8)
+	0x95, FidoReportLength, // REPORT_COUNT (64)
+	0x81, 0x02, // INPUT (Data,Var,Abs)
+
+	// output report
+	0x09, 0x21, // USAGE (Output Report Data)
+	0x15, 0x00, // LOGICAL_MINIMUM (0)
+	0x26, 0xff, 0x00, // LOGICAL_MAXIMUM (255)
+	0x75, 0x08, // REPORT_SIZE (8)
+	0x95, FidoReportLength, // REPORT_COUNT (64)
+	0x91, 0x02, // OUTPUT (Data,Var,Abs)
+
+	// End
+	0xc0, // END_COLLECTION
+}
+
+// FidoDevicePath returns the device node of the FIDO function. Unlike the keyboard and mice,
+// the hidg number depends on which HID functions were created before, so it's derived from the minor number.
+func (u *UsbGadget) FidoDevicePath() (string, error) {
+	dev, err := u.readFunctionAttr("fido", "dev")
+	if err != nil {
+		return "", err
+	}
+
+	_, minor, ok := strings.Cut(dev, ":")
+	if !ok {
+		return "", fmt.Errorf("invalid device number: %s", dev)
+	}
+	n, err := strconv.Atoi(minor)
+	if err != nil {
+		return "", fmt.Errorf("invalid device number: %s", dev)
+	}
+	return fmt.Sprintf("/dev/hidg%d", n), nil
+}
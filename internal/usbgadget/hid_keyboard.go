@@ -157,6 +157,11 @@ func (u *UsbGadget) SetOnKeepAliveReset(f func()) {
 	u.onKeepAliveReset = &f
 }
 
+// SetOnAutoRelease sets the callback for keys released because their keep-alives stopped.
+func (u *UsbGadget) SetOnAutoRelease(f func(key byte)) {
+	u.onAutoRelease = &f
+}
+
 // DefaultAutoReleaseDuration is the default duration for auto-release of a key.
 const DefaultAutoReleaseDuration = 100 * time.Millisecond
 
@@ -237,6 +242,11 @@ func (u *UsbGadget) performAutoRelease(key byte) {
 	_, err := u.keypressReport(key, false)
 	if err != nil {
 		u.log.Warn().Uint8("key", key).Msg("failed to release key")
+		return
+	}
+
+	if u.onAutoRelease != nil {
+		(*u.onAutoRelease)(key)
 	}
 }
 
@@ -0,0 +1,321 @@
+package usbgadget
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// IdentityPreset overrides gadget attributes and report descriptors per function,
+// so the gadget can pass as a specific device on hosts that only accept whitelisted devices.
+//
+// A report descriptor override must describe the same report layout as the one it replaces,
+// otherwise the reports written by the KVM won't make sense to the host.
+type IdentityPreset struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Attrs overrides the attributes of the gadget config items, keyed by item key and attribute name
+	Attrs map[string]map[string]string `json:"attrs,omitempty"`
+	// ConfigAttrs overrides the attributes of the config directory of the items
+	ConfigAttrs map[string]map[string]string `json:"config_attrs,omitempty"`
+	// ReportDescs overrides the HID report descriptors, keyed by item key
+	ReportDescs map[string][]byte `json:"report_descs,omitempty"`
+}
+
+// DefaultIdentityPresetName is the name of the preset that doesn't override anything.
+const DefaultIdentityPresetName = "default"
+
+var builtinIdentityPresets = []IdentityPreset{
+	{
+		Name:        DefaultIdentityPresetName,
+		Description: "JetKVM USB Emulation Device",
+	},
+	{
+		Name:        "dell_keyboard",
+		Description: "Generic Dell wired keyboard",
+		Attrs: withMassStorageAttrs(map[string]map[string]string{
+			"base": {
+				"idVendor":  "0x413c",
+				"idProduct": "0x2113",
+				"bcdDevice": "0x0110",
+			},
+			"base_info": {
+				"manufacturer": "Dell",
+				"product":      "Dell KB216 Wired Keyboard",
+			},
+		}, inquiryString("Dell", "USB Disk", "1.00")),
+		ConfigAttrs: map[string]map[string]string{
+			"base": {"MaxPower": "100"},
+		},
+		ReportDescs: map[string][]byte{
+			"keyboard": dellKeyboardReportDesc,
+		},
+	},
+	{
+		Name:        "logitech_receiver",
+		Description: "Logitech Unifying receiver",
+		Attrs: withMassStorageAttrs(map[string]map[string]string{
+			"base": {
+				"idVendor":  "0x046d",
+				"idProduct": "0xc52b",
+				"bcdDevice": "0x1211",
+			},
+			"base_info": {
+				"manufacturer": "Logitech",
+				"product":      "USB Receiver",
+			},
+		}, inquiryString("Logitech", "USB Disk", "1.00")),
+		ConfigAttrs: map[string]map[string]string{
+			"base": {"MaxPower": "98"},
+		},
+	},
+}
+
+// dellKeyboardReportDesc is the boot keyboard descriptor most vendor keyboards report. It has the
+// same report layout as keyboardReportDesc, but the key array covers the whole usage page.
+var dellKeyboardReportDesc = []byte{
+	0x05, 0x01, /* USAGE_PAGE (Generic Desktop)           */
+	0x09, 0x06, /* USAGE (Keyboard)                       */
+	0xa1, 0x01, /* COLLECTION (Application)               */
+	0x05, 0x07, /*   USAGE_PAGE (Keyboard)                */
+	0x19, 0xe0, /*   USAGE_MINIMUM (Keyboard LeftControl) */
+	0x29, 0xe7, /*   USAGE_MAXIMUM (Keyboard Right GUI)   */
+	0x15, 0x00, /*   LOGICAL_MINIMUM (0)                  */
+	0x25, 0x01, /*   LOGICAL_MAXIMUM (1)                  */
+	0x75, 0x01, /*   REPORT_SIZE (1)                      */
+	0x95, 0x08, /*   REPORT_COUNT (8)                     */
+	0x81, 0x02, /*   INPUT (Data,Var,Abs)                 */
+	0x95, 0x01, /*   REPORT_COUNT (1)                     */
+	0x75, 0x08, /*   REPORT_SIZE (8)                      */
+	0x81, 0x01, /*   INPUT (Cnst,Ary,Abs)                 */
+	0x95, 0x05, /*   REPORT_COUNT (5)                     */
+	0x75, 0x01, /*   REPORT_SIZE (1)                      */
+	0x05, 0x08, /*   USAGE_PAGE (LEDs)                    */
+	0x19, 0x01, /*   USAGE_MINIMUM (Num Lock)             */
+	0x29, 0x05, /*   USAGE_MAXIMUM (Kana)                 */
+	0x91, 0x02, /*   OUTPUT (Data,Var,Abs)                */
+	0x95, 0x01, /*   REPORT_COUNT (1)                     */
+	0x75, 0x03, /*   REPORT_SIZE (3)                      */
+	0x91, 0x01, /*   OUTPUT (Cnst,Ary,Abs)                */
+	0x95, 0x06, /*   REPORT_COUNT (6)                     */
+	0x75, 0x08, /*   REPORT_SIZE (8)                      */
+	0x15, 0x00, /*   LOGICAL_MINIMUM (0)                  */
+	0x26, 0xff, 0x00, /*   LOGICAL_MAXIMUM (255)          */
+	0x05, 0x07, /*   USAGE_PAGE (Keyboard)                */
+	0x19, 0x00, /*   USAGE_MINIMUM (Reserved)             */
+	0x2a, 0xff, 0x00, /*   USAGE_MAXIMUM (255)            */
+	0x81, 0x00, /*   INPUT (Data,Ary,Abs)                 */
+	0xc0, /* END_COLLECTION                         */
+}
+
+// inquiryString formats the SCSI inquiry string of the mass storage LUNs.
+func inquiryString(vendor, product, revision string) string {
+	return fmt.Sprintf("%-8.8s%-16.16s%-4.4s", vendor, product, revision)
+}
+
+// withMassStorageAttrs sets the inquiry string of every mass storage LUN, so the virtual media
+// matches the identity too.
+func withMassStorageAttrs(attrs map[string]map[string]string, inquiry string) map[string]map[string]string {
+	for lun := 0; lun < MaxMassStorageLuns; lun++ {
+		attrs[MassStorageLunKey(lun)] = map[string]string{"inquiry_string": inquiry}
+	}
+	return attrs
+}
+
+// GetBuiltinIdentityPresets returns the identity presets shipped with the gadget.
+func GetBuiltinIdentityPresets() []IdentityPreset {
+	return builtinIdentityPresets
+}
+
+// GetBuiltinIdentityPreset returns the built-in preset with the given name, or nil if there's none.
+func GetBuiltinIdentityPreset(name string) *IdentityPreset {
+	for i := range builtinIdentityPresets {
+		if builtinIdentityPresets[i].Name == name {
+			return &builtinIdentityPresets[i]
+		}
+	}
+	return nil
+}
+
+var (
+	identityPresetNameRegex = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+	hex16AttrRegex          = regexp.MustCompile(`^0x[0-9a-fA-F]{4}$`)
+)
+
+const maxReportDescLength = 4096
+
+func validateIdentityAttr(attr string, value string) error {
+	switch attr {
+	case "idVendor", "idProduct", "bcdDevice", "bcdUSB":
+		if !hex16AttrRegex.MatchString(value) {
+			return fmt.Errorf("%s must be a 16-bit hex value like 0x1234", attr)
+		}
+	case "MaxPower":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || n > 500 {
+			return fmt.Errorf("%s must be between 0 and 500", attr)
+		}
+	case "inquiry_string":
+		// vendor (8 chars), product (16 chars) and revision (4 chars)
+		if len(value) > 28 {
+			return fmt.Errorf("%s must be at most 28 characters", attr)
+		}
+	default:
+		if len(value) > 126 {
+			return fmt.Errorf("%s must be at most 126 characters", attr)
+		}
+	}
+	return nil
+}
+
+// validateReportDesc checks that the descriptor consists of complete HID items with balanced collections.
+func validateReportDesc(desc []byte) error {
+	if len(desc) == 0 || len(desc) > maxReportDescLength {
+		return fmt.Errorf("report descriptor must be between 1 and %d bytes", maxReportDescLength)
+	}
+
+	depth := 0
+	for i := 0; i < len(desc); {
+		prefix := desc[i]
+
+		// long item: prefix, data size, tag and data
+		if prefix == 0xfe {
+			if i+2 >= len(desc) {
+				return fmt.Errorf("truncated long item at offset %d", i)
+			}
+			i += 3 + int(desc[i+1])
+			if i > len(desc) {
+				return fmt.Errorf("truncated long item data")
+			}
+			continue
+		}
+
+		size := int(prefix & 0x03)
+		if size == 3 {
+			size = 4
+		}
+		if i+1+size > len(desc) {
+			return fmt.Errorf("truncated item at offset %d", i)
+		}
+
+		switch prefix & 0xfc {
+		case 0xa0: // COLLECTION
+			depth++
+		case 0xc0: // END_COLLECTION
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("unbalanced END_COLLECTION at offset %d", i)
+			}
+		}
+		i += 1 + size
+	}
+
+	if depth != 0 {
+		return fmt.Errorf("%d collection(s) not closed", depth)
+	}
+	return nil
+}
+
+func validateIdentityAttrs(configMap map[string]gadgetConfigItem, overrides map[string]map[string]string, configAttrs bool) error {
+	for key, attrs := range overrides {
+		item, ok := configMap[key]
+		if !ok {
+			return fmt.Errorf("config item %s not found", key)
+		}
+		existing := item.attrs
+		if configAttrs {
+			existing = item.configAttrs
+		}
+		for attr, value := range attrs {
+			// only known attributes can be overridden, configfs doesn't allow creating new ones anyway
+			if _, ok := existing[attr]; !ok {
+				return fmt.Errorf("config item %s has no attribute %s", key, attr)
+			}
+			if err := validateIdentityAttr(attr, value); err != nil {
+				return fmt.Errorf("invalid value for %s/%s: %w", key, attr, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateIdentityPreset checks that the preset only overrides existing attributes and report descriptors
+// and that the values are well-formed.
+func (u *UsbGadget) ValidateIdentityPreset(preset *IdentityPreset) error {
+	if !identityPresetNameRegex.MatchString(preset.Name) {
+		return fmt.Errorf("invalid preset name %q, only lowercase letters, digits, - and _ are allowed", preset.Name)
+	}
+
+	if err := validateIdentityAttrs(u.configMap, preset.Attrs, false); err != nil {
+		return err
+	}
+	if err := validateIdentityAttrs(u.configMap, preset.ConfigAttrs, true); err != nil {
+		return err
+	}
+
+	for key, desc := range preset.ReportDescs {
+		item, ok := u.configMap[key]
+		if !ok {
+			return fmt.Errorf("config item %s not found", key)
+		}
+		if item.reportDesc == nil {
+			return fmt.Errorf("config item %s has no report descriptor", key)
+		}
+		if err := validateReportDesc(desc); err != nil {
+			return fmt.Errorf("invalid report descriptor for %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (u *UsbGadget) loadIdentityPreset() {
+	if u.customConfig.Identity != nil {
+		u.identity = u.customConfig.Identity
+	}
+}
+
+// SetIdentityPreset validates and sets the identity preset, nil restores the default identity.
+// The change will be applied on the next UpdateGadgetConfig call.
+func (u *UsbGadget) SetIdentityPreset(preset *IdentityPreset) error {
+	u.configLock.Lock()
+	defer u.configLock.Unlock()
+
+	if preset != nil {
+		if err := u.ValidateIdentityPreset(preset); err != nil {
+			return err
+		}
+	}
+
+	u.customConfig.Identity = preset
+	u.identity = preset
+	return nil
+}
+
+func mergeGadgetAttributes(attrs gadgetAttributes, overrides map[string]string) gadgetAttributes {
+	if len(overrides) == 0 {
+		return attrs
+	}
+	merged := make(gadgetAttributes, len(attrs))
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
+// applyIdentity returns a copy of the item with the overrides of the identity preset applied,
+// the config map itself is left untouched so switching back to the default identity restores it.
+func (tx *UsbGadgetTransaction) applyIdentity(key string, item gadgetConfigItem) gadgetConfigItem {
+	if tx.identity == nil {
+		return item
+	}
+
+	item.attrs = mergeGadgetAttributes(item.attrs, tx.identity.Attrs[key])
+	item.configAttrs = mergeGadgetAttributes(item.configAttrs, tx.identity.ConfigAttrs[key])
+	if desc, ok := tx.identity.ReportDescs[key]; ok {
+		item.reportDesc = desc
+	}
+	return item
+}
@@ -0,0 +1,70 @@
+package usbgadget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateReportDesc(t *testing.T) {
+	for _, desc := range [][]byte{
+		keyboardReportDesc,
+		dellKeyboardReportDesc,
+		absoluteMouseCombinedReportDesc,
+		relativeMouseCombinedReportDesc,
+	} {
+		assert.NoError(t, validateReportDesc(desc))
+	}
+
+	assert.Error(t, validateReportDesc(nil))
+	// COLLECTION without END_COLLECTION
+	assert.Error(t, validateReportDesc([]byte{0x05, 0x01, 0xa1, 0x01}))
+	// END_COLLECTION without COLLECTION
+	assert.Error(t, validateReportDesc([]byte{0xc0}))
+	// LOGICAL_MAXIMUM with a 2 byte value, truncated
+	assert.Error(t, validateReportDesc([]byte{0x26, 0xff}))
+}
+
+func TestValidateIdentityPreset(t *testing.T) {
+	g := newUsbGadget("test", defaultGadgetConfig, nil, nil, nil)
+
+	for _, preset := range GetBuiltinIdentityPresets() {
+		assert.NoError(t, g.ValidateIdentityPreset(&preset), preset.Name)
+	}
+
+	assert.Error(t, g.ValidateIdentityPreset(&IdentityPreset{Name: "Invalid Name"}))
+	assert.Error(t, g.ValidateIdentityPreset(&IdentityPreset{
+		Name:  "unknown_item",
+		Attrs: map[string]map[string]string{"nonexistent": {"attr": "x"}},
+	}))
+	assert.Error(t, g.ValidateIdentityPreset(&IdentityPreset{
+		Name:  "unknown_attr",
+		Attrs: map[string]map[string]string{"base": {"bDeviceClass": "0x00"}},
+	}))
+	assert.Error(t, g.ValidateIdentityPreset(&IdentityPreset{
+		Name:  "invalid_vid",
+		Attrs: map[string]map[string]string{"base": {"idVendor": "1234"}},
+	}))
+	assert.Error(t, g.ValidateIdentityPreset(&IdentityPreset{
+		Name:        "no_report_desc",
+		ReportDescs: map[string][]byte{"mass_storage_base": keyboardReportDesc},
+	}))
+}
+
+func TestApplyIdentityKeepsConfigMap(t *testing.T) {
+	preset := GetBuiltinIdentityPreset("dell_keyboard")
+	tx := &UsbGadgetTransaction{identity: preset}
+
+	item := tx.applyIdentity("base", defaultGadgetConfig["base"])
+	assert.Equal(t, "0x413c", item.attrs["idVendor"])
+	assert.Equal(t, "100", item.configAttrs["MaxPower"])
+	assert.Equal(t, "0x1d6b", defaultGadgetConfig["base"].attrs["idVendor"])
+
+	item = tx.applyIdentity("keyboard", defaultGadgetConfig["keyboard"])
+	assert.Equal(t, dellKeyboardReportDesc, item.reportDesc)
+	assert.Equal(t, keyboardReportDesc, defaultGadgetConfig["keyboard"].reportDesc)
+
+	item = tx.applyIdentity(MassStorageLunKey(1), defaultGadgetConfig[MassStorageLunKey(1)])
+	assert.Equal(t, "Dell    USB Disk        1.00", item.attrs["inquiry_string"])
+	assert.Equal(t, "1", item.attrs["cdrom"])
+}
@@ -1,5 +1,10 @@
 package usbgadget
 
+import "fmt"
+
+// MaxMassStorageLuns is the maximum number of LUNs exposed by the mass storage function.
+const MaxMassStorageLuns = 4
+
 var massStorageBaseConfig = gadgetConfigItem{
 	order:      3000,
 	device:     "mass_storage.usb0",
@@ -10,17 +15,74 @@ var massStorageBaseConfig = gadgetConfigItem{
 	},
 }
 
-var massStorageLun0Config = gadgetConfigItem{
-	order: 3001,
-	path:  []string{"functions", "mass_storage.usb0", "lun.0"},
-	attrs: gadgetAttributes{
-		"cdrom":     "1",
-		"ro":        "1",
-		"removable": "1",
-		"file":      "\n",
-		// the additional whitespace is intentional to avoid the "JetKVM V irtual Media" string
-		// https://github.com/jetkvm/rv1106-system/blob/778133a1c153041e73f7de86c9c434a2753ea65d/sysdrv/source/uboot/u-boot/drivers/usb/gadget/f_mass_storage.c#L2556
-		// Vendor (8 chars), product (16 chars)
-		"inquiry_string": "JetKVM  Virtual Media",
-	},
+var massStorageLun0Config = massStorageLunConfig(0)
+var massStorageLun1Config = massStorageLunConfig(1)
+var massStorageLun2Config = massStorageLunConfig(2)
+var massStorageLun3Config = massStorageLunConfig(3)
+
+// MassStorageLunKey returns the config item key of the given LUN.
+func MassStorageLunKey(lun int) string {
+	return fmt.Sprintf("mass_storage_lun%d", lun)
+}
+
+func massStorageLunConfig(lun int) gadgetConfigItem {
+	item := gadgetConfigItem{
+		order: 3001 + uint(lun),
+		path:  []string{"functions", "mass_storage.usb0", fmt.Sprintf("lun.%d", lun)},
+		// the file isn't part of the config, writing it would eject the media whenever the gadget is reconfigured
+		attrs: gadgetAttributes{
+			"cdrom":     "1",
+			"ro":        "1",
+			"removable": "1",
+			// the additional whitespace is intentional to avoid the "JetKVM V irtual Media" string
+			// https://github.com/jetkvm/rv1106-system/blob/778133a1c153041e73f7de86c9c434a2753ea65d/sysdrv/source/uboot/u-boot/drivers/usb/gadget/f_mass_storage.c#L2556
+			// Vendor (8 chars), product (16 chars)
+			"inquiry_string": "JetKVM  Virtual Media",
+		},
+	}
+	// lun.0 is created by the kernel along with the function, the others have to be
+	// created (and removed) while the function isn't linked to the config
+	if lun > 0 {
+		item.parent = "mass_storage_base"
+	}
+	return item
+}
+
+// MassStorageLunCount returns the number of enabled mass storage LUNs, at least one.
+func (d *Devices) MassStorageLunCount() int {
+	if d.MassStorageLuns < 1 {
+		return 1
+	}
+	if d.MassStorageLuns > MaxMassStorageLuns {
+		return MaxMassStorageLuns
+	}
+	return d.MassStorageLuns
+}
+
+// loadMassStorageLunModes keeps the cdrom and ro attributes of the LUNs with media inserted,
+// the kernel refuses to change them until the media is ejected.
+func (u *UsbGadget) loadMassStorageLunModes() {
+	for lun := 0; lun < MaxMassStorageLuns; lun++ {
+		key := MassStorageLunKey(lun)
+		if file, err := u.readFunctionAttr(key, "file"); err != nil || file == "" {
+			continue
+		}
+		for _, attr := range []string{"cdrom", "ro"} {
+			if value, err := u.readFunctionAttr(key, attr); err == nil {
+				u.configMap[key].attrs[attr] = value
+			}
+		}
+	}
+}
+
+func (u *UsbGadget) isMassStorageLunEnabled(itemKey string) bool {
+	if !u.enabledDevices.MassStorage {
+		return false
+	}
+	for lun := 0; lun < u.enabledDevices.MassStorageLunCount(); lun++ {
+		if itemKey == MassStorageLunKey(lun) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,84 @@
+package usbgadget
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricHidWriteDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "jetkvm_usb_hid_write_duration_seconds",
+			Help:    "The time it takes to write a HID report to the gadget",
+			Buckets: []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1},
+		},
+		[]string{"device"},
+	)
+	metricHidWriteTimeoutCount = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jetkvm_usb_hid_write_timeouts_total",
+			Help: "The number of HID reports that were dropped because the host didn't read them in time",
+		},
+		[]string{"device"},
+	)
+	metricHidWriteErrorCount = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jetkvm_usb_hid_write_errors_total",
+			Help: "The number of HID reports that failed to be written",
+		},
+		[]string{"device"},
+	)
+)
+
+// HidWriteStats are the counters of the writes to a HID device, e.g. hidg0.
+type HidWriteStats struct {
+	Writes   uint64 `json:"writes"`
+	Timeouts uint64 `json:"timeouts"`
+	Errors   uint64 `json:"errors"`
+}
+
+var (
+	hidWriteStats     = make(map[string]*HidWriteStats)
+	hidWriteStatsLock sync.Mutex
+)
+
+func recordHidWrite(file string, duration time.Duration, timeout bool, err error) {
+	device := filepath.Base(file)
+	metricHidWriteDuration.WithLabelValues(device).Observe(duration.Seconds())
+	if timeout {
+		metricHidWriteTimeoutCount.WithLabelValues(device).Inc()
+	} else if err != nil {
+		metricHidWriteErrorCount.WithLabelValues(device).Inc()
+	}
+
+	hidWriteStatsLock.Lock()
+	defer hidWriteStatsLock.Unlock()
+
+	stats, ok := hidWriteStats[device]
+	if !ok {
+		stats = &HidWriteStats{}
+		hidWriteStats[device] = stats
+	}
+	stats.Writes++
+	if timeout {
+		stats.Timeouts++
+	} else if err != nil {
+		stats.Errors++
+	}
+}
+
+// GetHidWriteStats returns the write counters of every HID device that has been written to.
+func GetHidWriteStats() map[string]HidWriteStats {
+	hidWriteStatsLock.Lock()
+	defer hidWriteStatsLock.Unlock()
+
+	stats := make(map[string]HidWriteStats, len(hidWriteStats))
+	for device, s := range hidWriteStats {
+		stats[device] = *s
+	}
+	return stats
+}
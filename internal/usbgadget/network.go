@@ -0,0 +1,107 @@
+package usbgadget
+
+import "fmt"
+
+// NetworkConfig is the configuration of the USB network function.
+type NetworkConfig struct {
+	Function  string `json:"function"`   // one of "ecm", "ncm" or "rndis"
+	HostMac   string `json:"host_mac"`   // MAC address of the host side of the link
+	DeviceMac string `json:"device_mac"` // MAC address of the device side of the link
+}
+
+const (
+	NetworkFunctionECM   = "ecm"
+	NetworkFunctionNCM   = "ncm"
+	NetworkFunctionRNDIS = "rndis"
+)
+
+// networkConfigKeys maps the network function to its gadget config item.
+var networkConfigKeys = map[string]string{
+	NetworkFunctionECM:   "network_ecm",
+	NetworkFunctionNCM:   "network_ncm",
+	NetworkFunctionRNDIS: "network_rndis",
+}
+
+// Validate checks if the network function is supported.
+func (c *NetworkConfig) Validate() error {
+	if _, ok := networkConfigKeys[c.Function]; !ok {
+		return fmt.Errorf("invalid network function: %s", c.Function)
+	}
+	return nil
+}
+
+var networkEcmConfig = gadgetConfigItem{
+	order:      2100,
+	device:     "ecm.usb0",
+	path:       []string{"functions", "ecm.usb0"},
+	configPath: []string{"ecm.usb0"},
+	attrs:      gadgetAttributes{},
+}
+
+var networkNcmConfig = gadgetConfigItem{
+	order:      2101,
+	device:     "ncm.usb0",
+	path:       []string{"functions", "ncm.usb0"},
+	configPath: []string{"ncm.usb0"},
+	attrs:      gadgetAttributes{},
+}
+
+// RNDIS is only needed for older Windows hosts, newer ones ship a NCM driver.
+var networkRndisConfig = gadgetConfigItem{
+	order:      2102,
+	device:     "rndis.usb0",
+	path:       []string{"functions", "rndis.usb0"},
+	configPath: []string{"rndis.usb0"},
+	attrs:      gadgetAttributes{},
+}
+
+func (u *UsbGadget) isNetworkConfigItemEnabled(itemKey string) bool {
+	if !u.enabledDevices.Network {
+		return false
+	}
+	return networkConfigKeys[u.networkConfig.Function] == itemKey
+}
+
+func (u *UsbGadget) loadNetworkConfig() {
+	if u.customConfig.Network != nil {
+		u.networkConfig = *u.customConfig.Network
+	}
+
+	// the kernel generates random addresses if they're not set,
+	// which makes the host see a new network adapter on every boot
+	for _, key := range networkConfigKeys {
+		item, ok := u.configMap[key]
+		if !ok {
+			continue
+		}
+		if u.networkConfig.HostMac != "" {
+			item.attrs["host_addr"] = u.networkConfig.HostMac
+		}
+		if u.networkConfig.DeviceMac != "" {
+			item.attrs["dev_addr"] = u.networkConfig.DeviceMac
+		}
+	}
+}
+
+// SetNetworkConfig sets the configuration of the USB network function,
+// the change will be applied on the next UpdateGadgetConfig call.
+func (u *UsbGadget) SetNetworkConfig(config *NetworkConfig) {
+	u.configLock.Lock()
+	defer u.configLock.Unlock()
+
+	if config == nil {
+		return // nothing to do
+	}
+
+	u.customConfig.Network = config
+	u.loadNetworkConfig()
+}
+
+// GetNetworkInterfaceName returns the name of the network interface created by the USB network function.
+func (u *UsbGadget) GetNetworkInterfaceName() (string, error) {
+	key, ok := networkConfigKeys[u.networkConfig.Function]
+	if !ok {
+		return "", fmt.Errorf("invalid network function: %s", u.networkConfig.Function)
+	}
+	return u.readFunctionAttr(key, "ifname")
+}
@@ -0,0 +1,156 @@
+package usbgadget
+
+import (
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/tf-dag/dag"
+)
+
+// PlannedFileChange is a configfs change that would be applied by a transaction.
+type PlannedFileChange struct {
+	Component   string   `json:"component"`
+	Action      string   `json:"action"`
+	Path        string   `json:"path"`
+	Description string   `json:"description"`
+	OldContent  string   `json:"old_content,omitempty"`
+	NewContent  string   `json:"new_content,omitempty"`
+	DependsOn   []string `json:"depends_on,omitempty"`
+}
+
+// formatPlanContent returns the content as text if it's printable, hex encoded otherwise (e.g. report descriptors).
+func formatPlanContent(content []byte) string {
+	if utf8.Valid(content) && strings.IndexFunc(string(content), func(r rune) bool {
+		return !unicode.IsPrint(r) && !unicode.IsSpace(r)
+	}) < 0 {
+		return string(content)
+	}
+	return "0x" + hex.EncodeToString(content)
+}
+
+func newPlannedFileChange(change *FileChange) PlannedFileChange {
+	planned := PlannedFileChange{
+		Component:   change.Component,
+		Action:      FileChangeResolvedActionString[change.Action()],
+		Path:        change.Path,
+		Description: change.Description,
+		OldContent:  formatPlanContent(change.ActualContent),
+		NewContent:  formatPlanContent(change.ExpectedContent),
+	}
+
+	if len(change.ParamSymlinks) > 0 {
+		symlinks := make([]string, 0, len(change.ParamSymlinks))
+		for _, s := range change.ParamSymlinks {
+			symlinks = append(symlinks, fmt.Sprintf("%s -> %s", s.Path, s.Target))
+		}
+		planned.NewContent = strings.Join(symlinks, "\n")
+	}
+
+	planned.DependsOn = append(planned.DependsOn, change.DependsOn...)
+	planned.DependsOn = append(planned.DependsOn, change.resolvedDeps...)
+	return planned
+}
+
+// Plan resolves the changes without applying them, the changes that wouldn't do anything are left out.
+func (c *ChangeSet) Plan() ([]PlannedFileChange, error) {
+	r := ChangeSetResolver{
+		changeset: c,
+		g:         &dag.AcyclicGraph{},
+		l:         defaultLogger,
+	}
+
+	changes, err := r.GetChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	planned := make([]PlannedFileChange, 0)
+	for _, change := range changes {
+		if change.Action() == FileChangeResolvedActionDoNothing {
+			continue
+		}
+		planned = append(planned, newPlannedFileChange(change))
+	}
+	return planned, nil
+}
+
+// WithTransactionPlan runs fn like WithTransaction, but returns the changes the transaction would apply
+// instead of committing it.
+func (u *UsbGadget) WithTransactionPlan(fn func() error) ([]PlannedFileChange, error) {
+	u.txLock.Lock()
+	defer u.txLock.Unlock()
+
+	err := u.newUsbGadgetTransaction(false)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { u.tx = nil }()
+
+	if err := fn(); err != nil {
+		return nil, err
+	}
+	return u.tx.Plan()
+}
+
+// Plan returns the changes the transaction would apply on commit.
+func (tx *UsbGadgetTransaction) Plan() ([]PlannedFileChange, error) {
+	if tx.reorderSymlinkChanges != nil {
+		tx.addFileChange("gadget-finalize", *tx.reorderSymlinkChanges)
+	}
+	return tx.c.Plan()
+}
+
+func cloneGadgetConfigMap(configMap map[string]gadgetConfigItem) map[string]gadgetConfigItem {
+	clone := make(map[string]gadgetConfigItem, len(configMap))
+	for key, item := range configMap {
+		item.attrs = maps.Clone(item.attrs)
+		item.configAttrs = maps.Clone(item.configAttrs)
+		clone[key] = item
+	}
+	return clone
+}
+
+// PlanGadgetConfig returns the changes UpdateGadgetConfig would apply with the given config and devices,
+// nil keeps the current one. The gadget itself isn't modified.
+func (u *UsbGadget) PlanGadgetConfig(config *Config, devices *Devices) ([]PlannedFileChange, error) {
+	u.configLock.Lock()
+	shadow := &UsbGadget{
+		name:           u.name,
+		udc:            u.udc,
+		kvmGadgetPath:  u.kvmGadgetPath,
+		configC1Path:   u.configC1Path,
+		configMap:      cloneGadgetConfigMap(u.configMap),
+		customConfig:   u.customConfig,
+		networkConfig:  u.networkConfig,
+		identity:       u.identity,
+		remoteWakeup:   u.remoteWakeup,
+		enabledDevices: u.enabledDevices,
+		detachedItems:  maps.Clone(u.detachedItems),
+		log:            u.log,
+	}
+	u.configLock.Unlock()
+
+	if config != nil {
+		// the network, identity and remote wakeup settings are managed separately and kept as they are
+		config.Network = shadow.customConfig.Network
+		config.Identity = shadow.customConfig.Identity
+		config.RemoteWakeup = shadow.customConfig.RemoteWakeup
+		shadow.customConfig = *config
+	}
+	if devices != nil {
+		shadow.enabledDevices = *devices
+	}
+	shadow.loadGadgetConfig()
+
+	return shadow.WithTransactionPlan(func() error {
+		shadow.tx.MountConfigFS()
+		shadow.tx.CreateConfigPath()
+		shadow.tx.WriteGadgetConfig()
+		shadow.tx.RebindUsb(true)
+		return nil
+	})
+}
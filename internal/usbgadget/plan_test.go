@@ -0,0 +1,95 @@
+package usbgadget
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatPlanContent(t *testing.T) {
+	assert.Equal(t, "0x1d6b", formatPlanContent([]byte("0x1d6b")))
+	assert.Equal(t, "JetKVM  Virtual Media\n", formatPlanContent([]byte("JetKVM  Virtual Media\n")))
+	assert.Equal(t, "0x0501a101", formatPlanContent([]byte{0x05, 0x01, 0xa1, 0x01}))
+	assert.Equal(t, "", formatPlanContent(nil))
+}
+
+func TestCloneGadgetConfigMap(t *testing.T) {
+	clone := cloneGadgetConfigMap(defaultGadgetConfig)
+	clone["base"].attrs["idVendor"] = "0x0000"
+
+	assert.Equal(t, "0x1d6b", defaultGadgetConfig["base"].attrs["idVendor"])
+}
+
+func TestGadgetConfigOrderIsUnique(t *testing.T) {
+	keys := make(map[uint]string)
+	for key, item := range defaultGadgetConfig {
+		other, ok := keys[item.order]
+		assert.False(t, ok, "%s has the same order as %s", key, other)
+		keys[item.order] = key
+	}
+}
+
+func findPlannedChange(changes []PlannedFileChange, action string, path string) *PlannedFileChange {
+	for i := range changes {
+		if changes[i].Action == action && changes[i].Path == path {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+func TestPlanGadgetConfig(t *testing.T) {
+	g := newUsbGadget("test", cloneGadgetConfigMap(defaultGadgetConfig), nil, nil, nil)
+	require.NotNil(t, g)
+
+	printerPath := "/sys/kernel/config/usb_gadget/test/functions/printer.usb0"
+
+	changes, err := g.PlanGadgetConfig(nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, findPlannedChange(changes, "DIR_CREATE", printerPath))
+
+	devices := defaultUsbGadgetDevices
+	devices.Printer = true
+	changes, err = g.PlanGadgetConfig(nil, &devices)
+	require.NoError(t, err)
+
+	change := findPlannedChange(changes, "DIR_CREATE", printerPath)
+	require.NotNil(t, change)
+	assert.Equal(t, "printer.usb0", change.Component)
+
+	link := findPlannedChange(changes, "SYMLINK_REORDER", "/sys/kernel/config/usb_gadget/test/configs/c.1")
+	require.NotNil(t, link)
+	assert.Contains(t, link.NewContent, "printer.usb0 -> "+printerPath)
+
+	changes, err = g.PlanGadgetConfig(&Config{
+		VendorId:  "0x1234",
+		ProductId: "0x5678",
+	}, nil)
+	require.NoError(t, err)
+
+	change = findPlannedChange(changes, "FILE_CREATE", "/sys/kernel/config/usb_gadget/test/idVendor")
+	require.NotNil(t, change)
+	assert.Equal(t, "0x1234", change.NewContent)
+
+	// planning doesn't modify the gadget
+	assert.False(t, g.enabledDevices.Printer)
+	assert.Equal(t, "0x1d6b", g.configMap["base"].attrs["idVendor"])
+}
+
+func TestPlanGadgetConfigKeepsMassStorageMedia(t *testing.T) {
+	g := newUsbGadget("test", cloneGadgetConfigMap(defaultGadgetConfig), nil, nil, nil)
+	require.NotNil(t, g)
+
+	devices := defaultUsbGadgetDevices
+	devices.MassStorageLuns = MaxMassStorageLuns
+	changes, err := g.PlanGadgetConfig(nil, &devices)
+	require.NoError(t, err)
+	require.NotNil(t, findPlannedChange(changes, "DIR_CREATE", "/sys/kernel/config/usb_gadget/test/functions/mass_storage.usb0/lun.3"))
+
+	// writing the file of a LUN ejects its media
+	for _, change := range changes {
+		assert.NotEqual(t, "file", path.Base(change.Path), "%s would eject the media", change.Path)
+	}
+}
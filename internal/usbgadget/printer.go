@@ -0,0 +1,34 @@
+package usbgadget
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// printerConfig exposes a USB printer class function, the device side reads the print stream from /dev/g_printerN.
+var printerConfig = gadgetConfigItem{
+	order:      2300,
+	device:     "printer.usb0",
+	path:       []string{"functions", "printer.usb0"},
+	configPath: []string{"printer.usb0"},
+	attrs: gadgetAttributes{
+		// IEEE 1284 device ID, hosts pick the driver with it
+		"pnp_string": "MFG:JetKVM;MDL:Virtual Printer;CMD:PCL,POSTSCRIPT,PDF;CLS:PRINTER;",
+		"q_len":      "10",
+	},
+}
+
+// PrinterDevicePattern matches the device side of the printer function.
+const PrinterDevicePattern = "/dev/g_printer*"
+
+// PrinterDevicePath returns the device node of the printer function.
+func PrinterDevicePath() (string, error) {
+	matches, err := filepath.Glob(PrinterDevicePattern)
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no printer device found")
+	}
+	return matches[0], nil
+}
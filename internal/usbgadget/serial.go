@@ -0,0 +1,13 @@
+package usbgadget
+
+// serialConsoleConfig exposes a CDC-ACM function so the host sees a USB serial port,
+// the device side of the link is available as /dev/ttyGS0.
+var serialConsoleConfig = gadgetConfigItem{
+	order:      2000,
+	device:     "acm.usb0",
+	path:       []string{"functions", "acm.usb0"},
+	configPath: []string{"acm.usb0"},
+}
+
+// SerialConsoleDevicePath is the device side of the CDC-ACM function.
+const SerialConsoleDevicePath = "/dev/ttyGS0"
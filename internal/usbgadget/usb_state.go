@@ -0,0 +1,113 @@
+package usbgadget
+
+import (
+	"context"
+	"path"
+	"time"
+)
+
+// UsbState is the state of the UDC as reported by the kernel.
+type UsbState string
+
+const (
+	UsbStateUnknown     UsbState = "unknown"
+	UsbStateNotAttached UsbState = "not attached"
+	UsbStateAttached    UsbState = "attached"
+	UsbStatePowered     UsbState = "powered"
+	UsbStateDefault     UsbState = "default"
+	UsbStateAddressed   UsbState = "addressed"
+	UsbStateConfigured  UsbState = "configured"
+	UsbStateSuspended   UsbState = "suspended"
+)
+
+// UsbStateTransition is a change of the UDC state.
+type UsbStateTransition struct {
+	From UsbState  `json:"from"`
+	To   UsbState  `json:"to"`
+	Time time.Time `json:"time"`
+}
+
+const (
+	usbStateHistorySize = 32
+	// usbStatePollInterval is used when the state file can't be watched
+	usbStatePollInterval = 500 * time.Millisecond
+	// usbStateWatchTimeout re-reads the state file now and then, in case a notification was missed
+	usbStateWatchTimeout = 5 * time.Second
+)
+
+func (u *UsbGadget) getUsbStatePath() string {
+	return path.Join("/sys/class/udc", u.udc, "state")
+}
+
+// SetOnUsbStateChange sets the callback for UDC state transitions,
+// it's called from the monitor goroutine.
+func (u *UsbGadget) SetOnUsbStateChange(f func(transition UsbStateTransition)) {
+	u.onUsbStateChange = &f
+}
+
+// GetUsbStateHistory returns the recent UDC state transitions, oldest first.
+func (u *UsbGadget) GetUsbStateHistory() []UsbStateTransition {
+	u.usbStateLock.Lock()
+	defer u.usbStateLock.Unlock()
+
+	history := make([]UsbStateTransition, len(u.usbStateHistory))
+	copy(history, u.usbStateHistory)
+	return history
+}
+
+// updateUsbState records the state and notifies the callback if it changed.
+func (u *UsbGadget) updateUsbState(state UsbState) {
+	u.usbStateLock.Lock()
+	if state == u.usbState {
+		u.usbStateLock.Unlock()
+		return
+	}
+
+	transition := UsbStateTransition{
+		From: u.usbState,
+		To:   state,
+		Time: time.Now(),
+	}
+	u.usbState = state
+	u.usbStateHistory = append(u.usbStateHistory, transition)
+	if len(u.usbStateHistory) > usbStateHistorySize {
+		u.usbStateHistory = u.usbStateHistory[len(u.usbStateHistory)-usbStateHistorySize:]
+	}
+	u.usbStateLock.Unlock()
+
+	u.log.Info().Str("from", string(transition.From)).Str("to", string(transition.To)).Msg("USB state changed")
+
+	if u.onUsbStateChange != nil {
+		(*u.onUsbStateChange)(transition)
+	}
+}
+
+// StartUsbStateMonitor starts tracking the UDC state. The state file is watched for change notifications
+// from the kernel, if that isn't possible the monitor falls back to polling.
+func (u *UsbGadget) StartUsbStateMonitor() {
+	ctx, cancel := context.WithCancel(context.Background())
+	u.usbStateCancel = cancel
+
+	go func() {
+		watching := true
+		for {
+			err := u.watchUsbState(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+
+			// the file can't be watched (e.g. there's no UDC), poll it instead
+			if watching {
+				u.log.Debug().Err(err).Msg("unable to watch USB state, falling back to polling")
+				watching = false
+			}
+			u.updateUsbState(UsbState(u.GetUsbState()))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(usbStatePollInterval):
+			}
+		}
+	}()
+}
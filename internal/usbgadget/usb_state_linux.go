@@ -0,0 +1,39 @@
+//go:build linux
+
+package usbgadget
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchUsbState blocks until the context is done or the state file can't be watched anymore.
+// sysfs notifies pollers of attribute changes with POLLPRI, inotify doesn't work on sysfs.
+func (u *UsbGadget) watchUsbState(ctx context.Context) error {
+	file, err := os.Open(u.getUsbStatePath())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	buf := make([]byte, 64)
+	fds := []unix.PollFd{{Fd: int32(file.Fd()), Events: unix.POLLPRI | unix.POLLERR}}
+
+	for ctx.Err() == nil {
+		// the file has to be read from the start after every notification to re-arm it
+		n, err := file.ReadAt(buf, 0)
+		if err != nil && n == 0 {
+			return err
+		}
+		u.updateUsbState(UsbState(strings.TrimSpace(string(buf[:n]))))
+
+		_, err = unix.Poll(fds, int(usbStateWatchTimeout.Milliseconds()))
+		if err != nil && err != unix.EINTR {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package usbgadget
+
+import (
+	"context"
+	"errors"
+)
+
+func (u *UsbGadget) watchUsbState(ctx context.Context) error {
+	return errors.New("watching the USB state is not supported on this platform")
+}
@@ -0,0 +1,38 @@
+package usbgadget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateUsbState(t *testing.T) {
+	u := &UsbGadget{usbState: UsbStateUnknown, log: defaultLogger}
+
+	var transitions []UsbStateTransition
+	u.SetOnUsbStateChange(func(transition UsbStateTransition) {
+		transitions = append(transitions, transition)
+	})
+
+	u.updateUsbState(UsbStateAttached)
+	u.updateUsbState(UsbStateAttached)
+	u.updateUsbState(UsbStateConfigured)
+
+	assert.Len(t, transitions, 2)
+	assert.Equal(t, UsbStateUnknown, transitions[0].From)
+	assert.Equal(t, UsbStateConfigured, transitions[1].To)
+	assert.Equal(t, transitions, u.GetUsbStateHistory())
+}
+
+func TestUsbStateHistoryIsBounded(t *testing.T) {
+	u := &UsbGadget{usbState: UsbStateUnknown, log: defaultLogger}
+
+	for i := 0; i < usbStateHistorySize; i++ {
+		u.updateUsbState(UsbStateConfigured)
+		u.updateUsbState(UsbStateSuspended)
+	}
+
+	history := u.GetUsbStateHistory()
+	assert.Len(t, history, usbStateHistorySize)
+	assert.Equal(t, UsbStateSuspended, history[len(history)-1].To)
+}
@@ -18,7 +18,16 @@ type Devices struct {
 	AbsoluteMouse bool `json:"absolute_mouse"`
 	RelativeMouse bool `json:"relative_mouse"`
 	Keyboard      bool `json:"keyboard"`
+	Fido          bool `json:"fido"`
 	MassStorage   bool `json:"mass_storage"`
+	// MassStorageLuns is the number of LUNs of the mass storage function, 0 means 1
+	MassStorageLuns int  `json:"mass_storage_luns,omitempty"`
+	SerialConsole   bool `json:"serial_console"`
+	Printer         bool `json:"printer"`
+	Audio           bool `json:"audio"`
+	// AudioMicrophone exposes a microphone next to the speakers of the audio function
+	AudioMicrophone bool `json:"audio_microphone,omitempty"`
+	Network         bool `json:"network"`
 }
 
 // Config is a struct that represents the customizations for a USB gadget.
@@ -30,6 +39,13 @@ type Config struct {
 	Manufacturer string `json:"manufacturer"`
 	Product      string `json:"product"`
 
+	// Network is managed separately from the customizations above, so it's not serialized
+	Network *NetworkConfig `json:"-"`
+	// Identity is the identity preset, validated and stored by the caller
+	Identity *IdentityPreset `json:"-"`
+	// RemoteWakeup advertises remote wakeup to the host, nil keeps the current setting
+	RemoteWakeup *bool `json:"-"`
+
 	strictMode bool // when it's enabled, all warnings will be converted to errors
 	isEmpty    bool
 }
@@ -38,7 +54,12 @@ var defaultUsbGadgetDevices = Devices{
 	AbsoluteMouse: true,
 	RelativeMouse: true,
 	Keyboard:      true,
+	Fido:          false,
 	MassStorage:   true,
+	SerialConsole: false,
+	Printer:       false,
+	Audio:         false,
+	Network:       false,
 }
 
 type KeysDownState struct {
@@ -53,8 +74,11 @@ type UsbGadget struct {
 	kvmGadgetPath string
 	configC1Path  string
 
-	configMap    map[string]gadgetConfigItem
-	customConfig Config
+	configMap     map[string]gadgetConfigItem
+	customConfig  Config
+	networkConfig NetworkConfig
+	identity      *IdentityPreset
+	remoteWakeup  bool
 
 	configLock sync.Mutex
 
@@ -76,6 +100,7 @@ type UsbGadget struct {
 	keyboardStateCancel context.CancelFunc
 
 	enabledDevices Devices
+	detachedItems  map[string]bool // functions that are temporarily unlinked from the config
 
 	strictMode bool // only intended for testing for now
 
@@ -89,6 +114,13 @@ type UsbGadget struct {
 	onKeyboardStateChange *func(state KeyboardState)
 	onKeysDownChange      *func(state KeysDownState)
 	onKeepAliveReset      *func()
+	onAutoRelease         *func(key byte)
+	onUsbStateChange      *func(transition UsbStateTransition)
+
+	usbState        UsbState
+	usbStateHistory []UsbStateTransition
+	usbStateLock    sync.Mutex
+	usbStateCancel  context.CancelFunc
 
 	log *zerolog.Logger
 
@@ -138,7 +170,9 @@ func newUsbGadget(name string, configMap map[string]gadgetConfigItem, enabledDev
 		keysDownState:        KeysDownState{Modifier: 0, Keys: []byte{0, 0, 0, 0, 0, 0}}, // must be initialized to hidKeyBufferSize (6) zero bytes
 		kbdAutoReleaseTimers: make(map[byte]*time.Timer),
 		enabledDevices:       *enabledDevices,
+		detachedItems:        make(map[string]bool),
 		lastUserInput:        time.Now(),
+		usbState:             UsbStateUnknown,
 		log:                  logger,
 
 		strictMode: config.strictMode,
@@ -162,6 +196,11 @@ func (u *UsbGadget) Close() error {
 		u.keyboardStateCancel()
 	}
 
+	// Stop USB state monitor
+	if u.usbStateCancel != nil {
+		u.usbStateCancel()
+	}
+
 	// Stop auto-release timer
 	u.kbdAutoReleaseLock.Lock()
 	for _, timer := range u.kbdAutoReleaseTimers {
@@ -45,6 +45,20 @@ func joinPath(basePath string, paths []string) string {
 	return filepath.Join(pathArr...)
 }
 
+// readFunctionAttr reads an attribute of the function of the given config item.
+func (u *UsbGadget) readFunctionAttr(itemKey string, attr string) (string, error) {
+	functionPath, err := u.GetPath(itemKey)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(filepath.Join(functionPath, attr))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", attr, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
 func hexToDecimal(hex string) (int64, error) {
 	decimal, err := strconv.ParseInt(hex, 16, 64)
 	if err != nil {
@@ -116,7 +130,9 @@ func (u *UsbGadget) writeWithTimeout(file *os.File, data []byte) (n int, err err
 		return -1, err
 	}
 
+	start := time.Now()
 	n, err = file.Write(data)
+	recordHidWrite(file.Name(), time.Since(start), errors.Is(err, os.ErrDeadlineExceeded), err)
 	if err == nil {
 		return
 	}
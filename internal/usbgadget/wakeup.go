@@ -0,0 +1,72 @@
+package usbgadget
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+)
+
+var (
+	// ErrNotSuspended is returned when a remote wakeup is requested while the host isn't suspended.
+	ErrNotSuspended = errors.New("USB host is not suspended")
+	// ErrRemoteWakeupDisabled is returned when a remote wakeup is requested while it isn't advertised.
+	ErrRemoteWakeupDisabled = errors.New("USB remote wakeup is disabled")
+)
+
+const (
+	bmAttributesBusPowered   = "0x80"
+	bmAttributesRemoteWakeup = "0xa0" // bus powered, remote wakeup
+)
+
+func (u *UsbGadget) loadRemoteWakeup() {
+	if u.customConfig.RemoteWakeup != nil {
+		u.remoteWakeup = *u.customConfig.RemoteWakeup
+	}
+
+	bmAttributes := bmAttributesBusPowered
+	if u.remoteWakeup {
+		bmAttributes = bmAttributesRemoteWakeup
+	}
+	u.configMap["base"].configAttrs["bmAttributes"] = bmAttributes
+}
+
+// SetRemoteWakeup sets whether remote wakeup is advertised to the host,
+// the change will be applied on the next UpdateGadgetConfig call.
+func (u *UsbGadget) SetRemoteWakeup(enabled bool) {
+	u.configLock.Lock()
+	defer u.configLock.Unlock()
+
+	u.customConfig.RemoteWakeup = &enabled
+	u.loadRemoteWakeup()
+}
+
+// IsSuspended returns true if the host suspended the bus, e.g. because it went to sleep.
+func (u *UsbGadget) IsSuspended() bool {
+	u.usbStateLock.Lock()
+	defer u.usbStateLock.Unlock()
+	return u.usbState == UsbStateSuspended
+}
+
+// RemoteWakeup signals remote wakeup to the suspended host. The host only honors it if remote wakeup
+// is advertised in bmAttributes and was enabled by the host before it suspended.
+func (u *UsbGadget) RemoteWakeup() error {
+	u.configLock.Lock()
+	enabled := u.remoteWakeup
+	u.configLock.Unlock()
+	if !enabled {
+		return ErrRemoteWakeupDisabled
+	}
+	if !u.IsSuspended() {
+		return ErrNotSuspended
+	}
+
+	// writing to srp calls usb_gadget_wakeup() on the UDC
+	srpPath := path.Join("/sys/class/udc", u.udc, "srp")
+	if err := os.WriteFile(srpPath, []byte("1"), 0644); err != nil {
+		return fmt.Errorf("failed to trigger remote wakeup: %w", err)
+	}
+
+	u.log.Info().Msg("remote wakeup signaled")
+	return nil
+}
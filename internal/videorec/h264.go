@@ -0,0 +1,335 @@
+package videorec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// H.264 NAL unit types, see ITU-T H.264 table 7-1
+const (
+	nalSliceIDR = 5
+	nalSPS      = 7
+	nalPPS      = 8
+	nalAUD      = 9
+)
+
+func nalType(nalu []byte) byte {
+	return nalu[0] & 0x1f
+}
+
+// splitAnnexB returns the NAL units of the access unit without their start codes.
+func splitAnnexB(data []byte) [][]byte {
+	var nalus [][]byte
+	start := -1
+	for i := 0; i+2 < len(data); i++ {
+		if data[i] != 0 || data[i+1] != 0 || data[i+2] != 1 {
+			continue
+		}
+		if start >= 0 {
+			nalus = appendNALU(nalus, data[start:i])
+		}
+		start = i + 3
+		i += 2
+	}
+	if start >= 0 {
+		nalus = appendNALU(nalus, data[start:])
+	}
+	return nalus
+}
+
+func appendNALU(nalus [][]byte, nalu []byte) [][]byte {
+	// the zero byte of a 4 byte start code and trailing zero bytes aren't part of the NAL unit
+	nalu = bytes.TrimRight(nalu, "\x00")
+	if len(nalu) == 0 {
+		return nalus
+	}
+	return append(nalus, nalu)
+}
+
+// lengthPrefixed converts the NAL units to the sample format of MP4 and Matroska,
+// the parameter sets are left out as they are part of the codec configuration.
+func lengthPrefixed(nalus [][]byte) []byte {
+	size := 0
+	for _, nalu := range nalus {
+		size += 4 + len(nalu)
+	}
+	data := make([]byte, 0, size)
+	for _, nalu := range nalus {
+		switch nalType(nalu) {
+		case nalSPS, nalPPS, nalAUD:
+			continue
+		}
+		data = binary.BigEndian.AppendUint32(data, uint32(len(nalu)))
+		data = append(data, nalu...)
+	}
+	return data
+}
+
+// bitReader reads the RBSP of a NAL unit, the emulation prevention bytes are already removed.
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+var errShortSPS = errors.New("SPS is truncated")
+
+func (r *bitReader) bit() (uint, error) {
+	if r.pos >= len(r.data)*8 {
+		return 0, errShortSPS
+	}
+	bit := uint(r.data[r.pos/8]>>(7-r.pos%8)) & 1
+	r.pos++
+	return bit, nil
+}
+
+func (r *bitReader) bits(n int) (uint, error) {
+	var v uint
+	for range n {
+		bit, err := r.bit()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<1 | bit
+	}
+	return v, nil
+}
+
+// ue reads an unsigned Exp-Golomb code
+func (r *bitReader) ue() (uint, error) {
+	zeros := 0
+	for {
+		bit, err := r.bit()
+		if err != nil {
+			return 0, err
+		}
+		if bit == 1 {
+			break
+		}
+		zeros++
+		if zeros > 31 {
+			return 0, errors.New("invalid Exp-Golomb code")
+		}
+	}
+	v, err := r.bits(zeros)
+	return 1<<zeros - 1 + v, err
+}
+
+// se reads a signed Exp-Golomb code
+func (r *bitReader) se() (int, error) {
+	v, err := r.ue()
+	if v%2 == 1 {
+		return int(v+1) / 2, err
+	}
+	return -int(v / 2), err
+}
+
+func unescapeRBSP(nalu []byte) []byte {
+	rbsp := make([]byte, 0, len(nalu))
+	zeros := 0
+	for _, b := range nalu {
+		if zeros >= 2 && b == 3 {
+			zeros = 0
+			continue
+		}
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+		rbsp = append(rbsp, b)
+	}
+	return rbsp
+}
+
+type spsInfo struct {
+	profile        byte
+	chromaFormat   uint
+	bitDepthLuma   uint
+	bitDepthChroma uint
+	width          int
+	height         int
+}
+
+// highProfile reports whether the SPS has the chroma format and bit depths, see H.264 7.3.2.1.1
+func highProfile(profile byte) bool {
+	switch profile {
+	case 100, 110, 122, 244, 44, 83, 86, 118, 128, 138, 139, 134, 135:
+		return true
+	}
+	return false
+}
+
+func skipScalingList(r *bitReader, size int) error {
+	last, next := 8, 8
+	for range size {
+		if next != 0 {
+			delta, err := r.se()
+			if err != nil {
+				return err
+			}
+			next = (last + delta + 256) % 256
+		}
+		if next != 0 {
+			last = next
+		}
+	}
+	return nil
+}
+
+// parseSPS reads the fields of the SPS the containers need, most importantly the picture size.
+func parseSPS(nalu []byte) (*spsInfo, error) {
+	if len(nalu) < 4 || nalType(nalu) != nalSPS {
+		return nil, errors.New("not an SPS")
+	}
+	info := &spsInfo{profile: nalu[1], chromaFormat: 1}
+	// skip the NAL header, profile, constraint flags and level
+	r := &bitReader{data: unescapeRBSP(nalu[4:])}
+
+	if _, err := r.ue(); err != nil { // seq_parameter_set_id
+		return nil, err
+	}
+	separateColourPlanes := false
+	if highProfile(info.profile) {
+		var err error
+		if info.chromaFormat, err = r.ue(); err != nil {
+			return nil, err
+		}
+		if info.chromaFormat == 3 {
+			flag, err := r.bit()
+			if err != nil {
+				return nil, err
+			}
+			separateColourPlanes = flag == 1
+		}
+		if info.bitDepthLuma, err = r.ue(); err != nil {
+			return nil, err
+		}
+		if info.bitDepthChroma, err = r.ue(); err != nil {
+			return nil, err
+		}
+		if _, err := r.bit(); err != nil { // qpprime_y_zero_transform_bypass_flag
+			return nil, err
+		}
+		scalingMatrix, err := r.bit()
+		if err != nil {
+			return nil, err
+		}
+		if scalingMatrix == 1 {
+			lists := 8
+			if info.chromaFormat == 3 {
+				lists = 12
+			}
+			for i := range lists {
+				present, err := r.bit()
+				if err != nil {
+					return nil, err
+				}
+				if present == 0 {
+					continue
+				}
+				size := 16
+				if i >= 6 {
+					size = 64
+				}
+				if err := skipScalingList(r, size); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if _, err := r.ue(); err != nil { // log2_max_frame_num_minus4
+		return nil, err
+	}
+	pocType, err := r.ue()
+	if err != nil {
+		return nil, err
+	}
+	switch pocType {
+	case 0:
+		if _, err := r.ue(); err != nil { // log2_max_pic_order_cnt_lsb_minus4
+			return nil, err
+		}
+	case 1:
+		if _, err := r.bit(); err != nil { // delta_pic_order_always_zero_flag
+			return nil, err
+		}
+		if _, err := r.se(); err != nil { // offset_for_non_ref_pic
+			return nil, err
+		}
+		if _, err := r.se(); err != nil { // offset_for_top_to_bottom_field
+			return nil, err
+		}
+		cycle, err := r.ue()
+		if err != nil {
+			return nil, err
+		}
+		for range cycle {
+			if _, err := r.se(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, err := r.ue(); err != nil { // max_num_ref_frames
+		return nil, err
+	}
+	if _, err := r.bit(); err != nil { // gaps_in_frame_num_value_allowed_flag
+		return nil, err
+	}
+	widthMbs, err := r.ue()
+	if err != nil {
+		return nil, err
+	}
+	heightMapUnits, err := r.ue()
+	if err != nil {
+		return nil, err
+	}
+	frameMbsOnly, err := r.bit()
+	if err != nil {
+		return nil, err
+	}
+	if frameMbsOnly == 0 {
+		if _, err := r.bit(); err != nil { // mb_adaptive_frame_field_flag
+			return nil, err
+		}
+	}
+	if _, err := r.bit(); err != nil { // direct_8x8_inference_flag
+		return nil, err
+	}
+
+	info.width = int(widthMbs+1) * 16
+	info.height = int(2-frameMbsOnly) * int(heightMapUnits+1) * 16
+
+	cropping, err := r.bit()
+	if err != nil {
+		return nil, err
+	}
+	if cropping == 1 {
+		var crop [4]uint // left, right, top, bottom
+		for i := range crop {
+			if crop[i], err = r.ue(); err != nil {
+				return nil, err
+			}
+		}
+		// the crop units depend on the chroma subsampling, see H.264 table 6-1
+		unitX, unitY := 1, 1
+		if !separateColourPlanes && info.chromaFormat != 0 {
+			if info.chromaFormat < 3 {
+				unitX = 2
+			}
+			if info.chromaFormat == 1 {
+				unitY = 2
+			}
+		}
+		unitY *= int(2 - frameMbsOnly)
+		info.width -= int(crop[0]+crop[1]) * unitX
+		info.height -= int(crop[2]+crop[3]) * unitY
+	}
+
+	if info.width <= 0 || info.height <= 0 {
+		return nil, errors.New("invalid picture size in SPS")
+	}
+	return info, nil
+}
@@ -0,0 +1,189 @@
+package videorec
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// Matroska element IDs, see RFC 9559
+const (
+	mkvEBML               = 0x1a45dfa3
+	mkvEBMLVersion        = 0x4286
+	mkvEBMLReadVersion    = 0x42f7
+	mkvEBMLMaxIDLength    = 0x42f2
+	mkvEBMLMaxSizeLength  = 0x42f3
+	mkvDocType            = 0x4282
+	mkvDocTypeVersion     = 0x4287
+	mkvDocTypeReadVersion = 0x4285
+	mkvSegment            = 0x18538067
+	mkvInfo               = 0x1549a966
+	mkvTimestampScale     = 0x2ad7b1
+	mkvMuxingApp          = 0x4d80
+	mkvWritingApp         = 0x5741
+	mkvDateUTC            = 0x4461
+	mkvTracks             = 0x1654ae6b
+	mkvTrackEntry         = 0xae
+	mkvTrackNumber        = 0xd7
+	mkvTrackUID           = 0x73c5
+	mkvTrackType          = 0x83
+	mkvFlagLacing         = 0x9c
+	mkvCodecID            = 0x86
+	mkvCodecPrivate       = 0x63a2
+	mkvVideo              = 0xe0
+	mkvPixelWidth         = 0xb0
+	mkvPixelHeight        = 0xba
+	mkvCluster            = 0x1f43b675
+	mkvTimestamp          = 0xe7
+	mkvSimpleBlock        = 0xa3
+)
+
+// mkvUnknownSize lets the segment grow while it's written
+var mkvUnknownSize = []byte{0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+// mkvEpoch is the origin of DateUTC
+var mkvEpoch = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const mkvWritingAppName = "JetKVM"
+
+func appendEBMLID(b []byte, id uint32) []byte {
+	switch {
+	case id >= 1<<24:
+		return append(b, byte(id>>24), byte(id>>16), byte(id>>8), byte(id))
+	case id >= 1<<16:
+		return append(b, byte(id>>16), byte(id>>8), byte(id))
+	case id >= 1<<8:
+		return append(b, byte(id>>8), byte(id))
+	}
+	return append(b, byte(id))
+}
+
+// appendEBMLSize appends the size as a variable length integer of as few bytes as possible.
+func appendEBMLSize(b []byte, size uint64) []byte {
+	n := 1
+	// the value with all bits set is reserved for the unknown size
+	for n < 8 && size >= 1<<(7*n)-1 {
+		n++
+	}
+	v := size | 1<<(7*n)
+	for i := n - 1; i >= 0; i-- {
+		b = append(b, byte(v>>(8*i)))
+	}
+	return b
+}
+
+func ebmlElement(id uint32, payloads ...[]byte) []byte {
+	size := 0
+	for _, p := range payloads {
+		size += len(p)
+	}
+	b := appendEBMLSize(appendEBMLID(nil, id), uint64(size))
+	for _, p := range payloads {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func ebmlUint(id uint32, v uint64) []byte {
+	n := 1
+	for n < 8 && v >= 1<<(8*n) {
+		n++
+	}
+	p := make([]byte, n)
+	for i := range p {
+		p[i] = byte(v >> (8 * (n - 1 - i)))
+	}
+	return ebmlElement(id, p)
+}
+
+func ebmlString(id uint32, s string) []byte {
+	return ebmlElement(id, []byte(s))
+}
+
+type matroskaMuxer struct {
+	w        io.Writer
+	fragment fragment
+}
+
+func newMatroskaMuxer(w io.Writer, track Track, created time.Time) (*matroskaMuxer, error) {
+	header := ebmlElement(mkvEBML,
+		ebmlUint(mkvEBMLVersion, 1),
+		ebmlUint(mkvEBMLReadVersion, 1),
+		ebmlUint(mkvEBMLMaxIDLength, 4),
+		ebmlUint(mkvEBMLMaxSizeLength, 8),
+		ebmlString(mkvDocType, "matroska"),
+		ebmlUint(mkvDocTypeVersion, 4),
+		ebmlUint(mkvDocTypeReadVersion, 2),
+	)
+
+	segment := appendEBMLID(nil, mkvSegment)
+	segment = append(segment, mkvUnknownSize...)
+
+	date := binary.BigEndian.AppendUint64(nil, uint64(created.Sub(mkvEpoch).Nanoseconds()))
+	info := ebmlElement(mkvInfo,
+		ebmlUint(mkvTimestampScale, uint64(time.Millisecond)),
+		ebmlString(mkvMuxingApp, mkvWritingAppName),
+		ebmlString(mkvWritingApp, mkvWritingAppName),
+		ebmlElement(mkvDateUTC, date),
+	)
+
+	tracks := ebmlElement(mkvTracks, ebmlElement(mkvTrackEntry,
+		ebmlUint(mkvTrackNumber, 1),
+		ebmlUint(mkvTrackUID, 1),
+		ebmlUint(mkvTrackType, 1), // video
+		ebmlUint(mkvFlagLacing, 0),
+		ebmlString(mkvCodecID, "V_MPEG4/ISO/AVC"),
+		ebmlElement(mkvCodecPrivate, track.avcConfig()),
+		ebmlElement(mkvVideo,
+			ebmlUint(mkvPixelWidth, uint64(track.Width)),
+			ebmlUint(mkvPixelHeight, uint64(track.Height)),
+		),
+	))
+
+	for _, b := range [][]byte{header, segment, info, tracks} {
+		if _, err := w.Write(b); err != nil {
+			return nil, err
+		}
+	}
+	return &matroskaMuxer{w: w}, nil
+}
+
+func (m *matroskaMuxer) WriteSample(s Sample) error {
+	if m.fragment.full(s) {
+		if err := m.flush(); err != nil {
+			return err
+		}
+	}
+	m.fragment.samples = append(m.fragment.samples, s)
+	return nil
+}
+
+// flush writes the fragment as a cluster, the timestamps of its blocks are relative to the
+// cluster and maxFragmentDuration keeps them in the int16 range.
+func (m *matroskaMuxer) flush() error {
+	samples := m.fragment.samples
+	if len(samples) == 0 {
+		return nil
+	}
+	m.fragment.samples = nil
+
+	start := samples[0].Time.Milliseconds()
+	payloads := [][]byte{ebmlUint(mkvTimestamp, uint64(start))}
+	for _, s := range samples {
+		var flags byte
+		if s.Keyframe {
+			flags = 0x80
+		}
+		block := []byte{0x81} // track number 1
+		block = binary.BigEndian.AppendUint16(block, uint16(int16(s.Time.Milliseconds()-start)))
+		block = append(block, flags)
+		payloads = append(payloads, ebmlElement(mkvSimpleBlock, block, s.Data))
+	}
+
+	_, err := m.w.Write(ebmlElement(mkvCluster, payloads...))
+	return err
+}
+
+func (m *matroskaMuxer) Close() error {
+	return m.flush()
+}
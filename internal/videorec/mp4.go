@@ -0,0 +1,195 @@
+package videorec
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// mp4Timescale is the common 90 kHz clock of video
+const mp4Timescale = 90000
+
+// mp4Epoch is the origin of the creation times of ISO/IEC 14496-12
+var mp4Epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// sample flags of the track fragment run, a sync sample doesn't depend on other samples
+const (
+	mp4SyncSampleFlags    = 0x02000000
+	mp4NonSyncSampleFlags = 0x01010000
+)
+
+var mp4Matrix = []uint32{0x00010000, 0, 0, 0, 0x00010000, 0, 0, 0, 0x40000000}
+
+func mp4Box(typ string, payloads ...[]byte) []byte {
+	size := 8
+	for _, p := range payloads {
+		size += len(p)
+	}
+	b := binary.BigEndian.AppendUint32(make([]byte, 0, size), uint32(size))
+	b = append(b, typ...)
+	for _, p := range payloads {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func mp4FullBox(typ string, version byte, flags uint32, payloads ...[]byte) []byte {
+	header := binary.BigEndian.AppendUint32(nil, uint32(version)<<24|flags)
+	return mp4Box(typ, append([][]byte{header}, payloads...)...)
+}
+
+func u16(vs ...uint16) []byte {
+	b := make([]byte, 0, 2*len(vs))
+	for _, v := range vs {
+		b = binary.BigEndian.AppendUint16(b, v)
+	}
+	return b
+}
+
+func u32(vs ...uint32) []byte {
+	b := make([]byte, 0, 4*len(vs))
+	for _, v := range vs {
+		b = binary.BigEndian.AppendUint32(b, v)
+	}
+	return b
+}
+
+func mp4Ticks(d time.Duration) uint64 {
+	return uint64(d) * mp4Timescale / uint64(time.Second)
+}
+
+type mp4Muxer struct {
+	w        io.Writer
+	sequence uint32
+	fragment fragment
+	// lastDuration is used for the last sample, its duration is only known from the next sample
+	lastDuration uint64
+}
+
+func newMP4Muxer(w io.Writer, track Track, created time.Time) (*mp4Muxer, error) {
+	createdAt := uint32(created.Sub(mp4Epoch) / time.Second)
+	width, height := uint32(track.Width), uint32(track.Height)
+
+	ftyp := mp4Box("ftyp", []byte("iso5"), u32(512), []byte("iso5iso6mp41"))
+
+	mvhd := mp4FullBox("mvhd", 0, 0,
+		u32(createdAt, createdAt, 1000, 0, 0x00010000),
+		u16(0x0100, 0), u32(0, 0), u32(mp4Matrix...), u32(0, 0, 0, 0, 0, 0),
+		u32(2), // next track ID
+	)
+	tkhd := mp4FullBox("tkhd", 0, 3, // enabled and in the movie
+		u32(createdAt, createdAt, 1, 0, 0, 0, 0),
+		u16(0, 0, 0, 0), u32(mp4Matrix...),
+		u32(width<<16, height<<16),
+	)
+	mdhd := mp4FullBox("mdhd", 0, 0,
+		u32(createdAt, createdAt, mp4Timescale, 0),
+		u16(0x55c4, 0), // language "und"
+	)
+	hdlr := mp4FullBox("hdlr", 0, 0, u32(0), []byte("vide"), u32(0, 0, 0), []byte("VideoHandler\x00"))
+
+	avc1 := mp4Box("avc1",
+		make([]byte, 6), u16(1), // data reference index
+		u16(0, 0), u32(0, 0, 0),
+		u16(uint16(width), uint16(height)),
+		u32(0x00480000, 0x00480000, 0), // 72 dpi
+		u16(1), make([]byte, 32), u16(0x0018, 0xffff),
+		mp4Box("avcC", track.avcConfig()),
+	)
+	stbl := mp4Box("stbl",
+		mp4FullBox("stsd", 0, 0, u32(1), avc1),
+		mp4FullBox("stts", 0, 0, u32(0)),
+		mp4FullBox("stsc", 0, 0, u32(0)),
+		mp4FullBox("stsz", 0, 0, u32(0, 0)),
+		mp4FullBox("stco", 0, 0, u32(0)),
+	)
+	minf := mp4Box("minf",
+		mp4FullBox("vmhd", 0, 1, u16(0, 0, 0, 0)),
+		mp4Box("dinf", mp4FullBox("dref", 0, 0, u32(1), mp4FullBox("url ", 0, 1))),
+		stbl,
+	)
+	mvex := mp4Box("mvex", mp4FullBox("trex", 0, 0, u32(1, 1, 0, 0, 0)))
+	moov := mp4Box("moov", mvhd, mp4Box("trak", tkhd, mp4Box("mdia", mdhd, hdlr, minf)), mvex)
+
+	for _, b := range [][]byte{ftyp, moov} {
+		if _, err := w.Write(b); err != nil {
+			return nil, err
+		}
+	}
+	return &mp4Muxer{w: w, lastDuration: mp4Timescale / 30}, nil
+}
+
+func (m *mp4Muxer) WriteSample(s Sample) error {
+	if m.fragment.full(s) {
+		if err := m.flush(mp4Ticks(s.Time)); err != nil {
+			return err
+		}
+	}
+	m.fragment.samples = append(m.fragment.samples, s)
+	return nil
+}
+
+// flush writes the fragment as a moof and mdat box, end is the decode time of the next sample.
+func (m *mp4Muxer) flush(end uint64) error {
+	samples := m.fragment.samples
+	if len(samples) == 0 {
+		return nil
+	}
+	m.fragment.samples = nil
+	m.sequence++
+
+	start := mp4Ticks(samples[0].Time)
+	entries := make([]byte, 0, 12*len(samples))
+	size := 0
+	for i, s := range samples {
+		next := end
+		if i+1 < len(samples) {
+			next = mp4Ticks(samples[i+1].Time)
+		}
+		duration := m.lastDuration
+		if next > mp4Ticks(s.Time) {
+			duration = next - mp4Ticks(s.Time)
+			m.lastDuration = duration
+		}
+		flags := uint32(mp4NonSyncSampleFlags)
+		if s.Keyframe {
+			flags = mp4SyncSampleFlags
+		}
+		entries = append(entries, u32(uint32(duration), uint32(len(s.Data)), flags)...)
+		size += len(s.Data)
+	}
+
+	moof := func(dataOffset uint32) []byte {
+		return mp4Box("moof",
+			mp4FullBox("mfhd", 0, 0, u32(m.sequence)),
+			mp4Box("traf",
+				mp4FullBox("tfhd", 0, 0x020000, u32(1)), // the data offset is relative to the moof
+				mp4FullBox("tfdt", 1, 0, binary.BigEndian.AppendUint64(nil, start)),
+				// data offset, duration, size and flags of every sample
+				mp4FullBox("trun", 0, 0x000701, u32(uint32(len(samples)), dataOffset), entries),
+			),
+		)
+	}
+	// the samples follow the moof and the header of the mdat
+	header := moof(uint32(len(moof(0)) + 8))
+	header = binary.BigEndian.AppendUint32(header, uint32(8+size))
+	header = append(header, "mdat"...)
+
+	if _, err := m.w.Write(header); err != nil {
+		return err
+	}
+	for _, s := range samples {
+		if _, err := m.w.Write(s.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mp4Muxer) Close() error {
+	samples := m.fragment.samples
+	if len(samples) == 0 {
+		return nil
+	}
+	return m.flush(mp4Ticks(samples[len(samples)-1].Time) + m.lastDuration)
+}
@@ -0,0 +1,107 @@
+// Package videorec records the H.264 video to fragmented MP4 or Matroska files, which stay
+// playable up to the last fragment when the recording is interrupted.
+package videorec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatMP4      Format = "mp4"
+	FormatMatroska Format = "mkv"
+)
+
+func (f Format) Valid() bool {
+	return f == FormatMP4 || f == FormatMatroska
+}
+
+func (f Format) Ext() string {
+	return "." + string(f)
+}
+
+// maxFragmentDuration bounds the video lost when the recording is interrupted, a fragment
+// also ends at every keyframe
+const maxFragmentDuration = 2 * time.Second
+
+// Track is the codec configuration of the video.
+type Track struct {
+	Width  int
+	Height int
+	SPS    []byte
+	PPS    []byte
+}
+
+func newTrack(sps, pps []byte) (Track, error) {
+	info, err := parseSPS(sps)
+	if err != nil {
+		return Track{}, err
+	}
+	return Track{Width: info.width, Height: info.height, SPS: sps, PPS: pps}, nil
+}
+
+func (t Track) equal(o Track) bool {
+	return bytes.Equal(t.SPS, o.SPS) && bytes.Equal(t.PPS, o.PPS)
+}
+
+// avcConfig returns the AVCDecoderConfigurationRecord of ISO/IEC 14496-15, the codec private
+// data of both containers.
+func (t Track) avcConfig() []byte {
+	b := []byte{1, t.SPS[1], t.SPS[2], t.SPS[3], 0xff, 0xe1}
+	b = binary.BigEndian.AppendUint16(b, uint16(len(t.SPS)))
+	b = append(b, t.SPS...)
+	b = append(b, 1)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(t.PPS)))
+	b = append(b, t.PPS...)
+
+	if highProfile(t.SPS[1]) {
+		info, err := parseSPS(t.SPS)
+		if err == nil {
+			b = append(b, 0xfc|byte(info.chromaFormat), 0xf8|byte(info.bitDepthLuma), 0xf8|byte(info.bitDepthChroma), 0)
+		}
+	}
+	return b
+}
+
+// Sample is an access unit in the length prefixed format of the containers.
+type Sample struct {
+	Data     []byte
+	Time     time.Duration // since the start of the file
+	Keyframe bool
+}
+
+// Muxer writes the samples of a single video track.
+type Muxer interface {
+	// WriteSample buffers the sample, the buffered fragment is written when it's complete
+	WriteSample(s Sample) error
+	// Close writes the buffered fragment, it doesn't close the writer
+	Close() error
+}
+
+func NewMuxer(w io.Writer, format Format, track Track, created time.Time) (Muxer, error) {
+	switch format {
+	case FormatMP4:
+		return newMP4Muxer(w, track, created)
+	case FormatMatroska:
+		return newMatroskaMuxer(w, track, created)
+	}
+	return nil, fmt.Errorf("unsupported recording format: %s", format)
+}
+
+// fragment collects the samples until they are written together
+type fragment struct {
+	samples []Sample
+}
+
+// full reports whether the fragment should be written before the sample is added.
+func (f *fragment) full(s Sample) bool {
+	if len(f.samples) == 0 {
+		return false
+	}
+	return s.Keyframe || s.Time-f.samples[0].Time >= maxFragmentDuration
+}
@@ -0,0 +1,241 @@
+package videorec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// fileTimeFormat starts the file names, so the recordings sort by their start
+const fileTimeFormat = "20060102-150405.000"
+
+var (
+	labelRegexp = regexp.MustCompile(`^[a-z0-9-]+$`)
+	fileRegexp  = regexp.MustCompile(`^[0-9]{8}-[0-9]{6}\.[0-9]{3}_[a-z0-9-]+\.(mp4|mkv)$`)
+)
+
+// ValidFileName reports whether the name is one of a recording, it can't point outside of the folder.
+func ValidFileName(name string) bool {
+	return fileRegexp.MatchString(name)
+}
+
+type Options struct {
+	Dir    string
+	Format Format
+	// Label is part of the file names, lower case letters, digits and dashes
+	Label string
+	// MaxFileSize and MaxFileDuration start a new file at the next keyframe, 0 is unlimited
+	MaxFileSize     int64
+	MaxFileDuration time.Duration
+	// Quota limits the size of all recordings in Dir, the oldest are deleted, 0 is unlimited
+	Quota int64
+}
+
+type Stats struct {
+	File      string    `json:"file,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	Files     int       `json:"files"`
+	Bytes     int64     `json:"bytes"`
+}
+
+// Recorder writes the H.264 frames to files, it starts every file on a keyframe.
+type Recorder struct {
+	opts Options
+
+	sps []byte
+	pps []byte
+
+	file      *os.File
+	muxer     Muxer
+	track     Track
+	fileStart time.Time
+	fileSize  int64
+
+	stats Stats
+}
+
+func NewRecorder(opts Options) (*Recorder, error) {
+	if !opts.Format.Valid() {
+		return nil, fmt.Errorf("unsupported recording format: %s", opts.Format)
+	}
+	if !labelRegexp.MatchString(opts.Label) {
+		return nil, fmt.Errorf("invalid recording label: %q", opts.Label)
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create recordings folder: %w", err)
+	}
+	return &Recorder{opts: opts, stats: Stats{StartedAt: time.Now()}}, nil
+}
+
+func (r *Recorder) Stats() Stats {
+	return r.stats
+}
+
+// WriteFrame adds the H.264 access unit in Annex B format. The frames are skipped until a keyframe
+// follows the SPS and PPS, the codec configuration of the files is taken from them.
+func (r *Recorder) WriteFrame(data []byte, received time.Time) error {
+	nalus := splitAnnexB(data)
+	keyframe := false
+	for _, nalu := range nalus {
+		switch nalType(nalu) {
+		case nalSPS:
+			r.sps = slices.Clone(nalu)
+		case nalPPS:
+			r.pps = slices.Clone(nalu)
+		case nalSliceIDR:
+			keyframe = true
+		}
+	}
+
+	if keyframe && r.sps != nil && r.pps != nil {
+		track, err := newTrack(r.sps, r.pps)
+		if err != nil {
+			return err
+		}
+		if r.muxer == nil || !track.equal(r.track) || r.full(received) {
+			if err := r.rotate(track, received); err != nil {
+				return err
+			}
+		}
+	}
+	if r.muxer == nil {
+		return nil
+	}
+
+	sample := Sample{Data: lengthPrefixed(nalus), Time: received.Sub(r.fileStart), Keyframe: keyframe}
+	if err := r.muxer.WriteSample(sample); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	r.fileSize += int64(len(sample.Data))
+	r.stats.Bytes += int64(len(sample.Data))
+	return nil
+}
+
+func (r *Recorder) full(now time.Time) bool {
+	if r.opts.MaxFileSize > 0 && r.fileSize >= r.opts.MaxFileSize {
+		return true
+	}
+	return r.opts.MaxFileDuration > 0 && now.Sub(r.fileStart) >= r.opts.MaxFileDuration
+}
+
+// rotate finishes the current file and starts the next one
+func (r *Recorder) rotate(track Track, start time.Time) error {
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s_%s%s", start.UTC().Format(fileTimeFormat), r.opts.Label, r.opts.Format.Ext())
+	f, err := os.OpenFile(filepath.Join(r.opts.Dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create recording: %w", err)
+	}
+	muxer, err := NewMuxer(f, r.opts.Format, track, start)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+
+	r.file, r.muxer, r.track = f, muxer, track
+	r.fileStart, r.fileSize = start, 0
+	r.stats.File = name
+	r.stats.Files++
+
+	if _, err := EnforceQuota(r.opts.Dir, r.opts.Quota, name); err != nil {
+		return fmt.Errorf("failed to enforce recording quota: %w", err)
+	}
+	return nil
+}
+
+func (r *Recorder) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+	f, muxer := r.file, r.muxer
+	r.file, r.muxer = nil, nil
+
+	err := muxer.Close()
+	if syncErr := f.Sync(); err == nil {
+		err = syncErr
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to finish recording: %w", err)
+	}
+	return nil
+}
+
+// Close finishes the current file.
+func (r *Recorder) Close() error {
+	err := r.closeFile()
+	r.stats.File = ""
+	return err
+}
+
+type File struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// List returns the recordings in the folder, the newest first.
+func List(dir string) ([]File, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []File{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]File, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !ValidFileName(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, File{Name: entry.Name(), Size: info.Size(), ModifiedAt: info.ModTime()})
+	}
+	slices.SortFunc(files, func(a, b File) int {
+		return strings.Compare(b.Name, a.Name)
+	})
+	return files, nil
+}
+
+// EnforceQuota deletes the oldest recordings until all of them fit in the quota, except the one being written.
+func EnforceQuota(dir string, quota int64, current string) ([]string, error) {
+	if quota <= 0 {
+		return nil, nil
+	}
+	files, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, f := range files {
+		total += f.Size
+	}
+
+	var deleted []string
+	for i := len(files) - 1; i >= 0 && total > quota; i-- {
+		if files[i].Name == current {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, files[i].Name)); err != nil {
+			return deleted, err
+		}
+		total -= files[i].Size
+		deleted = append(deleted, files[i].Name)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,261 @@
+package videorec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bitWriter struct {
+	data []byte
+	n    int
+}
+
+func (w *bitWriter) bit(b uint) {
+	if w.n%8 == 0 {
+		w.data = append(w.data, 0)
+	}
+	w.data[len(w.data)-1] |= byte(b&1) << (7 - w.n%8)
+	w.n++
+}
+
+func (w *bitWriter) ue(v uint) {
+	v++
+	bits := 0
+	for x := v; x > 1; x >>= 1 {
+		bits++
+	}
+	for range bits {
+		w.bit(0)
+	}
+	for i := bits; i >= 0; i-- {
+		w.bit(v >> i)
+	}
+}
+
+// testSPS returns a 1920x1080 SPS, it's coded as 1920x1088 with the bottom 8 lines cropped
+func testSPS(profile byte) []byte {
+	w := &bitWriter{}
+	w.ue(0) // seq_parameter_set_id
+	if highProfile(profile) {
+		w.ue(1)  // chroma_format_idc
+		w.ue(0)  // bit_depth_luma_minus8
+		w.ue(0)  // bit_depth_chroma_minus8
+		w.bit(0) // qpprime_y_zero_transform_bypass_flag
+		w.bit(0) // seq_scaling_matrix_present_flag
+	}
+	w.ue(0)  // log2_max_frame_num_minus4
+	w.ue(0)  // pic_order_cnt_type
+	w.ue(0)  // log2_max_pic_order_cnt_lsb_minus4
+	w.ue(1)  // max_num_ref_frames
+	w.bit(0) // gaps_in_frame_num_value_allowed_flag
+	w.ue(119)
+	w.ue(67)
+	w.bit(1) // frame_mbs_only_flag
+	w.bit(1) // direct_8x8_inference_flag
+	w.bit(1) // frame_cropping_flag
+	w.ue(0)
+	w.ue(0)
+	w.ue(0)
+	w.ue(4)
+	w.bit(0) // vui_parameters_present_flag
+	w.bit(1) // rbsp_stop_one_bit
+	return append([]byte{0x67, profile, 0, 40}, w.data...)
+}
+
+var testPPS = []byte{0x68, 0xce, 0x38, 0x80}
+
+func annexB(nalus ...[]byte) []byte {
+	var b []byte
+	for _, nalu := range nalus {
+		b = append(b, 0, 0, 0, 1)
+		b = append(b, nalu...)
+	}
+	return b
+}
+
+func keyframe() []byte {
+	return annexB([]byte{0x09, 0xf0}, testSPS(66), testPPS, []byte{0x65, 0x88, 0x84, 0x21})
+}
+
+func deltaFrame() []byte {
+	return annexB([]byte{0x41, 0x9a, 0x02, 0x04})
+}
+
+func TestSplitAnnexB(t *testing.T) {
+	data := []byte{0, 0, 0, 1, 0x67, 0x42, 0, 0, 1, 0x68, 0xce, 0, 0, 0, 1, 0x65, 0x88, 0}
+	assert.Equal(t, [][]byte{{0x67, 0x42}, {0x68, 0xce}, {0x65, 0x88}}, splitAnnexB(data))
+	assert.Empty(t, splitAnnexB([]byte{0x65, 0x88}))
+}
+
+func TestLengthPrefixed(t *testing.T) {
+	data := lengthPrefixed(splitAnnexB(keyframe()))
+	assert.Equal(t, []byte{0, 0, 0, 4, 0x65, 0x88, 0x84, 0x21}, data)
+}
+
+func TestParseSPS(t *testing.T) {
+	for _, profile := range []byte{66, 100} {
+		info, err := parseSPS(testSPS(profile))
+		require.NoError(t, err)
+		assert.Equal(t, 1920, info.width)
+		assert.Equal(t, 1080, info.height)
+		assert.Equal(t, uint(1), info.chromaFormat)
+	}
+
+	_, err := parseSPS(testSPS(66)[:6])
+	assert.Error(t, err)
+	_, err = parseSPS(testPPS)
+	assert.Error(t, err)
+}
+
+func TestUnescapeRBSP(t *testing.T) {
+	assert.Equal(t, []byte{0, 0, 1, 0, 0, 3}, unescapeRBSP([]byte{0, 0, 3, 1, 0, 0, 3, 3}))
+}
+
+func TestEBMLSize(t *testing.T) {
+	assert.Equal(t, []byte{0x81}, appendEBMLSize(nil, 1))
+	assert.Equal(t, []byte{0x40, 0x7f}, appendEBMLSize(nil, 127))
+	assert.Equal(t, []byte{0x41, 0x00}, appendEBMLSize(nil, 256))
+}
+
+func testTrack(t *testing.T) Track {
+	track, err := newTrack(testSPS(66), testPPS)
+	require.NoError(t, err)
+	return track
+}
+
+func testSamples() []Sample {
+	var samples []Sample
+	for i := range 6 {
+		samples = append(samples, Sample{
+			Data:     []byte{0, 0, 0, 2, 0x41, byte(i)},
+			Time:     time.Duration(i) * 500 * time.Millisecond,
+			Keyframe: i%3 == 0,
+		})
+	}
+	return samples
+}
+
+// mp4Boxes returns the types of the top level boxes
+func mp4Boxes(t *testing.T, data []byte) []string {
+	var types []string
+	for len(data) > 0 {
+		require.GreaterOrEqual(t, len(data), 8)
+		size := binary.BigEndian.Uint32(data)
+		require.LessOrEqual(t, int(size), len(data))
+		types = append(types, string(data[4:8]))
+		data = data[size:]
+	}
+	return types
+}
+
+func TestMP4Muxer(t *testing.T) {
+	var buf bytes.Buffer
+	m, err := NewMuxer(&buf, FormatMP4, testTrack(t), time.Now())
+	require.NoError(t, err)
+	for _, s := range testSamples() {
+		require.NoError(t, m.WriteSample(s))
+	}
+	require.NoError(t, m.Close())
+
+	// a fragment for every keyframe
+	assert.Equal(t, []string{"ftyp", "moov", "moof", "mdat", "moof", "mdat"}, mp4Boxes(t, buf.Bytes()))
+	assert.Contains(t, buf.String(), "avcC")
+}
+
+func TestMatroskaMuxer(t *testing.T) {
+	var buf bytes.Buffer
+	m, err := NewMuxer(&buf, FormatMatroska, testTrack(t), time.Now())
+	require.NoError(t, err)
+	for _, s := range testSamples() {
+		require.NoError(t, m.WriteSample(s))
+	}
+	require.NoError(t, m.Close())
+
+	data := buf.Bytes()
+	assert.True(t, bytes.HasPrefix(data, []byte{0x1a, 0x45, 0xdf, 0xa3}))
+	assert.Equal(t, 2, bytes.Count(data, []byte{0x1f, 0x43, 0xb6, 0x75}), "a cluster for every keyframe")
+	assert.Contains(t, buf.String(), "V_MPEG4/ISO/AVC")
+}
+
+func TestNewMuxerUnsupportedFormat(t *testing.T) {
+	_, err := NewMuxer(&bytes.Buffer{}, "avi", testTrack(t), time.Now())
+	assert.Error(t, err)
+}
+
+func TestRecorderStartsOnKeyframe(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRecorder(Options{Dir: dir, Format: FormatMatroska, Label: "test"})
+	require.NoError(t, err)
+
+	start := time.Now()
+	require.NoError(t, r.WriteFrame(deltaFrame(), start))
+	files, err := List(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	require.NoError(t, r.WriteFrame(keyframe(), start))
+	require.NoError(t, r.WriteFrame(deltaFrame(), start.Add(33*time.Millisecond)))
+	require.NoError(t, r.Close())
+
+	files, err = List(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, ValidFileName(files[0].Name))
+	assert.Equal(t, 1, r.Stats().Files)
+	assert.Equal(t, int64(16), r.Stats().Bytes)
+}
+
+func TestRecorderRotation(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRecorder(Options{Dir: dir, Format: FormatMP4, Label: "test", MaxFileDuration: time.Second})
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := range 60 {
+		frame := deltaFrame()
+		if i%10 == 0 {
+			frame = keyframe()
+		}
+		require.NoError(t, r.WriteFrame(frame, start.Add(time.Duration(i)*100*time.Millisecond)))
+	}
+	require.NoError(t, r.Close())
+
+	files, err := List(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 6)
+}
+
+func TestEnforceQuota(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"20261018-100000.000_test.mkv",
+		"20261018-110000.000_test.mkv",
+		"20261018-120000.000_test.mkv",
+	}
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), make([]byte, 100), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.txt"), make([]byte, 1000), 0644))
+
+	deleted, err := EnforceQuota(dir, 150, names[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{names[1], names[2]}, deleted)
+
+	files, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, names[0], files[0].Name)
+}
+
+func TestValidFileName(t *testing.T) {
+	assert.True(t, ValidFileName("20261018-120000.000_always-on.mp4"))
+	assert.False(t, ValidFileName("../20261018-120000.000_test.mp4"))
+	assert.False(t, ValidFileName("20261018-120000.000_test.avi"))
+}
@@ -3,23 +3,106 @@ package kvm
 import (
 	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 	_ "time/tzdata"
 
 	"github.com/go-co-op/gocron/v2"
+	"github.com/jetkvm/kvm/internal/activehours"
+	"github.com/jetkvm/kvm/internal/keyboard"
 	"github.com/jetkvm/kvm/internal/tzdata"
 )
 
+// Jiggler modes, the absolute mode is the default for configs that don't have one
+const (
+	JigglerModeAbsolute = "absolute"
+	JigglerModeRelative = "relative"
+	JigglerModeKeypress = "keypress"
+	JigglerModeWheel    = "wheel"
+)
+
+const (
+	defaultJigglerKey     = "F15"
+	jigglerHistorySize    = 100
+	maxJigglerActiveHours = 50
+)
+
 type JigglerConfig struct {
 	InactivityLimitSeconds int    `json:"inactivity_limit_seconds"`
 	JitterPercentage       int    `json:"jitter_percentage"`
 	ScheduleCronTab        string `json:"schedule_cron_tab"`
 	Timezone               string `json:"timezone,omitempty"`
+	Mode                   string `json:"mode,omitempty"`
+	// Key is pressed in the keypress mode, e.g. F15 or ShiftLeft
+	Key string `json:"key,omitempty"`
+	// ActiveHours limits the jiggler to these windows in the timezone of the config, it always runs if there are none
+	ActiveHours     []activehours.Window `json:"active_hours,omitempty"`
+	OnlyWithSession bool                 `json:"only_with_session,omitempty"`
+}
+
+type JigglerEvent struct {
+	Time  time.Time `json:"time"`
+	Mode  string    `json:"mode"`
+	Error string    `json:"error,omitempty"`
 }
 
 var jobDelta time.Duration = 0
 var scheduler gocron.Scheduler = nil
 
+var (
+	jigglerHistory     []JigglerEvent
+	jigglerHistoryLock sync.Mutex
+)
+
+func (c *JigglerConfig) Validate() error {
+	switch c.Mode {
+	case "", JigglerModeAbsolute, JigglerModeRelative, JigglerModeWheel:
+	case JigglerModeKeypress:
+		if _, err := jigglerKeyCode(c.Key); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid jiggler mode: %q", c.Mode)
+	}
+
+	if len(c.ActiveHours) > maxJigglerActiveHours {
+		return fmt.Errorf("too many active hours (max %d)", maxJigglerActiveHours)
+	}
+	for _, h := range c.ActiveHours {
+		if err := h.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *JigglerConfig) location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// isActive checks the active hours and the session requirement of the config
+func (c *JigglerConfig) isActive(now time.Time) bool {
+	if c.OnlyWithSession && currentSession == nil {
+		return false
+	}
+	return activehours.Active(c.ActiveHours, now, c.location())
+}
+
+func jigglerKeyCode(name string) (byte, error) {
+	if name == "" {
+		name = defaultJigglerKey
+	}
+	code, ok := keyboard.Keys[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown jiggler key: %q", name)
+	}
+	return code, nil
+}
+
 func rpcSetJigglerState(enabled bool) error {
 	config.JigglerEnabled = enabled
 	err := SaveConfig()
@@ -41,8 +124,20 @@ func rpcGetJigglerConfig() (JigglerConfig, error) {
 	return *config.JigglerConfig, nil
 }
 
+func rpcGetJigglerHistory() ([]JigglerEvent, error) {
+	jigglerHistoryLock.Lock()
+	defer jigglerHistoryLock.Unlock()
+
+	history := make([]JigglerEvent, len(jigglerHistory))
+	copy(history, jigglerHistory)
+	return history, nil
+}
+
 func rpcSetJigglerConfig(jigglerConfig JigglerConfig) error {
-	logger.Info().Msgf("jigglerConfig: %v, %v, %v, %v", jigglerConfig.InactivityLimitSeconds, jigglerConfig.JitterPercentage, jigglerConfig.ScheduleCronTab, jigglerConfig.Timezone)
+	logger.Info().Msgf("jigglerConfig: %v, %v, %v, %v, %v", jigglerConfig.InactivityLimitSeconds, jigglerConfig.JitterPercentage, jigglerConfig.ScheduleCronTab, jigglerConfig.Timezone, jigglerConfig.Mode)
+	if err := jigglerConfig.Validate(); err != nil {
+		return err
+	}
 	config.JigglerConfig = &jigglerConfig
 	err := removeExistingCrobJobs(scheduler)
 	if err != nil {
@@ -127,21 +222,71 @@ func runJiggler() {
 			jitter := calculateJitterDuration(jobDelta)
 			time.Sleep(jitter)
 		}
+		if !config.JigglerConfig.isActive(time.Now()) {
+			logger.Debug().Msg("Jiggler is outside of its active hours or has no session")
+			return
+		}
 		inactivitySeconds := config.JigglerConfig.InactivityLimitSeconds
 		timeSinceLastInput := time.Since(gadget.GetLastUserInputTime())
 		logger.Debug().Msgf("Time since last user input %v", timeSinceLastInput)
 		if timeSinceLastInput > time.Duration(inactivitySeconds)*time.Second {
-			logger.Debug().Msg("Jiggling mouse...")
-			//TODO: change to rel mouse
-			err := rpcAbsMouseReport(1, 1, 0)
-			if err != nil {
-				logger.Warn().Msgf("Failed to jiggle mouse: %v", err)
+			mode := config.JigglerConfig.Mode
+			if mode == "" {
+				mode = JigglerModeAbsolute
 			}
-			err = rpcAbsMouseReport(0, 0, 0)
+			logger.Debug().Str("mode", mode).Msg("Jiggling...")
+			err := jiggle(mode)
 			if err != nil {
-				logger.Warn().Msgf("Failed to reset mouse position: %v", err)
+				logger.Warn().Msgf("Failed to jiggle: %v", err)
 			}
+			addJigglerEvent(mode, err)
+		}
+	}
+}
+
+// jiggle sends an input that doesn't change anything on the host
+func jiggle(mode string) error {
+	switch mode {
+	case JigglerModeRelative:
+		if err := rpcRelMouseReport(1, 0, 0); err != nil {
+			return err
+		}
+		return rpcRelMouseReport(-1, 0, 0)
+	case JigglerModeKeypress:
+		key, err := jigglerKeyCode(config.JigglerConfig.Key)
+		if err != nil {
+			return err
+		}
+		// the jiggler must not wake the host up, so the key goes straight to the gadget
+		if err := gadget.KeypressReport(key, true); err != nil {
+			return err
+		}
+		return gadget.KeypressReport(key, false)
+	case JigglerModeWheel:
+		if err := rpcWheelReport(1); err != nil {
+			return err
+		}
+		return rpcWheelReport(-1)
+	default:
+		// moves the cursor to the corner of the screen
+		if err := rpcAbsMouseReport(1, 1, 0); err != nil {
+			return err
 		}
+		return rpcAbsMouseReport(0, 0, 0)
+	}
+}
+
+func addJigglerEvent(mode string, err error) {
+	event := JigglerEvent{Time: time.Now(), Mode: mode}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	jigglerHistoryLock.Lock()
+	defer jigglerHistoryLock.Unlock()
+	jigglerHistory = append(jigglerHistory, event)
+	if len(jigglerHistory) > jigglerHistorySize {
+		jigglerHistory = jigglerHistory[len(jigglerHistory)-jigglerHistorySize:]
 	}
 }
 
@@ -19,6 +19,7 @@ import (
 	"go.bug.st/serial"
 
 	"github.com/jetkvm/kvm/internal/hidrpc"
+	"github.com/jetkvm/kvm/internal/kbmacro"
 	"github.com/jetkvm/kvm/internal/usbgadget"
 	"github.com/jetkvm/kvm/internal/utils"
 )
@@ -155,6 +156,7 @@ func onRPCMessage(message webrtc.DataChannelMessage, session *Session) {
 	}
 
 	scopedLogger.Trace().Interface("result", result).Msg("RPC handler returned")
+	recordJSONRPCHidEvent(request.Method, request.Params)
 
 	response := JSONRPCResponse{
 		JSONRPC: "2.0",
@@ -571,6 +573,17 @@ func riskyCallRPCHandler(logger zerolog.Logger, handler RPCHandler, params map[s
 								return nil, fmt.Errorf("value out of range for uint8: %v for parameter %s", intValue, paramName)
 							}
 							newSlice.Index(j).SetUint(uint64(intValue))
+						} else if elemValue.Kind() == reflect.Map && paramType.Elem().Kind() == reflect.Struct {
+							jsonData, err := json.Marshal(elemValue.Interface())
+							if err != nil {
+								return nil, fmt.Errorf("failed to marshal map to JSON: %v for parameter %s", err, paramName)
+							}
+
+							newElem := reflect.New(paramType.Elem())
+							if err := json.Unmarshal(jsonData, newElem.Interface()); err != nil {
+								return nil, fmt.Errorf("failed to unmarshal JSON into struct: %v for parameter %s", err, paramName)
+							}
+							newSlice.Index(j).Set(newElem.Elem())
 						} else {
 							fromType := elemValue.Type()
 							toType := paramType.Elem()
@@ -637,6 +650,13 @@ func asError(value reflect.Value) (bool, error) {
 }
 
 func rpcSetMassStorageMode(mode string) (string, error) {
+	return rpcSetLunMassStorageMode(0, mode)
+}
+
+func rpcSetLunMassStorageMode(lun int, mode string) (string, error) {
+	if err := validateMassStorageLun(lun); err != nil {
+		return "", err
+	}
 	logger.Info().Str("mode", mode).Msg("Setting mass storage mode")
 	var cdrom bool
 	switch mode {
@@ -651,7 +671,12 @@ func rpcSetMassStorageMode(mode string) (string, error) {
 
 	logger.Info().Str("mode", mode).Msg("Setting mass storage mode")
 
-	err := setMassStorageMode(cdrom)
+	// the kernel only changes the mode of an empty LUN
+	if isVirtualMediaMounted(lun) {
+		return "", fmt.Errorf("LUN %d has media mounted, unmount it first", lun)
+	}
+
+	err := setMassStorageMode(lun, cdrom, true)
 	if err != nil {
 		return "", fmt.Errorf("failed to set mass storage mode: %w", err)
 	}
@@ -659,11 +684,18 @@ func rpcSetMassStorageMode(mode string) (string, error) {
 	logger.Info().Str("mode", mode).Msg("Mass storage mode set")
 
 	// Get the updated mode after setting
-	return rpcGetMassStorageMode()
+	return rpcGetLunMassStorageMode(lun)
 }
 
 func rpcGetMassStorageMode() (string, error) {
-	cdrom, err := getMassStorageCDROMEnabled()
+	return rpcGetLunMassStorageMode(0)
+}
+
+func rpcGetLunMassStorageMode(lun int) (string, error) {
+	if err := validateMassStorageLun(lun); err != nil {
+		return "", err
+	}
+	cdrom, err := getMassStorageCDROMEnabled(lun)
 	if err != nil {
 		return "", fmt.Errorf("failed to get mass storage mode: %w", err)
 	}
@@ -703,6 +735,19 @@ func rpcSetUsbConfig(usbConfig usbgadget.Config) error {
 	return updateUsbRelatedConfig()
 }
 
+// rpcPlanUsbConfig returns the configfs changes setUsbConfig would apply, without applying them.
+func rpcPlanUsbConfig(usbConfig usbgadget.Config) ([]usbgadget.PlannedFileChange, error) {
+	return gadget.PlanGadgetConfig(&usbConfig, nil)
+}
+
+// rpcPlanUsbDevices returns the configfs changes setUsbDevices would apply, without applying them.
+func rpcPlanUsbDevices(usbDevices usbgadget.Devices) ([]usbgadget.PlannedFileChange, error) {
+	if usbDevices.MassStorageLuns == 0 {
+		usbDevices.MassStorageLuns = config.UsbDevices.MassStorageLuns
+	}
+	return gadget.PlanGadgetConfig(nil, &usbDevices)
+}
+
 func rpcGetWakeOnLanDevices() ([]WakeOnLanDevice, error) {
 	if config.WakeOnLanDevices == nil {
 		return []WakeOnLanDevice{}, nil
@@ -918,10 +963,15 @@ func updateUsbRelatedConfig() error {
 	if err := SaveConfig(); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
+	restartChangedUsbServices()
 	return nil
 }
 
 func rpcSetUsbDevices(usbDevices usbgadget.Devices) error {
+	// the LUN count is managed by setMassStorageLunCount
+	if usbDevices.MassStorageLuns == 0 {
+		usbDevices.MassStorageLuns = config.UsbDevices.MassStorageLuns
+	}
 	config.UsbDevices = &usbDevices
 	gadget.SetGadgetDevices(config.UsbDevices)
 	return updateUsbRelatedConfig()
@@ -935,8 +985,20 @@ func rpcSetUsbDeviceState(device string, enabled bool) error {
 		config.UsbDevices.RelativeMouse = enabled
 	case "keyboard":
 		config.UsbDevices.Keyboard = enabled
+	case "fido":
+		config.UsbDevices.Fido = enabled
 	case "massStorage":
 		config.UsbDevices.MassStorage = enabled
+	case "serialConsole":
+		config.UsbDevices.SerialConsole = enabled
+	case "printer":
+		config.UsbDevices.Printer = enabled
+	case "audio":
+		config.UsbDevices.Audio = enabled
+	case "audioMicrophone":
+		config.UsbDevices.AudioMicrophone = enabled
+	case "network":
+		config.UsbDevices.Network = enabled
 	default:
 		return fmt.Errorf("invalid device: %s", device)
 	}
@@ -973,10 +1035,7 @@ func rpcSetKeyboardLayout(layout string) error {
 }
 
 func getKeyboardMacros() (any, error) {
-	macros := make([]KeyboardMacro, len(config.KeyboardMacros))
-	copy(macros, config.KeyboardMacros)
-
-	return macros, nil
+	return getStoredKeyboardMacros()
 }
 
 type KeyboardMacrosParams struct {
@@ -988,7 +1047,7 @@ func setKeyboardMacros(params KeyboardMacrosParams) (any, error) {
 		return nil, fmt.Errorf("missing or invalid macros parameter")
 	}
 
-	newMacros := make([]KeyboardMacro, 0, len(params.Macros))
+	newMacros := make([]kbmacro.Macro, 0, len(params.Macros))
 
 	for i, item := range params.Macros {
 		macroMap, ok := item.(map[string]any)
@@ -998,7 +1057,7 @@ func setKeyboardMacros(params KeyboardMacrosParams) (any, error) {
 
 		id, _ := macroMap["id"].(string)
 		if id == "" {
-			id = fmt.Sprintf("macro-%d", time.Now().UnixNano())
+			id = kbmacro.NewID()
 		}
 
 		name, _ := macroMap["name"].(string)
@@ -1008,7 +1067,7 @@ func setKeyboardMacros(params KeyboardMacrosParams) (any, error) {
 			sortOrder = int(sortOrderFloat)
 		}
 
-		steps := []KeyboardMacroStep{}
+		steps := []kbmacro.Step{}
 		if stepsArray, ok := macroMap["steps"].([]any); ok {
 			for _, stepItem := range stepsArray {
 				stepMap, ok := stepItem.(map[string]any)
@@ -1016,7 +1075,7 @@ func setKeyboardMacros(params KeyboardMacrosParams) (any, error) {
 					continue
 				}
 
-				step := KeyboardMacroStep{}
+				step := kbmacro.Step{}
 
 				if keysArray, ok := stepMap["keys"].([]any); ok {
 					for _, k := range keysArray {
@@ -1038,27 +1097,38 @@ func setKeyboardMacros(params KeyboardMacrosParams) (any, error) {
 					step.Delay = int(delay)
 				}
 
+				if mouseMap, ok := stepMap["mouse"].(map[string]any); ok {
+					mouse := &kbmacro.Mouse{}
+					mouse.Action, _ = mouseMap["action"].(string)
+					mouse.Button, _ = mouseMap["button"].(string)
+					if x, ok := mouseMap["x"].(float64); ok {
+						mouse.X = int(x)
+					}
+					if y, ok := mouseMap["y"].(float64); ok {
+						mouse.Y = int(y)
+					}
+					step.Mouse = mouse
+				}
+
 				steps = append(steps, step)
 			}
 		}
 
-		macro := KeyboardMacro{
+		macro := kbmacro.Macro{
 			ID:        id,
 			Name:      name,
 			Steps:     steps,
 			SortOrder: sortOrder,
 		}
 
-		if err := macro.Validate(); err != nil {
+		if err := macro.Validate(config.KeyboardMacroLimits); err != nil {
 			return nil, fmt.Errorf("invalid macro at index %d: %w", i, err)
 		}
 
 		newMacros = append(newMacros, macro)
 	}
 
-	config.KeyboardMacros = newMacros
-
-	if err := SaveConfig(); err != nil {
+	if err := setStoredKeyboardMacros(newMacros); err != nil {
 		return nil, err
 	}
 
@@ -1086,6 +1156,7 @@ func rpcSetLocalLoopbackOnly(enabled bool) error {
 
 var (
 	keyboardMacroCancel context.CancelFunc
+	keyboardMacroRun    uint64 // incremented for every run, tells a run whether it was superseded
 	keyboardMacroLock   sync.Mutex
 )
 
@@ -1101,18 +1172,53 @@ func cancelKeyboardMacro() {
 	}
 }
 
-func setKeyboardMacroCancel(cancel context.CancelFunc) {
+// startKeyboardMacroRun cancels the ongoing run and makes cancel the current one, it returns the run
+// to pass to finishKeyboardMacroRun.
+func startKeyboardMacroRun(cancel context.CancelFunc) uint64 {
 	keyboardMacroLock.Lock()
 	defer keyboardMacroLock.Unlock()
 
+	if keyboardMacroCancel != nil {
+		keyboardMacroCancel()
+		logger.Info().Msg("canceled keyboard macro")
+	}
+	keyboardMacroRun++
 	keyboardMacroCancel = cancel
+	return keyboardMacroRun
 }
 
-func rpcExecuteKeyboardMacro(macro []hidrpc.KeyboardMacroStep) error {
-	cancelKeyboardMacro()
+// finishKeyboardMacroRun clears the cancel func if run is still the current one and returns whether it was,
+// a run that was superseded must leave the cancel func of the new one in place.
+func finishKeyboardMacroRun(run uint64) bool {
+	keyboardMacroLock.Lock()
+	defer keyboardMacroLock.Unlock()
+
+	if run != keyboardMacroRun {
+		return false
+	}
+	keyboardMacroCancel = nil
+	return true
+}
+
+// rpcExecuteKeyboardMacro runs the macro, with syncLockKeys Caps Lock and Num Lock are set to a
+// known state while it runs.
+func rpcExecuteKeyboardMacro(macro []hidrpc.KeyboardMacroStep, syncLockKeys bool) error {
+	return runCancellableKeyboardInput(func(ctx context.Context) error {
+		if syncLockKeys {
+			return runWithLockKeys(ctx, func(ctx context.Context) error {
+				return rpcDoExecuteKeyboardMacro(ctx, macro)
+			})
+		}
+		return rpcDoExecuteKeyboardMacro(ctx, macro)
+	})
+}
 
+// runCancellableKeyboardInput runs macros, pastes and scripts one at a time, starting one cancels
+// the previous one. The UI is told about it through the keyboard macro state.
+func runCancellableKeyboardInput(run func(ctx context.Context) error) error {
 	ctx, cancel := context.WithCancel(context.Background())
-	setKeyboardMacroCancel(cancel)
+	defer cancel()
+	id := startKeyboardMacroRun(cancel)
 
 	s := hidrpc.KeyboardMacroState{
 		State:   true,
@@ -1123,13 +1229,14 @@ func rpcExecuteKeyboardMacro(macro []hidrpc.KeyboardMacroStep) error {
 		currentSession.reportHidRPCKeyboardMacroState(s)
 	}
 
-	err := rpcDoExecuteKeyboardMacro(ctx, macro)
+	err := run(ctx)
 
-	setKeyboardMacroCancel(nil)
-
-	s.State = false
-	if currentSession != nil {
-		currentSession.reportHidRPCKeyboardMacroState(s)
+	// the run that superseded this one reports its own state
+	if finishKeyboardMacroRun(id) {
+		s.State = false
+		if currentSession != nil {
+			currentSession.reportHidRPCKeyboardMacroState(s)
+		}
 	}
 
 	return err
@@ -1148,18 +1255,33 @@ func isClearKeyStep(step hidrpc.KeyboardMacroStep) bool {
 func rpcDoExecuteKeyboardMacro(ctx context.Context, macro []hidrpc.KeyboardMacroStep) error {
 	logger.Debug().Interface("macro", macro).Msg("Executing keyboard macro")
 
+	// buttons the macro didn't let go of are released however it ends
+	var pointer macroPointer
+	defer func() {
+		if err := pointer.release(); err != nil {
+			logger.Warn().Err(err).Msg("failed to release mouse buttons")
+		}
+	}()
+
 	for i, step := range macro {
 		delay := time.Duration(step.Delay) * time.Millisecond
 
-		err := rpcKeyboardReport(step.Modifier, step.Keys)
-		if err != nil {
-			logger.Warn().Err(err).Msg("failed to execute keyboard macro")
-			return err
-		}
+		if step.Kind != hidrpc.MacroStepKeyboard {
+			if err := pointer.execute(step); err != nil {
+				logger.Warn().Err(err).Msg("failed to execute keyboard macro")
+				return err
+			}
+		} else {
+			err := rpcKeyboardReport(step.Modifier, step.Keys)
+			if err != nil {
+				logger.Warn().Err(err).Msg("failed to execute keyboard macro")
+				return err
+			}
 
-		// notify the device that the keyboard state is being cleared
-		if isClearKeyStep(step) {
-			gadget.UpdateKeysDown(0, keyboardClearStateKeys)
+			// notify the device that the keyboard state is being cleared
+			if isClearKeyStep(step) {
+				gadget.UpdateKeysDown(0, keyboardClearStateKeys)
+			}
 		}
 
 		// Use context-aware sleep that can be cancelled
@@ -1192,20 +1314,51 @@ var rpcHandlers = map[string]RPCHandler{
 	"setNetworkSettings":     {Func: rpcSetNetworkSettings, Params: []string{"settings"}},
 	"renewDHCPLease":         {Func: rpcRenewDHCPLease},
 	"getKeyboardLedState":    {Func: rpcGetKeyboardLedState},
+	"setKeyboardLockState":   {Func: rpcSetKeyboardLockState, Params: []string{"state"}},
+	"getHidDiagnostics":      {Func: rpcGetHidDiagnostics},
 	"getKeyDownState":        {Func: rpcGetKeysDownState},
-	"keyboardReport":         {Func: rpcKeyboardReport, Params: []string{"modifier", "keys"}},
-	"keypressReport":         {Func: rpcKeypressReport, Params: []string{"key", "press"}},
+	"keyboardReport":         {Func: rpcRemappedKeyboardReport, Params: []string{"modifier", "keys"}},
+	"keypressReport":         {Func: rpcRemappedKeypressReport, Params: []string{"key", "press"}},
 	"absMouseReport":         {Func: rpcAbsMouseReport, Params: []string{"x", "y", "buttons"}},
 	"relMouseReport":         {Func: rpcRelMouseReport, Params: []string{"dx", "dy", "buttons"}},
 	"wheelReport":            {Func: rpcWheelReport, Params: []string{"wheelY"}},
+	"getHidCoalescing":       {Func: rpcGetHidCoalescing},
+	"setHidCoalescing":       {Func: rpcSetHidCoalescing, Params: []string{"options"}},
 	"getVideoState":          {Func: rpcGetVideoState},
+	"getVideoFrameStats":     {Func: rpcGetVideoFrameStats},
 	"getUSBState":            {Func: rpcGetUSBState},
+	"getUSBStateHistory":     {Func: rpcGetUSBStateHistory},
+	"getUsbSuspendState":     {Func: rpcGetUsbSuspendState},
+	"wakeUpHost":             {Func: rpcWakeUpHost},
+	"detachUsbFunction":      {Func: rpcDetachUsbFunction, Params: []string{"function"}},
+	"attachUsbFunction":      {Func: rpcAttachUsbFunction, Params: []string{"function"}},
+	"replugUsbFunction":      {Func: rpcReplugUsbFunction, Params: []string{"function", "delayMs"}},
+	"getDetachedFunctions":   {Func: rpcGetDetachedUsbFunctions},
+	"getUsbRemoteWakeup":     {Func: rpcGetUsbRemoteWakeup},
+	"setUsbRemoteWakeup":     {Func: rpcSetUsbRemoteWakeup, Params: []string{"enabled"}},
+	"getUsbWakeOnKeyPress":   {Func: rpcGetUsbWakeOnKeyPress},
+	"setUsbWakeOnKeyPress":   {Func: rpcSetUsbWakeOnKeyPress, Params: []string{"enabled"}},
+	"confirmFidoPresence":    {Func: rpcConfirmFidoPresence, Params: []string{"id", "approved"}},
+	"getFidoCredentials":     {Func: rpcGetFidoCredentials},
+	"deleteFidoCredential":   {Func: rpcDeleteFidoCredential, Params: []string{"id"}},
+	"resetFidoCredentials":   {Func: rpcResetFidoCredentials},
+	"getPrintJobs":           {Func: rpcGetPrintJobs},
+	"readPrintJob":           {Func: rpcReadPrintJob, Params: []string{"filename", "offset", "length"}},
+	"deletePrintJob":         {Func: rpcDeletePrintJob, Params: []string{"filename"}},
 	"unmountImage":           {Func: rpcUnmountImage},
+	"unmountLunImage":        {Func: rpcUnmountLunImage, Params: []string{"lun"}},
 	"rpcMountBuiltInImage":   {Func: rpcMountBuiltInImage, Params: []string{"filename"}},
+	"mountLunBuiltInImage":   {Func: rpcMountLunBuiltInImage, Params: []string{"lun", "filename"}},
 	"setJigglerState":        {Func: rpcSetJigglerState, Params: []string{"enabled"}},
 	"getJigglerState":        {Func: rpcGetJigglerState},
 	"setJigglerConfig":       {Func: rpcSetJigglerConfig, Params: []string{"jigglerConfig"}},
 	"getJigglerConfig":       {Func: rpcGetJigglerConfig},
+	"getJigglerHistory":      {Func: rpcGetJigglerHistory},
+	"getKeyRemapProfiles":    {Func: rpcGetKeyRemapProfiles},
+	"setKeyRemapProfiles":    {Func: rpcSetKeyRemapProfiles, Params: []string{"profiles"}},
+	"getKeyRemapState":       {Func: rpcGetKeyRemapState},
+	"setKeyRemapProfile":     {Func: rpcSetKeyRemapProfile, Params: []string{"id"}},
+	"setSessionKeyRemap":     {Func: rpcSetSessionKeyRemap, Params: []string{"override", "id"}},
 	"getTimezones":           {Func: rpcGetTimezones},
 	"sendWOLMagicPacket":     {Func: rpcSendWOLMagicPacket, Params: []string{"macAddress"}},
 	"getStreamQualityFactor": {Func: rpcGetStreamQualityFactor},
@@ -1227,17 +1380,28 @@ var rpcHandlers = map[string]RPCHandler{
 	"getTLSState":            {Func: rpcGetTLSState},
 	"setTLSState":            {Func: rpcSetTLSState, Params: []string{"state"}},
 	"setMassStorageMode":     {Func: rpcSetMassStorageMode, Params: []string{"mode"}},
+	"setLunMassStorageMode":  {Func: rpcSetLunMassStorageMode, Params: []string{"lun", "mode"}},
 	"getMassStorageMode":     {Func: rpcGetMassStorageMode},
+	"getLunMassStorageMode":  {Func: rpcGetLunMassStorageMode, Params: []string{"lun"}},
 	"isUpdatePending":        {Func: rpcIsUpdatePending},
 	"getUsbEmulationState":   {Func: rpcGetUsbEmulationState},
 	"setUsbEmulationState":   {Func: rpcSetUsbEmulationState, Params: []string{"enabled"}},
 	"getUsbConfig":           {Func: rpcGetUsbConfig},
 	"setUsbConfig":           {Func: rpcSetUsbConfig, Params: []string{"usbConfig"}},
+	"planUsbConfig":          {Func: rpcPlanUsbConfig, Params: []string{"usbConfig"}},
 	"checkMountUrl":          {Func: rpcCheckMountUrl, Params: []string{"url"}},
 	"getVirtualMediaState":   {Func: rpcGetVirtualMediaState},
+	"getVirtualMediaStates":  {Func: rpcGetVirtualMediaStates},
 	"getStorageSpace":        {Func: rpcGetStorageSpace},
 	"mountWithHTTP":          {Func: rpcMountWithHTTP, Params: []string{"url", "mode"}},
 	"mountWithStorage":       {Func: rpcMountWithStorage, Params: []string{"filename", "mode"}},
+	"mountLunWithHTTP":       {Func: rpcMountLunWithHTTP, Params: []string{"lun", "url", "mode"}},
+	"mountLunWithStorage":    {Func: rpcMountLunWithStorage, Params: []string{"lun", "filename", "mode"}},
+	"mountWritableStorage":   {Func: rpcMountWritableStorage, Params: []string{"lun", "filename", "overlay"}},
+	"commitMediaOverlay":     {Func: rpcCommitMediaOverlay, Params: []string{"lun"}},
+	"discardMediaOverlay":    {Func: rpcDiscardMediaOverlay, Params: []string{"lun"}},
+	"getMassStorageLunCount": {Func: rpcGetMassStorageLunCount},
+	"setMassStorageLunCount": {Func: rpcSetMassStorageLunCount, Params: []string{"count"}},
 	"listStorageFiles":       {Func: rpcListStorageFiles},
 	"deleteStorageFile":      {Func: rpcDeleteStorageFile, Params: []string{"filename"}},
 	"startStorageFileUpload": {Func: rpcStartStorageFileUpload, Params: []string{"filename", "size"}},
@@ -1259,12 +1423,56 @@ var rpcHandlers = map[string]RPCHandler{
 	"setSerialSettings":      {Func: rpcSetSerialSettings, Params: []string{"settings"}},
 	"getUsbDevices":          {Func: rpcGetUsbDevices},
 	"setUsbDevices":          {Func: rpcSetUsbDevices, Params: []string{"devices"}},
+	"planUsbDevices":         {Func: rpcPlanUsbDevices, Params: []string{"devices"}},
 	"setUsbDeviceState":      {Func: rpcSetUsbDeviceState, Params: []string{"device", "enabled"}},
+	"getUsbNetworkConfig":    {Func: rpcGetUsbNetworkConfig},
+	"setUsbNetworkConfig":    {Func: rpcSetUsbNetworkConfig, Params: []string{"config"}},
+	"getUsbNetworkState":     {Func: rpcGetUsbNetworkState},
+	"getIdentityPresets":     {Func: rpcGetIdentityPresets},
+	"getIdentityPreset":      {Func: rpcGetIdentityPreset},
+	"setIdentityPreset":      {Func: rpcSetIdentityPreset, Params: []string{"name"}},
+	"saveIdentityPreset":     {Func: rpcSaveIdentityPreset, Params: []string{"preset"}},
+	"deleteIdentityPreset":   {Func: rpcDeleteIdentityPreset, Params: []string{"name"}},
 	"setCloudUrl":            {Func: rpcSetCloudUrl, Params: []string{"apiUrl", "appUrl"}},
 	"getKeyboardLayout":      {Func: rpcGetKeyboardLayout},
 	"setKeyboardLayout":      {Func: rpcSetKeyboardLayout, Params: []string{"layout"}},
 	"getKeyboardMacros":      {Func: getKeyboardMacros},
 	"setKeyboardMacros":      {Func: setKeyboardMacros, Params: []string{"params"}},
+
+	// the stored macros
+	"getKeyboardMacroLimits":   {Func: rpcGetKeyboardMacroLimits},
+	"setKeyboardMacroLimits":   {Func: rpcSetKeyboardMacroLimits, Params: []string{"limits"}},
+	"exportKeyboardMacros":     {Func: rpcExportKeyboardMacros},
+	"importKeyboardMacros":     {Func: rpcImportKeyboardMacros, Params: []string{"file", "replace"}},
+	"executeKeyboardMacroById": {Func: rpcExecuteKeyboardMacroById, Params: []string{"id"}},
+
+	// the recordings of the video
+	"getVideoRecordingState":    {Func: rpcGetVideoRecordingState},
+	"startVideoRecording":       {Func: rpcStartVideoRecording},
+	"stopVideoRecording":        {Func: rpcStopVideoRecording},
+	"getVideoRecordingSettings": {Func: rpcGetVideoRecordingSettings},
+	"setVideoRecordingSettings": {Func: rpcSetVideoRecordingSettings, Params: []string{"settings"}},
+	"getVideoRecordings":        {Func: rpcGetVideoRecordings},
+	"deleteVideoRecording":      {Func: rpcDeleteVideoRecording, Params: []string{"name"}},
+
+	"typeText":               {Func: rpcTypeText, Params: []string{"text", "delay", "syncLockKeys"}},
+	"getKeySequences":        {Func: rpcGetKeySequences},
+	"setKeySequences":        {Func: rpcSetKeySequences, Params: []string{"sequences"}},
+	"sendKeySequence":        {Func: rpcSendKeySequence, Params: []string{"name"}},
+	"cancelKeyboardMacro":    {Func: rpcCancelKeyboardMacro},
+	"getKeyboardScripts":     {Func: rpcGetKeyboardScripts},
+	"setKeyboardScripts":     {Func: rpcSetKeyboardScripts, Params: []string{"scripts"}},
+	"validateKeyboardScript": {Func: rpcValidateKeyboardScript, Params: []string{"script"}},
+	"runKeyboardScript":      {Func: rpcRunKeyboardScript, Params: []string{"id"}},
+	"executeKeyboardScript":  {Func: rpcExecuteKeyboardScript, Params: []string{"script"}},
+	"cancelKeyboardScript":   {Func: rpcCancelKeyboardMacro},
+	"startHidRecording":      {Func: rpcStartHidRecording, Params: []string{"name"}},
+	"stopHidRecording":       {Func: rpcStopHidRecording},
+	"getHidRecordingState":   {Func: rpcGetHidRecordingState},
+	"getHidRecordings":       {Func: rpcGetHidRecordings},
+	"deleteHidRecording":     {Func: rpcDeleteHidRecording, Params: []string{"id"}},
+	"replayHidRecording":     {Func: rpcReplayHidRecording, Params: []string{"id", "speed"}},
+	"cancelHidReplay":        {Func: rpcCancelKeyboardMacro},
 	"getLocalLoopbackOnly":   {Func: rpcGetLocalLoopbackOnly},
 	"setLocalLoopbackOnly":   {Func: rpcSetLocalLoopbackOnly, Params: []string{"enabled"}},
 }
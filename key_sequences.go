@@ -0,0 +1,108 @@
+package kvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jetkvm/kvm/internal/keyseq"
+)
+
+const MaxKeySequencesPerDevice = 25
+
+var errKeySequenceNotFound = errors.New("key sequence not found")
+
+// KeySequence is a sequence of the catalog, the builtin ones can't be changed.
+type KeySequence struct {
+	keyseq.Sequence
+	Builtin bool `json:"builtin"`
+}
+
+func findKeySequence(name string) (*keyseq.Sequence, error) {
+	if seq := keyseq.Find(keyseq.Builtin(), name); seq != nil {
+		return seq, nil
+	}
+	if seq := keyseq.Find(config.KeySequences, name); seq != nil {
+		return seq, nil
+	}
+	return nil, fmt.Errorf("%w: %s", errKeySequenceNotFound, name)
+}
+
+func rpcGetKeySequences() ([]KeySequence, error) {
+	sequences := make([]KeySequence, 0)
+	for _, seq := range keyseq.Builtin() {
+		sequences = append(sequences, KeySequence{Sequence: seq, Builtin: true})
+	}
+	for _, seq := range config.KeySequences {
+		sequences = append(sequences, KeySequence{Sequence: seq})
+	}
+	return sequences, nil
+}
+
+// rpcSetKeySequences replaces the custom key sequences.
+func rpcSetKeySequences(sequences []keyseq.Sequence) error {
+	if len(sequences) > MaxKeySequencesPerDevice {
+		return fmt.Errorf("too many key sequences (max %d)", MaxKeySequencesPerDevice)
+	}
+
+	for i := range sequences {
+		if err := sequences[i].Validate(); err != nil {
+			return fmt.Errorf("invalid key sequence at index %d: %w", i, err)
+		}
+		if keyseq.Find(sequences[:i], sequences[i].Name) != nil {
+			return fmt.Errorf("duplicate key sequence name: %s", sequences[i].Name)
+		}
+	}
+
+	config.KeySequences = sequences
+	if err := SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// rpcSendKeySequence sends the key sequence in the background, it can be cancelled like a keyboard macro.
+func rpcSendKeySequence(name string) error {
+	seq, err := findKeySequence(name)
+	if err != nil {
+		return err
+	}
+	macro, err := seq.Macro()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		logger.Info().Str("name", name).Msg("sending key sequence")
+		err := runCancellableKeyboardInput(func(ctx context.Context) error {
+			return rpcDoExecuteKeyboardMacro(ctx, macro)
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logger.Warn().Err(err).Str("name", name).Msg("failed to send key sequence")
+		}
+	}()
+	return nil
+}
+
+func handleListKeySequences(c *gin.Context) {
+	sequences, err := rpcGetKeySequences()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sequences)
+}
+
+func handleSendKeySequence(c *gin.Context) {
+	if err := rpcSendKeySequence(c.Param("name")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errKeySequenceNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "key sequence is being sent"})
+}
@@ -0,0 +1,154 @@
+package kvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jetkvm/kvm/internal/kbmacro"
+)
+
+const keyboardMacrosPath = "/userdata/jetkvm/keyboard_macros.json"
+
+var (
+	keyboardMacroStore = kbmacro.NewStore(keyboardMacrosPath)
+
+	keyboardMacrosMigrateLock sync.Mutex
+	keyboardMacrosMigrated    bool
+)
+
+// getKeyboardMacroStore returns the macro store, the macros of older versions are moved from
+// the config into it on first use.
+func getKeyboardMacroStore() (*kbmacro.Store, error) {
+	keyboardMacrosMigrateLock.Lock()
+	defer keyboardMacrosMigrateLock.Unlock()
+
+	if keyboardMacrosMigrated {
+		return keyboardMacroStore, nil
+	}
+
+	migrated, warnings, err := keyboardMacroStore.Migrate(config.KeyboardMacros)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate keyboard macros: %w", err)
+	}
+	for _, warning := range warnings {
+		logger.Warn().Str("change", warning).Msg("changed keyboard macro while migrating it")
+	}
+	if migrated {
+		logger.Info().Int("macros", len(config.KeyboardMacros)).Msg("migrated keyboard macros to the macro store")
+		config.KeyboardMacros = nil
+		if err := SaveConfig(); err != nil {
+			logger.Warn().Err(err).Msg("failed to remove the migrated keyboard macros from the config")
+		}
+	}
+	keyboardMacrosMigrated = true
+	return keyboardMacroStore, nil
+}
+
+func getStoredKeyboardMacros() ([]kbmacro.Macro, error) {
+	store, err := getKeyboardMacroStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.Macros()
+}
+
+func setStoredKeyboardMacros(macros []kbmacro.Macro) error {
+	store, err := getKeyboardMacroStore()
+	if err != nil {
+		return err
+	}
+	return store.Set(macros, config.KeyboardMacroLimits)
+}
+
+func rpcGetKeyboardMacroLimits() (kbmacro.Limits, error) {
+	return config.KeyboardMacroLimits, nil
+}
+
+func rpcSetKeyboardMacroLimits(limits kbmacro.Limits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	config.KeyboardMacroLimits = limits
+	if err := SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+func rpcExportKeyboardMacros() (kbmacro.File, error) {
+	store, err := getKeyboardMacroStore()
+	if err != nil {
+		return kbmacro.File{}, err
+	}
+	return store.Export()
+}
+
+// rpcImportKeyboardMacros adds the macros of an export, or replaces all macros with them.
+// It returns the number of imported macros.
+func rpcImportKeyboardMacros(file kbmacro.File, replace bool) (int, error) {
+	store, err := getKeyboardMacroStore()
+	if err != nil {
+		return 0, err
+	}
+	return store.Import(file, replace, config.KeyboardMacroLimits)
+}
+
+// rpcExecuteKeyboardMacroById runs a stored macro in the background, it can be cancelled like any keyboard macro.
+func rpcExecuteKeyboardMacroById(id string) error {
+	store, err := getKeyboardMacroStore()
+	if err != nil {
+		return err
+	}
+	macro, err := store.Find(id)
+	if err != nil {
+		return err
+	}
+	steps := macro.Report()
+
+	go func() {
+		if err := rpcExecuteKeyboardMacro(steps, false); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Warn().Err(err).Str("id", id).Msg("failed to execute keyboard macro")
+		}
+	}()
+	return nil
+}
+
+func handleExportKeyboardMacros(c *gin.Context) {
+	file, err := rpcExportKeyboardMacros()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="jetkvm-macros.json"`)
+	c.JSON(http.StatusOK, file)
+}
+
+func handleImportKeyboardMacros(c *gin.Context) {
+	var file kbmacro.File
+	if err := c.ShouldBindJSON(&file); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	imported, err := rpcImportKeyboardMacros(file, c.Query("replace") == "true")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"imported": imported})
+}
+
+func handleExecuteKeyboardMacro(c *gin.Context) {
+	if err := rpcExecuteKeyboardMacroById(c.Param("id")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, kbmacro.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "keyboard macro is running"})
+}
@@ -0,0 +1,148 @@
+package kvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jetkvm/kvm/internal/keyboard"
+	"github.com/jetkvm/kvm/internal/keyscript"
+	"github.com/jetkvm/kvm/internal/usbgadget"
+)
+
+// scriptKeyboard types keyboard scripts on the USB gadget
+type scriptKeyboard struct{}
+
+func (scriptKeyboard) KeyboardReport(modifier byte, keys []byte) error {
+	return rpcKeyboardReport(modifier, keys)
+}
+
+func (scriptKeyboard) WaitForUsbConfigured(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		usbStateLock.Lock()
+		state := usbState
+		usbStateLock.Unlock()
+		if state == string(usbgadget.UsbStateConfigured) {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("USB isn't configured after %s, state is %s", timeout, state)
+		}
+	}
+}
+
+type KeyboardScriptState struct {
+	ID      string `json:"id,omitempty"`
+	Running bool   `json:"running"`
+	Error   string `json:"error,omitempty"`
+}
+
+func reportKeyboardScriptState(state KeyboardScriptState) {
+	go func() {
+		if currentSession == nil {
+			return
+		}
+		writeJSONRPCEvent("keyboardScriptState", state, currentSession)
+	}()
+}
+
+func parseKeyboardScript(script string) (*keyscript.Script, error) {
+	layout, err := keyboard.GetLayout(config.KeyboardLayout)
+	if err != nil {
+		return nil, err
+	}
+	return keyscript.Parse(script, layout)
+}
+
+func findKeyboardScript(id string) (*KeyboardScript, error) {
+	for i := range config.KeyboardScripts {
+		if config.KeyboardScripts[i].ID == id {
+			return &config.KeyboardScripts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("keyboard script not found: %s", id)
+}
+
+// startKeyboardScript parses the script and runs it in the background, it cancels any running macro or script.
+func startKeyboardScript(id string, source string) error {
+	script, err := parseKeyboardScript(source)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		reportKeyboardScriptState(KeyboardScriptState{ID: id, Running: true})
+		err := runCancellableKeyboardInput(func(ctx context.Context) error {
+			return script.Run(ctx, scriptKeyboard{})
+		})
+
+		state := KeyboardScriptState{ID: id}
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logger.Warn().Err(err).Str("id", id).Msg("keyboard script failed")
+			state.Error = err.Error()
+		}
+		reportKeyboardScriptState(state)
+	}()
+	return nil
+}
+
+func rpcGetKeyboardScripts() ([]KeyboardScript, error) {
+	scripts := make([]KeyboardScript, len(config.KeyboardScripts))
+	copy(scripts, config.KeyboardScripts)
+	return scripts, nil
+}
+
+func rpcSetKeyboardScripts(scripts []KeyboardScript) error {
+	if len(scripts) > MaxScriptsPerDevice {
+		return fmt.Errorf("too many keyboard scripts (max %d)", MaxScriptsPerDevice)
+	}
+
+	for i := range scripts {
+		if scripts[i].Name == "" {
+			return fmt.Errorf("invalid script at index %d: name cannot be empty", i)
+		}
+		if _, err := parseKeyboardScript(scripts[i].Script); err != nil {
+			return fmt.Errorf("invalid script %q: %w", scripts[i].Name, err)
+		}
+		if scripts[i].ID == "" {
+			scripts[i].ID = uuid.New().String()
+		}
+	}
+
+	config.KeyboardScripts = scripts
+	if err := SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// rpcValidateKeyboardScript returns the problems of the script, an empty list if it's valid.
+func rpcValidateKeyboardScript(script string) ([]keyscript.Error, error) {
+	if _, err := parseKeyboardScript(script); err != nil {
+		return keyscript.Errors(err), nil
+	}
+	return []keyscript.Error{}, nil
+}
+
+func rpcRunKeyboardScript(id string) error {
+	script, err := findKeyboardScript(id)
+	if err != nil {
+		return err
+	}
+	return startKeyboardScript(script.ID, script.Script)
+}
+
+// rpcExecuteKeyboardScript runs a script that isn't stored.
+func rpcExecuteKeyboardScript(script string) error {
+	return startKeyboardScript("", script)
+}
@@ -0,0 +1,152 @@
+package kvm
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jetkvm/kvm/internal/keymap"
+	"github.com/jetkvm/kvm/internal/usbgadget"
+)
+
+const MaxKeyRemapProfiles = 25
+
+// keyRemapper remaps the keyboard input of the user, macros and typed text aren't remapped
+var keyRemapper = keymap.NewRemapper()
+
+type KeyRemapState struct {
+	// Profile is the ID of the profile of the config, empty if keys aren't remapped
+	Profile string `json:"profile"`
+	// SessionOverride is set if the current session uses SessionProfile instead
+	SessionOverride bool   `json:"sessionOverride"`
+	SessionProfile  string `json:"sessionProfile"`
+}
+
+func findKeyRemapProfile(id string) *keymap.Profile {
+	for i := range config.KeyRemapProfiles {
+		if config.KeyRemapProfiles[i].ID == id {
+			return &config.KeyRemapProfiles[i]
+		}
+	}
+	return nil
+}
+
+// activeKeyRemapTable returns the table of the session's profile, or of the config's if the session doesn't override it
+func activeKeyRemapTable() *keymap.Table {
+	id := config.KeyRemapProfile
+	if session := currentSession; session != nil && session.keyRemapOverride {
+		id = session.keyRemapProfile
+	}
+	if id == "" {
+		return nil
+	}
+	return keymap.NewTable(findKeyRemapProfile(id))
+}
+
+func rpcRemappedKeyboardReport(modifier byte, keys []byte) error {
+	modifier, keys = keyRemapper.Report(activeKeyRemapTable(), modifier, keys)
+	return rpcKeyboardReport(modifier, keys)
+}
+
+func rpcRemappedKeypressReport(key byte, press bool) error {
+	return rpcKeypressReport(keyRemapper.Keypress(activeKeyRemapTable(), key, press), press)
+}
+
+func keysDown(state usbgadget.KeysDownState) bool {
+	return state.Modifier != 0 || slices.ContainsFunc(state.Keys, func(k byte) bool { return k != 0 })
+}
+
+// unmapKeysDownState reports the keys down on the target as the keys the user pressed,
+// so the client releases the keys it knows about. The remapped keys are forgotten once
+// all keys are released.
+func unmapKeysDownState(state usbgadget.KeysDownState) usbgadget.KeysDownState {
+	if !keysDown(state) {
+		keyRemapper.Reset()
+		return state
+	}
+	state.Modifier, state.Keys = keyRemapper.Unmap(state.Modifier, state.Keys)
+	return state
+}
+
+// resetKeyRemap is called when the profile changes, the keys that are down were remapped
+// with the previous one, so they're released instead of being released as different keys later.
+func resetKeyRemap() {
+	if keysDown(gadget.GetKeysDownState()) {
+		if err := rpcKeyboardReport(0, keyboardClearStateKeys); err != nil {
+			logger.Warn().Err(err).Msg("failed to release the remapped keys")
+		}
+	}
+	keyRemapper.Reset()
+}
+
+func rpcGetKeyRemapProfiles() ([]keymap.Profile, error) {
+	profiles := make([]keymap.Profile, len(config.KeyRemapProfiles))
+	copy(profiles, config.KeyRemapProfiles)
+	return profiles, nil
+}
+
+func rpcSetKeyRemapProfiles(profiles []keymap.Profile) error {
+	if len(profiles) > MaxKeyRemapProfiles {
+		return fmt.Errorf("too many key remap profiles (max %d)", MaxKeyRemapProfiles)
+	}
+	for i := range profiles {
+		if err := profiles[i].Validate(); err != nil {
+			return fmt.Errorf("invalid profile at index %d: %w", i, err)
+		}
+		if profiles[i].ID == "" {
+			profiles[i].ID = uuid.New().String()
+		}
+	}
+
+	config.KeyRemapProfiles = profiles
+	if config.KeyRemapProfile != "" && findKeyRemapProfile(config.KeyRemapProfile) == nil {
+		config.KeyRemapProfile = ""
+	}
+	if err := SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+func rpcGetKeyRemapState() (KeyRemapState, error) {
+	state := KeyRemapState{Profile: config.KeyRemapProfile}
+	if session := currentSession; session != nil {
+		state.SessionOverride = session.keyRemapOverride
+		state.SessionProfile = session.keyRemapProfile
+	}
+	return state, nil
+}
+
+// rpcSetKeyRemapProfile sets the profile of the config, an empty ID turns remapping off.
+func rpcSetKeyRemapProfile(id string) error {
+	if id != "" && findKeyRemapProfile(id) == nil {
+		return fmt.Errorf("key remap profile not found: %s", id)
+	}
+
+	config.KeyRemapProfile = id
+	resetKeyRemap()
+	if err := SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// rpcSetSessionKeyRemap overrides the profile for the current session only,
+// an empty ID turns remapping off for the session.
+func rpcSetSessionKeyRemap(override bool, id string) error {
+	session := currentSession
+	if session == nil {
+		return fmt.Errorf("no active session")
+	}
+	if override && id != "" && findKeyRemapProfile(id) == nil {
+		return fmt.Errorf("key remap profile not found: %s", id)
+	}
+	if !override {
+		id = ""
+	}
+
+	session.keyRemapOverride = override
+	session.keyRemapProfile = id
+	resetKeyRemap()
+	return nil
+}
@@ -0,0 +1,116 @@
+package kvm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jetkvm/kvm/internal/usbgadget"
+)
+
+const (
+	// lockKeyConfirmTimeout is how long the host has to update the keyboard LEDs after a lock key is pressed
+	lockKeyConfirmTimeout = 500 * time.Millisecond
+	lockKeyHold           = 20 * time.Millisecond
+)
+
+// LockKeyState is the wanted state of the lock keys, the keys that are nil are left as they are.
+type LockKeyState struct {
+	CapsLock   *bool `json:"caps_lock,omitempty"`
+	NumLock    *bool `json:"num_lock,omitempty"`
+	ScrollLock *bool `json:"scroll_lock,omitempty"`
+}
+
+var lockKeys = []struct {
+	name  string
+	key   byte
+	mask  byte
+	state func(s *LockKeyState) **bool
+}{
+	{"Caps Lock", 0x39, usbgadget.KeyboardLedMaskCapsLock, func(s *LockKeyState) **bool { return &s.CapsLock }},
+	{"Num Lock", 0x53, usbgadget.KeyboardLedMaskNumLock, func(s *LockKeyState) **bool { return &s.NumLock }},
+	{"Scroll Lock", 0x47, usbgadget.KeyboardLedMaskScrollLock, func(s *LockKeyState) **bool { return &s.ScrollLock }},
+}
+
+// typingLockKeyState is the state the keyboard layouts are made for:
+// Caps Lock is off and Num Lock is on, so the keypad types digits.
+func typingLockKeyState() LockKeyState {
+	capsLock, numLock := false, true
+	return LockKeyState{CapsLock: &capsLock, NumLock: &numLock}
+}
+
+func keyboardLedState() byte {
+	state := gadget.GetKeyboardState()
+	return state.Byte()
+}
+
+// tapLockKey presses and releases the lock key, then waits until the host turns the LED on or off.
+func tapLockKey(ctx context.Context, key byte, mask byte) error {
+	before := keyboardLedState() & mask
+
+	keys := make([]byte, len(keyboardClearStateKeys))
+	keys[0] = key
+	if err := rpcKeyboardReport(0, keys); err != nil {
+		return err
+	}
+	time.Sleep(lockKeyHold)
+	if err := rpcKeyboardReport(0, keyboardClearStateKeys); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lockKeyConfirmTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for keyboardLedState()&mask == before {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// setLockKeys toggles the lock keys that aren't in the wanted state. It returns the previous state
+// of the keys it toggled, which restores them when it's passed to setLockKeys again.
+func setLockKeys(ctx context.Context, want LockKeyState) (LockKeyState, error) {
+	var previous LockKeyState
+	for _, k := range lockKeys {
+		wanted := *k.state(&want)
+		on := keyboardLedState()&k.mask != 0
+		if wanted == nil || *wanted == on {
+			continue
+		}
+
+		*k.state(&previous) = &on
+		if err := tapLockKey(ctx, k.key, k.mask); err != nil {
+			return previous, fmt.Errorf("the host didn't confirm %s: %w", k.name, err)
+		}
+	}
+	return previous, nil
+}
+
+// runWithLockKeys sets the lock keys to the state typing expects while run types on the host,
+// and restores them afterwards, also when run is cancelled.
+func runWithLockKeys(ctx context.Context, run func(ctx context.Context) error) error {
+	previous, err := setLockKeys(ctx, typingLockKeyState())
+	if err != nil {
+		// some hosts don't report every LED, typing may still work
+		logger.Warn().Err(err).Msg("failed to set the lock keys for typing")
+	}
+	defer func() {
+		if _, err := setLockKeys(context.Background(), previous); err != nil {
+			logger.Warn().Err(err).Msg("failed to restore the lock keys")
+		}
+	}()
+	return run(ctx)
+}
+
+func rpcSetKeyboardLockState(state LockKeyState) (usbgadget.KeyboardState, error) {
+	if _, err := setLockKeys(context.Background(), state); err != nil {
+		return gadget.GetKeyboardState(), err
+	}
+	return gadget.GetKeyboardState(), nil
+}
@@ -28,6 +28,8 @@ var (
 	displayLogger   = logging.GetSubsystemLogger("display")
 	wolLogger       = logging.GetSubsystemLogger("wol")
 	usbLogger       = logging.GetSubsystemLogger("usb")
+	fidoLogger      = logging.GetSubsystemLogger("fido")
+	audioLogger     = logging.GetSubsystemLogger("audio")
 	// external components
 	ginLogger = logging.GetSubsystemLogger("gin")
 )
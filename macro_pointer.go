@@ -0,0 +1,57 @@
+package kvm
+
+import (
+	"fmt"
+
+	"github.com/jetkvm/kvm/internal/hidrpc"
+)
+
+// macroPointer tracks the mouse state of a running macro, so buttons stay held
+// while the pointer moves and can be released when the macro is cancelled.
+type macroPointer struct {
+	x, y     int
+	absolute bool
+	buttons  uint8
+}
+
+// report sends the button state at the last absolute position, or without moving if there is none
+func (p *macroPointer) report() error {
+	if p.absolute {
+		return rpcAbsMouseReport(p.x, p.y, p.buttons)
+	}
+	return rpcRelMouseReport(0, 0, p.buttons)
+}
+
+func (p *macroPointer) execute(step hidrpc.KeyboardMacroStep) error {
+	switch step.Kind {
+	case hidrpc.MacroStepAbsMove:
+		p.x, p.y, p.absolute = int(step.X), int(step.Y), true
+		return p.report()
+	case hidrpc.MacroStepRelMove:
+		p.absolute = false
+		return rpcRelMouseReport(clampInt8(step.X), clampInt8(step.Y), p.buttons)
+	case hidrpc.MacroStepButtonDown:
+		p.buttons |= step.Button
+		return p.report()
+	case hidrpc.MacroStepButtonUp:
+		p.buttons &^= step.Button
+		return p.report()
+	case hidrpc.MacroStepWheel:
+		return rpcWheelReport(clampInt8(step.Y))
+	default:
+		return fmt.Errorf("unsupported macro step kind: %d", step.Kind)
+	}
+}
+
+// release lets go of all buttons that are still held
+func (p *macroPointer) release() error {
+	if p.buttons == 0 {
+		return nil
+	}
+	p.buttons = 0
+	return p.report()
+}
+
+func clampInt8(v int16) int8 {
+	return int8(max(-127, min(127, v)))
+}
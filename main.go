@@ -65,6 +65,7 @@ func Main() {
 
 	// initialize usb gadget
 	initUsbGadget()
+	initUsbServices()
 	if err := setInitialVirtualMediaState(); err != nil {
 		logger.Warn().Err(err).Msg("failed to set initial virtual media state")
 	}
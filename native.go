@@ -3,11 +3,9 @@ package kvm
 import (
 	"os"
 	"sync"
-	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/jetkvm/kvm/internal/native"
-	"github.com/pion/webrtc/v4/pkg/media"
 )
 
 var (
@@ -47,14 +45,6 @@ func initNative(systemVersion *semver.Version, appVersion *semver.Version) {
 				nativeLogger.Warn().Str("event", event).Msg("unknown rpc event received")
 			}
 		},
-		OnVideoFrameReceived: func(frame []byte, duration time.Duration) {
-			if currentSession != nil {
-				err := currentSession.VideoTrack.WriteSample(media.Sample{Data: frame, Duration: duration})
-				if err != nil {
-					nativeLogger.Warn().Err(err).Msg("error writing sample")
-				}
-			}
-		},
 	})
 	nativeInstance.Start()
 
@@ -0,0 +1,68 @@
+package kvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jetkvm/kvm/internal/hidrpc"
+	"github.com/jetkvm/kvm/internal/keyboard"
+)
+
+const (
+	// how long each key is held, the same as the paste in the UI
+	typeTextKeyHold      = 20
+	defaultTypeTextDelay = 20
+)
+
+// textToKeyboardMacro translates the text into a macro for the configured keyboard layout,
+// every key is pressed and released, delay is the pause in milliseconds after each key.
+func textToKeyboardMacro(text string, delay int) ([]hidrpc.KeyboardMacroStep, error) {
+	if delay < 0 || delay > math.MaxUint16 {
+		return nil, fmt.Errorf("delay must be between 0 and %d", math.MaxUint16)
+	}
+	if delay == 0 {
+		delay = defaultTypeTextDelay
+	}
+
+	layout, err := keyboard.GetLayout(config.KeyboardLayout)
+	if err != nil {
+		return nil, err
+	}
+	strokes, err := layout.TextStrokes(text)
+	if err != nil {
+		return nil, err
+	}
+
+	macro := make([]hidrpc.KeyboardMacroStep, 0, len(strokes)*2)
+	for _, stroke := range strokes {
+		keys := make([]byte, hidrpc.HidKeyBufferSize)
+		keys[0] = stroke.Key
+		macro = append(macro,
+			hidrpc.KeyboardMacroStep{Modifier: stroke.Modifier, Keys: keys, Delay: typeTextKeyHold},
+			hidrpc.KeyboardMacroStep{Keys: keyboardClearStateKeys, Delay: uint16(delay)},
+		)
+	}
+	return macro, nil
+}
+
+// rpcTypeText types the text on the host. It returns once the text has been validated,
+// the typing runs in the background and can be cancelled like any keyboard macro.
+// With syncLockKeys Caps Lock and Num Lock are set to a known state and restored afterwards.
+func rpcTypeText(text string, delay int, syncLockKeys bool) error {
+	macro, err := textToKeyboardMacro(text, delay)
+	if err != nil {
+		return err
+	}
+	if len(macro) == 0 {
+		return nil
+	}
+
+	go func() {
+		if err := rpcExecuteKeyboardMacro(macro, syncLockKeys); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Warn().Err(err).Msg("failed to type text")
+		}
+	}()
+	return nil
+}
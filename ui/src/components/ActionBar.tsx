@@ -29,8 +29,8 @@ export default function Actionbar({
   const { isVirtualKeyboardEnabled, setVirtualKeyboardEnabled } = useHidStore();
   const { setDisableVideoFocusTrap, terminalType, setTerminalType, toggleSidebarView } = useUiStore();
 
-  const remoteVirtualMediaState = useMountMediaStore(
-    state => state.remoteVirtualMediaState,
+  const remoteVirtualMediaStates = useMountMediaStore(
+    state => state.remoteVirtualMediaStates,
   );
   const { developerMode } = useSettingsStore();
 
@@ -112,7 +112,7 @@ export default function Actionbar({
                         <LuHardDrive className={className} />
                         <div
                           className={cx(className, "h-2 w-2 rounded-full bg-blue-700", {
-                            hidden: !remoteVirtualMediaState,
+                            hidden: remoteVirtualMediaStates.length === 0,
                           })}
                         />
                       </>
@@ -0,0 +1,74 @@
+import { useState } from "react";
+
+import { ConfirmDialog } from "@/components/ConfirmDialog";
+import { JsonRpcResponse, useJsonRpc } from "@/hooks/useJsonRpc";
+import notifications from "@/notifications";
+
+interface FidoPresenceRequest {
+  id: string;
+  operation: "register" | "authenticate" | "reset";
+  rpId?: string;
+  userName?: string;
+  expiresAt: string;
+}
+
+const operationTitles: Record<FidoPresenceRequest["operation"], string> = {
+  register: "Register security key",
+  authenticate: "Sign in with security key",
+  reset: "Reset security key",
+};
+
+function describeRequest(request: FidoPresenceRequest) {
+  if (request.operation === "reset") {
+    return "The target computer wants to delete all credentials of the emulated security key.";
+  }
+
+  const site = request.rpId ? <strong>{request.rpId}</strong> : "a website";
+  const user = request.userName ? <> as <strong>{request.userName}</strong></> : null;
+  return request.operation === "register" ? (
+    <>The target computer wants to register the emulated security key with {site}{user}.</>
+  ) : (
+    <>The target computer wants to sign in to {site}{user} with the emulated security key.</>
+  );
+}
+
+export function FidoPresencePrompt() {
+  const [request, setRequest] = useState<FidoPresenceRequest | null>(null);
+
+  const { send } = useJsonRpc(function onRequest(resp) {
+    if (resp.method === "fidoPresenceRequest") {
+      setRequest(resp.params as FidoPresenceRequest);
+    }
+
+    if (resp.method === "fidoPresenceDone") {
+      const { id } = resp.params as { id: string };
+      setRequest(current => (current?.id === id ? null : current));
+    }
+  });
+
+  const answer = (approved: boolean) => {
+    if (!request) return;
+
+    send("confirmFidoPresence", { id: request.id, approved }, (resp: JsonRpcResponse) => {
+      if ("error" in resp) {
+        notifications.error(
+          `Failed to answer security key request: ${resp.error.data || "Unknown error"}`,
+        );
+      }
+    });
+    setRequest(null);
+  };
+
+  return (
+    <ConfirmDialog
+      open={request !== null}
+      onClose={() => answer(false)}
+      title={request ? operationTitles[request.operation] : ""}
+      description={request ? describeRequest(request) : null}
+      variant={request?.operation === "reset" ? "danger" : "info"}
+      confirmText="Approve"
+      cancelText="Deny"
+      onConfirm={() => answer(true)}
+    />
+  );
+}
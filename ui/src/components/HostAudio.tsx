@@ -0,0 +1,76 @@
+import { useEffect, useRef } from "react";
+
+import { useRTCStore } from "@/hooks/stores";
+import { JsonRpcResponse, useJsonRpc } from "@/hooks/useJsonRpc";
+import notifications from "@/notifications";
+
+interface UsbAudioDevices {
+  audio?: boolean;
+  audio_microphone?: boolean;
+}
+
+// HostAudio plays what the target computer outputs on the USB sound card,
+// and sends the local microphone back when microphone passthrough is enabled.
+export function HostAudio() {
+  const audioElm = useRef<HTMLAudioElement>(null);
+  const { mediaStream, audioTransceiver, peerConnectionState } = useRTCStore();
+  const { send } = useJsonRpc();
+
+  useEffect(
+    function playHostAudio() {
+      const elm = audioElm.current;
+      if (!elm || !mediaStream) return;
+      elm.srcObject = mediaStream;
+
+      // browsers block unmuted autoplay until the user interacted with the page
+      const abortController = new AbortController();
+      const play = () => {
+        elm.play().catch(() => {
+          window.addEventListener("pointerdown", play, {
+            once: true,
+            signal: abortController.signal,
+          });
+        });
+      };
+      play();
+
+      return () => abortController.abort();
+    },
+    [mediaStream],
+  );
+
+  useEffect(
+    function sendMicrophone() {
+      if (!audioTransceiver || peerConnectionState !== "connected") return;
+
+      let stream: MediaStream | null = null;
+      let cancelled = false;
+
+      send("getUsbDevices", {}, async (resp: JsonRpcResponse) => {
+        if ("error" in resp) return;
+        const devices = resp.result as UsbAudioDevices;
+        if (!devices.audio || !devices.audio_microphone) return;
+
+        try {
+          stream = await navigator.mediaDevices.getUserMedia({ audio: true });
+        } catch (error) {
+          notifications.error(`Failed to access the microphone: ${error}`);
+          return;
+        }
+        if (cancelled) {
+          stream.getTracks().forEach(track => track.stop());
+          return;
+        }
+        await audioTransceiver.sender.replaceTrack(stream.getAudioTracks()[0]);
+      });
+
+      return () => {
+        cancelled = true;
+        stream?.getTracks().forEach(track => track.stop());
+      };
+    },
+    [audioTransceiver, peerConnectionState, send],
+  );
+
+  return <audio ref={audioElm} autoPlay hidden />;
+}
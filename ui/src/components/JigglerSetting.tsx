@@ -4,16 +4,40 @@ import { LuExternalLink } from "react-icons/lu";
 import { Button, LinkButton } from "@components/Button";
 import { JsonRpcResponse, useJsonRpc } from "@/hooks/useJsonRpc";
 
+import { CheckboxWithLabel } from "./Checkbox";
 import { InputFieldWithLabel } from "./InputField";
 import { SelectMenuBasic } from "./SelectMenuBasic";
 
+export interface JigglerActiveHours {
+  weekday: number;
+  start: string;
+  end: string;
+}
+
 export interface JigglerConfig {
   inactivity_limit_seconds: number;
   jitter_percentage: number;
   schedule_cron_tab: string;
   timezone?: string;
+  mode?: "absolute" | "relative" | "keypress" | "wheel";
+  key?: string;
+  active_hours?: JigglerActiveHours[];
+  only_with_session?: boolean;
 }
 
+const jigglerModeOptions = [
+  { value: "absolute", label: "Move cursor to the corner" },
+  { value: "relative", label: "Relative micro-move" },
+  { value: "keypress", label: "Harmless keypress" },
+  { value: "wheel", label: "Scroll wheel tick" },
+];
+
+const jigglerKeyOptions = [
+  { value: "F15", label: "F15" },
+  { value: "F13", label: "F13" },
+  { value: "ShiftLeft", label: "Shift" },
+];
+
 export function JigglerSetting({
   onSave,
   defaultJigglerState,
@@ -83,7 +107,7 @@ export function JigglerSetting({
               size="XS"
               theme="light"
               text={example.name}
-              onClick={() => setJigglerConfigState(example.config)}
+              onClick={() => setJigglerConfigState({ ...jigglerConfigState, ...example.config })}
             />
           ))}
           <LinkButton
@@ -161,8 +185,50 @@ export function JigglerSetting({
           }
           options={timezoneOptions}
         />
+
+        <SelectMenuBasic
+          size="SM"
+          label="Jiggle Mode"
+          description="Input sent to keep the host awake"
+          value={jigglerConfigState.mode || "absolute"}
+          onChange={e =>
+            setJigglerConfigState({
+              ...jigglerConfigState,
+              mode: e.target.value as JigglerConfig["mode"],
+            })
+          }
+          options={jigglerModeOptions}
+        />
+
+        {jigglerConfigState.mode === "keypress" && (
+          <SelectMenuBasic
+            size="SM"
+            label="Key"
+            description="Key that is pressed and released"
+            value={jigglerConfigState.key || "F15"}
+            onChange={e =>
+              setJigglerConfigState({
+                ...jigglerConfigState,
+                key: e.target.value,
+              })
+            }
+            options={jigglerKeyOptions}
+          />
+        )}
       </div>
 
+      <CheckboxWithLabel
+        label="Only while connected"
+        description="Jiggle only while a browser session is connected"
+        checked={!!jigglerConfigState.only_with_session}
+        onChange={e =>
+          setJigglerConfigState({
+            ...jigglerConfigState,
+            only_with_session: e.target.checked,
+          })
+        }
+      />
+
       <div className="flex gap-x-2">
         <Button
           size="SM"
@@ -6,12 +6,8 @@ import FieldLabel from "@/components/FieldLabel";
 import Fieldset from "@/components/Fieldset";
 import { InputFieldWithLabel, FieldError } from "@/components/InputField";
 import { MacroStepCard } from "@/components/MacroStepCard";
-import {
-  DEFAULT_DELAY,
-  MAX_STEPS_PER_MACRO,
-  MAX_KEYS_PER_STEP,
-} from "@/constants/macros";
-import { KeySequence } from "@/hooks/stores";
+import { DEFAULT_DELAY } from "@/constants/macros";
+import { KeySequence, useMacrosStore } from "@/hooks/stores";
 import useKeyboardLayout from "@/hooks/useKeyboardLayout";
 
 interface ValidationErrors {
@@ -46,6 +42,7 @@ export function MacroForm({
   const [errors, setErrors] = useState<ValidationErrors>({});
   const [errorMessage, setErrorMessage] = useState<string | null>(null);
   const { selectedKeyboard } = useKeyboardLayout();
+  const limits = useMacrosStore(state => state.limits);
 
   const showTemporaryError = (message: string) => {
     setErrorMessage(message);
@@ -113,8 +110,8 @@ export function MacroForm({
       const keysArray = Array.isArray(newSteps[stepIndex].keys)
         ? newSteps[stepIndex].keys
         : [];
-      if (keysArray.length >= MAX_KEYS_PER_STEP) {
-        showTemporaryError(`Maximum of ${MAX_KEYS_PER_STEP} keys per step allowed`);
+      if (keysArray.length >= limits.max_keys_per_step) {
+        showTemporaryError(`Maximum of ${limits.max_keys_per_step} keys per step allowed`);
         return;
       }
       newSteps[stepIndex].keys = [...keysArray, option.value];
@@ -170,7 +167,7 @@ export function MacroForm({
     setMacro({ ...macro, steps: newSteps });
   };
 
-  const isMaxStepsReached = (macro.steps?.length || 0) >= MAX_STEPS_PER_MACRO;
+  const isMaxStepsReached = (macro.steps?.length || 0) >= limits.max_steps_per_macro;
 
   return (
     <>
@@ -202,7 +199,7 @@ export function MacroForm({
               />
             </div>
             <span className="text-slate-500 dark:text-slate-400">
-              {macro.steps?.length || 0}/{MAX_STEPS_PER_MACRO} steps
+              {macro.steps?.length || 0}/{limits.max_steps_per_macro} steps
             </span>
           </div>
           {errors.steps && errors.steps[0]?.keys && (
@@ -248,11 +245,11 @@ export function MacroForm({
               theme="light"
               fullWidth
               LeadingIcon={LuPlus}
-              text={`Add Step ${isMaxStepsReached ? `(${MAX_STEPS_PER_MACRO} max)` : ""}`}
+              text={`Add Step ${isMaxStepsReached ? `(${limits.max_steps_per_macro} max)` : ""}`}
               onClick={() => {
                 if (isMaxStepsReached) {
                   showTemporaryError(
-                    `You can only add a maximum of ${MAX_STEPS_PER_MACRO} steps per macro.`,
+                    `You can only add a maximum of ${limits.max_steps_per_macro} steps per macro.`,
                   );
                   return;
                 }
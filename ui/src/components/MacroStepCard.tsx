@@ -6,7 +6,8 @@ import { Combobox } from "@/components/Combobox";
 import { SelectMenuBasic } from "@/components/SelectMenuBasic";
 import Card from "@/components/Card";
 import FieldLabel from "@/components/FieldLabel";
-import { MAX_KEYS_PER_STEP, DEFAULT_DELAY } from "@/constants/macros";
+import { DEFAULT_DELAY } from "@/constants/macros";
+import { useMacrosStore } from "@/hooks/stores";
 import { KeyboardLayout } from "@/keyboardLayouts";
 import { keys, modifiers } from "@/keyboardMappings";
 
@@ -84,6 +85,7 @@ export function MacroStepCard({
   keyboard
 }: MacroStepCardProps) {
   const { keyDisplayMap } = keyboard;
+  const maxKeysPerStep = useMacrosStore(state => state.limits.max_keys_per_step);
 
   const keyOptions = useMemo(() =>
     Object.keys(keys)
@@ -179,7 +181,7 @@ export function MacroStepCard({
         
         <div className="w-full flex flex-col gap-1">
           <div className="flex items-center gap-1">
-            <FieldLabel label="Keys" description={`Maximum ${MAX_KEYS_PER_STEP} keys per step.`} />
+            <FieldLabel label="Keys" description={`Maximum ${maxKeysPerStep} keys per step.`} />
           </div>
           {ensureArray(step.keys) && step.keys.length > 0 && (
             <div className="flex flex-wrap gap-1 pb-2">
@@ -217,8 +219,8 @@ export function MacroStepCard({
               disabledMessage="Max keys reached"
               size="SM"
               immediate
-              disabled={ensureArray(step.keys).length >= MAX_KEYS_PER_STEP}
-              placeholder={ensureArray(step.keys).length >= MAX_KEYS_PER_STEP ? "Max keys reached" : "Search for key..."}
+              disabled={ensureArray(step.keys).length >= maxKeysPerStep}
+              placeholder={ensureArray(step.keys).length >= maxKeysPerStep ? "Max keys reached" : "Search for key..."}
               emptyMessage="No matching keys found"
             />
           </div>
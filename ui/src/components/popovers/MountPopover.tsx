@@ -19,23 +19,25 @@ import notifications from "@/notifications";
 
 const MountPopopover = forwardRef<HTMLDivElement, object>((_props, ref) => {
   const { send } = useJsonRpc();
-  const { remoteVirtualMediaState, setModalView, setRemoteVirtualMediaState } =
+  const { remoteVirtualMediaStates, setModalView, setRemoteVirtualMediaStates } =
     useMountMediaStore();
+  // new media is mounted on the first LUN
+  const isFirstLunMounted = remoteVirtualMediaStates.some(state => state.lun === 0);
 
   const syncRemoteVirtualMediaState = useCallback(() => {
-    send("getVirtualMediaState", {}, (response: JsonRpcResponse) => {
+    send("getVirtualMediaStates", {}, (response: JsonRpcResponse) => {
       if ("error" in response) {
         notifications.error(
           `Failed to get virtual media state: ${response.error.message}`,
         );
       } else {
-        setRemoteVirtualMediaState(response.result as unknown as RemoteVirtualMediaState);
+        setRemoteVirtualMediaStates(response.result as unknown as RemoteVirtualMediaState[]);
       }
     });
-  }, [send, setRemoteVirtualMediaState]);
+  }, [send, setRemoteVirtualMediaStates]);
 
-  const handleUnmount = () => {
-    send("unmountImage", {}, (response: JsonRpcResponse) => {
+  const handleUnmount = (lun: number) => {
+    send("unmountLunImage", { lun }, (response: JsonRpcResponse) => {
       if ("error" in response) {
         notifications.error(`Failed to unmount image: ${response.error.message}`);
       } else {
@@ -44,30 +46,30 @@ const MountPopopover = forwardRef<HTMLDivElement, object>((_props, ref) => {
     });
   };
 
-  const renderGridCardContent = () => {
-    if (!remoteVirtualMediaState) {
-      return (
+  const renderEmptyCardContent = () => {
+    return (
+      <div className="space-y-1">
+        <div className="inline-block">
+          <Card>
+            <div className="p-1">
+              <PlusCircleIcon className="h-4 w-4 shrink-0 text-blue-700 dark:text-white" />
+            </div>
+          </Card>
+        </div>
         <div className="space-y-1">
-          <div className="inline-block">
-            <Card>
-              <div className="p-1">
-                <PlusCircleIcon className="h-4 w-4 shrink-0 text-blue-700 dark:text-white" />
-              </div>
-            </Card>
-          </div>
-          <div className="space-y-1">
-            <h3 className="text-sm font-semibold leading-none text-black dark:text-white">
-              No mounted media
-            </h3>
-            <p className="text-xs leading-none text-slate-700 dark:text-slate-300">
-              Add a file to get started
-            </p>
-          </div>
+          <h3 className="text-sm font-semibold leading-none text-black dark:text-white">
+            No mounted media
+          </h3>
+          <p className="text-xs leading-none text-slate-700 dark:text-slate-300">
+            Add a file to get started
+          </p>
         </div>
-      );
-    }
+      </div>
+    );
+  };
 
-    const { source, filename, size, url, path } = remoteVirtualMediaState;
+  const renderGridCardContent = (state: RemoteVirtualMediaState) => {
+    const { source, filename, size, url, path } = state;
 
     switch (source) {
       case "HTTP":
@@ -149,35 +151,40 @@ const MountPopopover = forwardRef<HTMLDivElement, object>((_props, ref) => {
                   animationDelay: "0.1s",
                 }}
               >
-                <div className="block select-none">
-                  <div className="group">
-                    <Card>
-                      <div className="w-full px-4 py-8">
-                        <div className="flex h-full flex-col items-center justify-center text-center">
-                          {renderGridCardContent()}
+                {remoteVirtualMediaStates.length === 0 && (
+                  <div className="block select-none">
+                    <div className="group">
+                      <Card>
+                        <div className="w-full px-4 py-8">
+                          <div className="flex h-full flex-col items-center justify-center text-center">
+                            {renderEmptyCardContent()}
+                          </div>
                         </div>
-                      </div>
-                    </Card>
+                      </Card>
+                    </div>
                   </div>
-                </div>
-                {remoteVirtualMediaState ? (
-                  <div className="flex select-none items-center justify-between text-xs">
-                    <div className="select-none text-white dark:text-slate-300">
-                      <span>Mounted as</span>{" "}
-                      <span className="font-semibold">
-                        {remoteVirtualMediaState.mode === "Disk" ? "Disk" : "CD-ROM"}
-                      </span>
+                )}
+                {remoteVirtualMediaStates.map(state => (
+                  <div key={state.lun} className="space-y-2">
+                    <div className="block select-none">
+                      <div className="group">
+                        <Card>
+                          <div className="w-full px-4 py-8">
+                            <div className="flex h-full flex-col items-center justify-center text-center">
+                              {renderGridCardContent(state)}
+                            </div>
+                          </div>
+                        </Card>
+                      </div>
                     </div>
+                    <div className="flex select-none items-center justify-between text-xs">
+                      <div className="select-none text-white dark:text-slate-300">
+                        <span>LUN {state.lun} mounted as</span>{" "}
+                        <span className="font-semibold">
+                          {state.mode === "Disk" ? "Disk" : "CD-ROM"}
+                        </span>
+                      </div>
 
-                    <div className="flex items-center gap-x-2">
-                      <Button
-                        size="SM"
-                        theme="blank"
-                        text="Close"
-                        onClick={() => {
-                          close();
-                        }}
-                      />
                       <Button
                         size="SM"
                         theme="light"
@@ -206,32 +213,32 @@ const MountPopopover = forwardRef<HTMLDivElement, object>((_props, ref) => {
                             </defs>
                           </svg>
                         )}
-                        onClick={handleUnmount}
+                        onClick={() => handleUnmount(state.lun)}
                       />
                     </div>
                   </div>
-                ) : null}
+                ))}
               </div>
             </div>
           </div>
         </div>
 
-        {!remoteVirtualMediaState && (
-          <div
-            className="flex animate-fadeIn opacity-0 items-center justify-end space-x-2"
-            style={{
-              animationDuration: "0.7s",
-              animationDelay: "0.2s",
+        <div
+          className="flex animate-fadeIn opacity-0 items-center justify-end space-x-2"
+          style={{
+            animationDuration: "0.7s",
+            animationDelay: "0.2s",
+          }}
+        >
+          <Button
+            size="SM"
+            theme="blank"
+            text="Close"
+            onClick={() => {
+              close();
             }}
-          >
-            <Button
-              size="SM"
-              theme="blank"
-              text="Close"
-              onClick={() => {
-                close();
-              }}
-            />
+          />
+          {!isFirstLunMounted && (
             <Button
               size="SM"
               theme="primary"
@@ -242,8 +249,8 @@ const MountPopopover = forwardRef<HTMLDivElement, object>((_props, ref) => {
               }}
               LeadingIcon={LuPlus}
             />
-          </div>
-        )}
+          )}
+        </div>
       </div>
     </GridCard>
   );
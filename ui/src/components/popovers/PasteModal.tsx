@@ -11,6 +11,7 @@ import useKeyboardLayout from "@/hooks/useKeyboardLayout";
 import notifications from "@/notifications";
 import { Button } from "@components/Button";
 import { GridCard } from "@components/Card";
+import { CheckboxWithLabel } from "@components/Checkbox";
 import { InputFieldWithLabel } from "@components/InputField";
 import { SettingsPageHeader } from "@components/SettingsPageheader";
 import { TextAreaWithLabel } from "@components/TextArea";
@@ -29,6 +30,7 @@ export default function PasteModal() {
 
   const [invalidChars, setInvalidChars] = useState<string[]>([]);
   const [delayValue, setDelayValue] = useState(defaultDelay);
+  const [syncLockKeys, setSyncLockKeys] = useState(true);
   const delay = useMemo(() => {
     if (delayValue < 0 || delayValue > 65534) {
       return defaultDelay;
@@ -101,13 +103,13 @@ export default function PasteModal() {
       }
 
       if (macroSteps.length > 0) {
-        await executeMacro(macroSteps);
+        await executeMacro(macroSteps, syncLockKeys);
       }
     } catch (error) {
       console.error("Failed to paste text:", error);
       notifications.error("Failed to paste text");
     }
-  }, [selectedKeyboard, executeMacro, delay]);
+  }, [selectedKeyboard, executeMacro, delay, syncLockKeys]);
 
   useEffect(() => {
     if (TextAreaRef.current) {
@@ -204,6 +206,12 @@ export default function PasteModal() {
                     </div>
                   )}
                 </div>
+                <CheckboxWithLabel
+                  label="Turn off Caps Lock while pasting"
+                  description="Restores Caps Lock and Num Lock when the paste is done"
+                  checked={syncLockKeys}
+                  onChange={e => setSyncLockKeys(e.target.checked)}
+                />
                 <div className="space-y-4">
                   <p className="text-xs text-slate-600 dark:text-slate-400">
                     Sending text using keyboard layout: {selectedKeyboard.isoCode}-
@@ -1,5 +1,6 @@
 export const DEFAULT_DELAY = 50;
-export const MAX_STEPS_PER_MACRO = 10;
-export const MAX_KEYS_PER_STEP = 10;
-export const MAX_TOTAL_MACROS = 25; 
+// the defaults of the limits, the device can be configured with other limits
+export const MAX_STEPS_PER_MACRO = 500;
+export const MAX_KEYS_PER_STEP = 6;
+export const MAX_TOTAL_MACROS = 100;
 export const COPY_SUFFIX = "(copy)";
\ No newline at end of file
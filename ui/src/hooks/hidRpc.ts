@@ -10,6 +10,7 @@ export const HID_RPC_MESSAGE_TYPES = {
     MouseReport: 0x06,
     KeyboardMacroReport: 0x07,
     CancelKeyboardMacroReport: 0x08,
+    RunKeyboardScriptReport: 0x0A,
     KeyboardLedState: 0x32,
     KeysDownState: 0x33,
     KeyboardMacroState: 0x34,
@@ -214,10 +215,38 @@ export class KeyboardReportMessage extends RpcMessage {
     }
 }
 
+// Kinds of macro steps, keyboard steps are the only kind older devices understand
+export const MACRO_STEP_KINDS = {
+    Keyboard: 0x00,
+    AbsMove: 0x01,
+    RelMove: 0x02,
+    ButtonDown: 0x03,
+    ButtonUp: 0x04,
+    Wheel: 0x05,
+}
+
+const MACRO_FLAG_PASTE = 0x01;
+const MACRO_FLAG_STEP_KINDS = 0x02;
+// Caps Lock and Num Lock are set to a known state while the macro runs
+const MACRO_FLAG_SYNC_LOCK_KEYS = 0x04;
+
 export interface KeyboardMacroStep extends KeysDownState {
     delay: number;
+    // pointer steps set the kind and use x, y and button instead of the keys
+    kind?: number;
+    x?: number;
+    y?: number;
+    button?: number;
 }
 
+const fromInt16toUint8 = (n: number) => {
+    if (n > 32767 || n < -32768) {
+        throw new Error(`Number ${n} is not within the int16 range`);
+    }
+
+    return fromUint16toUint8(n & 0xFFFF);
+};
+
 export class KeyboardMacroReportMessage extends RpcMessage {
     isPaste: boolean;
     stepCount: number;
@@ -225,11 +254,55 @@ export class KeyboardMacroReportMessage extends RpcMessage {
 
     KEYS_LENGTH = hidKeyBufferSize;
 
-    constructor(isPaste: boolean, stepCount: number, steps: KeyboardMacroStep[]) {
+    syncLockKeys: boolean;
+
+    constructor(isPaste: boolean, stepCount: number, steps: KeyboardMacroStep[], syncLockKeys = false) {
         super(HID_RPC_MESSAGE_TYPES.KeyboardMacroReport);
         this.isPaste = isPaste;
         this.stepCount = stepCount;
         this.steps = steps;
+        this.syncLockKeys = syncLockKeys;
+    }
+
+    marshalKeyboardStep(step: KeyboardMacroStep): Uint8Array {
+        if (!withinUint8Range(step.modifier)) {
+            throw new Error(`Modifier ${step.modifier} is not within the uint8 range`);
+        }
+
+        // Ensure the keys are within the KEYS_LENGTH range
+        const keys = step.keys;
+        if (keys.length > this.KEYS_LENGTH) {
+            throw new Error(`Keys ${keys} is not within the hidKeyBufferSize range`);
+        } else if (keys.length < this.KEYS_LENGTH) {
+            keys.push(...Array(this.KEYS_LENGTH - keys.length).fill(0));
+        }
+
+        for (const key of keys) {
+            if (!withinUint8Range(key)) {
+                throw new Error(`Key ${key} is not within the uint8 range`);
+            }
+        }
+
+        return new Uint8Array([
+            step.modifier,
+            ...keys,
+            ...fromUint16toUint8(step.delay),
+        ]);
+    }
+
+    marshalPointerStep(step: KeyboardMacroStep): Uint8Array {
+        const button = step.button ?? 0;
+        if (!withinUint8Range(button)) {
+            throw new Error(`Button ${button} is not within the uint8 range`);
+        }
+
+        return new Uint8Array([
+            ...fromInt16toUint8(step.x ?? 0),
+            ...fromInt16toUint8(step.y ?? 0),
+            button,
+            0, 0,
+            ...fromUint16toUint8(step.delay),
+        ]);
     }
 
     marshal(): Uint8Array {
@@ -238,41 +311,32 @@ export class KeyboardMacroReportMessage extends RpcMessage {
             throw new Error(`Length ${this.stepCount} is not equal to the number of steps ${this.steps.length}`);
         }
 
-        const data = new Uint8Array(this.stepCount * 9 + 6);
+        // the step kinds are only sent when needed, so keyboard macros still work with older devices
+        const withKinds = this.steps.some(step => (step.kind ?? MACRO_STEP_KINDS.Keyboard) !== MACRO_STEP_KINDS.Keyboard);
+        const stepSize = withKinds ? 10 : 9;
+        const flags = (this.isPaste ? MACRO_FLAG_PASTE : 0)
+            | (withKinds ? MACRO_FLAG_STEP_KINDS : 0)
+            | (this.syncLockKeys ? MACRO_FLAG_SYNC_LOCK_KEYS : 0);
+
+        const data = new Uint8Array(this.stepCount * stepSize + 6);
         data.set(new Uint8Array([
             this.messageType,
-            this.isPaste ? 1 : 0,
+            flags,
             ...fromUint32toUint8(this.stepCount),
         ]), 0);
 
         for (let i = 0; i < this.stepCount; i++) {
             const step = this.steps[i];
-            if (!withinUint8Range(step.modifier)) {
-                throw new Error(`Modifier ${step.modifier} is not within the uint8 range`);
-            }
-
-            // Ensure the keys are within the KEYS_LENGTH range
-            const keys = step.keys;
-            if (keys.length > this.KEYS_LENGTH) {
-                throw new Error(`Keys ${keys} is not within the hidKeyBufferSize range`);
-            } else if (keys.length < this.KEYS_LENGTH) {
-                keys.push(...Array(this.KEYS_LENGTH - keys.length).fill(0));
-            }
-
-            for (const key of keys) {
-                if (!withinUint8Range(key)) {
-                    throw new Error(`Key ${key} is not within the uint8 range`);
-                }
+            const kind = step.kind ?? MACRO_STEP_KINDS.Keyboard;
+            const macroBinary = kind === MACRO_STEP_KINDS.Keyboard
+                ? this.marshalKeyboardStep(step)
+                : this.marshalPointerStep(step);
+
+            let offset = 6 + i * stepSize;
+            if (withKinds) {
+                data[offset] = kind;
+                offset++;
             }
-
-            const macroBinary = new Uint8Array([
-                step.modifier,
-                ...keys,
-                ...fromUint16toUint8(step.delay),
-            ]);
-            const offset = 6 + i * 9;
-
-            
             data.set(macroBinary, offset);
         }
 
@@ -388,6 +452,32 @@ export class CancelKeyboardMacroReportMessage extends RpcMessage {
     }
 }
 
+export class RunKeyboardScriptReportMessage extends RpcMessage {
+    id: string;
+
+    constructor(id: string) {
+        super(HID_RPC_MESSAGE_TYPES.RunKeyboardScriptReport);
+        this.id = id;
+    }
+
+    marshal(): Uint8Array {
+        return new Uint8Array([this.messageType, ...new TextEncoder().encode(this.id)]);
+    }
+}
+
+export class WheelReportMessage extends RpcMessage {
+    wheelY: number;
+
+    constructor(wheelY: number) {
+        super(HID_RPC_MESSAGE_TYPES.WheelReport);
+        this.wheelY = wheelY;
+    }
+
+    marshal(): Uint8Array {
+        return new Uint8Array([this.messageType, fromInt8ToUint8(this.wheelY)]);
+    }
+}
+
 export class MouseReportMessage extends RpcMessage {
     dx: number;
     dy: number;
@@ -428,6 +518,7 @@ export const messageRegistry = {
     [HID_RPC_MESSAGE_TYPES.KeypressReport]: KeypressReportMessage,
     [HID_RPC_MESSAGE_TYPES.KeyboardMacroReport]: KeyboardMacroReportMessage,
     [HID_RPC_MESSAGE_TYPES.CancelKeyboardMacroReport]: CancelKeyboardMacroReportMessage,
+    [HID_RPC_MESSAGE_TYPES.RunKeyboardScriptReport]: RunKeyboardScriptReportMessage,
     [HID_RPC_MESSAGE_TYPES.KeyboardMacroState]: KeyboardMacroStateMessage,
     [HID_RPC_MESSAGE_TYPES.KeypressKeepAliveReport]: KeypressKeepAliveMessage,
 }
@@ -126,6 +126,9 @@ export interface RTCState {
   transceiver: RTCRtpTransceiver | null;
   setTransceiver: (transceiver: RTCRtpTransceiver) => void;
 
+  audioTransceiver: RTCRtpTransceiver | null;
+  setAudioTransceiver: (transceiver: RTCRtpTransceiver) => void;
+
   mediaStream: MediaStream | null;
   setMediaStream: (stream: MediaStream) => void;
 
@@ -184,6 +187,9 @@ export const useRTCStore = create<RTCState>(set => ({
   transceiver: null,
   setTransceiver: (transceiver: RTCRtpTransceiver) => set({ transceiver }),
 
+  audioTransceiver: null,
+  setAudioTransceiver: (transceiver: RTCRtpTransceiver) => set({ audioTransceiver: transceiver }),
+
   peerConnectionState: null,
   setPeerConnectionState: (state: RTCPeerConnectionState) => set({ peerConnectionState: state }),
 
@@ -405,6 +411,7 @@ export const useSettingsStore = create(
 );
 
 export interface RemoteVirtualMediaState {
+  lun: number;
   source: "HTTP" | "Storage" | null;
   mode: "CDROM" | "Disk" | null;
   filename: string | null;
@@ -414,8 +421,9 @@ export interface RemoteVirtualMediaState {
 }
 
 export interface MountMediaState {
-  remoteVirtualMediaState: RemoteVirtualMediaState | null;
-  setRemoteVirtualMediaState: (state: MountMediaState["remoteVirtualMediaState"]) => void;
+  // the media of every LUN that has something mounted
+  remoteVirtualMediaStates: RemoteVirtualMediaState[];
+  setRemoteVirtualMediaStates: (states: MountMediaState["remoteVirtualMediaStates"]) => void;
 
   modalView: "mode" | "url" | "device" | "upload" | "error" | null;
   setModalView: (view: MountMediaState["modalView"]) => void;
@@ -431,8 +439,8 @@ export interface MountMediaState {
 }
 
 export const useMountMediaStore = create<MountMediaState>(set => ({
-  remoteVirtualMediaState: null,
-  setRemoteVirtualMediaState: (state: MountMediaState["remoteVirtualMediaState"]) => set({ remoteVirtualMediaState: state }),
+  remoteVirtualMediaStates: [],
+  setRemoteVirtualMediaStates: (states: MountMediaState["remoteVirtualMediaStates"]) => set({ remoteVirtualMediaStates: states }),
 
   modalView: "mode",
   setModalView: (view: MountMediaState["modalView"]) => set({ modalView: view }),
@@ -759,10 +767,19 @@ export const useNetworkStateStore = create<NetworkState>((set, get) => ({
   },
 }));
 
+export interface MacroMouseAction {
+  action: "move" | "moveBy" | "down" | "up" | "wheel";
+  x?: number;
+  y?: number;
+  button?: "left" | "right" | "middle";
+}
+
 export interface KeySequenceStep {
   keys: string[];
   modifiers: string[];
   delay: number;
+  // a mouse step ignores its keys and modifiers
+  mouse?: MacroMouseAction | null;
 }
 
 export interface KeySequence {
@@ -772,8 +789,15 @@ export interface KeySequence {
   sortOrder?: number;
 }
 
+export interface MacroLimits {
+  max_macros: number;
+  max_steps_per_macro: number;
+  max_keys_per_step: number;
+}
+
 export interface MacrosState {
   macros: KeySequence[];
+  limits: MacroLimits;
   loading: boolean;
   initialized: boolean;
   loadMacros: () => Promise<void>;
@@ -800,6 +824,11 @@ export const generateMacroId = () => {
 
 export const useMacrosStore = create<MacrosState>((set, get) => ({
   macros: [],
+  limits: {
+    max_macros: MAX_TOTAL_MACROS,
+    max_steps_per_macro: MAX_STEPS_PER_MACRO,
+    max_keys_per_step: MAX_KEYS_PER_STEP,
+  },
   loading: false,
   initialized: false,
   sendFn: null,
@@ -819,6 +848,11 @@ export const useMacrosStore = create<MacrosState>((set, get) => ({
 
     set({ loading: true });
 
+    sendFn("getKeyboardMacroLimits", {}, (response: JsonRpcResponse) => {
+      if (response.error) return;
+      set({ limits: response.result as MacroLimits });
+    });
+
     try {
       await new Promise<void>((resolve, reject) => {
         sendFn("getKeyboardMacros", {}, (response: JsonRpcResponse) => {
@@ -861,29 +895,30 @@ export const useMacrosStore = create<MacrosState>((set, get) => ({
       throw new Error("JSON-RPC send function not available");
     }
 
-    if (macros.length > MAX_TOTAL_MACROS) {
-      console.error(`Cannot save: exceeded maximum of ${MAX_TOTAL_MACROS} macros`);
-      throw new Error(`Cannot save: exceeded maximum of ${MAX_TOTAL_MACROS} macros`);
+    const { limits } = get();
+    if (macros.length > limits.max_macros) {
+      console.error(`Cannot save: exceeded maximum of ${limits.max_macros} macros`);
+      throw new Error(`Cannot save: exceeded maximum of ${limits.max_macros} macros`);
     }
 
     for (const macro of macros) {
-      if (macro.steps.length > MAX_STEPS_PER_MACRO) {
+      if (macro.steps.length > limits.max_steps_per_macro) {
         console.error(
-          `Cannot save: macro "${macro.name}" exceeds maximum of ${MAX_STEPS_PER_MACRO} steps`,
+          `Cannot save: macro "${macro.name}" exceeds maximum of ${limits.max_steps_per_macro} steps`,
         );
         throw new Error(
-          `Cannot save: macro "${macro.name}" exceeds maximum of ${MAX_STEPS_PER_MACRO} steps`,
+          `Cannot save: macro "${macro.name}" exceeds maximum of ${limits.max_steps_per_macro} steps`,
         );
       }
 
       for (let i = 0; i < macro.steps.length; i++) {
         const step = macro.steps[i];
-        if (step.keys && step.keys.length > MAX_KEYS_PER_STEP) {
+        if (step.keys && step.keys.length > limits.max_keys_per_step) {
           console.error(
-            `Cannot save: macro "${macro.name}" step ${i + 1} exceeds maximum of ${MAX_KEYS_PER_STEP} keys`,
+            `Cannot save: macro "${macro.name}" step ${i + 1} exceeds maximum of ${limits.max_keys_per_step} keys`,
           );
           throw new Error(
-            `Cannot save: macro "${macro.name}" step ${i + 1} exceeds maximum of ${MAX_KEYS_PER_STEP} keys`,
+            `Cannot save: macro "${macro.name}" step ${i + 1} exceeds maximum of ${limits.max_keys_per_step} keys`,
           );
         }
       }
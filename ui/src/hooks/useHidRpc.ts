@@ -14,6 +14,7 @@ import {
   MouseReportMessage,
   PointerReportMessage,
   RpcMessage,
+  WheelReportMessage,
   unmarshalHidRpcMessage,
 } from "./hidRpc";
 
@@ -133,9 +134,16 @@ export function useHidRpc(onHidRpcMessage?: (payload: RpcMessage) => void) {
     [sendMessage],
   );
 
+  const reportWheelEvent = useCallback(
+    (wheelY: number) => {
+      sendMessage(new WheelReportMessage(wheelY));
+    },
+    [sendMessage],
+  );
+
   const reportKeyboardMacroEvent = useCallback(
-    (steps: KeyboardMacroStep[]) => {
-      sendMessage(new KeyboardMacroReportMessage(false, steps.length, steps));
+    (steps: KeyboardMacroStep[], syncLockKeys = false) => {
+      sendMessage(new KeyboardMacroReportMessage(false, steps.length, steps, syncLockKeys));
     },
     [sendMessage],
   );
@@ -246,6 +254,7 @@ export function useHidRpc(onHidRpcMessage?: (payload: RpcMessage) => void) {
     reportKeypressEvent,
     reportAbsMouseEvent,
     reportRelMouseEvent,
+    reportWheelEvent,
     reportKeyboardMacroEvent,
     cancelOngoingKeyboardMacro,
     reportKeypressKeepAlive,
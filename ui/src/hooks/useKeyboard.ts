@@ -5,11 +5,13 @@ import {
   KeyboardMacroStateMessage,
   KeyboardMacroStep,
   KeysDownStateMessage,
+  MACRO_STEP_KINDS,
 } from "@/hooks/hidRpc";
 import {
   hidErrorRollOver,
   hidKeyBufferSize,
   KeysDownState,
+  MacroMouseAction,
   useHidStore,
   useRTCStore,
 } from "@/hooks/stores";
@@ -27,6 +29,29 @@ export interface MacroStep {
   keys: string[] | null;
   modifiers: string[] | null;
   delay: number;
+  mouse?: MacroMouseAction | null;
+}
+
+const MACRO_MOUSE_BUTTONS = { left: 1, right: 2, middle: 4 };
+
+const MACRO_MOUSE_STEP_KINDS = {
+  move: MACRO_STEP_KINDS.AbsMove,
+  moveBy: MACRO_STEP_KINDS.RelMove,
+  down: MACRO_STEP_KINDS.ButtonDown,
+  up: MACRO_STEP_KINDS.ButtonUp,
+  wheel: MACRO_STEP_KINDS.Wheel,
+};
+
+function mouseMacroStep(mouse: MacroMouseAction, delay: number): KeyboardMacroStep {
+  return {
+    kind: MACRO_MOUSE_STEP_KINDS[mouse.action],
+    x: mouse.x ?? 0,
+    y: mouse.y ?? 0,
+    button: mouse.button ? MACRO_MOUSE_BUTTONS[mouse.button] : 0,
+    keys: [],
+    modifier: 0,
+    delay,
+  };
 }
 
 export type MacroSteps = MacroStep[];
@@ -284,12 +309,19 @@ export default function useKeyboard() {
   // After the delay, the keys and modifiers are released and the next step is executed.
   // If a step has no keys or modifiers, it is treated as a delay-only step.
   // A small pause is added between steps to ensure that the device can process the events.
+  // With syncLockKeys the device turns Caps Lock off and Num Lock on while the macro runs.
   const executeMacroRemote = useCallback(async (
     steps: MacroSteps,
+    syncLockKeys = false,
   ) => {
     const macro: KeyboardMacroStep[] = [];
 
     for (const [_, step] of steps.entries()) {
+      if (step.mouse) {
+        macro.push(mouseMacroStep(step.mouse, step.delay || 100));
+        continue;
+      }
+
       const keyValues = (step.keys || []).map(key => keys[key]).filter(Boolean);
       const modifierMask: number = (step.modifiers || [])
 
@@ -304,7 +336,7 @@ export default function useKeyboard() {
       }
     }
 
-    sendKeyboardMacroEventHidRpc(macro);
+    sendKeyboardMacroEventHidRpc(macro, syncLockKeys);
   }, [sendKeyboardMacroEventHidRpc]);
   const executeMacroClientSide = useCallback(async (steps: MacroSteps) => {
     const promises: (() => Promise<void>)[] = [];
@@ -355,9 +387,9 @@ export default function useKeyboard() {
         });
     });
   }, [sendKeystrokeLegacy, resetKeyboardState, setAbortController]);
-  const executeMacro = useCallback(async (steps: MacroSteps) => {
+  const executeMacro = useCallback(async (steps: MacroSteps, syncLockKeys = false) => {
     if (rpcHidReady) {
-      return executeMacroRemote(steps);
+      return executeMacroRemote(steps, syncLockKeys);
     }
     return executeMacroClientSide(steps);
   }, [rpcHidReady, executeMacroRemote, executeMacroClientSide]);
@@ -22,7 +22,7 @@ export default function useMouse() {
 
   // RPC hooks
   const { send } = useJsonRpc();
-  const { reportAbsMouseEvent, reportRelMouseEvent, rpcHidReady } = useHidRpc();
+  const { reportAbsMouseEvent, reportRelMouseEvent, reportWheelEvent, rpcHidReady } = useHidRpc();
   // Mouse-related
 
   const sendRelMouseMovement = useCallback(
@@ -148,7 +148,12 @@ export default function useMouse() {
       // Invert the clamped scroll value to match expected behavior
       const invertedScrollValue = -clampedScrollValue;
 
-      send("wheelReport", { wheelY: invertedScrollValue });
+      if (rpcHidReady) {
+        reportWheelEvent(invertedScrollValue);
+      } else {
+        // kept for backward compatibility
+        send("wheelReport", { wheelY: invertedScrollValue });
+      }
 
       // Apply blocking delay based of throttling settings
       if (scrollThrottling && !blockWheelEvent) {
@@ -156,7 +161,7 @@ export default function useMouse() {
         setTimeout(() => setBlockWheelEvent(false), scrollThrottling);
       }
     },
-    [send, blockWheelEvent, scrollThrottling],
+    [send, reportWheelEvent, rpcHidReady, blockWheelEvent, scrollThrottling],
   );
 
   const resetMousePosition = useCallback(() => {
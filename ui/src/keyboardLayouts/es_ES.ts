@@ -9,7 +9,7 @@ const keyTrema: KeyCombo = { key: "Quote", shift: true } // tréma (umlaut), two
 const keyAcute: KeyCombo = { key: "Quote" } // accent aigu (acute accent), mark ´ placed above the letter
 const keyHat: KeyCombo = { key: "BracketRight", shift: true } // accent circonflexe (accent hat), mark ^ placed above the letter
 const keyGrave: KeyCombo = { key: "BracketRight" } // accent grave, mark ` placed above the letter
-const keyTilde: KeyCombo = { key: "Key4", altRight: true } // tilde, mark ~ placed above the letter
+const keyTilde: KeyCombo = { key: "Digit4", altRight: true } // tilde, mark ~ placed above the letter
 
 const chars = {
   A: { key: "KeyA", shift: true },
@@ -30,7 +30,6 @@ import notifications from "../notifications";
 import { isOnDevice } from "../main";
 import { cx } from "../cva.config";
 import {
-  MountMediaState,
   RemoteVirtualMediaState,
   useMountMediaStore,
   useRTCStore,
@@ -48,7 +47,7 @@ export function Dialog({ onClose }: { onClose: () => void }) {
   const {
     modalView,
     setModalView,
-    setRemoteVirtualMediaState,
+    setRemoteVirtualMediaStates,
     errorMessage,
     setErrorMessage,
   } = useMountMediaStore();
@@ -57,19 +56,17 @@ export function Dialog({ onClose }: { onClose: () => void }) {
   const [incompleteFileName, setIncompleteFileName] = useState<string | null>(null);
   const [mountInProgress, setMountInProgress] = useState(false);
   function clearMountMediaState() {
-    setRemoteVirtualMediaState(null);
+    setRemoteVirtualMediaStates([]);
   }
 
   const { send } = useJsonRpc();
   async function syncRemoteVirtualMediaState() {
     return new Promise((resolve, reject) => {
-      send("getVirtualMediaState", {}, (resp: JsonRpcResponse) => {
+      send("getVirtualMediaStates", {}, (resp: JsonRpcResponse) => {
         if ("error" in resp) {
           reject(new Error(resp.error.message));
         } else {
-          setRemoteVirtualMediaState(
-            resp as unknown as MountMediaState["remoteVirtualMediaState"],
-          );
+          setRemoteVirtualMediaStates(resp.result as unknown as RemoteVirtualMediaState[]);
           resolve(null);
         }
       });
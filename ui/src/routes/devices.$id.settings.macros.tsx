@@ -16,7 +16,7 @@ import { SettingsPageHeader } from "@/components/SettingsPageheader";
 import { Button } from "@/components/Button";
 import EmptyCard from "@/components/EmptyCard";
 import Card from "@/components/Card";
-import { MAX_TOTAL_MACROS, COPY_SUFFIX, DEFAULT_DELAY } from "@/constants/macros";
+import { COPY_SUFFIX, DEFAULT_DELAY } from "@/constants/macros";
 import notifications from "@/notifications";
 import { ConfirmDialog } from "@/components/ConfirmDialog";
 import LoadingSpinner from "@/components/LoadingSpinner";
@@ -30,7 +30,7 @@ const normalizeSortOrders = (macros: KeySequence[]): KeySequence[] => {
 };
 
 export default function SettingsMacrosRoute() {
-  const { macros, loading, initialized, loadMacros, saveMacros } = useMacrosStore();
+  const { macros, limits, loading, initialized, loadMacros, saveMacros } = useMacrosStore();
   const navigate = useNavigate();
   const [actionLoadingId, setActionLoadingId] = useState<string | null>(null);
   const [showDeleteConfirm, setShowDeleteConfirm] = useState(false);
@@ -38,8 +38,8 @@ export default function SettingsMacrosRoute() {
   const { selectedKeyboard }  = useKeyboardLayout();
 
   const isMaxMacrosReached = useMemo(
-    () => macros.length >= MAX_TOTAL_MACROS,
-    [macros.length],
+    () => macros.length >= limits.max_macros,
+    [macros.length, limits.max_macros],
   );
 
   useEffect(() => {
@@ -56,7 +56,7 @@ export default function SettingsMacrosRoute() {
       }
 
       if (isMaxMacrosReached) {
-        notifications.error(`Maximum of ${MAX_TOTAL_MACROS} macros allowed`);
+        notifications.error(`Maximum of ${limits.max_macros} macros allowed`);
         return;
       }
 
@@ -82,7 +82,7 @@ export default function SettingsMacrosRoute() {
         setActionLoadingId(null);
       }
     },
-    [isMaxMacrosReached, macros, saveMacros, setActionLoadingId],
+    [isMaxMacrosReached, limits.max_macros, macros, saveMacros, setActionLoadingId],
   );
 
   const handleMoveMacro = useCallback(
@@ -10,19 +10,12 @@ import { JsonRpcResponse, useJsonRpc } from "@/hooks/useJsonRpc";
 import { SettingsItem } from "@components/SettingsItem";
 import { SettingsPageHeader } from "@components/SettingsPageheader";
 import { SelectMenuBasic } from "@components/SelectMenuBasic";
-import { JigglerSetting } from "@components/JigglerSetting";
+import { JigglerConfig, JigglerSetting } from "@components/JigglerSetting";
 
 import { cx } from "../cva.config";
 import notifications from "../notifications";
 import SettingsNestedSection from "../components/SettingsNestedSection";
 
-export interface JigglerConfig {
-  inactivity_limit_seconds: number;
-  jitter_percentage: number;
-  schedule_cron_tab: string;
-  timezone?: string;
-}
-
 const jigglerOptions = [
   { value: "disabled", label: "Disabled", config: null },
   {
@@ -62,6 +55,11 @@ const jigglerOptions = [
 
 type JigglerValues = (typeof jigglerOptions)[number]["value"] | "custom";
 
+interface HidCoalescing {
+  pointer: boolean;
+  wheel: boolean;
+}
+
 export default function SettingsMouseRoute() {
   const {
     isCursorHidden, setCursorVisibility,
@@ -85,6 +83,28 @@ export default function SettingsMouseRoute() {
 
   const { send } = useJsonRpc();
 
+  const [hidCoalescing, setHidCoalescing] = useState<HidCoalescing | null>(null);
+
+  useEffect(() => {
+    send("getHidCoalescing", {}, (resp: JsonRpcResponse) => {
+      if ("error" in resp) return;
+      setHidCoalescing(resp.result as HidCoalescing);
+    });
+  }, [send]);
+
+  const handleHidCoalescingChange = (enabled: boolean) => {
+    const options = { pointer: enabled, wheel: enabled };
+    send("setHidCoalescing", { options }, (resp: JsonRpcResponse) => {
+      if ("error" in resp) {
+        notifications.error(
+          `Failed to set pointer coalescing: ${resp.error.data || "Unknown error"}`,
+        );
+        return;
+      }
+      setHidCoalescing(options);
+    });
+  };
+
   const syncJigglerSettings = useCallback(() => {
     send("getJigglerState", {}, (resp: JsonRpcResponse) => {
       if ("error" in resp) return;
@@ -213,6 +233,16 @@ export default function SettingsMouseRoute() {
           />
         </SettingsItem>
 
+        <SettingsItem
+          title="Coalesce Pointer Updates"
+          description="Skip stale mouse movements and merge scrolling when the target is slow to keep up"
+        >
+          <Checkbox
+            checked={!!hidCoalescing && (hidCoalescing.pointer || hidCoalescing.wheel)}
+            onChange={e => handleHidCoalescingChange(e.target.checked)}
+          />
+        </SettingsItem>
+
         <SettingsItem title="Jiggler" description="Simulate movement of a computer mouse">
           <SelectMenuBasic
             size="SM"
@@ -40,6 +40,8 @@ const ConnectionStatsSidebar = lazy(() => import('@/components/sidebar/connectio
 const Terminal = lazy(() => import('@components/Terminal'));
 const UpdateInProgressStatusCard = lazy(() => import("@/components/UpdateInProgressStatusCard"));
 import Modal from "@/components/Modal";
+import { FidoPresencePrompt } from "@/components/FidoPresencePrompt";
+import { HostAudio } from "@/components/HostAudio";
 import { JsonRpcRequest, JsonRpcResponse, RpcMethodNotFound, useJsonRpc } from "@/hooks/useJsonRpc";
 import {
   ConnectionFailedOverlay,
@@ -133,6 +135,7 @@ export default function KvmIdRoute() {
     isTurnServerInUse, setTurnServerInUse,
     rpcDataChannel,
     setTransceiver,
+    setAudioTransceiver,
     setRpcHidChannel,
     setRpcHidUnreliableNonOrderedChannel,
     setRpcHidUnreliableChannel,
@@ -476,6 +479,8 @@ export default function KvmIdRoute() {
     };
 
     setTransceiver(pc.addTransceiver("video", { direction: "recvonly" }));
+    // sendrecv so the microphone can be attached later without renegotiating
+    setAudioTransceiver(pc.addTransceiver("audio", { direction: "sendrecv" }));
 
     const rpcDataChannel = pc.createDataChannel("rpc");
     rpcDataChannel.onopen = () => {
@@ -520,6 +525,7 @@ export default function KvmIdRoute() {
     setRpcHidUnreliableNonOrderedChannel,
     setRpcHidUnreliableChannel,
     setTransceiver,
+    setAudioTransceiver,
   ]);
 
   useEffect(() => {
@@ -872,6 +878,9 @@ export default function KvmIdRoute() {
         </Modal>
       </div>
 
+      <FidoPresencePrompt />
+      <HostAudio />
+
       {kvmTerminal && (
         <Terminal type="kvm" dataChannel={kvmTerminal} title="KVM Terminal" />
       )}
@@ -1,8 +1,8 @@
 package kvm
 
 import (
+	"slices"
 	"sync"
-	"time"
 
 	"github.com/jetkvm/kvm/internal/usbgadget"
 )
@@ -12,19 +12,21 @@ var gadget *usbgadget.UsbGadget
 // initUsbGadget initializes the USB gadget.
 // call it only after the config is loaded.
 func initUsbGadget() {
+	usbConfig := *config.UsbConfig
+	usbConfig.Network = getUsbGadgetNetworkConfig()
+	usbConfig.Identity = getUsbIdentityPreset(config.UsbIdentityPreset)
+	remoteWakeup := config.UsbRemoteWakeup
+	usbConfig.RemoteWakeup = &remoteWakeup
+
 	gadget = usbgadget.NewUsbGadget(
 		"jetkvm",
 		config.UsbDevices,
-		config.UsbConfig,
+		&usbConfig,
 		usbLogger,
 	)
 
-	go func() {
-		for {
-			checkUSBState()
-			time.Sleep(500 * time.Millisecond)
-		}
-	}()
+	gadget.SetOnUsbStateChange(onUsbStateChange)
+	gadget.StartUsbStateMonitor()
 
 	gadget.SetOnKeyboardStateChange(func(state usbgadget.KeyboardState) {
 		if currentSession != nil {
@@ -34,10 +36,15 @@ func initUsbGadget() {
 
 	gadget.SetOnKeysDownChange(func(state usbgadget.KeysDownState) {
 		if currentSession != nil {
-			currentSession.enqueueKeysDownState(state)
+			currentSession.enqueueKeysDownState(unmapKeysDownState(state))
 		}
 	})
 
+	gadget.SetOnAutoRelease(func(key byte) {
+		// the client stopped sending keep-alives, the keys it pressed don't need to be unmapped anymore
+		keyRemapper.Reset()
+	})
+
 	gadget.SetOnKeepAliveReset(func() {
 		if currentSession != nil {
 			currentSession.resetKeepAliveTime()
@@ -51,10 +58,16 @@ func initUsbGadget() {
 }
 
 func rpcKeyboardReport(modifier byte, keys []byte) error {
+	if modifier != 0 || slices.ContainsFunc(keys, func(k byte) bool { return k != 0 }) {
+		wakeUpHostOnKeyPress()
+	}
 	return gadget.KeyboardReport(modifier, keys)
 }
 
 func rpcKeypressReport(key byte, press bool) error {
+	if press {
+		wakeUpHostOnKeyPress()
+	}
 	return gadget.KeypressReport(key, press)
 }
 
@@ -75,7 +88,7 @@ func rpcGetKeyboardLedState() (state usbgadget.KeyboardState) {
 }
 
 func rpcGetKeysDownState() (state usbgadget.KeysDownState) {
-	return gadget.GetKeysDownState()
+	return unmapKeysDownState(gadget.GetKeysDownState())
 }
 
 var (
@@ -87,6 +100,19 @@ func rpcGetUSBState() (state string) {
 	return gadget.GetUsbState()
 }
 
+func rpcGetUSBStateHistory() ([]usbgadget.UsbStateTransition, error) {
+	return gadget.GetUsbStateHistory(), nil
+}
+
+func triggerUSBStateTransitionUpdate(transition usbgadget.UsbStateTransition) {
+	go func() {
+		if currentSession == nil {
+			return
+		}
+		writeJSONRPCEvent("usbStateTransition", transition, currentSession)
+	}()
+}
+
 func triggerUSBStateUpdate() {
 	go func() {
 		if currentSession == nil {
@@ -97,21 +123,92 @@ func triggerUSBStateUpdate() {
 	}()
 }
 
-func checkUSBState() {
+func onUsbStateChange(transition usbgadget.UsbStateTransition) {
 	usbStateLock.Lock()
-	defer usbStateLock.Unlock()
+	usbState = string(transition.To)
+	usbStateLock.Unlock()
 
-	newState := gadget.GetUsbState()
+	handleUsbSuspendTransition(transition)
 
-	usbLogger.Trace().Str("old", usbState).Str("new", newState).Msg("Checking USB state")
+	requestDisplayUpdate(true, "usb_state_changed")
+	triggerUSBStateUpdate()
+	triggerUSBStateTransitionUpdate(transition)
+}
 
-	if newState == usbState {
-		return
+// usbService runs on top of a USB function. It's only restarted when its own settings change,
+// so an unrelated USB change doesn't interrupt it.
+type usbService struct {
+	name string
+	// settings returns a comparable value of everything the service depends on
+	settings func() any
+	init     func()
+	restart  func() error
+
+	applied any
+}
+
+var (
+	usbServicesLock sync.Mutex
+	usbServices     = []*usbService{
+		{
+			name: "USB network",
+			settings: func() any {
+				return struct {
+					Enabled bool
+					Config  UsbNetworkConfig
+				}{config.UsbDevices.Network, *config.UsbNetworkConfig}
+			},
+			init:    initUsbNetwork,
+			restart: restartUsbNetwork,
+		},
+		{
+			name:     "FIDO authenticator",
+			settings: func() any { return config.UsbDevices.Fido },
+			init:     initFido,
+			restart:  restartFido,
+		},
+		{
+			name:     "printer",
+			settings: func() any { return config.UsbDevices.Printer },
+			init:     initPrinter,
+			restart:  restartPrinter,
+		},
+		{
+			name:     "audio",
+			settings: func() any { return [2]bool{config.UsbDevices.Audio, config.UsbDevices.AudioMicrophone} },
+			init:     initAudio,
+			restart:  restartAudio,
+		},
 	}
+)
 
-	usbState = newState
-	usbLogger.Info().Str("from", usbState).Str("to", newState).Msg("USB state changed")
+func initUsbServices() {
+	usbServicesLock.Lock()
+	defer usbServicesLock.Unlock()
 
-	requestDisplayUpdate(true, "usb_state_changed")
-	triggerUSBStateUpdate()
+	for _, s := range usbServices {
+		s.applied = s.settings()
+		s.init()
+	}
+}
+
+// restartChangedUsbServices restarts the services whose settings changed in the background,
+// starting a service can take a while and shouldn't block the RPC.
+func restartChangedUsbServices() {
+	usbServicesLock.Lock()
+	defer usbServicesLock.Unlock()
+
+	for _, s := range usbServices {
+		settings := s.settings()
+		if settings == s.applied {
+			continue
+		}
+		s.applied = settings
+
+		go func() {
+			if err := s.restart(); err != nil {
+				logger.Warn().Err(err).Str("service", s.name).Msg("failed to restart USB service")
+			}
+		}()
+	}
 }
@@ -0,0 +1,247 @@
+package kvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jetkvm/kvm/internal/audio"
+	"github.com/jetkvm/kvm/internal/usbgadget"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+const (
+	// Opus frames are 20ms, the ALSA period matches so every read is one frame
+	audioFrameDuration = 20 * time.Millisecond
+	audioFrameSize     = usbgadget.AudioSampleRate / 50
+	audioPeriods       = 4
+	audioBitrate       = 128000
+	// the largest packet libopus recommends, decoded packets can be up to 120ms
+	audioMaxPacketSize   = 4000
+	audioMaxDecodeFrames = usbgadget.AudioSampleRate * 120 / 1000
+
+	audioDeviceWait = 10 * time.Second
+	audioRetryDelay = time.Second
+	audioCardDevice = 0
+)
+
+var (
+	audioLock   sync.Mutex
+	audioCancel context.CancelFunc
+	audioDone   chan struct{}
+
+	// only one session at a time can play back into the host's microphone
+	microphoneLock sync.Mutex
+)
+
+var audioParams = audio.Params{
+	Rate:         usbgadget.AudioSampleRate,
+	Channels:     usbgadget.AudioChannels,
+	PeriodFrames: audioFrameSize,
+	Periods:      audioPeriods,
+}
+
+func newAudioTrack() (*webrtc.TrackLocalStaticSample, error) {
+	return webrtc.NewTrackLocalStaticSample(webrtc.RTPCodecCapability{
+		MimeType:  webrtc.MimeTypeOpus,
+		ClockRate: usbgadget.AudioSampleRate,
+		Channels:  usbgadget.AudioChannels,
+	}, "audio", "kvm")
+}
+
+func isUsbAudioEnabled() bool {
+	return config.UsbDevices != nil && config.UsbDevices.Audio
+}
+
+func waitAudioCard(ctx context.Context) (int, error) {
+	deadline := time.Now().Add(audioDeviceWait)
+	for {
+		card, err := usbgadget.AudioCardNumber()
+		if err == nil {
+			return card, nil
+		}
+		if time.Now().After(deadline) {
+			return 0, err
+		}
+		select {
+		case <-time.After(500 * time.Millisecond):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// captureHostAudio encodes what the host plays on the USB sound card and writes it to the current session.
+func captureHostAudio(ctx context.Context) error {
+	card, err := waitAudioCard(ctx)
+	if err != nil {
+		return err
+	}
+	pcm, err := audio.OpenPCM(card, audioCardDevice, true, audioParams)
+	if err != nil {
+		return err
+	}
+	defer pcm.Close()
+
+	encoder, err := audio.NewEncoder(usbgadget.AudioSampleRate, usbgadget.AudioChannels, audioBitrate)
+	if err != nil {
+		return err
+	}
+	defer encoder.Close()
+
+	audioLogger.Info().Int("card", card).Msg("capturing host audio")
+
+	samples := make([]int16, audioFrameSize*usbgadget.AudioChannels)
+	packet := make([]byte, audioMaxPacketSize)
+	for ctx.Err() == nil {
+		// keep reading even without a session, the stream overruns otherwise
+		n, err := pcm.Read(samples)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			// the host isn't playing anything
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		session := currentSession
+		if session == nil || session.AudioTrack == nil || n < audioFrameSize {
+			continue
+		}
+
+		size, err := encoder.Encode(samples, packet)
+		if err != nil {
+			return err
+		}
+		if err := session.AudioTrack.WriteSample(media.Sample{Data: packet[:size], Duration: audioFrameDuration}); err != nil {
+			audioLogger.Warn().Err(err).Msg("error writing audio sample")
+		}
+	}
+	return nil
+}
+
+func streamHostAudio(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	for ctx.Err() == nil {
+		err := captureHostAudio(ctx)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, audio.ErrOpusUnavailable) {
+			audioLogger.Warn().Err(err).Msg("audio streaming isn't supported by this build")
+			return
+		}
+		// the card goes away while the gadget is reconfigured
+		audioLogger.Warn().Err(err).Msg("failed to capture host audio, retrying")
+		select {
+		case <-time.After(audioRetryDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func stopAudio() {
+	if audioCancel == nil {
+		return
+	}
+	audioCancel()
+	<-audioDone
+	audioCancel = nil
+	audioDone = nil
+}
+
+// restartAudio starts streaming the host audio if the audio function is enabled.
+func restartAudio() error {
+	audioLock.Lock()
+	defer audioLock.Unlock()
+
+	stopAudio()
+
+	if !config.UsbDevices.Audio {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	audioCancel = cancel
+	audioDone = make(chan struct{})
+	go streamHostAudio(ctx, audioDone)
+	return nil
+}
+
+func initAudio() {
+	if !config.UsbDevices.Audio {
+		return
+	}
+
+	if err := restartAudio(); err != nil {
+		audioLogger.Warn().Err(err).Msg("failed to start audio")
+	}
+}
+
+// handleMicrophoneTrack plays the audio the browser sends back on the host's USB microphone.
+func handleMicrophoneTrack(track *webrtc.TrackRemote) {
+	if !config.UsbDevices.Audio || !config.UsbDevices.AudioMicrophone {
+		audioLogger.Debug().Msg("microphone passthrough is disabled, ignoring audio track")
+		return
+	}
+	if !microphoneLock.TryLock() {
+		audioLogger.Warn().Msg("microphone is already in use by another session")
+		return
+	}
+	defer microphoneLock.Unlock()
+
+	if err := playMicrophoneTrack(track); err != nil {
+		audioLogger.Warn().Err(err).Msg("microphone passthrough stopped")
+	}
+}
+
+func playMicrophoneTrack(track *webrtc.TrackRemote) error {
+	card, err := usbgadget.AudioCardNumber()
+	if err != nil {
+		return err
+	}
+	pcm, err := audio.OpenPCM(card, audioCardDevice, false, audioParams)
+	if err != nil {
+		return err
+	}
+	defer pcm.Close()
+
+	decoder, err := audio.NewDecoder(usbgadget.AudioSampleRate, usbgadget.AudioChannels)
+	if err != nil {
+		return err
+	}
+	defer decoder.Close()
+
+	audioLogger.Info().Int("card", card).Msg("playing microphone audio")
+
+	samples := make([]int16, audioMaxDecodeFrames*usbgadget.AudioChannels)
+	for {
+		packet, _, err := track.ReadRTP()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read audio track: %w", err)
+		}
+		if len(packet.Payload) == 0 {
+			continue
+		}
+
+		n, err := decoder.Decode(packet.Payload, samples)
+		if err != nil {
+			audioLogger.Trace().Err(err).Msg("failed to decode microphone audio")
+			continue
+		}
+		// a timeout means the host isn't recording, the samples are dropped
+		if _, err := pcm.Write(samples[:n*usbgadget.AudioChannels]); err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+			return err
+		}
+	}
+}
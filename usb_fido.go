@@ -0,0 +1,212 @@
+package kvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jetkvm/kvm/internal/fido"
+)
+
+const (
+	fidoFolder    = "/userdata/jetkvm/fido"
+	fidoStorePath = fidoFolder + "/credentials"
+
+	fidoWriteTimeout = 100 * time.Millisecond
+)
+
+var (
+	fidoLock   sync.Mutex
+	fidoStore  *fido.Store
+	fidoDevice *os.File
+	fidoCancel context.CancelFunc
+
+	fidoPresenceLock     sync.Mutex
+	fidoPresenceRequests = make(map[string]chan bool)
+)
+
+// FidoPresenceRequest is sent to the session when the host asks the authenticator for user presence.
+type FidoPresenceRequest struct {
+	ID string `json:"id"`
+	fido.PresenceRequest
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// fidoHidDevice drops reports the host doesn't read in time instead of blocking the authenticator.
+type fidoHidDevice struct {
+	*os.File
+}
+
+func (d fidoHidDevice) Write(p []byte) (int, error) {
+	if err := d.SetWriteDeadline(time.Now().Add(fidoWriteTimeout)); err != nil {
+		return 0, err
+	}
+	return d.File.Write(p)
+}
+
+// getFidoStore opens the credential store on first use, fidoLock must be held.
+func getFidoStore() (*fido.Store, error) {
+	if fidoStore != nil {
+		return fidoStore, nil
+	}
+
+	store, err := fido.OpenStore(fidoStorePath)
+	if err != nil {
+		return nil, err
+	}
+	fidoStore = store
+	return store, nil
+}
+
+func stopFido() {
+	if fidoCancel == nil {
+		return
+	}
+	fidoCancel()
+	_ = fidoDevice.Close()
+	fidoCancel = nil
+	fidoDevice = nil
+}
+
+// restartFido starts the authenticator on the FIDO HID function if it's enabled.
+func restartFido() error {
+	fidoLock.Lock()
+	defer fidoLock.Unlock()
+
+	stopFido()
+
+	if !config.UsbDevices.Fido {
+		return nil
+	}
+
+	store, err := getFidoStore()
+	if err != nil {
+		return fmt.Errorf("failed to open FIDO credential store: %w", err)
+	}
+
+	devicePath, err := gadget.FidoDevicePath()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(devicePath, os.O_RDWR, 0666)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", devicePath, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fidoDevice = f
+	fidoCancel = cancel
+
+	server := fido.NewServer(fidoHidDevice{f}, fido.NewAuthenticator(store, requestFidoPresence), fidoLogger)
+	go func() {
+		if err := server.Serve(ctx); err != nil {
+			fidoLogger.Warn().Err(err).Str("device", devicePath).Msg("FIDO authenticator stopped")
+		}
+	}()
+
+	fidoLogger.Info().Str("device", devicePath).Msg("FIDO authenticator started")
+	return nil
+}
+
+func initFido() {
+	if !config.UsbDevices.Fido {
+		return
+	}
+
+	if err := restartFido(); err != nil {
+		fidoLogger.Warn().Err(err).Msg("failed to start FIDO authenticator")
+	}
+}
+
+// requestFidoPresence asks the controlling session to confirm the user presence,
+// there's no button to touch so the prompt is the only way to approve a request.
+func requestFidoPresence(ctx context.Context, req fido.PresenceRequest) (bool, error) {
+	session := currentSession
+	if session == nil {
+		return false, errors.New("no session to confirm the user presence")
+	}
+
+	id := uuid.New().String()
+	result := make(chan bool, 1)
+
+	fidoPresenceLock.Lock()
+	fidoPresenceRequests[id] = result
+	fidoPresenceLock.Unlock()
+
+	defer func() {
+		fidoPresenceLock.Lock()
+		delete(fidoPresenceRequests, id)
+		fidoPresenceLock.Unlock()
+	}()
+
+	expiresAt, _ := ctx.Deadline()
+	fidoLogger.Info().Str("id", id).Str("operation", req.Operation).Str("rp_id", req.RPID).Msg("requesting user presence")
+	writeJSONRPCEvent("fidoPresenceRequest", FidoPresenceRequest{
+		ID:              id,
+		PresenceRequest: req,
+		ExpiresAt:       expiresAt,
+	}, session)
+
+	select {
+	case approved := <-result:
+		fidoLogger.Info().Str("id", id).Bool("approved", approved).Msg("user presence confirmed")
+		return approved, nil
+	case <-ctx.Done():
+		// let the session close the prompt
+		writeJSONRPCEvent("fidoPresenceDone", map[string]string{"id": id}, session)
+		return false, ctx.Err()
+	}
+}
+
+func rpcConfirmFidoPresence(id string, approved bool) error {
+	fidoPresenceLock.Lock()
+	defer fidoPresenceLock.Unlock()
+
+	result, ok := fidoPresenceRequests[id]
+	if !ok {
+		return fmt.Errorf("presence request %s not found or expired", id)
+	}
+	select {
+	case result <- approved:
+	default:
+		return fmt.Errorf("presence request %s already answered", id)
+	}
+	return nil
+}
+
+func rpcGetFidoCredentials() ([]fido.CredentialInfo, error) {
+	fidoLock.Lock()
+	defer fidoLock.Unlock()
+
+	store, err := getFidoStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.List(), nil
+}
+
+func rpcDeleteFidoCredential(id string) error {
+	fidoLock.Lock()
+	defer fidoLock.Unlock()
+
+	store, err := getFidoStore()
+	if err != nil {
+		return err
+	}
+	return store.Delete(id)
+}
+
+func rpcResetFidoCredentials() error {
+	fidoLock.Lock()
+	defer fidoLock.Unlock()
+
+	store, err := getFidoStore()
+	if err != nil {
+		return err
+	}
+	return store.Reset()
+}
@@ -0,0 +1,91 @@
+package kvm
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/jetkvm/kvm/internal/usbgadget"
+)
+
+// getUsbIdentityPreset returns the user-defined or built-in preset with the given name,
+// nil means the default identity.
+func getUsbIdentityPreset(name string) *usbgadget.IdentityPreset {
+	if name == "" || name == usbgadget.DefaultIdentityPresetName {
+		return nil
+	}
+	for i := range config.UsbIdentityPresets {
+		if config.UsbIdentityPresets[i].Name == name {
+			return &config.UsbIdentityPresets[i]
+		}
+	}
+	return usbgadget.GetBuiltinIdentityPreset(name)
+}
+
+func rpcGetIdentityPresets() ([]usbgadget.IdentityPreset, error) {
+	presets := slices.Clone(usbgadget.GetBuiltinIdentityPresets())
+	return append(presets, config.UsbIdentityPresets...), nil
+}
+
+func rpcGetIdentityPreset() (string, error) {
+	if config.UsbIdentityPreset == "" {
+		return usbgadget.DefaultIdentityPresetName, nil
+	}
+	return config.UsbIdentityPreset, nil
+}
+
+func applyUsbIdentityPreset(name string) error {
+	preset := getUsbIdentityPreset(name)
+	if preset == nil && name != "" && name != usbgadget.DefaultIdentityPresetName {
+		return fmt.Errorf("identity preset %s not found", name)
+	}
+
+	if err := gadget.SetIdentityPreset(preset); err != nil {
+		return fmt.Errorf("invalid identity preset %s: %w", name, err)
+	}
+	config.UsbIdentityPreset = name
+	return updateUsbRelatedConfig()
+}
+
+func rpcSetIdentityPreset(name string) error {
+	logger.Info().Str("name", name).Msg("Setting USB identity preset")
+	return applyUsbIdentityPreset(name)
+}
+
+// rpcSaveIdentityPreset adds or replaces a user-defined preset, it's re-applied if it's the active one.
+func rpcSaveIdentityPreset(preset usbgadget.IdentityPreset) error {
+	if usbgadget.GetBuiltinIdentityPreset(preset.Name) != nil {
+		return fmt.Errorf("%s is a built-in identity preset", preset.Name)
+	}
+	if err := gadget.ValidateIdentityPreset(&preset); err != nil {
+		return err
+	}
+
+	index := slices.IndexFunc(config.UsbIdentityPresets, func(p usbgadget.IdentityPreset) bool {
+		return p.Name == preset.Name
+	})
+	if index >= 0 {
+		config.UsbIdentityPresets[index] = preset
+	} else {
+		config.UsbIdentityPresets = append(config.UsbIdentityPresets, preset)
+	}
+
+	if config.UsbIdentityPreset == preset.Name {
+		return applyUsbIdentityPreset(preset.Name)
+	}
+	return SaveConfig()
+}
+
+func rpcDeleteIdentityPreset(name string) error {
+	if config.UsbIdentityPreset == name {
+		return fmt.Errorf("identity preset %s is in use", name)
+	}
+
+	index := slices.IndexFunc(config.UsbIdentityPresets, func(p usbgadget.IdentityPreset) bool {
+		return p.Name == name
+	})
+	if index < 0 {
+		return fmt.Errorf("identity preset %s not found", name)
+	}
+	config.UsbIdentityPresets = slices.Delete(config.UsbIdentityPresets, index, index+1)
+	return SaveConfig()
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/pion/webrtc/v4"
 	"github.com/psanford/httpreadat"
 
+	"github.com/jetkvm/kvm/internal/cowimage"
+	"github.com/jetkvm/kvm/internal/usbgadget"
 	"github.com/jetkvm/kvm/resource"
 )
 
@@ -26,8 +28,19 @@ func writeFile(path string, data string) error {
 	return os.WriteFile(path, []byte(data), 0644)
 }
 
-func getMassStorageImage() (string, error) {
-	massStorageFunctionPath, err := gadget.GetPath("mass_storage_lun0")
+func getMassStorageLunCount() int {
+	return config.UsbDevices.MassStorageLunCount()
+}
+
+func validateMassStorageLun(lun int) error {
+	if lun < 0 || lun >= getMassStorageLunCount() {
+		return fmt.Errorf("invalid LUN %d, %d LUN(s) enabled", lun, getMassStorageLunCount())
+	}
+	return nil
+}
+
+func getMassStorageImage(lun int) (string, error) {
+	massStorageFunctionPath, err := gadget.GetPath(usbgadget.MassStorageLunKey(lun))
 	if err != nil {
 		return "", fmt.Errorf("failed to get mass storage path: %w", err)
 	}
@@ -39,8 +52,8 @@ func getMassStorageImage() (string, error) {
 	return strings.TrimSpace(string(imagePath)), nil
 }
 
-func setMassStorageImage(imagePath string) error {
-	massStorageFunctionPath, err := gadget.GetPath("mass_storage_lun0")
+func setMassStorageImage(lun int, imagePath string) error {
+	massStorageFunctionPath, err := gadget.GetPath(usbgadget.MassStorageLunKey(lun))
 	if err != nil {
 		return fmt.Errorf("failed to get mass storage path: %w", err)
 	}
@@ -51,42 +64,61 @@ func setMassStorageImage(imagePath string) error {
 	return nil
 }
 
-func setMassStorageMode(cdrom bool) error {
-	mode := "0"
-	if cdrom {
-		mode = "1"
+func boolToAttr(b bool) string {
+	if b {
+		return "1"
 	}
+	return "0"
+}
 
-	err, changed := gadget.OverrideGadgetConfig("mass_storage_lun0", "cdrom", mode)
+// setMassStorageMode sets the mode of a LUN without media. Only the attributes of the LUN are written,
+// reconfiguring the whole gadget would disconnect the media of the other LUNs.
+func setMassStorageMode(lun int, cdrom bool, readOnly bool) error {
+	key := usbgadget.MassStorageLunKey(lun)
+	massStorageFunctionPath, err := gadget.GetPath(key)
 	if err != nil {
-		return fmt.Errorf("failed to set cdrom mode: %w", err)
+		return fmt.Errorf("failed to get mass storage path: %w", err)
 	}
 
-	if !changed {
-		return nil
+	// cdrom goes first, the kernel makes a CD-ROM read-only
+	attrs := []struct{ name, value string }{
+		{"cdrom", boolToAttr(cdrom)},
+		{"ro", boolToAttr(readOnly || cdrom)},
 	}
+	for _, attr := range attrs {
+		// the gadget config keeps the mode for the next reconfiguration
+		if err, _ := gadget.OverrideGadgetConfig(key, attr.name, attr.value); err != nil {
+			return fmt.Errorf("failed to set %s mode: %w", attr.name, err)
+		}
 
-	return gadget.UpdateGadgetConfig()
+		attrPath := path.Join(massStorageFunctionPath, attr.name)
+		current, err := os.ReadFile(attrPath)
+		if err == nil && strings.TrimSpace(string(current)) == attr.value {
+			continue
+		}
+		if err := writeFile(attrPath, attr.value); err != nil {
+			return fmt.Errorf("failed to set %s mode: %w", attr.name, err)
+		}
+	}
+	return nil
 }
 
-func mountImage(imagePath string) error {
-	err := setMassStorageImage("")
+func mountImage(lun int, imagePath string) error {
+	err := setMassStorageImage(lun, "")
 	if err != nil {
 		return fmt.Errorf("remove mass storage image error: %w", err)
 	}
-	err = setMassStorageImage(imagePath)
+	err = setMassStorageImage(lun, imagePath)
 	if err != nil {
 		return fmt.Errorf("set mass storage image error: %w", err)
 	}
-	err = setMassStorageImage(imagePath)
+	err = setMassStorageImage(lun, imagePath)
 	if err != nil {
 		return fmt.Errorf("set Mass Storage Image Error: %w", err)
 	}
 	return nil
 }
 
-var nbdDevice *NBDDevice
-
 const imagesFolder = "/userdata/jetkvm/images"
 
 func initImagesFolder() error {
@@ -98,7 +130,14 @@ func initImagesFolder() error {
 }
 
 func rpcMountBuiltInImage(filename string) error {
-	logger.Info().Str("filename", filename).Msg("Mount Built-In Image")
+	return rpcMountLunBuiltInImage(0, filename)
+}
+
+func rpcMountLunBuiltInImage(lun int, filename string) error {
+	if err := validateMassStorageLun(lun); err != nil {
+		return err
+	}
+	logger.Info().Int("lun", lun).Str("filename", filename).Msg("Mount Built-In Image")
 	if err := initImagesFolder(); err != nil {
 		return err
 	}
@@ -107,7 +146,7 @@ func rpcMountBuiltInImage(filename string) error {
 
 	// Check if the file exists in the imagesFolder
 	if _, err := os.Stat(imagePath); err == nil {
-		return mountImage(imagePath)
+		return mountImage(lun, imagePath)
 	}
 
 	// If not, try to find it in ResourceFS
@@ -131,11 +170,11 @@ func rpcMountBuiltInImage(filename string) error {
 	}
 
 	// Mount the newly created image
-	return mountImage(imagePath)
+	return mountImage(lun, imagePath)
 }
 
-func getMassStorageCDROMEnabled() (bool, error) {
-	massStorageFunctionPath, err := gadget.GetPath("mass_storage_lun0")
+func getMassStorageCDROMEnabled(lun int) (bool, error) {
+	massStorageFunctionPath, err := gadget.GetPath(usbgadget.MassStorageLunKey(lun))
 	if err != nil {
 		return false, fmt.Errorf("failed to get mass storage path: %w", err)
 	}
@@ -173,53 +212,124 @@ const (
 )
 
 type VirtualMediaState struct {
+	Lun      int                `json:"lun"`
 	Source   VirtualMediaSource `json:"source"`
 	Mode     VirtualMediaMode   `json:"mode"`
 	Filename string             `json:"filename,omitempty"`
 	URL      string             `json:"url,omitempty"`
 	Size     int64              `json:"size"`
+	Writable bool               `json:"writable"`
+	// Overlay is set when the writes go to a copy-on-write overlay instead of the image itself
+	Overlay     bool  `json:"overlay"`
+	OverlaySize int64 `json:"overlaySize,omitempty"`
+}
+
+// virtualMediaLun holds everything that's mounted on a single LUN.
+type virtualMediaLun struct {
+	state           *VirtualMediaState
+	nbdDevice       *NBDDevice
+	httpRangeReader *httpreadat.RangeReader
+	overlay         *cowimage.Image
 }
 
-var currentVirtualMediaState *VirtualMediaState
+var virtualMediaLuns [usbgadget.MaxMassStorageLuns]virtualMediaLun
 var virtualMediaStateMutex sync.RWMutex
 
+// rpcGetVirtualMediaState returns the state of LUN 0, nil if nothing is mounted.
 func rpcGetVirtualMediaState() (*VirtualMediaState, error) {
+	states, err := rpcGetVirtualMediaStates()
+	if err != nil {
+		return nil, err
+	}
+	for i := range states {
+		if states[i].Lun == 0 {
+			return &states[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// rpcGetVirtualMediaStates returns the state of all LUNs with mounted media.
+func rpcGetVirtualMediaStates() ([]VirtualMediaState, error) {
 	virtualMediaStateMutex.RLock()
 	defer virtualMediaStateMutex.RUnlock()
-	return currentVirtualMediaState, nil
+
+	states := make([]VirtualMediaState, 0)
+	for _, l := range virtualMediaLuns {
+		if l.state == nil {
+			continue
+		}
+		state := *l.state
+		if l.overlay != nil {
+			state.OverlaySize = l.overlay.DirtyBytes()
+		}
+		states = append(states, state)
+	}
+	return states, nil
 }
 
-func rpcUnmountImage() error {
+func isVirtualMediaMounted(lun int) bool {
+	virtualMediaStateMutex.RLock()
+	defer virtualMediaStateMutex.RUnlock()
+	return virtualMediaLuns[lun].state != nil
+}
+
+func unmountImage(lun int) {
 	virtualMediaStateMutex.Lock()
 	defer virtualMediaStateMutex.Unlock()
-	err := setMassStorageImage("\n")
+	err := setMassStorageImage(lun, "\n")
 	if err != nil {
-		logger.Warn().Err(err).Msg("Remove Mass Storage Image Error")
+		logger.Warn().Err(err).Int("lun", lun).Msg("Remove Mass Storage Image Error")
 	}
 	//TODO: check if we still need it
 	time.Sleep(500 * time.Millisecond)
-	if nbdDevice != nil {
-		nbdDevice.Close()
-		nbdDevice = nil
+	if virtualMediaLuns[lun].nbdDevice != nil {
+		virtualMediaLuns[lun].nbdDevice.Close()
+	}
+	hadOverlay := virtualMediaLuns[lun].overlay != nil
+	if hadOverlay {
+		if err := virtualMediaLuns[lun].overlay.Close(); err != nil {
+			logger.Warn().Err(err).Int("lun", lun).Msg("failed to close overlay")
+		}
+	}
+	virtualMediaLuns[lun] = virtualMediaLun{}
+	if hadOverlay {
+		saveOverlayMounts()
 	}
-	currentVirtualMediaState = nil
+}
+
+func rpcUnmountImage() error {
+	return rpcUnmountLunImage(0)
+}
+
+func rpcUnmountLunImage(lun int) error {
+	if err := validateMassStorageLun(lun); err != nil {
+		return err
+	}
+	unmountImage(lun)
 	return nil
 }
 
-var httpRangeReader *httpreadat.RangeReader
+// unmountAllImages unmounts the media of every enabled LUN.
+func unmountAllImages() {
+	for lun := 0; lun < getMassStorageLunCount(); lun++ {
+		unmountImage(lun)
+	}
+}
 
-func getInitialVirtualMediaState() (*VirtualMediaState, error) {
-	cdromEnabled, err := getMassStorageCDROMEnabled()
+func getInitialVirtualMediaState(lun int) (*VirtualMediaState, error) {
+	cdromEnabled, err := getMassStorageCDROMEnabled(lun)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mass storage cdrom enabled: %w", err)
 	}
 
-	diskPath, err := getMassStorageImage()
+	diskPath, err := getMassStorageImage(lun)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mass storage image: %w", err)
 	}
 
 	initialState := &VirtualMediaState{
+		Lun:    lun,
 		Source: Storage,
 		Mode:   Disk,
 	}
@@ -228,10 +338,20 @@ func getInitialVirtualMediaState() (*VirtualMediaState, error) {
 		initialState.Mode = CDROM
 	}
 
-	switch diskPath {
-	case "":
+	switch {
+	case diskPath == "":
 		return nil, nil
-	case "/dev/nbd0":
+	case strings.HasPrefix(diskPath, "/dev/nbd") && loadOverlayMount(lun) != "":
+		// the NBD server went away with the previous process, serve the overlay again
+		initialState.Filename = loadOverlayMount(lun)
+		initialState.Writable = true
+		initialState.Overlay = true
+		info, err := os.Stat(filepath.Join(imagesFolder, initialState.Filename))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file info: %w", err)
+		}
+		initialState.Size = info.Size()
+	case strings.HasPrefix(diskPath, "/dev/nbd"):
 		initialState.Source = HTTP
 		initialState.URL = "/"
 		initialState.Size = 1
@@ -252,61 +372,98 @@ func getInitialVirtualMediaState() (*VirtualMediaState, error) {
 func setInitialVirtualMediaState() error {
 	virtualMediaStateMutex.Lock()
 	defer virtualMediaStateMutex.Unlock()
-	initialState, err := getInitialVirtualMediaState()
-	if err != nil {
-		return fmt.Errorf("failed to get initial virtual media state: %w", err)
-	}
-	currentVirtualMediaState = initialState
+	for lun := 0; lun < getMassStorageLunCount(); lun++ {
+		initialState, err := getInitialVirtualMediaState(lun)
+		if err != nil {
+			return fmt.Errorf("failed to get initial virtual media state of LUN %d: %w", lun, err)
+		}
+		if initialState != nil && initialState.Overlay {
+			// eject the device of the previous process before serving it again
+			if err := setMassStorageImage(lun, "\n"); err != nil {
+				logger.Warn().Err(err).Int("lun", lun).Msg("failed to eject media")
+			}
+			if err := startOverlayMount(lun, initialState); err != nil {
+				logger.Warn().Err(err).Int("lun", lun).Msg("failed to mount image with overlay again")
+				_ = setMassStorageImage(lun, "\n")
+				initialState = nil
+			}
+		}
+		virtualMediaLuns[lun].state = initialState
 
-	logger.Info().Interface("initial_virtual_media_state", initialState).Msg("initial virtual media state set")
+		logger.Info().Int("lun", lun).Interface("initial_virtual_media_state", initialState).Msg("initial virtual media state set")
+	}
 	return nil
 }
 
 func rpcMountWithHTTP(url string, mode VirtualMediaMode) error {
+	return rpcMountLunWithHTTP(0, url, mode)
+}
+
+func rpcMountLunWithHTTP(lun int, url string, mode VirtualMediaMode) error {
+	if err := validateMassStorageLun(lun); err != nil {
+		return err
+	}
+
 	virtualMediaStateMutex.Lock()
-	if currentVirtualMediaState != nil {
+	if virtualMediaLuns[lun].state != nil {
 		virtualMediaStateMutex.Unlock()
-		return fmt.Errorf("another virtual media is already mounted")
+		return fmt.Errorf("another virtual media is already mounted on LUN %d", lun)
 	}
-	httpRangeReader = httpreadat.New(url)
+	httpRangeReader := httpreadat.New(url)
 	n, err := httpRangeReader.Size()
 	if err != nil {
 		virtualMediaStateMutex.Unlock()
 		return fmt.Errorf("failed to use http url: %w", err)
 	}
-	logger.Info().Str("url", url).Int64("size", n).Msg("using remote url")
+	logger.Info().Int("lun", lun).Str("url", url).Int64("size", n).Msg("using remote url")
 
-	if err := setMassStorageMode(mode == CDROM); err != nil {
+	if err := setMassStorageMode(lun, mode == CDROM, true); err != nil {
+		virtualMediaStateMutex.Unlock()
 		return fmt.Errorf("failed to set mass storage mode: %w", err)
 	}
 
-	currentVirtualMediaState = &VirtualMediaState{
-		Source: HTTP,
-		Mode:   mode,
-		URL:    url,
-		Size:   n,
+	nbdDevice := NewNBDDevice(lun, &remoteImageBackend{lun: lun}, true)
+	virtualMediaLuns[lun] = virtualMediaLun{
+		state: &VirtualMediaState{
+			Lun:    lun,
+			Source: HTTP,
+			Mode:   mode,
+			URL:    url,
+			Size:   n,
+		},
+		nbdDevice:       nbdDevice,
+		httpRangeReader: httpRangeReader,
 	}
 	virtualMediaStateMutex.Unlock()
 
-	logger.Debug().Msg("Starting nbd device")
-	nbdDevice = NewNBDDevice()
+	logger.Debug().Int("lun", lun).Msg("Starting nbd device")
 	err = nbdDevice.Start()
 	if err != nil {
 		logger.Warn().Err(err).Msg("failed to start nbd device")
+		unmountImage(lun)
 		return err
 	}
 	logger.Debug().Msg("nbd device started")
 	//TODO: replace by polling on block device having right size
 	time.Sleep(1 * time.Second)
-	err = setMassStorageImage("/dev/nbd0")
+	err = setMassStorageImage(lun, nbdDevice.DevicePath())
 	if err != nil {
+		unmountImage(lun)
 		return err
 	}
-	logger.Info().Msg("usb mass storage mounted")
+	logger.Info().Int("lun", lun).Msg("usb mass storage mounted")
 	return nil
 }
 
 func rpcMountWithStorage(filename string, mode VirtualMediaMode) error {
+	return rpcMountLunWithStorage(0, filename, mode)
+}
+
+func rpcMountLunWithStorage(lun int, filename string, mode VirtualMediaMode) error {
+	if err := validateMassStorageLun(lun); err != nil {
+		return err
+	}
+
 	filename, err := sanitizeFilename(filename)
 	if err != nil {
 		return err
@@ -314,8 +471,8 @@ func rpcMountWithStorage(filename string, mode VirtualMediaMode) error {
 
 	virtualMediaStateMutex.Lock()
 	defer virtualMediaStateMutex.Unlock()
-	if currentVirtualMediaState != nil {
-		return fmt.Errorf("another virtual media is already mounted")
+	if virtualMediaLuns[lun].state != nil {
+		return fmt.Errorf("another virtual media is already mounted on LUN %d", lun)
 	}
 
 	fullPath := filepath.Join(imagesFolder, filename)
@@ -324,15 +481,16 @@ func rpcMountWithStorage(filename string, mode VirtualMediaMode) error {
 		return fmt.Errorf("failed to get file info: %w", err)
 	}
 
-	if err := setMassStorageMode(mode == CDROM); err != nil {
+	if err := setMassStorageMode(lun, mode == CDROM, true); err != nil {
 		return fmt.Errorf("failed to set mass storage mode: %w", err)
 	}
 
-	err = setMassStorageImage(fullPath)
+	err = setMassStorageImage(lun, fullPath)
 	if err != nil {
 		return fmt.Errorf("failed to set mass storage image: %w", err)
 	}
-	currentVirtualMediaState = &VirtualMediaState{
+	virtualMediaLuns[lun].state = &VirtualMediaState{
+		Lun:      lun,
 		Source:   Storage,
 		Mode:     mode,
 		Filename: filename,
@@ -341,6 +499,236 @@ func rpcMountWithStorage(filename string, mode VirtualMediaMode) error {
 	return nil
 }
 
+const overlaysFolder = "/userdata/jetkvm/overlays"
+
+// overlayMountsPath keeps the images mounted with an overlay, so they can be mounted again after a restart
+var overlayMountsPath = filepath.Join(overlaysFolder, "mounts.json")
+
+type overlayMount struct {
+	Lun      int    `json:"lun"`
+	Filename string `json:"filename"`
+}
+
+func getOverlayPath(filename string) string {
+	return filepath.Join(overlaysFolder, filename+".overlay")
+}
+
+// saveOverlayMounts must be called with virtualMediaStateMutex held.
+func saveOverlayMounts() {
+	mounts := make([]overlayMount, 0)
+	for _, l := range virtualMediaLuns {
+		if l.overlay != nil {
+			mounts = append(mounts, overlayMount{Lun: l.state.Lun, Filename: l.state.Filename})
+		}
+	}
+
+	data, err := json.Marshal(mounts)
+	if err == nil {
+		err = os.WriteFile(overlayMountsPath, data, 0644)
+	}
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to save overlay mounts")
+	}
+}
+
+func loadOverlayMount(lun int) string {
+	data, err := os.ReadFile(overlayMountsPath)
+	if err != nil {
+		return ""
+	}
+	var mounts []overlayMount
+	if err := json.Unmarshal(data, &mounts); err != nil {
+		logger.Warn().Err(err).Msg("failed to read overlay mounts")
+		return ""
+	}
+	for _, m := range mounts {
+		if m.Lun == lun {
+			return m.Filename
+		}
+	}
+	return ""
+}
+
+// startOverlayMount serves the image with its overlay on the NBD device of the LUN and inserts it,
+// it must be called with virtualMediaStateMutex held.
+func startOverlayMount(lun int, state *VirtualMediaState) error {
+	if err := os.MkdirAll(overlaysFolder, 0755); err != nil {
+		return fmt.Errorf("failed to create overlays folder: %w", err)
+	}
+	image, err := cowimage.Open(filepath.Join(imagesFolder, state.Filename), getOverlayPath(state.Filename), cowimage.DefaultBlockSize)
+	if err != nil {
+		return fmt.Errorf("failed to open overlay: %w", err)
+	}
+
+	nbdDevice := NewNBDDevice(lun, image, false)
+	logger.Debug().Int("lun", lun).Msg("Starting nbd device")
+	if err := nbdDevice.Start(); err != nil {
+		nbdDevice.Close()
+		_ = image.Close()
+		return fmt.Errorf("failed to start nbd device: %w", err)
+	}
+	//TODO: replace by polling on block device having right size
+	time.Sleep(1 * time.Second)
+	if err := setMassStorageImage(lun, nbdDevice.DevicePath()); err != nil {
+		nbdDevice.Close()
+		_ = image.Close()
+		return err
+	}
+
+	virtualMediaLuns[lun] = virtualMediaLun{
+		state:     state,
+		nbdDevice: nbdDevice,
+		overlay:   image,
+	}
+	saveOverlayMounts()
+	return nil
+}
+
+// rpcMountWritableStorage mounts a storage image as a writable disk. When overlay is set,
+// the host's writes go to a copy-on-write overlay that can be committed to the image or discarded later,
+// an overlay left from a previous mount of the same image is picked up again.
+func rpcMountWritableStorage(lun int, filename string, overlay bool) error {
+	if err := validateMassStorageLun(lun); err != nil {
+		return err
+	}
+
+	filename, err := sanitizeFilename(filename)
+	if err != nil {
+		return err
+	}
+
+	virtualMediaStateMutex.Lock()
+	if virtualMediaLuns[lun].state != nil {
+		virtualMediaStateMutex.Unlock()
+		return fmt.Errorf("another virtual media is already mounted on LUN %d", lun)
+	}
+	if isStorageFileMounted(filename) {
+		virtualMediaStateMutex.Unlock()
+		return fmt.Errorf("%s is already mounted", filename)
+	}
+
+	fullPath := filepath.Join(imagesFolder, filename)
+	fileInfo, err := os.Stat(fullPath)
+	if err != nil {
+		virtualMediaStateMutex.Unlock()
+		return fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	if err := setMassStorageMode(lun, false, false); err != nil {
+		virtualMediaStateMutex.Unlock()
+		return fmt.Errorf("failed to set mass storage mode: %w", err)
+	}
+
+	state := &VirtualMediaState{
+		Lun:      lun,
+		Source:   Storage,
+		Mode:     Disk,
+		Filename: filename,
+		Size:     fileInfo.Size(),
+		Writable: true,
+		Overlay:  overlay,
+	}
+
+	defer virtualMediaStateMutex.Unlock()
+	if !overlay {
+		if err := setMassStorageImage(lun, fullPath); err != nil {
+			return fmt.Errorf("failed to set mass storage image: %w", err)
+		}
+		virtualMediaLuns[lun].state = state
+		return nil
+	}
+
+	if err := startOverlayMount(lun, state); err != nil {
+		logger.Warn().Err(err).Int("lun", lun).Msg("failed to mount image with overlay")
+		return err
+	}
+	logger.Info().Int("lun", lun).Str("filename", filename).Msg("usb mass storage mounted with overlay")
+	return nil
+}
+
+// isStorageFileMounted must be called with virtualMediaStateMutex held.
+func isStorageFileMounted(filename string) bool {
+	for _, l := range virtualMediaLuns {
+		if l.state != nil && l.state.Source == Storage && l.state.Filename == filename {
+			return true
+		}
+	}
+	return false
+}
+
+// getVirtualMediaOverlay must be called with virtualMediaStateMutex held, so the overlay isn't closed
+// while it's used.
+func getVirtualMediaOverlay(lun int) (*cowimage.Image, error) {
+	if err := validateMassStorageLun(lun); err != nil {
+		return nil, err
+	}
+	if virtualMediaLuns[lun].overlay == nil {
+		return nil, fmt.Errorf("no overlay mounted on LUN %d", lun)
+	}
+	return virtualMediaLuns[lun].overlay, nil
+}
+
+// rpcCommitMediaOverlay writes the changes in the overlay to the image,
+// the host's view of the disk doesn't change so it stays mounted.
+func rpcCommitMediaOverlay(lun int) error {
+	virtualMediaStateMutex.RLock()
+	defer virtualMediaStateMutex.RUnlock()
+
+	overlay, err := getVirtualMediaOverlay(lun)
+	if err != nil {
+		return err
+	}
+	if err := overlay.Commit(); err != nil {
+		return fmt.Errorf("failed to commit overlay: %w", err)
+	}
+	logger.Info().Int("lun", lun).Msg("virtual media overlay committed")
+	return nil
+}
+
+// rpcDiscardMediaOverlay drops the changes in the overlay. The media is ejected while doing so,
+// otherwise the host would keep caching data that no longer exists.
+func rpcDiscardMediaOverlay(lun int) error {
+	virtualMediaStateMutex.Lock()
+	defer virtualMediaStateMutex.Unlock()
+
+	overlay, err := getVirtualMediaOverlay(lun)
+	if err != nil {
+		return err
+	}
+	devicePath := virtualMediaLuns[lun].nbdDevice.DevicePath()
+
+	if err := setMassStorageImage(lun, "\n"); err != nil {
+		return fmt.Errorf("failed to eject media: %w", err)
+	}
+	if err := overlay.Discard(); err != nil {
+		return fmt.Errorf("failed to discard overlay: %w", err)
+	}
+	if err := setMassStorageImage(lun, devicePath); err != nil {
+		return fmt.Errorf("failed to insert media: %w", err)
+	}
+	logger.Info().Int("lun", lun).Msg("virtual media overlay discarded")
+	return nil
+}
+
+func rpcGetMassStorageLunCount() (int, error) {
+	return getMassStorageLunCount(), nil
+}
+
+func rpcSetMassStorageLunCount(count int) error {
+	if count < 1 || count > usbgadget.MaxMassStorageLuns {
+		return fmt.Errorf("LUN count must be between 1 and %d", usbgadget.MaxMassStorageLuns)
+	}
+	for lun := count; lun < usbgadget.MaxMassStorageLuns; lun++ {
+		if isVirtualMediaMounted(lun) {
+			return fmt.Errorf("LUN %d has media mounted, unmount it first", lun)
+		}
+	}
+
+	config.UsbDevices.MassStorageLuns = count
+	gadget.SetGadgetDevices(config.UsbDevices)
+	return updateUsbRelatedConfig()
+}
+
 type StorageSpace struct {
 	BytesUsed int64 `json:"bytesUsed"`
 	BytesFree int64 `json:"bytesFree"`
@@ -429,6 +817,10 @@ func rpcDeleteStorageFile(filename string) error {
 		return fmt.Errorf("failed to delete file: %v", err)
 	}
 
+	if err := cowimage.Remove(getOverlayPath(sanitizedFilename)); err != nil {
+		logger.Warn().Err(err).Str("filename", sanitizedFilename).Msg("failed to delete overlay")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,243 @@
+package kvm
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jetkvm/kvm/internal/dhcpd"
+	"github.com/jetkvm/kvm/internal/usbgadget"
+	"github.com/vishvananda/netlink"
+)
+
+// UsbNetworkConfig is the configuration of the point-to-point USB Ethernet link to the host.
+type UsbNetworkConfig struct {
+	Function      string `json:"function"`             // one of "ecm", "ncm" or "rndis"
+	HostMac       string `json:"host_mac,omitempty"`   // derived from the device ID if empty
+	DeviceMac     string `json:"device_mac,omitempty"` // derived from the device ID if empty
+	DeviceAddress string `json:"device_address"`       // address of the device side in CIDR notation
+	HostAddress   string `json:"host_address"`         // address handed out to the host
+	DHCPServer    bool   `json:"dhcp_server"`
+}
+
+var defaultUsbNetworkConfig = &UsbNetworkConfig{
+	Function:      usbgadget.NetworkFunctionNCM,
+	DeviceAddress: "172.31.255.1/30",
+	HostAddress:   "172.31.255.2",
+	DHCPServer:    true,
+}
+
+func (c *UsbNetworkConfig) Validate() error {
+	gadgetConfig := usbgadget.NetworkConfig{Function: c.Function}
+	if err := gadgetConfig.Validate(); err != nil {
+		return err
+	}
+
+	for _, mac := range []string{c.HostMac, c.DeviceMac} {
+		if mac == "" {
+			continue
+		}
+		if _, err := net.ParseMAC(mac); err != nil {
+			return fmt.Errorf("invalid MAC address %s: %w", mac, err)
+		}
+	}
+
+	deviceIP, subnet, err := net.ParseCIDR(c.DeviceAddress)
+	if err != nil || deviceIP.To4() == nil {
+		return fmt.Errorf("invalid device address: %s", c.DeviceAddress)
+	}
+
+	hostIP := net.ParseIP(c.HostAddress)
+	if hostIP == nil || hostIP.To4() == nil {
+		return fmt.Errorf("invalid host address: %s", c.HostAddress)
+	}
+	if !subnet.Contains(hostIP) {
+		return fmt.Errorf("host address %s is not in %s", c.HostAddress, subnet.String())
+	}
+	if hostIP.Equal(deviceIP) {
+		return fmt.Errorf("host and device addresses must be different")
+	}
+
+	return nil
+}
+
+// derivedMac returns a stable, locally administered MAC address for the given link side,
+// so the host doesn't see a new network adapter every time the device boots.
+func derivedMac(side string) string {
+	sum := sha256.Sum256([]byte(GetDeviceID() + "/usb-network/" + side))
+	mac := net.HardwareAddr(sum[:6])
+	mac[0] = (mac[0] | 0x02) &^ 0x01 // locally administered, unicast
+	return mac.String()
+}
+
+func getUsbGadgetNetworkConfig() *usbgadget.NetworkConfig {
+	c := config.UsbNetworkConfig
+
+	gadgetConfig := &usbgadget.NetworkConfig{
+		Function:  c.Function,
+		HostMac:   c.HostMac,
+		DeviceMac: c.DeviceMac,
+	}
+	if gadgetConfig.HostMac == "" {
+		gadgetConfig.HostMac = derivedMac("host")
+	}
+	if gadgetConfig.DeviceMac == "" {
+		gadgetConfig.DeviceMac = derivedMac("device")
+	}
+	return gadgetConfig
+}
+
+var (
+	usbNetworkLock       sync.Mutex
+	usbNetworkInterface  string
+	usbNetworkDHCPServer *dhcpd.Server
+)
+
+const usbNetworkInterfaceTimeout = 10 * time.Second
+
+// waitUsbNetworkInterface waits for the gadget to create the network interface.
+func waitUsbNetworkInterface() (netlink.Link, error) {
+	deadline := time.Now().Add(usbNetworkInterfaceTimeout)
+	for {
+		ifname, err := gadget.GetNetworkInterfaceName()
+		if err == nil && ifname != "" {
+			link, err := netlink.LinkByName(ifname)
+			if err == nil {
+				return link, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("USB network interface not found: %v", err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+func stopUsbNetwork() {
+	if usbNetworkDHCPServer != nil {
+		if err := usbNetworkDHCPServer.Stop(); err != nil {
+			usbLogger.Warn().Err(err).Msg("failed to stop USB network DHCP server")
+		}
+		usbNetworkDHCPServer = nil
+	}
+	usbNetworkInterface = ""
+}
+
+// restartUsbNetwork configures the device side of the USB network link
+// and starts the DHCP server if the network function is enabled.
+func restartUsbNetwork() error {
+	usbNetworkLock.Lock()
+	defer usbNetworkLock.Unlock()
+
+	stopUsbNetwork()
+
+	if !config.UsbDevices.Network {
+		return nil
+	}
+
+	c := config.UsbNetworkConfig
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("invalid USB network config: %w", err)
+	}
+
+	link, err := waitUsbNetworkInterface()
+	if err != nil {
+		return err
+	}
+	ifname := link.Attrs().Name
+
+	addr, err := netlink.ParseAddr(c.DeviceAddress)
+	if err != nil {
+		return fmt.Errorf("failed to parse device address: %w", err)
+	}
+	if err := netlink.AddrReplace(link, addr); err != nil {
+		return fmt.Errorf("failed to set address on %s: %w", ifname, err)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return fmt.Errorf("failed to bring up %s: %w", ifname, err)
+	}
+	usbNetworkInterface = ifname
+
+	l := usbLogger.With().Str("interface", ifname).Logger()
+	l.Info().Str("address", c.DeviceAddress).Msg("USB network interface configured")
+
+	if !c.DHCPServer {
+		return nil
+	}
+
+	server, err := dhcpd.NewServer(&dhcpd.Options{
+		InterfaceName: ifname,
+		ServerIP:      addr.IP,
+		ClientIP:      net.ParseIP(c.HostAddress),
+		SubnetMask:    addr.Mask,
+		Logger:        &l,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create DHCP server: %w", err)
+	}
+	if err := server.Start(); err != nil {
+		return fmt.Errorf("failed to start DHCP server: %w", err)
+	}
+	usbNetworkDHCPServer = server
+
+	return nil
+}
+
+func initUsbNetwork() {
+	if !config.UsbDevices.Network {
+		return
+	}
+
+	go func() {
+		if err := restartUsbNetwork(); err != nil {
+			usbLogger.Warn().Err(err).Msg("failed to start USB network")
+		}
+	}()
+}
+
+type UsbNetworkState struct {
+	Enabled       bool         `json:"enabled"`
+	Interface     string       `json:"interface,omitempty"`
+	DeviceAddress string       `json:"device_address,omitempty"`
+	HostAddress   string       `json:"host_address,omitempty"`
+	Lease         *dhcpd.Lease `json:"lease,omitempty"`
+}
+
+func rpcGetUsbNetworkState() (UsbNetworkState, error) {
+	usbNetworkLock.Lock()
+	defer usbNetworkLock.Unlock()
+
+	state := UsbNetworkState{
+		Enabled:   config.UsbDevices.Network,
+		Interface: usbNetworkInterface,
+	}
+	if usbNetworkInterface != "" {
+		state.DeviceAddress = config.UsbNetworkConfig.DeviceAddress
+		state.HostAddress = config.UsbNetworkConfig.HostAddress
+	}
+	if usbNetworkDHCPServer != nil {
+		state.Lease = usbNetworkDHCPServer.GetLease()
+	}
+	return state, nil
+}
+
+func rpcGetUsbNetworkConfig() (UsbNetworkConfig, error) {
+	return *config.UsbNetworkConfig, nil
+}
+
+func rpcSetUsbNetworkConfig(usbNetworkConfig UsbNetworkConfig) error {
+	if err := usbNetworkConfig.Validate(); err != nil {
+		return err
+	}
+
+	config.UsbNetworkConfig = &usbNetworkConfig
+	gadget.SetNetworkConfig(getUsbGadgetNetworkConfig())
+
+	if !config.UsbDevices.Network {
+		return SaveConfig()
+	}
+	return updateUsbRelatedConfig()
+}
@@ -0,0 +1,413 @@
+package kvm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jetkvm/kvm/internal/usbgadget"
+)
+
+const (
+	printJobsFolder = "/userdata/jetkvm/print_jobs"
+
+	// the printer class has no notion of jobs, a job ends when the host stops sending data
+	printJobIdleTimeout = 5 * time.Second
+	maxPrintJobSize     = 256 * 1024 * 1024
+	maxPrintJobRead     = 1024 * 1024
+	printerDeviceWait   = 10 * time.Second
+)
+
+var errPrintJobNotFound = errors.New("print job does not exist")
+
+var (
+	printerLock   sync.Mutex
+	printerDevice *os.File
+	printerCancel context.CancelFunc
+)
+
+type PrintJob struct {
+	Filename  string    `json:"filename"`
+	Format    string    `json:"format"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type PrintJobChunk struct {
+	Data []byte `json:"data"`
+	EOF  bool   `json:"eof"`
+}
+
+var printJobFormats = map[string]string{
+	".pdf": "pdf",
+	".ps":  "postscript",
+	".pcl": "pcl",
+	".txt": "text",
+	".prn": "raw",
+}
+
+// detectPrintJobExtension guesses the page description language from the start of the print stream.
+func detectPrintJobExtension(header []byte) string {
+	switch {
+	case bytes.HasPrefix(header, []byte("%PDF")):
+		return ".pdf"
+	case bytes.HasPrefix(header, []byte("%!")):
+		return ".ps"
+	case bytes.HasPrefix(header, []byte("\x1b%-12345X")):
+		// PJL wraps the actual language
+		if bytes.Contains(header, []byte("LANGUAGE=POSTSCRIPT")) || bytes.Contains(header, []byte("LANGUAGE = POSTSCRIPT")) {
+			return ".ps"
+		}
+		if bytes.Contains(header, []byte("LANGUAGE=PDF")) || bytes.Contains(header, []byte("LANGUAGE = PDF")) {
+			return ".pdf"
+		}
+		return ".pcl"
+	case bytes.HasPrefix(header, []byte("\x1bE")):
+		return ".pcl"
+	case utf8.Valid(header) && !bytes.ContainsFunc(header, func(r rune) bool {
+		return r < 0x20 && r != '\n' && r != '\r' && r != '\t' && r != '\f'
+	}):
+		return ".txt"
+	default:
+		return ".prn"
+	}
+}
+
+type printJobWriter struct {
+	file      *os.File
+	path      string
+	size      int64
+	truncated bool
+	createdAt time.Time
+}
+
+func newPrintJobWriter(header []byte) (*printJobWriter, error) {
+	if err := os.MkdirAll(printJobsFolder, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create print jobs folder: %w", err)
+	}
+
+	now := time.Now()
+	base := "job-" + now.Format("20060102-150405")
+	ext := detectPrintJobExtension(header)
+	for i := 0; ; i++ {
+		name := base + ext
+		if i > 0 {
+			name = fmt.Sprintf("%s-%d%s", base, i, ext)
+		}
+		path := filepath.Join(printJobsFolder, name)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+
+		file, err := os.OpenFile(path+".incomplete", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create print job file: %w", err)
+		}
+		return &printJobWriter{file: file, path: path, createdAt: now}, nil
+	}
+}
+
+func (w *printJobWriter) write(data []byte) error {
+	if w.size+int64(len(data)) > maxPrintJobSize {
+		// keep what fits, the rest of the job is dropped
+		data = data[:maxPrintJobSize-w.size]
+		w.truncated = true
+	}
+	n, err := w.file.Write(data)
+	w.size += int64(n)
+	return err
+}
+
+func (w *printJobWriter) finish() (*PrintJob, error) {
+	if err := w.file.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(w.file.Name(), w.path); err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(w.path)
+	return &PrintJob{
+		Filename:  name,
+		Format:    printJobFormats[filepath.Ext(name)],
+		Size:      w.size,
+		CreatedAt: w.createdAt,
+	}, nil
+}
+
+func triggerPrintJobReceived(job *PrintJob) {
+	go func() {
+		if currentSession == nil {
+			return
+		}
+		writeJSONRPCEvent("printJobReceived", job, currentSession)
+	}()
+}
+
+// capturePrintJobs reads the print stream and saves every burst of data as a separate job.
+func capturePrintJobs(ctx context.Context, f io.Reader) {
+	data := make(chan []byte)
+	go func() {
+		defer close(data)
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := f.Read(buf)
+			if n > 0 {
+				select {
+				case data <- bytes.Clone(buf[:n]):
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err == nil {
+				continue
+			}
+			if ctx.Err() != nil || errors.Is(err, os.ErrClosed) {
+				return
+			}
+			// the read fails while the host isn't connected, try again later
+			usbLogger.Trace().Err(err).Msg("failed to read from printer device")
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var job *printJobWriter
+	finishJob := func() {
+		if job == nil {
+			return
+		}
+		printJob, err := job.finish()
+		if err != nil {
+			usbLogger.Warn().Err(err).Str("path", job.path).Msg("failed to save print job")
+		} else {
+			usbLogger.Info().
+				Str("filename", printJob.Filename).
+				Int64("size", printJob.Size).
+				Bool("truncated", job.truncated).
+				Msg("print job received")
+			triggerPrintJobReceived(printJob)
+		}
+		job = nil
+	}
+	defer finishJob()
+
+	idle := time.NewTimer(printJobIdleTimeout)
+	idle.Stop()
+	defer idle.Stop()
+
+	for {
+		select {
+		case chunk, ok := <-data:
+			if !ok {
+				return
+			}
+			if job == nil {
+				var err error
+				if job, err = newPrintJobWriter(chunk); err != nil {
+					usbLogger.Warn().Err(err).Msg("failed to start print job")
+					continue
+				}
+			}
+			if err := job.write(chunk); err != nil {
+				usbLogger.Warn().Err(err).Str("path", job.path).Msg("failed to write print job")
+			}
+			idle.Reset(printJobIdleTimeout)
+		case <-idle.C:
+			finishJob()
+		}
+	}
+}
+
+func waitPrinterDevice() (string, error) {
+	deadline := time.Now().Add(printerDeviceWait)
+	for {
+		devicePath, err := usbgadget.PrinterDevicePath()
+		if err == nil {
+			return devicePath, nil
+		}
+		if time.Now().After(deadline) {
+			return "", err
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+func stopPrinter() {
+	if printerCancel == nil {
+		return
+	}
+	printerCancel()
+	_ = printerDevice.Close()
+	printerCancel = nil
+	printerDevice = nil
+}
+
+// restartPrinter starts capturing print jobs if the printer function is enabled.
+func restartPrinter() error {
+	printerLock.Lock()
+	defer printerLock.Unlock()
+
+	stopPrinter()
+
+	if !config.UsbDevices.Printer {
+		return nil
+	}
+
+	devicePath, err := waitPrinterDevice()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(devicePath, os.O_RDWR, 0666)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", devicePath, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	printerDevice = f
+	printerCancel = cancel
+	go capturePrintJobs(ctx, f)
+
+	usbLogger.Info().Str("device", devicePath).Msg("capturing print jobs")
+	return nil
+}
+
+func initPrinter() {
+	if !config.UsbDevices.Printer {
+		return
+	}
+
+	go func() {
+		if err := restartPrinter(); err != nil {
+			usbLogger.Warn().Err(err).Msg("failed to start printer")
+		}
+	}()
+}
+
+func listPrintJobs() ([]PrintJob, error) {
+	files, err := os.ReadDir(printJobsFolder)
+	if errors.Is(err, os.ErrNotExist) {
+		return []PrintJob{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %v", err)
+	}
+
+	jobs := make([]PrintJob, 0)
+	for _, file := range files {
+		format, ok := printJobFormats[filepath.Ext(file.Name())]
+		if file.IsDir() || !ok {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file info: %v", err)
+		}
+
+		jobs = append(jobs, PrintJob{
+			Filename:  file.Name(),
+			Format:    format,
+			Size:      info.Size(),
+			CreatedAt: info.ModTime(),
+		})
+	}
+
+	slices.SortFunc(jobs, func(a, b PrintJob) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return jobs, nil
+}
+
+func getPrintJobPath(filename string) (string, error) {
+	sanitizedFilename, err := sanitizeFilename(filename)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := printJobFormats[filepath.Ext(sanitizedFilename)]; !ok {
+		return "", fmt.Errorf("invalid print job: %s", filename)
+	}
+
+	fullPath := filepath.Join(printJobsFolder, sanitizedFilename)
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s", errPrintJobNotFound, filename)
+	}
+	return fullPath, nil
+}
+
+func rpcGetPrintJobs() ([]PrintJob, error) {
+	return listPrintJobs()
+}
+
+// rpcReadPrintJob returns a chunk of the print job, so jobs can be downloaded over the data channel.
+func rpcReadPrintJob(filename string, offset int64, length int) (*PrintJobChunk, error) {
+	fullPath, err := getPrintJobPath(filename)
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 || length <= 0 || length > maxPrintJobRead {
+		return nil, fmt.Errorf("offset must be positive and length between 1 and %d", maxPrintJobRead)
+	}
+
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, length)
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read print job: %w", err)
+	}
+	return &PrintJobChunk{Data: buf[:n], EOF: err == io.EOF}, nil
+}
+
+func rpcDeletePrintJob(filename string) error {
+	fullPath, err := getPrintJobPath(filename)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil {
+		return fmt.Errorf("failed to delete print job: %v", err)
+	}
+	return nil
+}
+
+func handleListPrintJobs(c *gin.Context) {
+	jobs, err := listPrintJobs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+func handleDownloadPrintJob(c *gin.Context) {
+	fullPath, err := getPrintJobPath(c.Param("filename"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, errPrintJobNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.FileAttachment(fullPath, filepath.Base(fullPath))
+}
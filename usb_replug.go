@@ -0,0 +1,115 @@
+package kvm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const maxUsbReplugDelay = 60 * time.Second
+
+var (
+	// usbReplugTimers holds the pending attach of each function being replugged
+	usbReplugTimers     = make(map[string]*time.Timer)
+	usbReplugTimersLock sync.Mutex
+)
+
+// cancelUsbReplug stops the pending attach of the function, if any.
+func cancelUsbReplug(function string) {
+	usbReplugTimersLock.Lock()
+	defer usbReplugTimersLock.Unlock()
+
+	if timer, ok := usbReplugTimers[function]; ok {
+		timer.Stop()
+		delete(usbReplugTimers, function)
+	}
+}
+
+type UsbFunctionState struct {
+	Function string `json:"function"`
+	Attached bool   `json:"attached"`
+	Error    string `json:"error,omitempty"`
+}
+
+func triggerUsbFunctionStateUpdate(function string, attached bool, err error) {
+	state := UsbFunctionState{Function: function, Attached: attached}
+	if err != nil {
+		state.Error = err.Error()
+	}
+
+	// the rebind changes the USB state as well, make sure the UI picks it up
+	triggerUSBStateUpdate()
+
+	go func() {
+		if currentSession == nil {
+			return
+		}
+		writeJSONRPCEvent("usbFunctionState", state, currentSession)
+	}()
+}
+
+func setUsbFunctionAttached(function string, attached bool) error {
+	var err error
+	if attached {
+		err = gadget.AttachFunction(function)
+	} else {
+		err = gadget.DetachFunction(function)
+	}
+	if err != nil {
+		usbLogger.Warn().Err(err).Str("function", function).Bool("attached", attached).Msg("failed to update USB function")
+		// report the state the function is actually in
+		triggerUsbFunctionStateUpdate(function, !attached, err)
+		return err
+	}
+
+	triggerUsbFunctionStateUpdate(function, attached, nil)
+	return nil
+}
+
+func rpcDetachUsbFunction(function string) error {
+	cancelUsbReplug(function)
+	return setUsbFunctionAttached(function, false)
+}
+
+func rpcAttachUsbFunction(function string) error {
+	cancelUsbReplug(function)
+	return setUsbFunctionAttached(function, true)
+}
+
+// rpcReplugUsbFunction detaches the function and attaches it again after delayMs,
+// it returns once the function is detached and the outcome of the attach is reported as an event.
+// A replug, attach or detach of the same function cancels the pending attach.
+func rpcReplugUsbFunction(function string, delayMs int) error {
+	delay := time.Duration(delayMs) * time.Millisecond
+	if delay < 0 || delay > maxUsbReplugDelay {
+		return fmt.Errorf("delay must be between 0 and %d ms", maxUsbReplugDelay.Milliseconds())
+	}
+
+	cancelUsbReplug(function)
+	if err := setUsbFunctionAttached(function, false); err != nil {
+		return err
+	}
+
+	usbReplugTimersLock.Lock()
+	defer usbReplugTimersLock.Unlock()
+
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		usbReplugTimersLock.Lock()
+		pending := usbReplugTimers[function] == timer
+		if pending {
+			delete(usbReplugTimers, function)
+		}
+		usbReplugTimersLock.Unlock()
+
+		if pending {
+			_ = setUsbFunctionAttached(function, true)
+		}
+	})
+	usbReplugTimers[function] = timer
+	return nil
+}
+
+func rpcGetDetachedUsbFunctions() ([]string, error) {
+	return gadget.GetDetachedFunctions(), nil
+}
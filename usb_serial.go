@@ -0,0 +1,111 @@
+package kvm
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/jetkvm/kvm/internal/usbgadget"
+	"github.com/pion/webrtc/v4"
+	"go.bug.st/serial"
+)
+
+var (
+	usbSerialPort     serial.Port
+	usbSerialPortLock sync.Mutex
+)
+
+// openUsbSerialPort opens the device side of the CDC-ACM function,
+// the port is opened in raw mode so the line discipline doesn't echo the host's input back.
+func openUsbSerialPort() (serial.Port, error) {
+	usbSerialPortLock.Lock()
+	defer usbSerialPortLock.Unlock()
+
+	if !config.UsbDevices.SerialConsole {
+		return nil, errors.New("USB serial console is disabled")
+	}
+
+	if usbSerialPort != nil {
+		_ = usbSerialPort.Close()
+		usbSerialPort = nil
+	}
+
+	p, err := serial.Open(usbgadget.SerialConsoleDevicePath, defaultMode)
+	if err != nil {
+		return nil, err
+	}
+	usbSerialPort = p
+	return p, nil
+}
+
+func closeUsbSerialPort(p serial.Port) {
+	usbSerialPortLock.Lock()
+	defer usbSerialPortLock.Unlock()
+
+	if p == nil {
+		return
+	}
+	_ = p.Close()
+	if usbSerialPort == p {
+		usbSerialPort = nil
+	}
+}
+
+func handleUsbSerialChannel(d *webrtc.DataChannel) {
+	scopedLogger := serialLogger.With().
+		Str("port", usbgadget.SerialConsoleDevicePath).
+		Uint16("data_channel_id", *d.ID()).Logger()
+
+	var p serial.Port
+
+	d.OnOpen(func() {
+		var err error
+		p, err = openUsbSerialPort()
+		if err != nil {
+			scopedLogger.Warn().Err(err).Msg("Failed to open USB serial port")
+			_ = d.Close()
+			return
+		}
+
+		go func() {
+			buf := make([]byte, 1024)
+			for {
+				n, err := p.Read(buf)
+				if err != nil {
+					if err != io.EOF {
+						scopedLogger.Warn().Err(err).Msg("Failed to read from USB serial port")
+					}
+					break
+				}
+				// the port returns 0 bytes when it's closed
+				if n == 0 {
+					break
+				}
+				err = d.Send(buf[:n])
+				if err != nil {
+					scopedLogger.Warn().Err(err).Msg("Failed to send USB serial output")
+					break
+				}
+			}
+		}()
+	})
+
+	d.OnMessage(func(msg webrtc.DataChannelMessage) {
+		if p == nil {
+			return
+		}
+		_, err := p.Write(msg.Data)
+		if err != nil {
+			scopedLogger.Warn().Err(err).Msg("Failed to write to USB serial")
+		}
+	})
+
+	d.OnError(func(err error) {
+		scopedLogger.Warn().Err(err).Msg("USB serial channel error")
+	})
+
+	d.OnClose(func() {
+		closeUsbSerialPort(p)
+		scopedLogger.Info().Msg("USB serial channel closed")
+	})
+}
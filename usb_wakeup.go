@@ -0,0 +1,152 @@
+package kvm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jetkvm/kvm/internal/usbgadget"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	usbSuspendedGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "jetkvm_usb_host_suspended",
+			Help: "Whether the USB host has suspended the bus (1 = suspended, 0 = not suspended)",
+		},
+	)
+	usbSuspendCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "jetkvm_usb_host_suspend_total",
+			Help: "Number of times the USB host suspended the bus",
+		},
+	)
+	usbResumeCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "jetkvm_usb_host_resume_total",
+			Help: "Number of times the USB host resumed the bus",
+		},
+	)
+	usbRemoteWakeupCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jetkvm_usb_remote_wakeup_total",
+			Help: "Number of remote wakeups signaled to the USB host",
+		},
+		[]string{"trigger"},
+	)
+)
+
+type UsbSuspendState struct {
+	Suspended bool      `json:"suspended"`
+	Time      time.Time `json:"time"`
+}
+
+// handleUsbSuspendTransition tracks suspend and resume of the host, it's called for every USB state transition.
+func handleUsbSuspendTransition(transition usbgadget.UsbStateTransition) {
+	var suspended bool
+	switch {
+	case transition.To == usbgadget.UsbStateSuspended:
+		suspended = true
+		usbSuspendCounter.Inc()
+		usbLogger.Info().Msg("USB host suspended")
+	case transition.From == usbgadget.UsbStateSuspended:
+		suspended = false
+		usbResumeCounter.Inc()
+		usbLogger.Info().Str("state", string(transition.To)).Msg("USB host resumed")
+	default:
+		return
+	}
+
+	if suspended {
+		usbSuspendedGauge.Set(1)
+	} else {
+		usbSuspendedGauge.Set(0)
+	}
+
+	go func() {
+		if currentSession == nil {
+			return
+		}
+		writeJSONRPCEvent("usbSuspendState", UsbSuspendState{
+			Suspended: suspended,
+			Time:      transition.Time,
+		}, currentSession)
+	}()
+}
+
+// hostWakeupInterval throttles the wakeups triggered by key presses,
+// the host needs some time to resume and the keys pressed meanwhile would trigger it again.
+const hostWakeupInterval = 2 * time.Second
+
+var (
+	lastHostWakeup     time.Time
+	lastHostWakeupLock sync.Mutex
+)
+
+func wakeUpHost(trigger string) error {
+	lastHostWakeupLock.Lock()
+	lastHostWakeup = time.Now()
+	lastHostWakeupLock.Unlock()
+
+	if err := gadget.RemoteWakeup(); err != nil {
+		return err
+	}
+	usbRemoteWakeupCounter.WithLabelValues(trigger).Inc()
+	return nil
+}
+
+// wakeUpHostOnKeyPress wakes the host up if it's suspended and waking on key press is enabled.
+func wakeUpHostOnKeyPress() {
+	if !config.UsbWakeOnKeyPress || !gadget.IsSuspended() {
+		return
+	}
+
+	lastHostWakeupLock.Lock()
+	throttled := time.Since(lastHostWakeup) < hostWakeupInterval
+	lastHostWakeupLock.Unlock()
+	if throttled {
+		return
+	}
+
+	if err := wakeUpHost("key_press"); err != nil {
+		usbLogger.Warn().Err(err).Msg("failed to wake up host on key press")
+	}
+}
+
+func rpcWakeUpHost() error {
+	return wakeUpHost("rpc")
+}
+
+func rpcGetUsbSuspendState() (UsbSuspendState, error) {
+	return UsbSuspendState{Suspended: gadget.IsSuspended(), Time: time.Now()}, nil
+}
+
+func rpcGetUsbRemoteWakeup() (bool, error) {
+	return config.UsbRemoteWakeup, nil
+}
+
+// rpcSetUsbRemoteWakeup sets whether remote wakeup is advertised in bmAttributes,
+// the host only notices the change after the gadget is re-enumerated.
+func rpcSetUsbRemoteWakeup(enabled bool) error {
+	gadget.SetRemoteWakeup(enabled)
+	config.UsbRemoteWakeup = enabled
+	return updateUsbRelatedConfig()
+}
+
+func rpcGetUsbWakeOnKeyPress() (bool, error) {
+	return config.UsbWakeOnKeyPress, nil
+}
+
+// rpcSetUsbWakeOnKeyPress enables remote wakeup as well when enabling, waking on key press needs it.
+func rpcSetUsbWakeOnKeyPress(enabled bool) error {
+	config.UsbWakeOnKeyPress = enabled
+	if enabled && !config.UsbRemoteWakeup {
+		return rpcSetUsbRemoteWakeup(true)
+	}
+	if err := SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
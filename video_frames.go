@@ -0,0 +1,54 @@
+package kvm
+
+import (
+	"github.com/jetkvm/kvm/internal/framebus"
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+// webrtcVideoFrameBuffer is about a second of video, a session that falls further behind skips to the next keyframe
+const webrtcVideoFrameBuffer = 32
+
+// subscribeVideoFrames adds a consumer of the encoded video frames, it has to be closed when it's no longer needed.
+func subscribeVideoFrames(opts framebus.Options) *framebus.Subscriber {
+	return nativeInstance.FrameBus().Subscribe(opts)
+}
+
+// startVideoFrames sends the video to the session until stopVideoFrames is called.
+func (s *Session) startVideoFrames() {
+	if s.videoFrames != nil {
+		return
+	}
+	// the session joins on the next frame like it did before the bus: the encoder only sends a
+	// keyframe every 60 frames and can't be asked for one, so waiting for it would keep a new
+	// session black for up to two seconds
+	sub := subscribeVideoFrames(framebus.Options{
+		Name:   "webrtc",
+		Buffer: webrtcVideoFrameBuffer,
+		Policy: framebus.DropUntilKeyframe,
+	})
+	s.videoFrames = sub
+
+	go func() {
+		for frame := range sub.Frames() {
+			err := s.VideoTrack.WriteSample(media.Sample{Data: frame.Data, Duration: frame.Duration})
+			if err != nil {
+				nativeLogger.Warn().Err(err).Msg("error writing sample")
+			}
+		}
+	}()
+}
+
+func (s *Session) stopVideoFrames() {
+	if s.videoFrames == nil {
+		return
+	}
+	stats := s.videoFrames.Stats()
+	s.videoFrames.Close()
+	s.videoFrames = nil
+	nativeLogger.Debug().Uint64("delivered", stats.Delivered).Uint64("dropped", stats.Dropped).Msg("stopped sending video to session")
+}
+
+// rpcGetVideoFrameStats returns the frames every consumer of the video got and lost.
+func rpcGetVideoFrameStats() ([]framebus.Stats, error) {
+	return nativeInstance.FrameBus().Stats(), nil
+}
@@ -0,0 +1,334 @@
+package kvm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jetkvm/kvm/internal/framebus"
+	"github.com/jetkvm/kvm/internal/videorec"
+)
+
+const (
+	videoRecordingsFolder = "/userdata/jetkvm/video_recordings"
+	// videoRecordingBuffer is about three seconds of video, the recorder skips to the next keyframe
+	// when the storage falls further behind
+	videoRecordingBuffer = 90
+)
+
+const (
+	VideoRecordingModeSession  = "session"
+	VideoRecordingModeAlwaysOn = "always_on"
+)
+
+var errVideoRecordingNotFound = errors.New("video recording does not exist")
+
+type VideoRecordingConfig struct {
+	// AlwaysOn records every session, from the first connection until the last one closes
+	AlwaysOn       bool   `json:"always_on"`
+	Format         string `json:"format"`
+	MaxFileSizeMB  int    `json:"max_file_size_mb"`
+	MaxFileMinutes int    `json:"max_file_minutes"`
+	// QuotaMB limits the size of all recordings, the oldest are deleted to make room
+	QuotaMB int `json:"quota_mb"`
+}
+
+var defaultVideoRecordingConfig = VideoRecordingConfig{
+	Format:         string(videorec.FormatMP4),
+	MaxFileSizeMB:  256,
+	MaxFileMinutes: 30,
+	QuotaMB:        2048,
+}
+
+func (c VideoRecordingConfig) Validate() error {
+	if !videorec.Format(c.Format).Valid() {
+		return fmt.Errorf("unsupported recording format: %s", c.Format)
+	}
+	if c.QuotaMB <= 0 {
+		return errors.New("quota must be positive")
+	}
+	if c.MaxFileSizeMB <= 0 || c.MaxFileSizeMB > c.QuotaMB {
+		return fmt.Errorf("max file size must be between 1 and %d MB", c.QuotaMB)
+	}
+	if c.MaxFileMinutes <= 0 {
+		return errors.New("max file duration must be positive")
+	}
+	return nil
+}
+
+type VideoRecordingState struct {
+	Recording bool   `json:"recording"`
+	Mode      string `json:"mode,omitempty"`
+	Error     string `json:"error,omitempty"`
+	videorec.Stats
+}
+
+type videoRecording struct {
+	mode    string
+	session *Session // the recorded session, nil when it's always on
+	sub     *framebus.Subscriber
+	done    chan struct{}
+
+	lock     sync.Mutex
+	recorder *videorec.Recorder
+	err      error
+}
+
+var (
+	videoRecordingLock   sync.Mutex
+	activeVideoRecording *videoRecording
+)
+
+func (r *videoRecording) failed() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.err != nil
+}
+
+func (r *videoRecording) state() VideoRecordingState {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	state := VideoRecordingState{Recording: r.err == nil, Mode: r.mode, Stats: r.recorder.Stats()}
+	if r.err != nil {
+		state.Error = r.err.Error()
+	}
+	return state
+}
+
+// record writes the frames until the subscription is closed, it stops on the first error.
+func (r *videoRecording) record() {
+	defer close(r.done)
+	for frame := range r.sub.Frames() {
+		r.lock.Lock()
+		if r.err == nil {
+			r.err = r.recorder.WriteFrame(frame.Data, frame.Received)
+			if r.err != nil {
+				logger.Warn().Err(r.err).Msg("video recording failed")
+				go reportVideoRecordingState()
+			}
+		}
+		r.lock.Unlock()
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if err := r.recorder.Close(); err != nil {
+		logger.Warn().Err(err).Msg("failed to finish video recording")
+	}
+}
+
+func reportVideoRecordingState() {
+	state, _ := rpcGetVideoRecordingState()
+	go func() {
+		if currentSession == nil {
+			return
+		}
+		writeJSONRPCEvent("videoRecordingState", state, currentSession)
+	}()
+}
+
+func startVideoRecording(mode string, session *Session) error {
+	settings := config.VideoRecording
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	videoRecordingLock.Lock()
+	defer videoRecordingLock.Unlock()
+	if rec := activeVideoRecording; rec != nil {
+		if !rec.failed() {
+			return errors.New("the video is already being recorded")
+		}
+		rec.sub.Close()
+		<-rec.done
+		activeVideoRecording = nil
+	}
+
+	label := "session"
+	if mode == VideoRecordingModeAlwaysOn {
+		label = "always-on"
+	}
+	recorder, err := videorec.NewRecorder(videorec.Options{
+		Dir:             videoRecordingsFolder,
+		Format:          videorec.Format(settings.Format),
+		Label:           label,
+		MaxFileSize:     int64(settings.MaxFileSizeMB) << 20,
+		MaxFileDuration: time.Duration(settings.MaxFileMinutes) * time.Minute,
+		Quota:           int64(settings.QuotaMB) << 20,
+	})
+	if err != nil {
+		return err
+	}
+
+	rec := &videoRecording{
+		mode:     mode,
+		session:  session,
+		recorder: recorder,
+		done:     make(chan struct{}),
+		sub: subscribeVideoFrames(framebus.Options{
+			Name:            "recorder",
+			Buffer:          videoRecordingBuffer,
+			Policy:          framebus.DropUntilKeyframe,
+			WaitForKeyframe: true,
+		}),
+	}
+	activeVideoRecording = rec
+	go rec.record()
+
+	logger.Info().Str("mode", mode).Str("format", settings.Format).Msg("started video recording")
+	go reportVideoRecordingState()
+	return nil
+}
+
+// stopVideoRecording stops the recording if it matches, it waits until the file is finished.
+func stopVideoRecording(match func(r *videoRecording) bool) (*VideoRecordingState, error) {
+	videoRecordingLock.Lock()
+	rec := activeVideoRecording
+	if rec == nil || !match(rec) {
+		videoRecordingLock.Unlock()
+		return nil, errors.New("no video recording in progress")
+	}
+	activeVideoRecording = nil
+	videoRecordingLock.Unlock()
+
+	rec.sub.Close()
+	<-rec.done
+	defer reportVideoRecordingState()
+
+	state := rec.state()
+	state.Recording = false
+	logger.Info().Str("mode", rec.mode).Int("files", state.Files).Int64("bytes", state.Bytes).Msg("stopped video recording")
+	return &state, nil
+}
+
+// onVideoRecordingSessionConnected starts the recording when it's always on.
+func onVideoRecordingSessionConnected() {
+	if !config.VideoRecording.AlwaysOn {
+		return
+	}
+	if err := startVideoRecording(VideoRecordingModeAlwaysOn, nil); err != nil {
+		logger.Warn().Err(err).Msg("failed to start video recording")
+	}
+}
+
+// onVideoRecordingSessionClosed stops the recording of the session, and the recording that's
+// always on when it was the last session.
+func onVideoRecordingSessionClosed(session *Session, lastSession bool) {
+	_, _ = stopVideoRecording(func(r *videoRecording) bool {
+		if r.mode == VideoRecordingModeAlwaysOn {
+			return lastSession
+		}
+		return r.session == session
+	})
+}
+
+func rpcGetVideoRecordingState() (VideoRecordingState, error) {
+	videoRecordingLock.Lock()
+	rec := activeVideoRecording
+	videoRecordingLock.Unlock()
+
+	if rec == nil {
+		return VideoRecordingState{}, nil
+	}
+	return rec.state(), nil
+}
+
+// rpcStartVideoRecording records the current session until it closes or the recording is stopped.
+func rpcStartVideoRecording() error {
+	session := currentSession
+	if session == nil {
+		return errors.New("no active session")
+	}
+	return startVideoRecording(VideoRecordingModeSession, session)
+}
+
+func rpcStopVideoRecording() (*VideoRecordingState, error) {
+	return stopVideoRecording(func(*videoRecording) bool { return true })
+}
+
+func rpcGetVideoRecordingSettings() (VideoRecordingConfig, error) {
+	return config.VideoRecording, nil
+}
+
+// rpcSetVideoRecordingSettings saves the settings, they apply to the next recording. Turning
+// always on recording on takes over a recording of the session.
+func rpcSetVideoRecordingSettings(settings VideoRecordingConfig) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	config.VideoRecording = settings
+	if err := SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if !settings.AlwaysOn {
+		_, _ = stopVideoRecording(func(r *videoRecording) bool { return r.mode == VideoRecordingModeAlwaysOn })
+		return nil
+	}
+	if actionSessions == 0 {
+		return nil
+	}
+	_, _ = stopVideoRecording(func(r *videoRecording) bool { return r.mode == VideoRecordingModeSession })
+	if state, _ := rpcGetVideoRecordingState(); state.Mode != VideoRecordingModeAlwaysOn {
+		return startVideoRecording(VideoRecordingModeAlwaysOn, nil)
+	}
+	return nil
+}
+
+func rpcGetVideoRecordings() ([]videorec.File, error) {
+	return videorec.List(videoRecordingsFolder)
+}
+
+func getVideoRecordingPath(name string) (string, error) {
+	if !videorec.ValidFileName(name) {
+		return "", fmt.Errorf("invalid video recording name: %s", name)
+	}
+	fullPath := filepath.Join(videoRecordingsFolder, name)
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s", errVideoRecordingNotFound, name)
+	}
+	return fullPath, nil
+}
+
+func rpcDeleteVideoRecording(name string) error {
+	fullPath, err := getVideoRecordingPath(name)
+	if err != nil {
+		return err
+	}
+	if state, _ := rpcGetVideoRecordingState(); state.File == name {
+		return errors.New("the video recording is still being written")
+	}
+	if err := os.Remove(fullPath); err != nil {
+		return fmt.Errorf("failed to delete video recording: %v", err)
+	}
+	return nil
+}
+
+func handleListVideoRecordings(c *gin.Context) {
+	recordings, err := rpcGetVideoRecordings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, recordings)
+}
+
+func handleDownloadVideoRecording(c *gin.Context) {
+	fullPath, err := getVideoRecordingPath(c.Param("name"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, errVideoRecordingNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.FileAttachment(fullPath, filepath.Base(fullPath))
+}
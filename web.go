@@ -184,6 +184,15 @@ func setupRouter() *gin.Engine {
 		protected.PUT("/auth/password-local", handleUpdatePassword)
 		protected.DELETE("/auth/local-password", handleDeletePassword)
 		protected.POST("/storage/upload", handleUploadHttp)
+		protected.GET("/printer/jobs", handleListPrintJobs)
+		protected.GET("/printer/jobs/:filename", handleDownloadPrintJob)
+		protected.GET("/keyboard/sequences", handleListKeySequences)
+		protected.POST("/keyboard/sequences/:name", handleSendKeySequence)
+		protected.GET("/keyboard/macros/export", handleExportKeyboardMacros)
+		protected.POST("/keyboard/macros/import", handleImportKeyboardMacros)
+		protected.POST("/keyboard/macros/:id/execute", handleExecuteKeyboardMacro)
+		protected.GET("/video/recordings", handleListVideoRecordings)
+		protected.GET("/video/recordings/:name", handleDownloadVideoRecording)
 
 		// HDMI Output API endpoints
 		protected.GET("/hdmi/status", handleHDMIOutputStatus)
@@ -12,6 +12,7 @@ import (
 	"github.com/coder/websocket"
 	"github.com/coder/websocket/wsjson"
 	"github.com/gin-gonic/gin"
+	"github.com/jetkvm/kvm/internal/framebus"
 	"github.com/jetkvm/kvm/internal/hidrpc"
 	"github.com/jetkvm/kvm/internal/logging"
 	"github.com/jetkvm/kvm/internal/usbgadget"
@@ -22,6 +23,7 @@ import (
 type Session struct {
 	peerConnection           *webrtc.PeerConnection
 	VideoTrack               *webrtc.TrackLocalStaticSample
+	AudioTrack               *webrtc.TrackLocalStaticSample
 	ControlChannel           *webrtc.DataChannel
 	RPCChannel               *webrtc.DataChannel
 	HidChannel               *webrtc.DataChannel
@@ -37,6 +39,11 @@ type Session struct {
 	hidQueue                 []chan hidQueueMessage
 
 	keysDownStateQueue chan usbgadget.KeysDownState
+
+	keyRemapOverride bool // use keyRemapProfile instead of the profile of the config
+	keyRemapProfile  string
+
+	videoFrames *framebus.Subscriber // sends the video to VideoTrack while the session is connected
 }
 
 func (s *Session) resetKeepAliveTime() {
@@ -48,7 +55,8 @@ func (s *Session) resetKeepAliveTime() {
 
 type hidQueueMessage struct {
 	webrtc.DataChannelMessage
-	channel string
+	channel  string
+	received time.Time
 }
 
 type SessionConfig struct {
@@ -105,11 +113,44 @@ func (s *Session) initQueues() {
 }
 
 func (s *Session) handleQueues(index int) {
-	for msg := range s.hidQueue[index] {
-		onHidMessage(msg, s)
+	queue := s.hidQueue[index]
+	for msg := range queue {
+		batch := []hidQueueMessage{msg}
+		opts := config.HidCoalescing
+		if opts.Pointer || opts.Wheel {
+			// take everything that's waiting, so stale reports can be merged
+			batch = drainHidQueue(queue, batch)
+			coalesced := hidrpc.Coalesce(batch, opts,
+				func(m hidQueueMessage) []byte { return m.Data },
+				func(m hidQueueMessage, data []byte) hidQueueMessage {
+					m.Data = data
+					return m
+				},
+			)
+			for range len(batch) - len(coalesced) {
+				recordHidMessageDropped("coalesced")
+			}
+			batch = coalesced
+		}
+		setHidQueueDepth(index, len(queue))
+
+		for _, m := range batch {
+			onHidMessage(m, s)
+		}
 	}
 }
 
+func drainHidQueue(queue chan hidQueueMessage, batch []hidQueueMessage) []hidQueueMessage {
+	for range len(queue) {
+		msg, ok := <-queue
+		if !ok {
+			break
+		}
+		batch = append(batch, msg)
+	}
+	return batch
+}
+
 const keysDownStateQueueSize = 64
 
 func (s *Session) initKeysDownStateQueue() {
@@ -171,9 +212,12 @@ func getOnHidMessageHandler(session *Session, scopedLogger *zerolog.Logger, chan
 			queue <- hidQueueMessage{
 				DataChannelMessage: msg,
 				channel:            channel,
+				received:           time.Now(),
 			}
+			setHidQueueDepth(queueIndex, len(queue))
 		} else {
 			l.Warn().Int("queueIndex", queueIndex).Msg("received data in HID RPC message handler, but queue is nil")
+			recordHidMessageDropped("queue_unavailable")
 			return
 		}
 	}
@@ -265,6 +309,8 @@ func newSession(config SessionConfig) (*Session, error) {
 			handleTerminalChannel(d)
 		case "serial":
 			handleSerialChannel(d)
+		case "usb-serial":
+			handleUsbSerialChannel(d)
 		default:
 			if strings.HasPrefix(d.Label(), uploadIdPrefix) {
 				go handleUploadChannel(d)
@@ -284,17 +330,45 @@ func newSession(config SessionConfig) (*Session, error) {
 		return nil, err
 	}
 
+	senders := []*webrtc.RTPSender{rtpSender}
+	// without the audio function there's nothing to stream, the microphone still works without the track
+	if isUsbAudioEnabled() {
+		session.AudioTrack, err = newAudioTrack()
+		if err != nil {
+			scopedLogger.Warn().Err(err).Msg("Failed to create AudioTrack")
+			return nil, err
+		}
+
+		audioRtpSender, err := peerConnection.AddTrack(session.AudioTrack)
+		if err != nil {
+			scopedLogger.Warn().Err(err).Msg("Failed to add AudioTrack to PeerConnection")
+			return nil, err
+		}
+		senders = append(senders, audioRtpSender)
+	}
+
 	// Read incoming RTCP packets
 	// Before these packets are returned they are processed by interceptors. For things
 	// like NACK this needs to be called.
-	go func() {
-		rtcpBuf := make([]byte, 1500)
-		for {
-			if _, _, rtcpErr := rtpSender.Read(rtcpBuf); rtcpErr != nil {
-				return
+	for _, sender := range senders {
+		go func() {
+			rtcpBuf := make([]byte, 1500)
+			for {
+				if _, _, rtcpErr := sender.Read(rtcpBuf); rtcpErr != nil {
+					return
+				}
 			}
+		}()
+	}
+
+	// the browser sends its microphone on the audio transceiver
+	peerConnection.OnTrack(func(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		if track.Kind() != webrtc.RTPCodecTypeAudio {
+			return
 		}
-	}()
+		scopedLogger.Info().Str("codec", track.Codec().MimeType).Msg("New audio track")
+		handleMicrophoneTrack(track)
+	})
 	var isConnected bool
 
 	peerConnection.OnICECandidate(func(candidate *webrtc.ICECandidate) {
@@ -310,6 +384,7 @@ func newSession(config SessionConfig) (*Session, error) {
 	peerConnection.OnICEConnectionStateChange(func(connectionState webrtc.ICEConnectionState) {
 		scopedLogger.Info().Str("connectionState", connectionState.String()).Msg("ICE Connection State has changed")
 		if connectionState == webrtc.ICEConnectionStateConnected {
+			session.startVideoFrames()
 			if !isConnected {
 				isConnected = true
 				actionSessions++
@@ -330,7 +405,11 @@ func newSession(config SessionConfig) (*Session, error) {
 				// Cancel any ongoing keyboard report multi when session closes
 				cancelKeyboardMacro()
 				currentSession = nil
+				// the keys were remapped for this session
+				keyRemapper.Reset()
 			}
+			session.stopVideoFrames()
+
 			// Stop RPC processor
 			if session.rpcQueue != nil {
 				close(session.rpcQueue)
@@ -347,9 +426,7 @@ func newSession(config SessionConfig) (*Session, error) {
 			session.keysDownStateQueue = nil
 
 			if session.shouldUmountVirtualMedia {
-				if err := rpcUnmountImage(); err != nil {
-					scopedLogger.Warn().Err(err).Msg("unmount image failed on connection close")
-				}
+				unmountAllImages()
 			}
 			if isConnected {
 				isConnected = false
@@ -359,6 +436,7 @@ func newSession(config SessionConfig) (*Session, error) {
 					onLastSessionDisconnected()
 				}
 			}
+			onVideoRecordingSessionClosed(session, actionSessions == 0)
 		}
 	})
 	return session, nil
@@ -372,6 +450,7 @@ func onActiveSessionsChanged() {
 
 func onFirstSessionConnected() {
 	_ = nativeInstance.VideoStart()
+	onVideoRecordingSessionConnected()
 }
 
 func onLastSessionDisconnected() {